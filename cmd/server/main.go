@@ -1,17 +1,23 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/torantous1337/retail-management/internal/adapters/handler"
+	"github.com/torantous1337/retail-management/internal/adapters/label"
+	"github.com/torantous1337/retail-management/internal/adapters/printer"
 	"github.com/torantous1337/retail-management/internal/adapters/storage"
+	"github.com/torantous1337/retail-management/internal/core/ports"
 	"github.com/torantous1337/retail-management/internal/core/services"
 )
 
@@ -35,14 +41,69 @@ func main() {
 	productRepo := storage.NewProductRepository(db)
 	auditRepo := storage.NewAuditLogRepository(db)
 	categoryRepo := storage.NewCategoryRepository(db)
+	recallRepo := storage.NewRecallRepository(db)
+	restrictionRepo := storage.NewRestrictionRepository(db)
+	packRepo := storage.NewPackRepository(db)
+	barcodeRepo := storage.NewBarcodeRepository(db)
+	saleRepo := storage.NewSaleRepository(db)
+	priceRepo := storage.NewPriceRepository(db)
+	priceListRepo := storage.NewPriceListRepository(db)
+	analyticsRepo := storage.NewAnalyticsRepository(db)
+	associationRepo := storage.NewAssociationRepository(db)
+	synonymRepo := storage.NewSynonymRepository(db)
 	txManager := storage.NewSQLTransactionManager(db)
 
 	// Initialize services (Clean Architecture: Services depend on Repository interfaces)
 	auditSvc := services.NewAuditService(auditRepo)
-	categorySvc := services.NewCategoryService(categoryRepo)
-	productSvc := services.NewProductService(productRepo, categoryRepo, auditSvc, txManager)
-	analyticsSvc := services.NewAnalyticsService(productRepo)
+	categorySvc := services.NewCategoryService(categoryRepo, txManager)
+	productSvc := services.NewProductService(productRepo, categoryRepo, auditSvc, txManager, synonymRepo)
+	synonymSvc := services.NewSynonymService(synonymRepo)
+
+	// "reindex" rebuilds the product search index and exits, e.g. after
+	// attributes are marked searchable outside the API
+	if len(os.Args) > 1 && os.Args[1] == "reindex" {
+		reindexed, err := productSvc.RebuildSearchIndex(context.Background())
+		if err != nil {
+			log.Fatalf("Failed to rebuild search index: %v", err)
+		}
+		log.Printf("Search index rebuilt, %d product(s) reindexed", reindexed)
+		return
+	}
+	analyticsSvc := services.NewAnalyticsService(productRepo, categoryRepo, analyticsRepo)
 	saleSvc := services.NewSaleService(txManager)
+	recallSvc := services.NewRecallService(recallRepo, txManager)
+	restrictionSvc := services.NewRestrictionService(restrictionRepo)
+	inventorySvc := services.NewInventoryService(packRepo, productRepo, txManager)
+	barcodeSvc := services.NewBarcodeService(barcodeRepo, productRepo, packRepo, txManager)
+	pricingSvc := services.NewPricingService(priceRepo, productRepo, txManager)
+	priceListSvc := services.NewPriceListService(priceListRepo, productRepo, categoryRepo)
+	repriceSvc := services.NewRepriceService(productSvc, categoryRepo, txManager)
+	basketSvc := services.NewBasketService(analyticsRepo, associationRepo, txManager)
+	forecastSvc := services.NewForecastService(analyticsRepo, productRepo)
+	labelSvc := services.NewLabelService(productSvc, categoryRepo, barcodeRepo, map[string]ports.LabelRenderer{
+		"zpl": label.NewZPLRenderer(0),
+		"pdf": label.NewPDFRenderer(),
+	})
+
+	// Stock is carried at weighted average cost unless COSTING_METHOD is fifo or last
+	costingMethod, err := services.ParseCostingMethod(os.Getenv("COSTING_METHOD"))
+	if err != nil {
+		log.Fatalf("Invalid COSTING_METHOD: %v", err)
+	}
+	saleSvc.SetCostingMethod(costingMethod)
+	inventorySvc.SetCostingMethod(costingMethod)
+	analyticsSvc.SetCostingMethod(costingMethod)
+
+	// Receipt printer is optional: "tcp://host:9100" or a device/file path
+	var receiptPrinter ports.PrinterSink
+	if target := os.Getenv("RECEIPT_PRINTER"); target != "" {
+		receiptPrinter, err = printer.NewSink(target)
+		if err != nil {
+			log.Fatalf("Invalid RECEIPT_PRINTER: %v", err)
+		}
+	}
+	receiptSvc := services.NewReceiptService(saleRepo, productRepo, packRepo, printer.NewESCPOSRenderer(0), receiptPrinter)
+	receiptSvc.SetReceiptText(splitLines(os.Getenv("RECEIPT_HEADER")), splitLines(os.Getenv("RECEIPT_FOOTER")))
 
 	// Initialize HTTP handlers
 	productHandler := handler.NewProductHandler(productSvc)
@@ -50,6 +111,18 @@ func main() {
 	categoryHandler := handler.NewCategoryHandler(categorySvc)
 	analyticsHandler := handler.NewAnalyticsHandler(analyticsSvc)
 	saleHandler := handler.NewSaleHandler(saleSvc)
+	recallHandler := handler.NewRecallHandler(recallSvc)
+	restrictionHandler := handler.NewRestrictionHandler(restrictionSvc)
+	inventoryHandler := handler.NewInventoryHandler(inventorySvc)
+	barcodeHandler := handler.NewBarcodeHandler(barcodeSvc)
+	labelHandler := handler.NewLabelHandler(labelSvc)
+	receiptHandler := handler.NewReceiptHandler(receiptSvc)
+	pricingHandler := handler.NewPricingHandler(pricingSvc)
+	priceListHandler := handler.NewPriceListHandler(priceListSvc)
+	repriceHandler := handler.NewRepriceHandler(repriceSvc)
+	basketHandler := handler.NewBasketHandler(basketSvc)
+	forecastHandler := handler.NewForecastHandler(forecastSvc)
+	synonymHandler := handler.NewSynonymHandler(synonymSvc)
 
 	// Create Fiber app
 	app := fiber.New(fiber.Config{
@@ -77,17 +150,38 @@ func main() {
 	products := api.Group("/products")
 	products.Post("/", productHandler.CreateProduct)
 	products.Post("/import", productHandler.ImportProducts)
+	products.Post("/reprice", repriceHandler.RepriceProducts)
 	products.Get("/search", productHandler.SearchProducts)
+	products.Get("/suggest", productHandler.SuggestProducts)
+	products.Post("/search-index/rebuild", productHandler.RebuildSearchIndex)
 	products.Get("/", productHandler.ListProducts)
 	products.Get("/:id", productHandler.GetProduct)
 	products.Get("/sku/:sku", productHandler.GetProductBySKU)
+	products.Get("/barcode/:code", barcodeHandler.LookupBarcode)
 	products.Put("/:id", productHandler.UpdateProduct)
 	products.Delete("/:id", productHandler.DeleteProduct)
+	products.Post("/:id/packs", inventoryHandler.CreatePack)
+	products.Get("/:id/packs", inventoryHandler.ListPacks)
+	products.Delete("/:id/packs/:packId", inventoryHandler.DeletePack)
+	products.Post("/:id/receive", inventoryHandler.ReceiveStock)
+	products.Post("/:id/barcodes", barcodeHandler.AddBarcode)
+	products.Get("/:id/barcodes", barcodeHandler.ListBarcodes)
+	products.Delete("/:id/barcodes/:code", barcodeHandler.RemoveBarcode)
+	products.Get("/:id/price-history", pricingHandler.GetPriceHistory)
+	products.Get("/:id/price", pricingHandler.GetPriceAt)
+	products.Post("/:id/price-changes", pricingHandler.SchedulePriceChange)
+	products.Get("/:id/price-changes", pricingHandler.ListProductPriceChanges)
+	products.Get("/:id/frequently-bought-with", basketHandler.FrequentlyBoughtWith)
+	products.Get("/:id/forecast", forecastHandler.ForecastProduct)
 
 	// Category routes
 	categories := api.Group("/categories")
 	categories.Post("/", categoryHandler.CreateCategory)
 	categories.Get("/", categoryHandler.ListCategories)
+	categories.Get("/:id", categoryHandler.GetCategory)
+	categories.Put("/:id", categoryHandler.UpdateCategory)
+	categories.Delete("/:id", categoryHandler.DeleteCategory)
+	categories.Get("/:id/versions", categoryHandler.ListCategoryVersions)
 
 	// Audit log routes
 	audit := api.Group("/audit-logs")
@@ -97,10 +191,68 @@ func main() {
 	// Analytics routes
 	analytics := api.Group("/analytics")
 	analytics.Get("/summary", analyticsHandler.GetInventorySummary)
+	analytics.Get("/sales", analyticsHandler.GetSalesTimeSeries)
+	analytics.Get("/margins", analyticsHandler.GetMarginReport)
+	analytics.Get("/margins/performers", analyticsHandler.GetPerformers)
+	analytics.Get("/margins/negative", analyticsHandler.GetNegativeMargins)
+	analytics.Get("/classification", analyticsHandler.ClassifyProducts)
+	analytics.Get("/dead-stock", analyticsHandler.GetDeadStock)
+	analytics.Get("/associations", basketHandler.TopAssociations)
+	analytics.Post("/associations/recompute", basketHandler.RecomputeAssociations)
 
 	// Sales routes
 	sales := api.Group("/sales")
 	sales.Post("/", saleHandler.ProcessSale)
+	sales.Get("/:id/receipt", receiptHandler.DownloadReceipt)
+	sales.Post("/:id/print", receiptHandler.PrintReceipt)
+
+	// Recall routes
+	recalls := api.Group("/recalls")
+	recalls.Post("/", recallHandler.CreateRecall)
+	recalls.Get("/", recallHandler.ListRecalls)
+	recalls.Get("/:id", recallHandler.GetRecall)
+	recalls.Get("/:id/report", recallHandler.GetRecallReport)
+	recalls.Post("/:id/close", recallHandler.CloseRecall)
+
+	// Sale restriction routes
+	restrictions := api.Group("/restrictions")
+	restrictions.Post("/", restrictionHandler.CreateRestriction)
+	restrictions.Get("/", restrictionHandler.ListRestrictions)
+	restrictions.Get("/:id", restrictionHandler.GetRestriction)
+	restrictions.Delete("/:id", restrictionHandler.DeleteRestriction)
+
+	// Search synonym routes
+	synonyms := api.Group("/search-synonyms")
+	synonyms.Post("/", synonymHandler.CreateSynonym)
+	synonyms.Get("/", synonymHandler.ListSynonyms)
+	synonyms.Delete("/:id", synonymHandler.DeleteSynonym)
+
+	// Label printing routes
+	labels := api.Group("/labels")
+	labels.Post("/", labelHandler.PrintLabels)
+	labels.Get("/templates", labelHandler.ListTemplates)
+
+	// Scheduled price change routes
+	priceChanges := api.Group("/price-changes")
+	priceChanges.Get("/", pricingHandler.ListPriceChanges)
+	priceChanges.Get("/:id", pricingHandler.GetPriceChange)
+	priceChanges.Post("/:id/cancel", pricingHandler.CancelPriceChange)
+
+	// Customer price list routes
+	priceLists := api.Group("/price-lists")
+	priceLists.Post("/", priceListHandler.CreatePriceList)
+	priceLists.Get("/", priceListHandler.ListPriceLists)
+	priceLists.Get("/:id", priceListHandler.GetPriceList)
+	priceLists.Delete("/:id", priceListHandler.DeletePriceList)
+	priceLists.Post("/:id/rules", priceListHandler.AddRule)
+	priceLists.Delete("/:id/rules/:ruleId", priceListHandler.RemoveRule)
+	priceLists.Get("/:id/quote", priceListHandler.QuotePrice)
+
+	// Apply scheduled price changes in the background
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	go runPriceScheduler(schedulerCtx, pricingSvc, time.Minute)
+	go runBasketScheduler(schedulerCtx, basketSvc, 6*time.Hour)
 
 	// Get port from environment or use default
 	port := os.Getenv("PORT")
@@ -123,9 +275,63 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down server...")
+	stopScheduler()
 	if err := app.Shutdown(); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
 	log.Println("Server exited")
 }
+
+// splitLines splits a "|"-separated environment value into lines.
+func splitLines(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, "|")
+}
+
+// runPriceScheduler applies due price changes on startup and then every
+// interval until ctx is cancelled.
+func runPriceScheduler(ctx context.Context, pricingSvc ports.PricingService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := pricingSvc.ApplyDueChanges(ctx)
+		if err != nil {
+			log.Printf("Price scheduler: %v", err)
+		}
+		if n > 0 {
+			log.Printf("Price scheduler: applied %d price change(s)", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runBasketScheduler recomputes product associations on startup and then
+// every interval until ctx is cancelled.
+func runBasketScheduler(ctx context.Context, basketSvc ports.BasketService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		analysis, err := basketSvc.RecomputeAssociations(ctx)
+		if err != nil {
+			log.Printf("Basket scheduler: %v", err)
+		} else {
+			log.Printf("Basket scheduler: mined %d association(s) from %d sale(s)", analysis.Associations, analysis.Transactions)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
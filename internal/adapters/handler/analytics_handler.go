@@ -1,8 +1,13 @@
 package handler
 
 import (
+	"errors"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/torantous1337/retail-management/internal/core/domain"
 	"github.com/torantous1337/retail-management/internal/core/ports"
+	"github.com/torantous1337/retail-management/internal/core/services"
 )
 
 // AnalyticsHandler handles HTTP requests for analytics.
@@ -17,6 +22,77 @@ func NewAnalyticsHandler(analyticsSvc ports.AnalyticsService) *AnalyticsHandler
 	}
 }
 
+// marginRowResponse represents one group in a margin report response.
+type marginRowResponse struct {
+	Key           string  `json:"key"`
+	Label         string  `json:"label"`
+	Sales         int     `json:"sales"`
+	Quantity      float64 `json:"quantity"`
+	Revenue       float64 `json:"revenue"`
+	Cost          float64 `json:"cost"`
+	Profit        float64 `json:"profit"`
+	MarginPercent float64 `json:"margin_percent"`
+}
+
+// salesBucketResponse represents one bucket of a sales time series.
+type salesBucketResponse struct {
+	Start         time.Time `json:"start"`
+	Revenue       float64   `json:"revenue"`
+	Units         float64   `json:"units"`
+	Transactions  int       `json:"transactions"`
+	AverageBasket float64   `json:"average_basket"`
+}
+
+// salesPeriodResponse represents the totals and buckets of a sales period.
+type salesPeriodResponse struct {
+	From          time.Time             `json:"from"`
+	To            time.Time             `json:"to"`
+	Revenue       float64               `json:"revenue"`
+	Units         float64               `json:"units"`
+	Transactions  int                   `json:"transactions"`
+	AverageBasket float64               `json:"average_basket"`
+	Buckets       []salesBucketResponse `json:"buckets"`
+}
+
+// salesComparisonResponse represents a baseline period and the percentage
+// change from it.
+type salesComparisonResponse struct {
+	salesPeriodResponse
+	RevenueChange       *float64 `json:"revenue_change_percent"`
+	UnitsChange         *float64 `json:"units_change_percent"`
+	TransactionsChange  *float64 `json:"transactions_change_percent"`
+	AverageBasketChange *float64 `json:"average_basket_change_percent"`
+}
+
+// classificationResponse represents one product in an ABC/XYZ classification.
+type classificationResponse struct {
+	ProductID       string  `json:"product_id"`
+	SKU             string  `json:"sku"`
+	Name            string  `json:"name"`
+	Revenue         float64 `json:"revenue"`
+	RevenueShare    float64 `json:"revenue_share"`
+	CumulativeShare float64 `json:"cumulative_share"`
+	ABCClass        string  `json:"abc_class"`
+	Units           float64 `json:"units"`
+	MeanDemand      float64 `json:"mean_weekly_demand"`
+	DemandCV        float64 `json:"demand_cv"`
+	XYZClass        string  `json:"xyz_class"`
+	Class           string  `json:"class"`
+}
+
+// deadStockResponse represents one product in a dead stock report.
+type deadStockResponse struct {
+	ProductID     string     `json:"product_id"`
+	SKU           string     `json:"sku"`
+	Name          string     `json:"name"`
+	CategoryID    string     `json:"category_id,omitempty"`
+	Quantity      float64    `json:"quantity"`
+	CostPrice     float64    `json:"cost_price"`
+	TiedUpCapital float64    `json:"tied_up_capital"`
+	LastSoldAt    *time.Time `json:"last_sold_at"`
+	DaysSinceSale *int       `json:"days_since_sale"`
+}
+
 // GetInventorySummary handles GET /analytics/summary
 func (h *AnalyticsHandler) GetInventorySummary(c *fiber.Ctx) error {
 	summary, err := h.analyticsSvc.GetInventorySummary(c.Context())
@@ -29,16 +105,302 @@ func (h *AnalyticsHandler) GetInventorySummary(c *fiber.Ctx) error {
 	breakdown := make([]fiber.Map, 0, len(summary.CategoryBreakdown))
 	for _, cb := range summary.CategoryBreakdown {
 		breakdown = append(breakdown, fiber.Map{
-			"category_id":   cb.CategoryID,
-			"category_name": cb.CategoryName,
-			"count":         cb.Count,
-			"total_value":   cb.TotalValue,
+			"category_id":      cb.CategoryID,
+			"category_name":    cb.CategoryName,
+			"count":            cb.Count,
+			"quantity":         cb.Quantity,
+			"total_value":      cb.TotalValue,
+			"retail_value":     cb.RetailValue,
+			"potential_profit": cb.PotentialProfit,
 		})
 	}
 
 	return c.JSON(fiber.Map{
+		"costing_method":     summary.CostingMethod,
 		"total_items":        summary.TotalItems,
+		"total_quantity":     summary.TotalQuantity,
 		"total_value":        summary.TotalValue,
+		"retail_value":       summary.RetailValue,
+		"potential_profit":   summary.PotentialProfit,
+		"margin_percent":     summary.MarginPercent,
+		"quarantined_value":  summary.QuarantinedValue,
 		"category_breakdown": breakdown,
 	})
 }
+
+// GetMarginReport handles GET /analytics/margins
+func (h *AnalyticsHandler) GetMarginReport(c *fiber.Ctx) error {
+	query, err := parseMarginQuery(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	report, err := h.analyticsSvc.GetMarginReport(c.Context(), query)
+	if err != nil {
+		return analyticsError(c, err, "Failed to get margin report")
+	}
+
+	return c.JSON(fiber.Map{
+		"group_by":       report.GroupBy,
+		"from":           report.From,
+		"to":             report.To,
+		"revenue":        report.Revenue,
+		"cost":           report.Cost,
+		"profit":         report.Profit,
+		"margin_percent": report.MarginPercent,
+		"rows":           toMarginRowResponses(report.Rows),
+	})
+}
+
+// GetPerformers handles GET /analytics/margins/performers
+func (h *AnalyticsHandler) GetPerformers(c *fiber.Ctx) error {
+	query, err := parseMarginQuery(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	metric := c.Query("metric", services.MetricProfit)
+	top, bottom, err := h.analyticsSvc.GetPerformers(c.Context(), query, metric, c.QueryInt("limit", 10))
+	if err != nil {
+		return analyticsError(c, err, "Failed to get performers")
+	}
+
+	return c.JSON(fiber.Map{
+		"group_by": query.GroupBy,
+		"metric":   metric,
+		"top":      toMarginRowResponses(top),
+		"bottom":   toMarginRowResponses(bottom),
+	})
+}
+
+// GetNegativeMargins handles GET /analytics/margins/negative
+func (h *AnalyticsHandler) GetNegativeMargins(c *fiber.Ctx) error {
+	query, err := parseMarginQuery(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	rows, err := h.analyticsSvc.GetNegativeMargins(c.Context(), query.From, query.To)
+	if err != nil {
+		return analyticsError(c, err, "Failed to get negative margins")
+	}
+
+	return c.JSON(toMarginRowResponses(rows))
+}
+
+// GetSalesTimeSeries handles GET /analytics/sales
+func (h *AnalyticsHandler) GetSalesTimeSeries(c *fiber.Ctx) error {
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if from == nil || to == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "from and to are required",
+		})
+	}
+
+	series, err := h.analyticsSvc.GetSalesTimeSeries(c.Context(), ports.SalesSeriesQuery{
+		Interval: c.Query("interval", domain.GroupByDay),
+		From:     *from,
+		To:       *to,
+	})
+	if err != nil {
+		return analyticsError(c, err, "Failed to get sales time series")
+	}
+
+	return c.JSON(fiber.Map{
+		"interval":        series.Interval,
+		"current":         toSalesPeriodResponse(series.Current),
+		"previous_period": toSalesComparisonResponse(series.PreviousPeriod),
+		"last_year":       toSalesComparisonResponse(series.LastYear),
+	})
+}
+
+// ClassifyProducts handles GET /analytics/classification
+func (h *AnalyticsHandler) ClassifyProducts(c *fiber.Ctx) error {
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	query := ports.ClassificationQuery{
+		AThreshold: c.QueryFloat("a", 0),
+		BThreshold: c.QueryFloat("b", 0),
+		XThreshold: c.QueryFloat("x", 0),
+		YThreshold: c.QueryFloat("y", 0),
+		CategoryID: c.Query("category_id"),
+	}
+	if from != nil {
+		query.From = *from
+	}
+	if to != nil {
+		query.To = *to
+	}
+
+	report, err := h.analyticsSvc.ClassifyProducts(c.Context(), query)
+	if err != nil {
+		return analyticsError(c, err, "Failed to classify products")
+	}
+
+	products := make([]classificationResponse, 0, len(report.Products))
+	for _, row := range report.Products {
+		products = append(products, classificationResponse{
+			ProductID:       row.ProductID,
+			SKU:             row.SKU,
+			Name:            row.Name,
+			Revenue:         row.Revenue,
+			RevenueShare:    row.RevenueShare,
+			CumulativeShare: row.CumulativeShare,
+			ABCClass:        row.ABCClass,
+			Units:           row.Units,
+			MeanDemand:      row.MeanDemand,
+			DemandCV:        row.DemandCV,
+			XYZClass:        row.XYZClass,
+			Class:           row.ABCClass + row.XYZClass,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"from":     report.From,
+		"to":       report.To,
+		"weeks":    report.Weeks,
+		"revenue":  report.Revenue,
+		"counts":   report.Counts,
+		"products": products,
+	})
+}
+
+// GetDeadStock handles GET /analytics/dead-stock
+func (h *AnalyticsHandler) GetDeadStock(c *fiber.Ctx) error {
+	report, err := h.analyticsSvc.GetDeadStock(c.Context(), c.QueryInt("days", 90), c.Query("category_id"))
+	if err != nil {
+		return analyticsError(c, err, "Failed to get dead stock")
+	}
+
+	items := make([]deadStockResponse, 0, len(report.Items))
+	for _, item := range report.Items {
+		items = append(items, deadStockResponse{
+			ProductID:     item.ProductID,
+			SKU:           item.SKU,
+			Name:          item.Name,
+			CategoryID:    item.CategoryID,
+			Quantity:      item.Quantity,
+			CostPrice:     item.CostPrice,
+			TiedUpCapital: item.TiedUpCapital,
+			LastSoldAt:    item.LastSoldAt,
+			DaysSinceSale: item.DaysSinceSale,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"days":            report.Days,
+		"cutoff":          report.Cutoff,
+		"tied_up_capital": report.TiedUpCapital,
+		"items":           items,
+	})
+}
+
+// parseMarginQuery reads the group_by, from and to query parameters.
+func parseMarginQuery(c *fiber.Ctx) (ports.MarginQuery, error) {
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		return ports.MarginQuery{}, err
+	}
+
+	return ports.MarginQuery{
+		GroupBy:    c.Query("group_by", domain.GroupByProduct),
+		From:       from,
+		To:         to,
+		CategoryID: c.Query("category_id"),
+	}, nil
+}
+
+// parseDateRange reads the optional from and to query parameters. A bare
+// to date includes the whole day.
+func parseDateRange(c *fiber.Ctx) (*time.Time, *time.Time, error) {
+	from, err := parseTimeParam(c.Query("from"), false)
+	if err != nil {
+		return nil, nil, errors.New("invalid from")
+	}
+	to, err := parseTimeParam(c.Query("to"), true)
+	if err != nil {
+		return nil, nil, errors.New("invalid to")
+	}
+	return from, to, nil
+}
+
+// analyticsError maps an analytics service error to a response.
+func analyticsError(c *fiber.Ctx, err error, message string) error {
+	if errors.Is(err, services.ErrInvalidAnalyticsQuery) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": message,
+	})
+}
+
+// toMarginRowResponses converts margin rows to their response form.
+func toMarginRowResponses(rows []domain.MarginRow) []marginRowResponse {
+	resp := make([]marginRowResponse, 0, len(rows))
+	for _, row := range rows {
+		resp = append(resp, marginRowResponse{
+			Key:           row.Key,
+			Label:         row.Label,
+			Sales:         row.Sales,
+			Quantity:      row.Quantity,
+			Revenue:       row.Revenue,
+			Cost:          row.Cost,
+			Profit:        row.Profit,
+			MarginPercent: row.MarginPercent,
+		})
+	}
+	return resp
+}
+
+// toSalesPeriodResponse converts a sales period to its response form.
+func toSalesPeriodResponse(period domain.SalesPeriod) salesPeriodResponse {
+	buckets := make([]salesBucketResponse, 0, len(period.Buckets))
+	for _, bucket := range period.Buckets {
+		buckets = append(buckets, salesBucketResponse{
+			Start:         bucket.Start,
+			Revenue:       bucket.Revenue,
+			Units:         bucket.Units,
+			Transactions:  bucket.Transactions,
+			AverageBasket: bucket.AverageBasket,
+		})
+	}
+
+	return salesPeriodResponse{
+		From:          period.From,
+		To:            period.To,
+		Revenue:       period.Revenue,
+		Units:         period.Units,
+		Transactions:  period.Transactions,
+		AverageBasket: period.AverageBasket,
+		Buckets:       buckets,
+	}
+}
+
+// toSalesComparisonResponse converts a sales comparison to its response form.
+func toSalesComparisonResponse(comparison domain.SalesComparison) salesComparisonResponse {
+	return salesComparisonResponse{
+		salesPeriodResponse: toSalesPeriodResponse(comparison.Period),
+		RevenueChange:       comparison.RevenueChange,
+		UnitsChange:         comparison.UnitsChange,
+		TransactionsChange:  comparison.TransactionsChange,
+		AverageBasketChange: comparison.AverageBasketChange,
+	}
+}
@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/torantous1337/retail-management/internal/core/domain"
 	"github.com/torantous1337/retail-management/internal/core/ports"
+	"github.com/torantous1337/retail-management/internal/core/services"
 )
 
 // AuditHandler handles HTTP requests for audit logs.
@@ -33,26 +35,28 @@ type AuditLogResponse struct {
 
 // ListAuditLogs handles GET /audit-logs
 func (h *AuditHandler) ListAuditLogs(c *fiber.Ctx) error {
-	limit := c.QueryInt("limit", 10)
-	offset := c.QueryInt("offset", 0)
+	page := parsePageRequest(c)
 
-	logs, err := h.auditSvc.GetAuditLogs(c.Context(), limit, offset)
+	logs, err := h.auditSvc.GetAuditLogs(c.Context(), page)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidPage) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to list audit logs",
 		})
 	}
 
-	responses := make([]AuditLogResponse, 0, len(logs))
-	for _, log := range logs {
+	responses := make([]AuditLogResponse, 0, len(logs.Logs))
+	for _, log := range logs.Logs {
 		responses = append(responses, h.toResponse(log))
 	}
 
-	return c.JSON(fiber.Map{
+	return c.JSON(addPageInfo(fiber.Map{
 		"audit_logs": responses,
-		"limit":      limit,
-		"offset":     offset,
-	})
+	}, page, logs.PageInfo))
 }
 
 // VerifyAuditChain handles GET /audit-logs/verify
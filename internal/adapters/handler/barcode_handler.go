@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+	"github.com/torantous1337/retail-management/internal/core/services"
+)
+
+// BarcodeHandler handles HTTP requests for product barcodes.
+type BarcodeHandler struct {
+	barcodeSvc ports.BarcodeService
+}
+
+// NewBarcodeHandler creates a new barcode handler instance.
+func NewBarcodeHandler(barcodeSvc ports.BarcodeService) *BarcodeHandler {
+	return &BarcodeHandler{
+		barcodeSvc: barcodeSvc,
+	}
+}
+
+// barcodeRequest represents the request body for assigning a barcode.
+type barcodeRequest struct {
+	Code string `json:"code"`
+}
+
+// barcodeResponse represents the response body for a product barcode.
+type barcodeResponse struct {
+	Code      string    `json:"code"`
+	GTIN      string    `json:"gtin"`
+	ProductID string    `json:"product_id"`
+	Format    string    `json:"format"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// barcodeLookupResponse represents the response body for a scanner lookup.
+type barcodeLookupResponse struct {
+	Product ProductResponse `json:"product"`
+	Pack    *packResponse   `json:"pack,omitempty"`
+}
+
+// AddBarcode handles POST /api/v1/products/:id/barcodes
+func (h *BarcodeHandler) AddBarcode(c *fiber.Ctx) error {
+	var req barcodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	barcode, err := h.barcodeSvc.AddBarcode(c.Context(), c.Params("id"), req.Code)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidBarcode) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if errors.Is(err, services.ErrBarcodeInUse) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(h.toResponse(barcode))
+}
+
+// ListBarcodes handles GET /api/v1/products/:id/barcodes
+func (h *BarcodeHandler) ListBarcodes(c *fiber.Ctx) error {
+	barcodes, err := h.barcodeSvc.ListBarcodes(c.Context(), c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list barcodes",
+		})
+	}
+
+	responses := make([]barcodeResponse, 0, len(barcodes))
+	for _, barcode := range barcodes {
+		responses = append(responses, h.toResponse(barcode))
+	}
+
+	return c.JSON(fiber.Map{
+		"barcodes": responses,
+	})
+}
+
+// RemoveBarcode handles DELETE /api/v1/products/:id/barcodes/:code
+func (h *BarcodeHandler) RemoveBarcode(c *fiber.Ctx) error {
+	err := h.barcodeSvc.RemoveBarcode(c.Context(), c.Params("id"), c.Params("code"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Barcode not found",
+		})
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// LookupBarcode handles GET /api/v1/products/barcode/:code
+func (h *BarcodeHandler) LookupBarcode(c *fiber.Ctx) error {
+	match, err := h.barcodeSvc.LookupBarcode(c.Context(), c.Params("code"))
+	if err != nil {
+		if errors.Is(err, services.ErrBarcodeNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Barcode not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	resp := barcodeLookupResponse{Product: toProductResponse(match.Product)}
+	if match.Pack != nil {
+		pack := toPackResponse(match.Pack)
+		resp.Pack = &pack
+	}
+
+	return c.JSON(resp)
+}
+
+// toResponse converts a domain product barcode to a response DTO.
+func (h *BarcodeHandler) toResponse(barcode *domain.ProductBarcode) barcodeResponse {
+	return barcodeResponse{
+		Code:      barcode.Code,
+		GTIN:      barcode.GTIN,
+		ProductID: barcode.ProductID,
+		Format:    barcode.Format,
+		CreatedAt: barcode.CreatedAt,
+	}
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+	"github.com/torantous1337/retail-management/internal/core/services"
+)
+
+// BasketHandler handles HTTP requests for market basket analysis.
+type BasketHandler struct {
+	basketSvc ports.BasketService
+}
+
+// NewBasketHandler creates a new market basket handler instance.
+func NewBasketHandler(basketSvc ports.BasketService) *BasketHandler {
+	return &BasketHandler{
+		basketSvc: basketSvc,
+	}
+}
+
+// associationResponse represents a product association in API responses.
+type associationResponse struct {
+	ProductID           string    `json:"product_id"`
+	ProductName         string    `json:"product_name"`
+	AssociatedProductID string    `json:"associated_product_id"`
+	AssociatedName      string    `json:"associated_name"`
+	PairCount           int       `json:"pair_count"`
+	Support             float64   `json:"support"`
+	Confidence          float64   `json:"confidence"`
+	Lift                float64   `json:"lift"`
+	ComputedAt          time.Time `json:"computed_at"`
+}
+
+// FrequentlyBoughtWith handles GET /api/v1/products/:id/frequently-bought-with
+func (h *BasketHandler) FrequentlyBoughtWith(c *fiber.Ctx) error {
+	associations, err := h.basketSvc.FrequentlyBoughtWith(c.Context(), c.Params("id"), c.QueryInt("limit", 10))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get associations",
+		})
+	}
+
+	return c.JSON(toAssociationResponses(associations))
+}
+
+// TopAssociations handles GET /api/v1/analytics/associations
+func (h *BasketHandler) TopAssociations(c *fiber.Ctx) error {
+	associations, err := h.basketSvc.TopAssociations(c.Context(), c.Query("metric", services.MetricLift), c.QueryInt("limit", 20))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAnalyticsQuery) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get associations",
+		})
+	}
+
+	return c.JSON(toAssociationResponses(associations))
+}
+
+// RecomputeAssociations handles POST /api/v1/analytics/associations/recompute
+func (h *BasketHandler) RecomputeAssociations(c *fiber.Ctx) error {
+	analysis, err := h.basketSvc.RecomputeAssociations(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"from":         analysis.From,
+		"to":           analysis.To,
+		"transactions": analysis.Transactions,
+		"associations": analysis.Associations,
+		"computed_at":  analysis.ComputedAt,
+	})
+}
+
+// toAssociationResponses converts domain associations to response DTOs.
+func toAssociationResponses(associations []*domain.ProductAssociation) []associationResponse {
+	resp := make([]associationResponse, 0, len(associations))
+	for _, a := range associations {
+		resp = append(resp, associationResponse{
+			ProductID:           a.ProductID,
+			ProductName:         a.ProductName,
+			AssociatedProductID: a.AssociatedProductID,
+			AssociatedName:      a.AssociatedName,
+			PairCount:           a.PairCount,
+			Support:             a.Support,
+			Confidence:          a.Confidence,
+			Lift:                a.Lift,
+			ComputedAt:          a.ComputedAt,
+		})
+	}
+	return resp
+}
@@ -1,10 +1,14 @@
 package handler
 
 import (
+	"errors"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/torantous1337/retail-management/internal/core/domain"
 	"github.com/torantous1337/retail-management/internal/core/ports"
+	"github.com/torantous1337/retail-management/internal/core/services"
 )
 
 // CategoryHandler handles HTTP requests for categories.
@@ -21,24 +25,85 @@ func NewCategoryHandler(categorySvc ports.CategoryService) *CategoryHandler {
 
 // AttributeDefinitionRequest represents an attribute definition in a request.
 type AttributeDefinitionRequest struct {
-	Key      string   `json:"key"`
-	Type     string   `json:"type"`
-	Required bool     `json:"required"`
-	Options  []string `json:"options"`
-	Unit     string   `json:"unit"`
+	Key        string      `json:"key"`
+	Type       string      `json:"type"`
+	Required   bool        `json:"required"`
+	Options    []string    `json:"options"`
+	Unit       string      `json:"unit"`
+	Min        *float64    `json:"min,omitempty"`
+	Max        *float64    `json:"max,omitempty"`
+	Step       *float64    `json:"step,omitempty"`
+	Integer    bool        `json:"integer,omitempty"`
+	MinLength  *int        `json:"min_length,omitempty"`
+	MaxLength  *int        `json:"max_length,omitempty"`
+	Pattern    string      `json:"pattern,omitempty"`
+	Default    interface{} `json:"default,omitempty"`
+	Unique     bool        `json:"unique,omitempty"`
+	Searchable bool        `json:"searchable,omitempty"`
 }
 
 // CreateCategoryRequest represents the request body for creating a category.
 type CreateCategoryRequest struct {
 	Name                 string                       `json:"name"`
+	ParentID             string                       `json:"parent_id"`
 	AttributeDefinitions []AttributeDefinitionRequest `json:"attribute_definitions"`
 }
 
+// UpdateCategoryRequest represents the request body for updating a category.
+type UpdateCategoryRequest struct {
+	Name                 string                        `json:"name"`
+	ParentID             *string                       `json:"parent_id"`
+	AttributeDefinitions *[]AttributeDefinitionRequest `json:"attribute_definitions"` // Omitted keeps the current definitions
+	ExpectedVersion      int                           `json:"expected_version"`
+	Renames              map[string]string             `json:"renames"`
+	Defaults             map[string]interface{}        `json:"defaults"`
+	ConvertTypes         bool                          `json:"convert_types"`
+	DryRun               bool                          `json:"dry_run"`
+}
+
 // CategoryResponse represents the response body for a category.
 type CategoryResponse struct {
 	ID                   string                       `json:"id"`
 	Name                 string                       `json:"name"`
+	ParentID             string                       `json:"parent_id,omitempty"`
+	Path                 []categoryRefResponse        `json:"path"`
+	AttributeDefinitions []AttributeDefinitionRequest `json:"attribute_definitions"`
+	EffectiveAttributes  []AttributeDefinitionRequest `json:"effective_attribute_definitions"`
+	Version              int                          `json:"version"`
+}
+
+// categoryRefResponse represents one step of a category breadcrumb path.
+type categoryRefResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// categoryVersionResponse represents a category version in API responses.
+type categoryVersionResponse struct {
+	Version              int                          `json:"version"`
+	Name                 string                       `json:"name"`
 	AttributeDefinitions []AttributeDefinitionRequest `json:"attribute_definitions"`
+	CreatedAt            time.Time                    `json:"created_at"`
+}
+
+// propertyViolationResponse represents a product failing validation in API responses.
+type propertyViolationResponse struct {
+	ProductID string `json:"product_id"`
+	SKU       string `json:"sku"`
+	Error     string `json:"error"`
+}
+
+// schemaEvolutionResponse represents the effect of a category update in API responses.
+type schemaEvolutionResponse struct {
+	CategoryID  string                      `json:"category_id"`
+	FromVersion int                         `json:"from_version"`
+	ToVersion   int                         `json:"to_version"`
+	Products    int                         `json:"products"`
+	Backfilled  int                         `json:"backfilled"`
+	Migrated    int                         `json:"migrated"`
+	Reindexed   int                         `json:"reindexed"`
+	Violations  []propertyViolationResponse `json:"violations"`
+	Applied     bool                        `json:"applied"`
 }
 
 // CreateCategory handles POST /categories
@@ -56,73 +121,255 @@ func (h *CategoryHandler) CreateCategory(c *fiber.Ctx) error {
 		})
 	}
 
-	attrs := make([]domain.AttributeDefinition, 0, len(req.AttributeDefinitions))
-	for _, a := range req.AttributeDefinitions {
-		attrs = append(attrs, domain.AttributeDefinition{
-			Key:      a.Key,
-			Type:     a.Type,
-			Required: a.Required,
-			Options:  a.Options,
-			Unit:     a.Unit,
-		})
-	}
-
 	category := &domain.Category{
 		ID:                   uuid.New().String(),
 		Name:                 req.Name,
-		AttributeDefinitions: attrs,
+		ParentID:             req.ParentID,
+		AttributeDefinitions: toAttributeDefinitions(req.AttributeDefinitions),
 	}
 
 	err := h.categorySvc.CreateCategory(c.Context(), category)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidCategory) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to create category",
 		})
 	}
 
+	// Reload to resolve the path and inherited attributes
+	if created, err := h.categorySvc.GetCategory(c.Context(), category.ID); err == nil {
+		category = created
+	}
+
 	return c.Status(fiber.StatusCreated).JSON(h.toResponse(category))
 }
 
 // ListCategories handles GET /categories
 func (h *CategoryHandler) ListCategories(c *fiber.Ctx) error {
-	limit := c.QueryInt("limit", 10)
-	offset := c.QueryInt("offset", 0)
+	page := parsePageRequest(c)
 
-	categories, err := h.categorySvc.ListCategories(c.Context(), limit, offset)
+	categories, err := h.categorySvc.ListCategories(c.Context(), page)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidPage) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to list categories",
 		})
 	}
 
-	responses := make([]CategoryResponse, 0, len(categories))
-	for _, category := range categories {
+	responses := make([]CategoryResponse, 0, len(categories.Categories))
+	for _, category := range categories.Categories {
 		responses = append(responses, h.toResponse(category))
 	}
 
-	return c.JSON(fiber.Map{
+	return c.JSON(addPageInfo(fiber.Map{
 		"categories": responses,
-		"limit":      limit,
-		"offset":     offset,
-	})
+	}, page, categories.PageInfo))
+}
+
+// GetCategory handles GET /categories/:id
+func (h *CategoryHandler) GetCategory(c *fiber.Ctx) error {
+	category, err := h.categorySvc.GetCategory(c.Context(), c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Category not found",
+		})
+	}
+
+	return c.JSON(h.toResponse(category))
+}
+
+// UpdateCategory handles PUT /categories/:id
+func (h *CategoryHandler) UpdateCategory(c *fiber.Ctx) error {
+	var req UpdateCategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	update := ports.CategoryUpdate{
+		Name:            req.Name,
+		ParentID:        req.ParentID,
+		ExpectedVersion: req.ExpectedVersion,
+		Renames:         req.Renames,
+		Defaults:        req.Defaults,
+		ConvertTypes:    req.ConvertTypes,
+		DryRun:          req.DryRun,
+	}
+	if req.AttributeDefinitions != nil {
+		attrs := toAttributeDefinitions(*req.AttributeDefinitions)
+		update.AttributeDefinitions = &attrs
+	}
+
+	evolution, err := h.categorySvc.UpdateCategory(c.Context(), c.Params("id"), update)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidProperty) && evolution != nil:
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"error":     err.Error(),
+				"evolution": toSchemaEvolutionResponse(evolution),
+			})
+		case errors.Is(err, services.ErrInvalidCategory):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, services.ErrCategoryVersionConflict):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, ports.ErrNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Category not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update category",
+		})
+	}
+
+	return c.JSON(toSchemaEvolutionResponse(evolution))
+}
+
+// DeleteCategory handles DELETE /categories/:id
+func (h *CategoryHandler) DeleteCategory(c *fiber.Ctx) error {
+	err := h.categorySvc.DeleteCategory(c.Context(), c.Params("id"))
+	if err != nil {
+		if errors.Is(err, services.ErrCategoryInUse) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if errors.Is(err, ports.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Category not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete category",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListCategoryVersions handles GET /categories/:id/versions
+func (h *CategoryHandler) ListCategoryVersions(c *fiber.Ctx) error {
+	versions, err := h.categorySvc.ListCategoryVersions(c.Context(), c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Category not found",
+		})
+	}
+
+	resp := make([]categoryVersionResponse, 0, len(versions))
+	for _, v := range versions {
+		resp = append(resp, categoryVersionResponse{
+			Version:              v.Version,
+			Name:                 v.Name,
+			AttributeDefinitions: toAttributeDefinitionResponses(v.AttributeDefinitions),
+			CreatedAt:            v.CreatedAt,
+		})
+	}
+
+	return c.JSON(resp)
 }
 
 // toResponse converts a domain category to a response DTO.
 func (h *CategoryHandler) toResponse(category *domain.Category) CategoryResponse {
-	attrs := make([]AttributeDefinitionRequest, 0, len(category.AttributeDefinitions))
-	for _, a := range category.AttributeDefinitions {
-		attrs = append(attrs, AttributeDefinitionRequest{
-			Key:      a.Key,
-			Type:     a.Type,
-			Required: a.Required,
-			Options:  a.Options,
-			Unit:     a.Unit,
-		})
+	path := make([]categoryRefResponse, 0, len(category.Path))
+	for _, ref := range category.Path {
+		path = append(path, categoryRefResponse{ID: ref.ID, Name: ref.Name})
 	}
 
 	return CategoryResponse{
 		ID:                   category.ID,
 		Name:                 category.Name,
-		AttributeDefinitions: attrs,
+		ParentID:             category.ParentID,
+		Path:                 path,
+		AttributeDefinitions: toAttributeDefinitionResponses(category.AttributeDefinitions),
+		EffectiveAttributes:  toAttributeDefinitionResponses(category.EffectiveAttributes),
+		Version:              category.Version,
+	}
+}
+
+// toAttributeDefinitions converts requested attribute definitions to domain form.
+func toAttributeDefinitions(reqs []AttributeDefinitionRequest) []domain.AttributeDefinition {
+	attrs := make([]domain.AttributeDefinition, 0, len(reqs))
+	for _, a := range reqs {
+		attrs = append(attrs, domain.AttributeDefinition{
+			Key:        a.Key,
+			Type:       a.Type,
+			Required:   a.Required,
+			Options:    a.Options,
+			Unit:       a.Unit,
+			Min:        a.Min,
+			Max:        a.Max,
+			Step:       a.Step,
+			Integer:    a.Integer,
+			MinLength:  a.MinLength,
+			MaxLength:  a.MaxLength,
+			Pattern:    a.Pattern,
+			Default:    a.Default,
+			Unique:     a.Unique,
+			Searchable: a.Searchable,
+		})
+	}
+	return attrs
+}
+
+// toAttributeDefinitionResponses converts attribute definitions to their response form.
+func toAttributeDefinitionResponses(attrs []domain.AttributeDefinition) []AttributeDefinitionRequest {
+	resp := make([]AttributeDefinitionRequest, 0, len(attrs))
+	for _, a := range attrs {
+		resp = append(resp, AttributeDefinitionRequest{
+			Key:        a.Key,
+			Type:       a.Type,
+			Required:   a.Required,
+			Options:    a.Options,
+			Unit:       a.Unit,
+			Min:        a.Min,
+			Max:        a.Max,
+			Step:       a.Step,
+			Integer:    a.Integer,
+			MinLength:  a.MinLength,
+			MaxLength:  a.MaxLength,
+			Pattern:    a.Pattern,
+			Default:    a.Default,
+			Unique:     a.Unique,
+			Searchable: a.Searchable,
+		})
+	}
+	return resp
+}
+
+// toSchemaEvolutionResponse converts a schema evolution report to its response form.
+func toSchemaEvolutionResponse(evolution *domain.SchemaEvolution) schemaEvolutionResponse {
+	violations := make([]propertyViolationResponse, 0, len(evolution.Violations))
+	for _, v := range evolution.Violations {
+		violations = append(violations, propertyViolationResponse{
+			ProductID: v.ProductID,
+			SKU:       v.SKU,
+			Error:     v.Error,
+		})
+	}
+
+	return schemaEvolutionResponse{
+		CategoryID:  evolution.CategoryID,
+		FromVersion: evolution.FromVersion,
+		ToVersion:   evolution.ToVersion,
+		Products:    evolution.Products,
+		Backfilled:  evolution.Backfilled,
+		Migrated:    evolution.Migrated,
+		Reindexed:   evolution.Reindexed,
+		Violations:  violations,
+		Applied:     evolution.Applied,
 	}
 }
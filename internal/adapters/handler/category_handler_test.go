@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// stubCategoryService fails every update and delete with err.
+type stubCategoryService struct {
+	ports.CategoryService
+	err error
+}
+
+func (s *stubCategoryService) UpdateCategory(_ context.Context, _ string, _ ports.CategoryUpdate) (*domain.SchemaEvolution, error) {
+	return nil, s.err
+}
+
+func (s *stubCategoryService) DeleteCategory(_ context.Context, _ string) error {
+	return s.err
+}
+
+func TestUpdateAndDeleteCategory_ErrorStatus(t *testing.T) {
+	cases := map[string]struct {
+		err    error
+		status int
+	}{
+		"not found":    {fmt.Errorf("category %w", ports.ErrNotFound), fiber.StatusNotFound},
+		"audit failed": {errors.New("audit log: disk I/O error"), fiber.StatusInternalServerError},
+	}
+
+	for name, tc := range cases {
+		app := fiber.New()
+		h := NewCategoryHandler(&stubCategoryService{err: tc.err})
+		app.Put("/categories/:id", h.UpdateCategory)
+		app.Delete("/categories/:id", h.DeleteCategory)
+
+		put := httptest.NewRequest(http.MethodPut, "/categories/c1", strings.NewReader(`{"name":"Bulbs"}`))
+		put.Header.Set("Content-Type", "application/json")
+		for _, req := range []*http.Request{put, httptest.NewRequest(http.MethodDelete, "/categories/c1", nil)} {
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", name, err)
+			}
+			if resp.StatusCode != tc.status {
+				t.Errorf("%s: expected status %d for %s, got %d", name, tc.status, req.Method, resp.StatusCode)
+			}
+		}
+	}
+}
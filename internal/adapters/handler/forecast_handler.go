@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+	"github.com/torantous1337/retail-management/internal/core/services"
+)
+
+// ForecastHandler handles HTTP requests for demand forecasts.
+type ForecastHandler struct {
+	forecastSvc ports.ForecastService
+}
+
+// NewForecastHandler creates a new forecast handler instance.
+func NewForecastHandler(forecastSvc ports.ForecastService) *ForecastHandler {
+	return &ForecastHandler{
+		forecastSvc: forecastSvc,
+	}
+}
+
+// forecastPointResponse represents one forecast day in API responses.
+type forecastPointResponse struct {
+	Date  string  `json:"date"`
+	Units float64 `json:"units"`
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+// forecastAccuracyResponse represents a model's backtest accuracy in API responses.
+type forecastAccuracyResponse struct {
+	Model       string   `json:"model"`
+	HoldoutDays int      `json:"holdout_days"`
+	MAPE        *float64 `json:"mape"`
+	MAE         float64  `json:"mae"`
+}
+
+// forecastResponse represents a demand forecast in API responses.
+type forecastResponse struct {
+	ProductID   string                     `json:"product_id"`
+	Model       string                     `json:"model"`
+	HistoryDays int                        `json:"history_days"`
+	GeneratedAt time.Time                  `json:"generated_at"`
+	Points      []forecastPointResponse    `json:"points"`
+	Accuracy    []forecastAccuracyResponse `json:"accuracy"`
+}
+
+// ForecastProduct handles GET /api/v1/products/:id/forecast
+func (h *ForecastHandler) ForecastProduct(c *fiber.Ctx) error {
+	forecast, err := h.forecastSvc.ForecastProduct(c.Context(), ports.ForecastQuery{
+		ProductID:   c.Params("id"),
+		Days:        c.QueryInt("days"),
+		HistoryDays: c.QueryInt("history"),
+		Model:       c.Query("model"),
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidForecast) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Product not found",
+		})
+	}
+
+	resp := forecastResponse{
+		ProductID:   forecast.ProductID,
+		Model:       forecast.Model,
+		HistoryDays: forecast.HistoryDays,
+		GeneratedAt: forecast.GeneratedAt,
+		Points:      make([]forecastPointResponse, 0, len(forecast.Points)),
+		Accuracy:    make([]forecastAccuracyResponse, 0, len(forecast.Accuracy)),
+	}
+	for _, p := range forecast.Points {
+		resp.Points = append(resp.Points, forecastPointResponse{
+			Date:  p.Date.Format("2006-01-02"),
+			Units: p.Units,
+			Lower: p.Lower,
+			Upper: p.Upper,
+		})
+	}
+	for _, a := range forecast.Accuracy {
+		resp.Accuracy = append(resp.Accuracy, forecastAccuracyResponse{
+			Model:       a.Model,
+			HoldoutDays: a.HoldoutDays,
+			MAPE:        a.MAPE,
+			MAE:         a.MAE,
+		})
+	}
+
+	return c.JSON(resp)
+}
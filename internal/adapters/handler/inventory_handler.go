@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+	"github.com/torantous1337/retail-management/internal/core/services"
+)
+
+// InventoryHandler handles HTTP requests for product packs and stock receiving.
+type InventoryHandler struct {
+	inventorySvc ports.InventoryService
+}
+
+// NewInventoryHandler creates a new inventory handler instance.
+func NewInventoryHandler(inventorySvc ports.InventoryService) *InventoryHandler {
+	return &InventoryHandler{
+		inventorySvc: inventorySvc,
+	}
+}
+
+// packRequest represents the request body for defining a pack.
+type packRequest struct {
+	Name         string  `json:"name"`
+	Barcode      string  `json:"barcode"`
+	UnitsPerPack float64 `json:"units_per_pack"`
+	Price        float64 `json:"price"` // 0 = units_per_pack × base_price
+}
+
+// packResponse represents the response body for a pack.
+type packResponse struct {
+	ID           string    `json:"id"`
+	ProductID    string    `json:"product_id"`
+	Name         string    `json:"name"`
+	Barcode      string    `json:"barcode,omitempty"`
+	UnitsPerPack float64   `json:"units_per_pack"`
+	Price        float64   `json:"price"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// receiveStockRequest represents the request body for receiving stock.
+type receiveStockRequest struct {
+	PackID   string   `json:"pack_id"`   // optional; quantity then counts packs
+	Quantity float64  `json:"quantity"`  // in the product's unit of measure otherwise
+	UnitCost *float64 `json:"unit_cost"` // optional; per pack when pack_id is set
+	UserID   string   `json:"user_id"`
+}
+
+// CreatePack handles POST /api/v1/products/:id/packs
+func (h *InventoryHandler) CreatePack(c *fiber.Ctx) error {
+	var req packRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	pack := &domain.ProductPack{
+		ProductID:    c.Params("id"),
+		Name:         req.Name,
+		Barcode:      req.Barcode,
+		UnitsPerPack: req.UnitsPerPack,
+		Price:        req.Price,
+	}
+
+	err := h.inventorySvc.CreatePack(c.Context(), pack)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPack) || errors.Is(err, services.ErrInvalidBarcode) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if errors.Is(err, services.ErrBarcodeInUse) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create pack",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(toPackResponse(pack))
+}
+
+// ListPacks handles GET /api/v1/products/:id/packs
+func (h *InventoryHandler) ListPacks(c *fiber.Ctx) error {
+	packs, err := h.inventorySvc.ListPacks(c.Context(), c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list packs",
+		})
+	}
+
+	responses := make([]packResponse, 0, len(packs))
+	for _, pack := range packs {
+		responses = append(responses, toPackResponse(pack))
+	}
+
+	return c.JSON(fiber.Map{
+		"packs": responses,
+	})
+}
+
+// DeletePack handles DELETE /api/v1/products/:id/packs/:packId
+func (h *InventoryHandler) DeletePack(c *fiber.Ctx) error {
+	err := h.inventorySvc.DeletePack(c.Context(), c.Params("id"), c.Params("packId"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Pack not found",
+		})
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// ReceiveStock handles POST /api/v1/products/:id/receive
+func (h *InventoryHandler) ReceiveStock(c *fiber.Ctx) error {
+	var req receiveStockRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	product, err := h.inventorySvc.ReceiveStock(c.Context(), ports.ReceiveStockRequest{
+		ProductID: c.Params("id"),
+		PackID:    req.PackID,
+		Quantity:  req.Quantity,
+		UnitCost:  req.UnitCost,
+		UserID:    req.UserID,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPack) || errors.Is(err, services.ErrInvalidQuantity) || errors.Is(err, services.ErrInvalidUnitCost) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"product_id":      product.ID,
+		"quantity":        product.Quantity,
+		"unit_of_measure": product.UnitOfMeasure,
+		"cost_price":      product.CostPrice,
+	})
+}
+
+// toPackResponse converts a domain pack to a response DTO.
+func toPackResponse(pack *domain.ProductPack) packResponse {
+	return packResponse{
+		ID:           pack.ID,
+		ProductID:    pack.ProductID,
+		Name:         pack.Name,
+		Barcode:      pack.Barcode,
+		UnitsPerPack: pack.UnitsPerPack,
+		Price:        pack.Price,
+		CreatedAt:    pack.CreatedAt,
+	}
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+	"github.com/torantous1337/retail-management/internal/core/services"
+)
+
+// LabelHandler handles HTTP requests for shelf label and barcode sticker printing.
+type LabelHandler struct {
+	labelSvc ports.LabelService
+}
+
+// NewLabelHandler creates a new label handler instance.
+func NewLabelHandler(labelSvc ports.LabelService) *LabelHandler {
+	return &LabelHandler{
+		labelSvc: labelSvc,
+	}
+}
+
+// labelFieldDTO represents one field of a label template.
+type labelFieldDTO struct {
+	Source string  `json:"source"` // name, sku, price, unit_price, barcode, text or property:<key>
+	Text   string  `json:"text,omitempty"`
+	Format string  `json:"format,omitempty"`
+	X      float64 `json:"x"` // mm from the left edge
+	Y      float64 `json:"y"` // mm from the top edge
+	Size   float64 `json:"size"`
+}
+
+// labelTemplateDTO represents a label template.
+type labelTemplateDTO struct {
+	Name          string          `json:"name"`
+	WidthMM       float64         `json:"width_mm"`
+	HeightMM      float64         `json:"height_mm"`
+	Fields        []labelFieldDTO `json:"fields"`
+	NetContentKey string          `json:"net_content_key,omitempty"`
+}
+
+// printLabelsRequest represents the request body for rendering labels.
+type printLabelsRequest struct {
+	ProductIDs     []string          `json:"product_ids"`
+	Filter         productFilterDTO  `json:"filter"`
+	Template       string            `json:"template"`
+	CustomTemplate *labelTemplateDTO `json:"custom_template"`
+	Format         string            `json:"format"` // zpl or pdf
+	Copies         int               `json:"copies"`
+}
+
+// PrintLabels handles POST /api/v1/labels
+func (h *LabelHandler) PrintLabels(c *fiber.Ctx) error {
+	var req printLabelsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	filter, err := toFilterOptions(req.Filter)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid changed_since",
+		})
+	}
+
+	labelReq := ports.LabelRequest{
+		ProductIDs: req.ProductIDs,
+		Filter:     filter,
+		Template:   req.Template,
+		Format:     req.Format,
+		Copies:     req.Copies,
+	}
+	if labelReq.Format == "" {
+		labelReq.Format = "pdf"
+	}
+	if req.CustomTemplate != nil {
+		custom := toDomainTemplate(*req.CustomTemplate)
+		labelReq.Custom = &custom
+	}
+
+	var buf bytes.Buffer
+	contentType, err := h.labelSvc.RenderLabels(c.Context(), labelReq, &buf)
+	if err != nil {
+		if errors.Is(err, services.ErrNoLabelProducts) {
+			return c.Status(fiber.StatusNoContent).Send(nil)
+		}
+		if errors.Is(err, services.ErrInvalidLabelRequest) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, `inline; filename="labels.`+labelReq.Format+`"`)
+	return c.Send(buf.Bytes())
+}
+
+// ListTemplates handles GET /api/v1/labels/templates
+func (h *LabelHandler) ListTemplates(c *fiber.Ctx) error {
+	templates := h.labelSvc.ListTemplates()
+
+	responses := make([]labelTemplateDTO, 0, len(templates))
+	for _, template := range templates {
+		responses = append(responses, toTemplateDTO(template))
+	}
+
+	return c.JSON(fiber.Map{
+		"templates": responses,
+	})
+}
+
+// toDomainTemplate converts a template DTO to a domain label template.
+func toDomainTemplate(dto labelTemplateDTO) domain.LabelTemplate {
+	fields := make([]domain.LabelField, 0, len(dto.Fields))
+	for _, f := range dto.Fields {
+		fields = append(fields, domain.LabelField{
+			Source: f.Source,
+			Text:   f.Text,
+			Format: f.Format,
+			X:      f.X,
+			Y:      f.Y,
+			Size:   f.Size,
+		})
+	}
+
+	return domain.LabelTemplate{
+		Name:          dto.Name,
+		WidthMM:       dto.WidthMM,
+		HeightMM:      dto.HeightMM,
+		Fields:        fields,
+		NetContentKey: dto.NetContentKey,
+	}
+}
+
+// toTemplateDTO converts a domain label template to a response DTO.
+func toTemplateDTO(template domain.LabelTemplate) labelTemplateDTO {
+	fields := make([]labelFieldDTO, 0, len(template.Fields))
+	for _, f := range template.Fields {
+		fields = append(fields, labelFieldDTO{
+			Source: f.Source,
+			Text:   f.Text,
+			Format: f.Format,
+			X:      f.X,
+			Y:      f.Y,
+			Size:   f.Size,
+		})
+	}
+
+	return labelTemplateDTO{
+		Name:          template.Name,
+		WidthMM:       template.WidthMM,
+		HeightMM:      template.HeightMM,
+		Fields:        fields,
+		NetContentKey: template.NetContentKey,
+	}
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+// parsePageRequest reads the limit, offset, sort, order and cursor query
+// parameters of a listing.
+func parsePageRequest(c *fiber.Ctx) domain.PageRequest {
+	return domain.PageRequest{
+		Limit:  c.QueryInt("limit", 10),
+		Offset: c.QueryInt("offset", 0),
+		Sort:   c.Query("sort"),
+		Order:  c.Query("order"),
+		Cursor: c.Query("cursor"),
+	}
+}
+
+// addPageInfo adds a page's total and the cursors of the pages either side
+// to a listing's response body.
+func addPageInfo(body fiber.Map, page domain.PageRequest, info domain.PageInfo) fiber.Map {
+	body["limit"] = page.Limit
+	body["offset"] = page.Offset
+	body["total"] = info.Total
+	if info.NextCursor != "" {
+		body["next_cursor"] = info.NextCursor
+	}
+	if info.PrevCursor != "" {
+		body["prev_cursor"] = info.PrevCursor
+	}
+	return body
+}
+
+// parseTimeParam parses an optional RFC3339 timestamp or YYYY-MM-DD date.
+// A bare date used as the end of a range covers the whole day.
+func parseTimeParam(value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &t, nil
+}
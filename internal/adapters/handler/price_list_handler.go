@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+	"github.com/torantous1337/retail-management/internal/core/services"
+)
+
+// PriceListHandler handles HTTP requests for customer price lists.
+type PriceListHandler struct {
+	priceListSvc ports.PriceListService
+}
+
+// NewPriceListHandler creates a new price list handler instance.
+func NewPriceListHandler(priceListSvc ports.PriceListService) *PriceListHandler {
+	return &PriceListHandler{
+		priceListSvc: priceListSvc,
+	}
+}
+
+// priceListRequest represents the request body for creating a price list.
+type priceListRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// priceListRuleRequest represents the request body for adding a price list rule.
+type priceListRuleRequest struct {
+	ProductID       string   `json:"product_id"`
+	CategoryID      string   `json:"category_id"`
+	MinQuantity     float64  `json:"min_quantity"`
+	Price           *float64 `json:"price"`
+	DiscountPercent *float64 `json:"discount_percent"`
+}
+
+// priceListRuleResponse represents the response body for a price list rule.
+type priceListRuleResponse struct {
+	ID              string    `json:"id"`
+	ProductID       string    `json:"product_id,omitempty"`
+	CategoryID      string    `json:"category_id,omitempty"`
+	MinQuantity     float64   `json:"min_quantity"`
+	Price           *float64  `json:"price,omitempty"`
+	DiscountPercent *float64  `json:"discount_percent,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// priceListResponse represents the response body for a price list.
+type priceListResponse struct {
+	ID          string                  `json:"id"`
+	Name        string                  `json:"name"`
+	Description string                  `json:"description,omitempty"`
+	Rules       []priceListRuleResponse `json:"rules,omitempty"`
+	CreatedAt   time.Time               `json:"created_at"`
+}
+
+// CreatePriceList handles POST /api/v1/price-lists
+func (h *PriceListHandler) CreatePriceList(c *fiber.Ctx) error {
+	var req priceListRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	list := &domain.PriceList{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+
+	err := h.priceListSvc.CreatePriceList(c.Context(), list)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPriceList) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create price list",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(h.toResponse(list))
+}
+
+// ListPriceLists handles GET /api/v1/price-lists
+func (h *PriceListHandler) ListPriceLists(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 10)
+	offset := c.QueryInt("offset", 0)
+
+	lists, err := h.priceListSvc.ListPriceLists(c.Context(), limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list price lists",
+		})
+	}
+
+	responses := make([]priceListResponse, 0, len(lists))
+	for _, list := range lists {
+		responses = append(responses, h.toResponse(list))
+	}
+
+	return c.JSON(fiber.Map{
+		"price_lists": responses,
+		"limit":       limit,
+		"offset":      offset,
+	})
+}
+
+// GetPriceList handles GET /api/v1/price-lists/:id
+func (h *PriceListHandler) GetPriceList(c *fiber.Ctx) error {
+	list, err := h.priceListSvc.GetPriceList(c.Context(), c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Price list not found",
+		})
+	}
+
+	return c.JSON(h.toResponse(list))
+}
+
+// DeletePriceList handles DELETE /api/v1/price-lists/:id
+func (h *PriceListHandler) DeletePriceList(c *fiber.Ctx) error {
+	err := h.priceListSvc.DeletePriceList(c.Context(), c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Price list not found",
+		})
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// AddRule handles POST /api/v1/price-lists/:id/rules
+func (h *PriceListHandler) AddRule(c *fiber.Ctx) error {
+	var req priceListRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	rule := &domain.PriceListRule{
+		PriceListID:     c.Params("id"),
+		ProductID:       req.ProductID,
+		CategoryID:      req.CategoryID,
+		MinQuantity:     req.MinQuantity,
+		Price:           req.Price,
+		DiscountPercent: req.DiscountPercent,
+	}
+
+	err := h.priceListSvc.AddRule(c.Context(), rule)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPriceRule) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Price list not found",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(h.toRuleResponse(rule))
+}
+
+// RemoveRule handles DELETE /api/v1/price-lists/:id/rules/:ruleId
+func (h *PriceListHandler) RemoveRule(c *fiber.Ctx) error {
+	err := h.priceListSvc.RemoveRule(c.Context(), c.Params("id"), c.Params("ruleId"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Price list rule not found",
+		})
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// QuotePrice handles GET /api/v1/price-lists/:id/quote?product_id=&quantity=
+func (h *PriceListHandler) QuotePrice(c *fiber.Ctx) error {
+	productID := c.Query("product_id")
+	quantity := c.QueryFloat("quantity", 1)
+	if productID == "" || quantity <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "product_id and a positive quantity are required",
+		})
+	}
+
+	unitPrice, err := h.priceListSvc.QuotePrice(c.Context(), c.Params("id"), productID, quantity)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPriceList) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Price list not found",
+			})
+		}
+		if errors.Is(err, ports.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Product not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to quote price",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"price_list_id": c.Params("id"),
+		"product_id":    productID,
+		"quantity":      quantity,
+		"unit_price":    unitPrice,
+	})
+}
+
+// toResponse converts a domain price list to a response DTO.
+func (h *PriceListHandler) toResponse(list *domain.PriceList) priceListResponse {
+	var rules []priceListRuleResponse
+	for _, rule := range list.Rules {
+		rules = append(rules, h.toRuleResponse(rule))
+	}
+
+	return priceListResponse{
+		ID:          list.ID,
+		Name:        list.Name,
+		Description: list.Description,
+		Rules:       rules,
+		CreatedAt:   list.CreatedAt,
+	}
+}
+
+// toRuleResponse converts a domain price list rule to a response DTO.
+func (h *PriceListHandler) toRuleResponse(rule *domain.PriceListRule) priceListRuleResponse {
+	return priceListRuleResponse{
+		ID:              rule.ID,
+		ProductID:       rule.ProductID,
+		CategoryID:      rule.CategoryID,
+		MinQuantity:     rule.MinQuantity,
+		Price:           rule.Price,
+		DiscountPercent: rule.DiscountPercent,
+		CreatedAt:       rule.CreatedAt,
+	}
+}
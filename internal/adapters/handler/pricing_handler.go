@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+	"github.com/torantous1337/retail-management/internal/core/services"
+)
+
+// PricingHandler handles HTTP requests for price history and scheduled price changes.
+type PricingHandler struct {
+	pricingSvc ports.PricingService
+}
+
+// NewPricingHandler creates a new pricing handler instance.
+func NewPricingHandler(pricingSvc ports.PricingService) *PricingHandler {
+	return &PricingHandler{
+		pricingSvc: pricingSvc,
+	}
+}
+
+// schedulePriceChangeRequest represents the request body for scheduling a price change.
+type schedulePriceChangeRequest struct {
+	Price         *float64 `json:"price"`
+	EffectiveFrom string   `json:"effective_from"` // RFC3339 or YYYY-MM-DD
+	EffectiveTo   string   `json:"effective_to"`   // Optional end of a temporary change
+	Reason        string   `json:"reason"`
+	UserID        string   `json:"user_id"`
+}
+
+// cancelPriceChangeRequest represents the request body for cancelling a price change.
+type cancelPriceChangeRequest struct {
+	UserID string `json:"user_id"`
+}
+
+// priceHistoryResponse represents the response body for a price history entry.
+type priceHistoryResponse struct {
+	ProductID string    `json:"product_id"`
+	Price     float64   `json:"price"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// priceChangeResponse represents the response body for a scheduled price change.
+type priceChangeResponse struct {
+	ID            string     `json:"id"`
+	ProductID     string     `json:"product_id"`
+	Price         float64    `json:"price"`
+	EffectiveFrom time.Time  `json:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"`
+	Reason        string     `json:"reason"`
+	Status        string     `json:"status"`
+	PreviousPrice *float64   `json:"previous_price,omitempty"`
+	CreatedBy     string     `json:"created_by"`
+	CreatedAt     time.Time  `json:"created_at"`
+	AppliedAt     *time.Time `json:"applied_at,omitempty"`
+	RevertedAt    *time.Time `json:"reverted_at,omitempty"`
+}
+
+// GetPriceHistory handles GET /api/v1/products/:id/price-history
+func (h *PricingHandler) GetPriceHistory(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 10)
+	offset := c.QueryInt("offset", 0)
+
+	entries, err := h.pricingSvc.GetPriceHistory(c.Context(), c.Params("id"), limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Product not found",
+		})
+	}
+
+	responses := make([]priceHistoryResponse, 0, len(entries))
+	for _, entry := range entries {
+		responses = append(responses, h.toHistoryResponse(entry))
+	}
+
+	return c.JSON(fiber.Map{
+		"price_history": responses,
+		"limit":         limit,
+		"offset":        offset,
+	})
+}
+
+// GetPriceAt handles GET /api/v1/products/:id/price?at=
+func (h *PricingHandler) GetPriceAt(c *fiber.Ctx) error {
+	at, err := parseTimeParam(c.Query("at"), true)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid at",
+		})
+	}
+	if at == nil {
+		now := time.Now()
+		at = &now
+	}
+
+	entry, err := h.pricingSvc.GetPriceAt(c.Context(), c.Params("id"), *at)
+	if err != nil {
+		if errors.Is(err, services.ErrNoPriceAt) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Product not found",
+		})
+	}
+
+	return c.JSON(h.toHistoryResponse(entry))
+}
+
+// SchedulePriceChange handles POST /api/v1/products/:id/price-changes
+func (h *PricingHandler) SchedulePriceChange(c *fiber.Ctx) error {
+	var req schedulePriceChangeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if req.Price == nil || req.EffectiveFrom == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "price and effective_from are required",
+		})
+	}
+
+	effectiveFrom, err := parseTimeParam(req.EffectiveFrom, false)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid effective_from",
+		})
+	}
+	effectiveTo, err := parseTimeParam(req.EffectiveTo, true)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid effective_to",
+		})
+	}
+
+	change := &domain.ScheduledPriceChange{
+		ProductID:     c.Params("id"),
+		Price:         *req.Price,
+		EffectiveFrom: *effectiveFrom,
+		EffectiveTo:   effectiveTo,
+		Reason:        req.Reason,
+		CreatedBy:     req.UserID,
+	}
+
+	if err := h.pricingSvc.SchedulePriceChange(c.Context(), change); err != nil {
+		if errors.Is(err, services.ErrPriceChangeOverlap) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(h.toChangeResponse(change))
+}
+
+// ListProductPriceChanges handles GET /api/v1/products/:id/price-changes
+func (h *PricingHandler) ListProductPriceChanges(c *fiber.Ctx) error {
+	return h.listPriceChanges(c, c.Params("id"))
+}
+
+// ListPriceChanges handles GET /api/v1/price-changes
+func (h *PricingHandler) ListPriceChanges(c *fiber.Ctx) error {
+	return h.listPriceChanges(c, c.Query("product_id"))
+}
+
+// GetPriceChange handles GET /api/v1/price-changes/:id
+func (h *PricingHandler) GetPriceChange(c *fiber.Ctx) error {
+	change, err := h.pricingSvc.GetScheduledChange(c.Context(), c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Price change not found",
+		})
+	}
+
+	return c.JSON(h.toChangeResponse(change))
+}
+
+// CancelPriceChange handles POST /api/v1/price-changes/:id/cancel
+func (h *PricingHandler) CancelPriceChange(c *fiber.Ctx) error {
+	var req cancelPriceChangeRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	change, err := h.pricingSvc.CancelScheduledChange(c.Context(), c.Params("id"), req.UserID)
+	if err != nil {
+		if errors.Is(err, services.ErrPriceChangeClosed) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Price change not found",
+		})
+	}
+
+	return c.JSON(h.toChangeResponse(change))
+}
+
+// listPriceChanges lists scheduled price changes, optionally for one product.
+func (h *PricingHandler) listPriceChanges(c *fiber.Ctx, productID string) error {
+	limit := c.QueryInt("limit", 10)
+	offset := c.QueryInt("offset", 0)
+
+	changes, err := h.pricingSvc.ListScheduledChanges(c.Context(), productID, c.Query("status"), limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list price changes",
+		})
+	}
+
+	responses := make([]priceChangeResponse, 0, len(changes))
+	for _, change := range changes {
+		responses = append(responses, h.toChangeResponse(change))
+	}
+
+	return c.JSON(fiber.Map{
+		"price_changes": responses,
+		"limit":         limit,
+		"offset":        offset,
+	})
+}
+
+// toHistoryResponse converts a domain price history entry to a response DTO.
+func (h *PricingHandler) toHistoryResponse(entry *domain.PriceHistoryEntry) priceHistoryResponse {
+	return priceHistoryResponse{
+		ProductID: entry.ProductID,
+		Price:     entry.Price,
+		ChangedAt: entry.ChangedAt,
+	}
+}
+
+// toChangeResponse converts a domain scheduled price change to a response DTO.
+func (h *PricingHandler) toChangeResponse(change *domain.ScheduledPriceChange) priceChangeResponse {
+	return priceChangeResponse{
+		ID:            change.ID,
+		ProductID:     change.ProductID,
+		Price:         change.Price,
+		EffectiveFrom: change.EffectiveFrom,
+		EffectiveTo:   change.EffectiveTo,
+		Reason:        change.Reason,
+		Status:        change.Status,
+		PreviousPrice: change.PreviousPrice,
+		CreatedBy:     change.CreatedBy,
+		CreatedAt:     change.CreatedAt,
+		AppliedAt:     change.AppliedAt,
+		RevertedAt:    change.RevertedAt,
+	}
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"errors"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -26,23 +27,31 @@ func NewProductHandler(productSvc ports.ProductService) *ProductHandler {
 
 // CreateProductRequest represents the request body for creating a product.
 type CreateProductRequest struct {
-	Name       string                 `json:"name"`
-	SKU        string                 `json:"sku"`
-	CategoryID string                 `json:"category_id"`
-	BasePrice  float64                `json:"base_price"`
-	Properties map[string]interface{} `json:"properties"`
+	Name              string                 `json:"name"`
+	SKU               string                 `json:"sku"`
+	CategoryID        string                 `json:"category_id"`
+	BasePrice         float64                `json:"base_price"`
+	UnitOfMeasure     string                 `json:"unit_of_measure"`    // each, kg, m or l
+	QuantityPrecision *int                   `json:"quantity_precision"` // decimal places, defaults per unit
+	Properties        map[string]interface{} `json:"properties"`
+	Description       string                 `json:"description"`
 }
 
 // ProductResponse represents the response body for a product.
 type ProductResponse struct {
-	ID         string                 `json:"id"`
-	Name       string                 `json:"name"`
-	SKU        string                 `json:"sku"`
-	CategoryID string                 `json:"category_id,omitempty"`
-	BasePrice  float64                `json:"base_price"`
-	Properties map[string]interface{} `json:"properties"`
-	CreatedAt  time.Time              `json:"created_at"`
-	UpdatedAt  time.Time              `json:"updated_at"`
+	ID                string                 `json:"id"`
+	Name              string                 `json:"name"`
+	SKU               string                 `json:"sku"`
+	CategoryID        string                 `json:"category_id,omitempty"`
+	BasePrice         float64                `json:"base_price"`
+	Quantity          float64                `json:"quantity"`
+	UnitOfMeasure     string                 `json:"unit_of_measure"`
+	QuantityPrecision int                    `json:"quantity_precision"`
+	Properties        map[string]interface{} `json:"properties"`
+	PropertyOriginals map[string]string      `json:"property_originals,omitempty"`
+	Description       string                 `json:"description,omitempty"`
+	CreatedAt         time.Time              `json:"created_at"`
+	UpdatedAt         time.Time              `json:"updated_at"`
 }
 
 // CreateProduct handles POST /products
@@ -71,21 +80,27 @@ func (h *ProductHandler) CreateProduct(c *fiber.Ctx) error {
 		Properties: req.Properties,
 		CreatedAt:  now,
 		UpdatedAt:  now,
+
+		UnitOfMeasure:     req.UnitOfMeasure,
+		QuantityPrecision: domain.DefaultQuantityPrecision,
+		Description:       req.Description,
+	}
+
+	if req.QuantityPrecision != nil {
+		product.QuantityPrecision = *req.QuantityPrecision
 	}
 
 	err := h.productSvc.CreateProduct(c.Context(), product)
 	if err != nil {
-		if errors.Is(err, services.ErrInvalidProperty) {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": err.Error(),
-			})
+		if errors.Is(err, services.ErrInvalidProperty) || errors.Is(err, services.ErrInvalidUnitOfMeasure) {
+			return c.Status(fiber.StatusBadRequest).JSON(propertyErrorBody(err))
 		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to create product",
 		})
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(h.toResponse(product))
+	return c.Status(fiber.StatusCreated).JSON(toProductResponse(product))
 }
 
 // GetProduct handles GET /products/:id
@@ -104,7 +119,7 @@ func (h *ProductHandler) GetProduct(c *fiber.Ctx) error {
 		})
 	}
 
-	return c.JSON(h.toResponse(product))
+	return c.JSON(toProductResponse(product))
 }
 
 // GetProductBySKU handles GET /products/sku/:sku
@@ -123,40 +138,42 @@ func (h *ProductHandler) GetProductBySKU(c *fiber.Ctx) error {
 		})
 	}
 
-	return c.JSON(h.toResponse(product))
+	return c.JSON(toProductResponse(product))
 }
 
 // ListProducts handles GET /products
 func (h *ProductHandler) ListProducts(c *fiber.Ctx) error {
-	limit := c.QueryInt("limit", 10)
-	offset := c.QueryInt("offset", 0)
+	page := parsePageRequest(c)
 
-	products, err := h.productSvc.ListProducts(c.Context(), limit, offset)
+	products, err := h.productSvc.ListProducts(c.Context(), page)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidPage) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to list products",
 		})
 	}
 
-	responses := make([]ProductResponse, 0, len(products))
-	for _, product := range products {
-		responses = append(responses, h.toResponse(product))
+	responses := make([]ProductResponse, 0, len(products.Products))
+	for _, product := range products.Products {
+		responses = append(responses, toProductResponse(product))
 	}
 
-	return c.JSON(fiber.Map{
+	return c.JSON(addPageInfo(fiber.Map{
 		"products": responses,
-		"limit":    limit,
-		"offset":   offset,
-	})
+	}, page, products.PageInfo))
 }
 
 // SearchProducts handles GET /products/search
 func (h *ProductHandler) SearchProducts(c *fiber.Ctx) error {
 	opts := domain.FilterOptions{
-		Query:      c.Query("q"),
-		CategoryID: c.Query("category_id"),
-		Limit:      c.QueryInt("limit", 10),
-		Offset:     c.QueryInt("offset", 0),
+		Query:       c.Query("q"),
+		Mode:        c.Query("mode"),
+		CategoryID:  c.Query("category_id"),
+		PageRequest: parsePageRequest(c),
 	}
 
 	if minStr := c.Query("min_price"); minStr != "" {
@@ -179,31 +196,113 @@ func (h *ProductHandler) SearchProducts(c *fiber.Ctx) error {
 		opts.MaxPrice = &v
 	}
 
-	// Collect property filters from query params prefixed with "prop."
+	changedSince, err := parseTimeParam(c.Query("changed_since"), false)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid changed_since",
+		})
+	}
+	opts.ChangedSince = changedSince
+
+	// Collect property filters from query params prefixed with "prop.", with
+	// an optional operator, e.g. prop.brand=Acme or prop.voltage[gte]=200V
 	opts.Properties = make(map[string]string)
 	c.Context().QueryArgs().VisitAll(func(key, value []byte) {
 		k := string(key)
-		if len(k) > 5 && k[:5] == "prop." {
-			opts.Properties[k[5:]] = string(value)
+		if len(k) <= 5 || k[:5] != "prop." {
+			return
+		}
+		k = k[5:]
+		if open := strings.IndexByte(k, '['); open > 0 && strings.HasSuffix(k, "]") {
+			opts.Filters = append(opts.Filters, domain.PropertyFilter{
+				Key:   k[:open],
+				Op:    k[open+1 : len(k)-1],
+				Value: string(value),
+			})
+			return
 		}
+		opts.Properties[k] = string(value)
 	})
 
 	products, err := h.productSvc.SearchProducts(c.Context(), opts)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidProperty) || errors.Is(err, services.ErrInvalidPage) || errors.Is(err, services.ErrInvalidSearch) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to search products",
 		})
 	}
 
-	responses := make([]ProductResponse, 0, len(products))
-	for _, product := range products {
-		responses = append(responses, h.toResponse(product))
+	responses := make([]ProductResponse, 0, len(products.Products))
+	for _, product := range products.Products {
+		responses = append(responses, toProductResponse(product))
 	}
 
-	return c.JSON(fiber.Map{
+	body := addPageInfo(fiber.Map{
 		"products": responses,
-		"limit":    opts.Limit,
-		"offset":   opts.Offset,
+	}, opts.PageRequest, products.PageInfo)
+
+	// Facet counts for the same filters, on request
+	if c.QueryBool("facets") {
+		facets, err := h.productSvc.FacetProducts(c.Context(), opts)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to count facets",
+			})
+		}
+		body["facets"] = toSearchFacetsResponse(facets)
+	}
+
+	return c.JSON(body)
+}
+
+// productSuggestionResponse represents a product offered while a search is
+// typed.
+type productSuggestionResponse struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	SKU   string `json:"sku"`
+	Fuzzy bool   `json:"fuzzy,omitempty"`
+}
+
+// SuggestProducts handles GET /products/suggest
+func (h *ProductHandler) SuggestProducts(c *fiber.Ctx) error {
+	suggestions, err := h.productSvc.SuggestProducts(c.Context(), c.Query("q"), c.QueryInt("limit", 10))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to suggest products",
+		})
+	}
+
+	responses := make([]productSuggestionResponse, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		responses = append(responses, productSuggestionResponse{
+			ID:    suggestion.ID,
+			Name:  suggestion.Name,
+			SKU:   suggestion.SKU,
+			Fuzzy: suggestion.Fuzzy,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"suggestions": responses,
+	})
+}
+
+// RebuildSearchIndex handles POST /products/search-index/rebuild
+func (h *ProductHandler) RebuildSearchIndex(c *fiber.Ctx) error {
+	reindexed, err := h.productSvc.RebuildSearchIndex(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to rebuild search index",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"reindexed": reindexed,
 	})
 }
 
@@ -231,30 +330,48 @@ func (h *ProductHandler) UpdateProduct(c *fiber.Ctx) error {
 		})
 	}
 
+	// Stock levels are managed by sales and recalls, not by product edits
 	product := &domain.Product{
 		ID:         id,
 		Name:       req.Name,
 		SKU:        req.SKU,
 		CategoryID: req.CategoryID,
 		BasePrice:  req.BasePrice,
+		Quantity:   existing.Quantity,
+		CostPrice:  existing.CostPrice,
 		Properties: req.Properties,
 		CreatedAt:  existing.CreatedAt,
 		UpdatedAt:  time.Now(),
+
+		QuarantinedQuantity: existing.QuarantinedQuantity,
+		UnitOfMeasure:       req.UnitOfMeasure,
+		QuantityPrecision:   domain.DefaultQuantityPrecision,
+		PropertyOriginals:   existing.PropertyOriginals,
+		Description:         req.Description,
+	}
+
+	// An omitted unit keeps the current one, and an omitted precision keeps
+	// the current one unless the unit changes
+	if req.UnitOfMeasure == "" {
+		product.UnitOfMeasure = existing.UnitOfMeasure
+	}
+	if req.QuantityPrecision != nil {
+		product.QuantityPrecision = *req.QuantityPrecision
+	} else if product.UnitOfMeasure == existing.UnitOfMeasure {
+		product.QuantityPrecision = existing.QuantityPrecision
 	}
 
 	err = h.productSvc.UpdateProduct(c.Context(), product)
 	if err != nil {
-		if errors.Is(err, services.ErrInvalidProperty) {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": err.Error(),
-			})
+		if errors.Is(err, services.ErrInvalidProperty) || errors.Is(err, services.ErrInvalidUnitOfMeasure) {
+			return c.Status(fiber.StatusBadRequest).JSON(propertyErrorBody(err))
 		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to update product",
 		})
 	}
 
-	return c.JSON(h.toResponse(product))
+	return c.JSON(toProductResponse(product))
 }
 
 // DeleteProduct handles DELETE /products/:id
@@ -297,9 +414,7 @@ func (h *ProductHandler) ImportProducts(c *fiber.Ctx) error {
 
 	count, err := h.productSvc.ImportProducts(c.Context(), categoryID, file)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return c.Status(fiber.StatusBadRequest).JSON(propertyErrorBody(err))
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
@@ -307,16 +422,138 @@ func (h *ProductHandler) ImportProducts(c *fiber.Ctx) error {
 	})
 }
 
-// toResponse converts a domain product to a response DTO.
-func (h *ProductHandler) toResponse(product *domain.Product) ProductResponse {
+// propertyErrorResponse represents one failing property in API responses.
+type propertyErrorResponse struct {
+	Key     string `json:"key"`
+	Message string `json:"message"`
+}
+
+// propertyErrorBody builds an error response body, listing every failing
+// property when the error is a property validation error.
+func propertyErrorBody(err error) fiber.Map {
+	body := fiber.Map{
+		"error": err.Error(),
+	}
+
+	var verr *services.ValidationError
+	if errors.As(err, &verr) {
+		errs := make([]propertyErrorResponse, 0, len(verr.Errors))
+		for _, pe := range verr.Errors {
+			errs = append(errs, propertyErrorResponse{Key: pe.Key, Message: pe.Message})
+		}
+		body["errors"] = errs
+	}
+	return body
+}
+
+// productFilterDTO represents a product search sent in a request body, used to
+// select the products a bulk operation applies to.
+type productFilterDTO struct {
+	Query        string            `json:"q"`
+	CategoryID   string            `json:"category_id"`
+	MinPrice     *float64          `json:"min_price"`
+	MaxPrice     *float64          `json:"max_price"`
+	Properties   map[string]string `json:"properties"`
+	ChangedSince string            `json:"changed_since"` // RFC3339 or YYYY-MM-DD
+	Limit        int               `json:"limit"`
+}
+
+// toFilterOptions converts a product filter DTO to domain filter options.
+func toFilterOptions(dto productFilterDTO) (domain.FilterOptions, error) {
+	changedSince, err := parseTimeParam(dto.ChangedSince, false)
+	if err != nil {
+		return domain.FilterOptions{}, err
+	}
+
+	return domain.FilterOptions{
+		Query:        dto.Query,
+		CategoryID:   dto.CategoryID,
+		MinPrice:     dto.MinPrice,
+		MaxPrice:     dto.MaxPrice,
+		Properties:   dto.Properties,
+		PageRequest:  domain.PageRequest{Limit: dto.Limit},
+		ChangedSince: changedSince,
+	}, nil
+}
+
+// toProductResponse converts a domain product to a response DTO.
+func toProductResponse(product *domain.Product) ProductResponse {
 	return ProductResponse{
-		ID:         product.ID,
-		Name:       product.Name,
-		SKU:        product.SKU,
-		CategoryID: product.CategoryID,
-		BasePrice:  product.BasePrice,
-		Properties: product.Properties,
-		CreatedAt:  product.CreatedAt,
-		UpdatedAt:  product.UpdatedAt,
+		ID:                product.ID,
+		Name:              product.Name,
+		SKU:               product.SKU,
+		CategoryID:        product.CategoryID,
+		BasePrice:         product.BasePrice,
+		Quantity:          product.Quantity,
+		UnitOfMeasure:     product.UnitOfMeasure,
+		QuantityPrecision: product.QuantityPrecision,
+		Properties:        product.Properties,
+		PropertyOriginals: product.PropertyOriginals,
+		Description:       product.Description,
+		CreatedAt:         product.CreatedAt,
+		UpdatedAt:         product.UpdatedAt,
+	}
+}
+
+// FacetValueResponse is the response DTO for one value of a facet.
+type FacetValueResponse struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int    `json:"count"`
+}
+
+// AttributeFacetResponse is the response DTO for an attribute's facet.
+type AttributeFacetResponse struct {
+	Key    string               `json:"key"`
+	Type   string               `json:"type"`
+	Values []FacetValueResponse `json:"values"`
+}
+
+// PriceBucketResponse is the response DTO for a price bucket.
+type PriceBucketResponse struct {
+	Min   *float64 `json:"min,omitempty"`
+	Max   *float64 `json:"max,omitempty"`
+	Count int      `json:"count"`
+}
+
+// SearchFacetsResponse is the response DTO for search facets.
+type SearchFacetsResponse struct {
+	Total      int                      `json:"total"`
+	Categories []FacetValueResponse     `json:"categories"`
+	Attributes []AttributeFacetResponse `json:"attributes"`
+	Prices     []PriceBucketResponse    `json:"prices"`
+	InStock    int                      `json:"in_stock"`
+	OutOfStock int                      `json:"out_of_stock"`
+}
+
+// toSearchFacetsResponse converts domain search facets to a response DTO.
+func toSearchFacetsResponse(facets *domain.SearchFacets) SearchFacetsResponse {
+	resp := SearchFacetsResponse{
+		Total:      facets.Total,
+		Categories: toFacetValueResponses(facets.Categories),
+		Attributes: make([]AttributeFacetResponse, 0, len(facets.Attributes)),
+		Prices:     make([]PriceBucketResponse, 0, len(facets.Prices)),
+		InStock:    facets.InStock,
+		OutOfStock: facets.OutOfStock,
+	}
+	for _, facet := range facets.Attributes {
+		resp.Attributes = append(resp.Attributes, AttributeFacetResponse{
+			Key:    facet.Key,
+			Type:   facet.Type,
+			Values: toFacetValueResponses(facet.Values),
+		})
+	}
+	for _, bucket := range facets.Prices {
+		resp.Prices = append(resp.Prices, PriceBucketResponse{Min: bucket.Min, Max: bucket.Max, Count: bucket.Count})
+	}
+	return resp
+}
+
+// toFacetValueResponses converts facet values to response DTOs.
+func toFacetValueResponses(values []domain.FacetValue) []FacetValueResponse {
+	resp := make([]FacetValueResponse, 0, len(values))
+	for _, v := range values {
+		resp = append(resp, FacetValueResponse{Value: v.Value, Label: v.Label, Count: v.Count})
 	}
+	return resp
 }
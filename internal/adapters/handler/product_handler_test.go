@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// stubProductService returns a fixed product and records the last update.
+type stubProductService struct {
+	ports.ProductService
+	product *domain.Product
+	updated *domain.Product
+}
+
+func (s *stubProductService) GetProduct(_ context.Context, _ string) (*domain.Product, error) {
+	return s.product, nil
+}
+
+func (s *stubProductService) UpdateProduct(_ context.Context, product *domain.Product) error {
+	s.updated = product
+	return nil
+}
+
+func TestUpdateProduct_OmittedUnitKeepsCurrent(t *testing.T) {
+	svc := &stubProductService{product: &domain.Product{
+		ID: "p1", Name: "Flour", SKU: "FLOUR", Quantity: 2.5,
+		UnitOfMeasure: domain.UnitKilogram, QuantityPrecision: 3,
+	}}
+	app := fiber.New()
+	app.Put("/products/:id", NewProductHandler(svc).UpdateProduct)
+
+	req := httptest.NewRequest(http.MethodPut, "/products/p1", strings.NewReader(`{"name":"Plain Flour","sku":"FLOUR","base_price":1.2}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	if svc.updated == nil {
+		t.Fatal("expected the product to be updated")
+	}
+	if svc.updated.UnitOfMeasure != domain.UnitKilogram || svc.updated.QuantityPrecision != 3 || svc.updated.Quantity != 2.5 {
+		t.Fatalf("expected 2.5 kg at precision 3, got %v %s at precision %d",
+			svc.updated.Quantity, svc.updated.UnitOfMeasure, svc.updated.QuantityPrecision)
+	}
+}
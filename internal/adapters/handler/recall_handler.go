@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+	"github.com/torantous1337/retail-management/internal/core/services"
+)
+
+// RecallHandler handles HTTP requests for product recalls.
+type RecallHandler struct {
+	recallSvc ports.RecallService
+}
+
+// NewRecallHandler creates a new recall handler instance.
+func NewRecallHandler(recallSvc ports.RecallService) *RecallHandler {
+	return &RecallHandler{
+		recallSvc: recallSvc,
+	}
+}
+
+// createRecallRequest represents the request body for declaring a recall.
+type createRecallRequest struct {
+	ProductID string `json:"product_id"`
+	Reason    string `json:"reason"`
+	SoldFrom  string `json:"sold_from"` // RFC3339 or YYYY-MM-DD
+	SoldTo    string `json:"sold_to"`   // RFC3339 or YYYY-MM-DD
+}
+
+// closeRecallRequest represents the request body for closing a recall.
+type closeRecallRequest struct {
+	ReleaseStock bool `json:"release_stock"`
+}
+
+// recallResponse represents the response body for a recall.
+type recallResponse struct {
+	ID                  string     `json:"id"`
+	ProductID           string     `json:"product_id"`
+	Reason              string     `json:"reason"`
+	SoldFrom            *time.Time `json:"sold_from,omitempty"`
+	SoldTo              *time.Time `json:"sold_to,omitempty"`
+	Status              string     `json:"status"`
+	QuarantinedQuantity float64    `json:"quarantined_quantity"`
+	CreatedAt           time.Time  `json:"created_at"`
+	ClosedAt            *time.Time `json:"closed_at,omitempty"`
+}
+
+// recallSaleResponse represents a single affected sale line in a recall report.
+type recallSaleResponse struct {
+	SaleID    string    `json:"sale_id"`
+	SoldAt    time.Time `json:"sold_at"`
+	Quantity  float64   `json:"quantity"`
+	UnitPrice float64   `json:"unit_price"`
+}
+
+// recallReportResponse represents the response body for a recall report.
+type recallReportResponse struct {
+	Recall        recallResponse       `json:"recall"`
+	Sales         []recallSaleResponse `json:"sales"`
+	TotalQuantity float64              `json:"total_quantity"`
+	TotalValue    float64              `json:"total_value"`
+}
+
+// CreateRecall handles POST /api/v1/recalls
+func (h *RecallHandler) CreateRecall(c *fiber.Ctx) error {
+	var req createRecallRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if req.ProductID == "" || req.Reason == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "product_id and reason are required",
+		})
+	}
+
+	soldFrom, err := parseTimeParam(req.SoldFrom, false)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid sold_from",
+		})
+	}
+	soldTo, err := parseTimeParam(req.SoldTo, true)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid sold_to",
+		})
+	}
+
+	recall := &domain.Recall{
+		ProductID: req.ProductID,
+		Reason:    req.Reason,
+		SoldFrom:  soldFrom,
+		SoldTo:    soldTo,
+	}
+
+	report, err := h.recallSvc.DeclareRecall(c.Context(), recall)
+	if err != nil {
+		if errors.Is(err, services.ErrRecallAlreadyActive) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(h.toReportResponse(report))
+}
+
+// ListRecalls handles GET /api/v1/recalls
+func (h *RecallHandler) ListRecalls(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 10)
+	offset := c.QueryInt("offset", 0)
+
+	recalls, err := h.recallSvc.ListRecalls(c.Context(), limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list recalls",
+		})
+	}
+
+	responses := make([]recallResponse, 0, len(recalls))
+	for _, recall := range recalls {
+		responses = append(responses, h.toResponse(recall))
+	}
+
+	return c.JSON(fiber.Map{
+		"recalls": responses,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// GetRecall handles GET /api/v1/recalls/:id
+func (h *RecallHandler) GetRecall(c *fiber.Ctx) error {
+	recall, err := h.recallSvc.GetRecall(c.Context(), c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Recall not found",
+		})
+	}
+
+	return c.JSON(h.toResponse(recall))
+}
+
+// GetRecallReport handles GET /api/v1/recalls/:id/report
+func (h *RecallHandler) GetRecallReport(c *fiber.Ctx) error {
+	report, err := h.recallSvc.GetRecallReport(c.Context(), c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Recall not found",
+		})
+	}
+
+	return c.JSON(h.toReportResponse(report))
+}
+
+// CloseRecall handles POST /api/v1/recalls/:id/close
+func (h *RecallHandler) CloseRecall(c *fiber.Ctx) error {
+	var req closeRecallRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	recall, err := h.recallSvc.CloseRecall(c.Context(), c.Params("id"), req.ReleaseStock)
+	if err != nil {
+		if errors.Is(err, services.ErrRecallClosed) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to close recall",
+		})
+	}
+
+	return c.JSON(h.toResponse(recall))
+}
+
+// toResponse converts a domain recall to a response DTO.
+func (h *RecallHandler) toResponse(recall *domain.Recall) recallResponse {
+	return recallResponse{
+		ID:                  recall.ID,
+		ProductID:           recall.ProductID,
+		Reason:              recall.Reason,
+		SoldFrom:            recall.SoldFrom,
+		SoldTo:              recall.SoldTo,
+		Status:              recall.Status,
+		QuarantinedQuantity: recall.QuarantinedQuantity,
+		CreatedAt:           recall.CreatedAt,
+		ClosedAt:            recall.ClosedAt,
+	}
+}
+
+// toReportResponse converts a domain recall report to a response DTO.
+func (h *RecallHandler) toReportResponse(report *domain.RecallReport) recallReportResponse {
+	sales := make([]recallSaleResponse, 0, len(report.Sales))
+	for _, sale := range report.Sales {
+		sales = append(sales, recallSaleResponse{
+			SaleID:    sale.SaleID,
+			SoldAt:    sale.SoldAt,
+			Quantity:  sale.Quantity,
+			UnitPrice: sale.UnitPrice,
+		})
+	}
+
+	return recallReportResponse{
+		Recall:        h.toResponse(report.Recall),
+		Sales:         sales,
+		TotalQuantity: report.TotalQuantity,
+		TotalValue:    report.TotalValue,
+	}
+}
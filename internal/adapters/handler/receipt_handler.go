@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+	"github.com/torantous1337/retail-management/internal/core/services"
+)
+
+// ReceiptHandler handles HTTP requests for sale receipts.
+type ReceiptHandler struct {
+	receiptSvc ports.ReceiptService
+}
+
+// NewReceiptHandler creates a new receipt handler instance.
+func NewReceiptHandler(receiptSvc ports.ReceiptService) *ReceiptHandler {
+	return &ReceiptHandler{
+		receiptSvc: receiptSvc,
+	}
+}
+
+// DownloadReceipt handles GET /api/v1/sales/:id/receipt
+func (h *ReceiptHandler) DownloadReceipt(c *fiber.Ctx) error {
+	saleID := c.Params("id")
+
+	var buf bytes.Buffer
+	if err := h.receiptSvc.RenderReceipt(c.Context(), saleID, &buf); err != nil {
+		if errors.Is(err, ports.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Sale not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEOctetStream)
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="receipt-`+saleID+`.bin"`)
+	return c.Send(buf.Bytes())
+}
+
+// PrintReceipt handles POST /api/v1/sales/:id/print
+func (h *ReceiptHandler) PrintReceipt(c *fiber.Ctx) error {
+	if err := h.receiptSvc.PrintReceipt(c.Context(), c.Params("id")); err != nil {
+		if errors.Is(err, ports.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Sale not found",
+			})
+		}
+		if errors.Is(err, services.ErrNoPrinter) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
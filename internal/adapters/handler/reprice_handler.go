@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+	"github.com/torantous1337/retail-management/internal/core/services"
+)
+
+// RepriceHandler handles HTTP requests for bulk repricing.
+type RepriceHandler struct {
+	repriceSvc ports.RepriceService
+}
+
+// NewRepriceHandler creates a new reprice handler instance.
+func NewRepriceHandler(repriceSvc ports.RepriceService) *RepriceHandler {
+	return &RepriceHandler{
+		repriceSvc: repriceSvc,
+	}
+}
+
+// repriceRuleDTO represents a bulk repricing rule.
+type repriceRuleDTO struct {
+	Type     string  `json:"type"` // percentage, fixed_amount or target_margin
+	Value    float64 `json:"value"`
+	Rounding string  `json:"rounding"` // empty for cents, ".99" to end prices in .99
+}
+
+// repriceRequest represents the request body for a bulk repricing.
+type repriceRequest struct {
+	Filter productFilterDTO `json:"filter"`
+	Rule   repriceRuleDTO   `json:"rule"`
+	DryRun bool             `json:"dry_run"`
+	UserID string           `json:"user_id"`
+}
+
+// repriceChangeResponse represents one product in a bulk repricing response.
+type repriceChangeResponse struct {
+	ProductID  string  `json:"product_id"`
+	SKU        string  `json:"sku"`
+	Name       string  `json:"name"`
+	OldPrice   float64 `json:"old_price"`
+	NewPrice   float64 `json:"new_price"`
+	CostPrice  float64 `json:"cost_price"`
+	BelowCost  bool    `json:"below_cost,omitempty"`
+	SkipReason string  `json:"skip_reason,omitempty"`
+}
+
+// repriceResponse represents the response body for a bulk repricing.
+type repriceResponse struct {
+	BatchID string                  `json:"batch_id,omitempty"`
+	DryRun  bool                    `json:"dry_run"`
+	Updated int                     `json:"updated"`
+	Skipped int                     `json:"skipped"`
+	Changes []repriceChangeResponse `json:"changes"`
+}
+
+// RepriceProducts handles POST /api/v1/products/reprice
+func (h *RepriceHandler) RepriceProducts(c *fiber.Ctx) error {
+	var req repriceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	filter, err := toFilterOptions(req.Filter)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid changed_since",
+		})
+	}
+
+	result, err := h.repriceSvc.RepriceProducts(c.Context(), ports.RepriceRequest{
+		Filter: filter,
+		Rule: domain.RepriceRule{
+			Type:     req.Rule.Type,
+			Value:    req.Rule.Value,
+			Rounding: req.Rule.Rounding,
+		},
+		DryRun: req.DryRun,
+		UserID: req.UserID,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidReprice) || errors.Is(err, services.ErrInvalidProperty) ||
+			errors.Is(err, services.ErrInvalidPage) || errors.Is(err, services.ErrInvalidSearch) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	changes := make([]repriceChangeResponse, 0, len(result.Changes))
+	for _, change := range result.Changes {
+		changes = append(changes, repriceChangeResponse{
+			ProductID:  change.ProductID,
+			SKU:        change.SKU,
+			Name:       change.Name,
+			OldPrice:   change.OldPrice,
+			NewPrice:   change.NewPrice,
+			CostPrice:  change.CostPrice,
+			BelowCost:  change.BelowCost,
+			SkipReason: change.SkipReason,
+		})
+	}
+
+	return c.JSON(repriceResponse{
+		BatchID: result.BatchID,
+		DryRun:  result.DryRun,
+		Updated: result.Updated,
+		Skipped: result.Skipped,
+		Changes: changes,
+	})
+}
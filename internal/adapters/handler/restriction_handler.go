@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+	"github.com/torantous1337/retail-management/internal/core/services"
+)
+
+// RestrictionHandler handles HTTP requests for checkout restriction rules.
+type RestrictionHandler struct {
+	restrictionSvc ports.RestrictionService
+}
+
+// NewRestrictionHandler creates a new restriction handler instance.
+func NewRestrictionHandler(restrictionSvc ports.RestrictionService) *RestrictionHandler {
+	return &RestrictionHandler{
+		restrictionSvc: restrictionSvc,
+	}
+}
+
+// restrictionRequest represents the request body for creating a restriction rule.
+type restrictionRequest struct {
+	CategoryID                string  `json:"category_id"`
+	ProductID                 string  `json:"product_id"`
+	MinimumAge                int     `json:"minimum_age"`
+	SaleWindowStart           string  `json:"sale_window_start"`
+	SaleWindowEnd             string  `json:"sale_window_end"`
+	MaxQuantityPerTransaction float64 `json:"max_quantity_per_transaction"`
+}
+
+// restrictionResponse represents the response body for a restriction rule.
+type restrictionResponse struct {
+	ID                        string    `json:"id"`
+	CategoryID                string    `json:"category_id,omitempty"`
+	ProductID                 string    `json:"product_id,omitempty"`
+	MinimumAge                int       `json:"minimum_age"`
+	SaleWindowStart           string    `json:"sale_window_start,omitempty"`
+	SaleWindowEnd             string    `json:"sale_window_end,omitempty"`
+	MaxQuantityPerTransaction float64   `json:"max_quantity_per_transaction"`
+	CreatedAt                 time.Time `json:"created_at"`
+}
+
+// CreateRestriction handles POST /api/v1/restrictions
+func (h *RestrictionHandler) CreateRestriction(c *fiber.Ctx) error {
+	var req restrictionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	restriction := &domain.SaleRestriction{
+		CategoryID:                req.CategoryID,
+		ProductID:                 req.ProductID,
+		MinimumAge:                req.MinimumAge,
+		SaleWindowStart:           req.SaleWindowStart,
+		SaleWindowEnd:             req.SaleWindowEnd,
+		MaxQuantityPerTransaction: req.MaxQuantityPerTransaction,
+	}
+
+	err := h.restrictionSvc.CreateRestriction(c.Context(), restriction)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidRestriction) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create restriction",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(h.toResponse(restriction))
+}
+
+// ListRestrictions handles GET /api/v1/restrictions
+func (h *RestrictionHandler) ListRestrictions(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 10)
+	offset := c.QueryInt("offset", 0)
+
+	restrictions, err := h.restrictionSvc.ListRestrictions(c.Context(), limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list restrictions",
+		})
+	}
+
+	responses := make([]restrictionResponse, 0, len(restrictions))
+	for _, restriction := range restrictions {
+		responses = append(responses, h.toResponse(restriction))
+	}
+
+	return c.JSON(fiber.Map{
+		"restrictions": responses,
+		"limit":        limit,
+		"offset":       offset,
+	})
+}
+
+// GetRestriction handles GET /api/v1/restrictions/:id
+func (h *RestrictionHandler) GetRestriction(c *fiber.Ctx) error {
+	restriction, err := h.restrictionSvc.GetRestriction(c.Context(), c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Restriction not found",
+		})
+	}
+
+	return c.JSON(h.toResponse(restriction))
+}
+
+// DeleteRestriction handles DELETE /api/v1/restrictions/:id
+func (h *RestrictionHandler) DeleteRestriction(c *fiber.Ctx) error {
+	err := h.restrictionSvc.DeleteRestriction(c.Context(), c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete restriction",
+		})
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// toResponse converts a domain restriction rule to a response DTO.
+func (h *RestrictionHandler) toResponse(restriction *domain.SaleRestriction) restrictionResponse {
+	return restrictionResponse{
+		ID:                        restriction.ID,
+		CategoryID:                restriction.CategoryID,
+		ProductID:                 restriction.ProductID,
+		MinimumAge:                restriction.MinimumAge,
+		SaleWindowStart:           restriction.SaleWindowStart,
+		SaleWindowEnd:             restriction.SaleWindowEnd,
+		MaxQuantityPerTransaction: restriction.MaxQuantityPerTransaction,
+		CreatedAt:                 restriction.CreatedAt,
+	}
+}
@@ -23,19 +23,27 @@ func NewSaleHandler(saleSvc ports.SaleService) *SaleHandler {
 
 // processSaleRequest represents the request body for processing a sale.
 type processSaleRequest struct {
-	Items []processSaleItemRequest `json:"items"`
+	CashierID         string                   `json:"cashier_id"`
+	IDVerified        bool                     `json:"id_verified"`
+	CustomerBirthDate string                   `json:"customer_birth_date"` // YYYY-MM-DD, optional
+	PriceListID       string                   `json:"price_list_id"`       // optional customer price list
+	Items             []processSaleItemRequest `json:"items"`
 }
 
 // processSaleItemRequest represents a single item in a sale request.
 type processSaleItemRequest struct {
-	ProductID string `json:"product_id"`
-	Quantity  int    `json:"quantity"`
+	ProductID string  `json:"product_id"`
+	PackID    string  `json:"pack_id"` // quantity then counts whole packs
+	Barcode   string  `json:"barcode"`
+	Quantity  float64 `json:"quantity"`
 }
 
 // saleResponse represents the response body for a sale.
 type saleResponse struct {
-	ID          string  `json:"id"`
-	TotalAmount float64 `json:"total_amount"`
+	ID          string    `json:"id"`
+	TotalAmount float64   `json:"total_amount"`
+	CashierID   string    `json:"cashier_id,omitempty"`
+	PriceListID string    `json:"price_list_id,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
 }
 
@@ -57,29 +65,58 @@ func (h *SaleHandler) ProcessSale(c *fiber.Ctx) error {
 	// Convert to service request
 	saleItems := make([]ports.SaleItemRequest, len(req.Items))
 	for i, item := range req.Items {
-		if item.ProductID == "" {
+		if item.ProductID == "" && item.PackID == "" && item.Barcode == "" {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "product_id is required for each item",
+				"error": "product_id, pack_id or barcode is required for each item",
 			})
 		}
-		if item.Quantity <= 0 {
+		// Scanned barcodes default to one unit or carry their own quantity
+		if item.Quantity < 0 || ((item.ProductID != "" || item.PackID != "") && item.Quantity == 0) {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 				"error": "quantity must be positive for each item",
 			})
 		}
 		saleItems[i] = ports.SaleItemRequest{
 			ProductID: item.ProductID,
+			PackID:    item.PackID,
+			Barcode:   item.Barcode,
 			Quantity:  item.Quantity,
 		}
 	}
 
-	sale, err := h.saleSvc.ProcessSale(c.Context(), saleItems)
+	saleReq := ports.SaleRequest{
+		CashierID:   req.CashierID,
+		IDVerified:  req.IDVerified,
+		PriceListID: req.PriceListID,
+		Items:       saleItems,
+	}
+	if req.CustomerBirthDate != "" {
+		birthDate, err := time.Parse("2006-01-02", req.CustomerBirthDate)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "customer_birth_date must be YYYY-MM-DD",
+			})
+		}
+		saleReq.CustomerBirthDate = &birthDate
+	}
+
+	sale, err := h.saleSvc.ProcessSale(c.Context(), saleReq)
 	if err != nil {
-		if errors.Is(err, services.ErrInsufficientStock) {
+		var restrictionErr *services.RestrictionError
+		if errors.As(err, &restrictionErr) {
+			return h.restrictionResponse(c, restrictionErr)
+		}
+		if errors.Is(err, services.ErrInsufficientStock) || errors.Is(err, services.ErrInvalidQuantity) || errors.Is(err, services.ErrInvalidBarcode) ||
+			errors.Is(err, services.ErrInvalidPack) || errors.Is(err, services.ErrInvalidPriceList) {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 				"error": err.Error(),
 			})
 		}
+		if errors.Is(err, services.ErrProductRecalled) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -88,6 +125,35 @@ func (h *SaleHandler) ProcessSale(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusCreated).JSON(saleResponse{
 		ID:          sale.ID,
 		TotalAmount: sale.TotalAmount,
+		CashierID:   sale.CashierID,
+		PriceListID: sale.PriceListID,
 		CreatedAt:   sale.CreatedAt,
 	})
 }
+
+// restrictionResponse maps a restriction violation to a response the till can
+// act on: 428 prompts the cashier for an ID check, 422 refuses the sale.
+func (h *SaleHandler) restrictionResponse(c *fiber.Ctx, err *services.RestrictionError) error {
+	status := fiber.StatusUnprocessableEntity
+	if err.Reason == services.RestrictionIDRequired {
+		status = fiber.StatusPreconditionRequired
+	}
+
+	body := fiber.Map{
+		"error":      err.Error(),
+		"code":       err.Reason,
+		"product_id": err.ProductID,
+	}
+	if err.MinimumAge > 0 {
+		body["minimum_age"] = err.MinimumAge
+	}
+	if err.MaxQuantity > 0 {
+		body["max_quantity"] = err.MaxQuantity
+	}
+	if err.SaleWindowStart != "" {
+		body["sale_window_start"] = err.SaleWindowStart
+		body["sale_window_end"] = err.SaleWindowEnd
+	}
+
+	return c.Status(status).JSON(body)
+}
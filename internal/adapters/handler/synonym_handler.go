@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+	"github.com/torantous1337/retail-management/internal/core/services"
+)
+
+// SynonymHandler handles HTTP requests for search synonyms.
+type SynonymHandler struct {
+	synonymSvc ports.SynonymService
+}
+
+// NewSynonymHandler creates a new synonym handler instance.
+func NewSynonymHandler(synonymSvc ports.SynonymService) *SynonymHandler {
+	return &SynonymHandler{
+		synonymSvc: synonymSvc,
+	}
+}
+
+// synonymRequest represents the request body for adding a search synonym.
+type synonymRequest struct {
+	Term    string `json:"term"`
+	Synonym string `json:"synonym"`
+}
+
+// synonymResponse represents the response body for a search synonym.
+type synonymResponse struct {
+	ID        string    `json:"id"`
+	Term      string    `json:"term"`
+	Synonym   string    `json:"synonym"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateSynonym handles POST /api/v1/search-synonyms
+func (h *SynonymHandler) CreateSynonym(c *fiber.Ctx) error {
+	var req synonymRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	synonym := &domain.SearchSynonym{Term: req.Term, Synonym: req.Synonym}
+	err := h.synonymSvc.AddSynonym(c.Context(), synonym)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidSynonym) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to add synonym",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(h.toResponse(synonym))
+}
+
+// ListSynonyms handles GET /api/v1/search-synonyms
+func (h *SynonymHandler) ListSynonyms(c *fiber.Ctx) error {
+	synonyms, err := h.synonymSvc.ListSynonyms(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list synonyms",
+		})
+	}
+
+	responses := make([]synonymResponse, 0, len(synonyms))
+	for _, synonym := range synonyms {
+		responses = append(responses, h.toResponse(synonym))
+	}
+
+	return c.JSON(fiber.Map{
+		"synonyms": responses,
+	})
+}
+
+// DeleteSynonym handles DELETE /api/v1/search-synonyms/:id
+func (h *SynonymHandler) DeleteSynonym(c *fiber.Ctx) error {
+	err := h.synonymSvc.RemoveSynonym(c.Context(), c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete synonym",
+		})
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// toResponse converts a domain search synonym to a response DTO.
+func (h *SynonymHandler) toResponse(synonym *domain.SearchSynonym) synonymResponse {
+	return synonymResponse{
+		ID:        synonym.ID,
+		Term:      synonym.Term,
+		Synonym:   synonym.Synonym,
+		CreatedAt: synonym.CreatedAt,
+	}
+}
@@ -0,0 +1,139 @@
+package label
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/services"
+)
+
+// Symbologies used for printed barcodes.
+const (
+	symbologyEAN13   = "ean13"
+	symbologyCode128 = "code128"
+)
+
+// eanDigitCodes holds the L-code modules of each digit. G-codes are the
+// reversed R-codes, and R-codes are the complement of L-codes.
+var eanDigitCodes = [10]string{
+	"0001101", "0011001", "0010011", "0111101", "0100011",
+	"0110001", "0101111", "0111011", "0110111", "0001011",
+}
+
+// eanParity gives the L/G pattern of the left half for each leading digit.
+var eanParity = [10]string{
+	"LLLLLL", "LLGLGG", "LLGGLG", "LLGGGL", "LGLLGG",
+	"LGGLLG", "LGGGLL", "LGLGLG", "LGLGGL", "LGGLGL",
+}
+
+// code128Patterns holds the bar/space widths of Code 128 symbols 0-106.
+var code128Patterns = [107]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+const (
+	code128StartB = 104
+	code128Stop   = 106
+)
+
+// barcodeSymbology picks EAN-13 for EAN-13 and UPC-A codes and Code 128 for
+// anything else, such as SKUs. UPC-A codes are returned in 13-digit form.
+func barcodeSymbology(code string) (string, string) {
+	format, err := services.ValidateGTIN(code)
+	if err != nil {
+		return symbologyCode128, code
+	}
+	switch format {
+	case domain.BarcodeEAN13:
+		return symbologyEAN13, code
+	case domain.BarcodeUPCA:
+		return symbologyEAN13, "0" + code
+	default:
+		return symbologyCode128, code
+	}
+}
+
+// encodeEAN13 returns the modules of a 13-digit EAN as '1' (bar) and '0' (space).
+func encodeEAN13(code string) string {
+	var b strings.Builder
+	b.WriteString("101")
+	parity := eanParity[code[0]-'0']
+	for i := 1; i <= 6; i++ {
+		digit := code[i] - '0'
+		if parity[i-1] == 'G' {
+			b.WriteString(reverse(complement(eanDigitCodes[digit])))
+		} else {
+			b.WriteString(eanDigitCodes[digit])
+		}
+	}
+	b.WriteString("01010")
+	for i := 7; i <= 12; i++ {
+		b.WriteString(complement(eanDigitCodes[code[i]-'0']))
+	}
+	b.WriteString("101")
+	return b.String()
+}
+
+// encodeCode128 returns the modules of text encoded in Code 128 set B.
+func encodeCode128(text string) (string, error) {
+	if text == "" {
+		return "", fmt.Errorf("code 128: empty text")
+	}
+
+	symbols := []int{code128StartB}
+	checksum := code128StartB
+	for i, r := range text {
+		if r < 32 || r > 127 {
+			return "", fmt.Errorf("code 128: character %q cannot be encoded", r)
+		}
+		value := int(r) - 32
+		symbols = append(symbols, value)
+		checksum += value * (i + 1)
+	}
+	symbols = append(symbols, checksum%103, code128Stop)
+
+	var b strings.Builder
+	for _, symbol := range symbols {
+		for i, width := range code128Patterns[symbol] {
+			bit := "1"
+			if i%2 == 1 {
+				bit = "0"
+			}
+			b.WriteString(strings.Repeat(bit, int(width-'0')))
+		}
+	}
+	return b.String(), nil
+}
+
+// complement swaps bars and spaces.
+func complement(modules string) string {
+	out := []byte(modules)
+	for i, c := range out {
+		if c == '0' {
+			out[i] = '1'
+		} else {
+			out[i] = '0'
+		}
+	}
+	return string(out)
+}
+
+// reverse reverses a module string.
+func reverse(modules string) string {
+	out := []byte(modules)
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
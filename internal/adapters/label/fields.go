@@ -0,0 +1,46 @@
+// Package label renders shelf labels and barcode stickers for printing.
+package label
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+// fieldText returns the text printed for a non-barcode field, or "" when the
+// label has no value for it.
+func fieldText(field domain.LabelField, label domain.Label) string {
+	switch field.Source {
+	case domain.LabelSourceName:
+		return formatValue(field.Format, "%s", label.Name)
+	case domain.LabelSourceSKU:
+		return formatValue(field.Format, "%s", label.SKU)
+	case domain.LabelSourcePrice:
+		return formatValue(field.Format, "%.2f", label.Price)
+	case domain.LabelSourceUnitPrice:
+		if label.UnitMeasure == "" {
+			return ""
+		}
+		return formatValue(field.Format, "%.2f", label.UnitPrice) + " / " + label.UnitMeasure
+	case domain.LabelSourceText:
+		return field.Text
+	}
+
+	if key := strings.TrimPrefix(field.Source, domain.LabelSourceProperty); key != field.Source {
+		value, ok := label.Properties[key]
+		if !ok || value == nil {
+			return ""
+		}
+		return formatValue(field.Format, "%v", value)
+	}
+	return ""
+}
+
+// formatValue formats a value with the field's format, or the default.
+func formatValue(format, fallback string, value interface{}) string {
+	if format == "" {
+		format = fallback
+	}
+	return fmt.Sprintf(format, value)
+}
@@ -0,0 +1,146 @@
+package label
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+var testTemplate = domain.LabelTemplate{
+	Name:     "test",
+	WidthMM:  60,
+	HeightMM: 35,
+	Fields: []domain.LabelField{
+		{Source: domain.LabelSourceName, X: 3, Y: 3, Size: 10},
+		{Source: domain.LabelSourcePrice, Format: "€%.2f", X: 3, Y: 10, Size: 20},
+		{Source: domain.LabelSourceUnitPrice, X: 3, Y: 20, Size: 7},
+		{Source: "property:voltage", Format: "%vV", X: 40, Y: 20, Size: 7},
+		{Source: domain.LabelSourceBarcode, X: 3, Y: 24, Size: 8},
+	},
+}
+
+func TestCode128Patterns(t *testing.T) {
+	for symbol, pattern := range code128Patterns {
+		sum := 0
+		for _, width := range pattern {
+			sum += int(width - '0')
+		}
+		want := 11
+		if symbol == code128Stop {
+			want = 13
+		}
+		if sum != want {
+			t.Errorf("symbol %d: pattern %s spans %d modules, want %d", symbol, pattern, sum, want)
+		}
+	}
+}
+
+func TestEncodeEAN13(t *testing.T) {
+	modules := encodeEAN13("4006381333931")
+	if len(modules) != 95 {
+		t.Fatalf("expected 95 modules, got %d", len(modules))
+	}
+	// Leading digit 4 gives parity LGLLGG: digit 0 in L, digit 0 in G
+	if modules[:17] != "101"+"0001101"+"0100111" {
+		t.Fatalf("unexpected left half %s", modules[:17])
+	}
+	if modules[45:50] != "01010" || modules[92:] != "101" {
+		t.Fatal("expected centre and end guards")
+	}
+}
+
+func TestEncodeCode128(t *testing.T) {
+	modules, err := encodeCode128("SKU-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Start, 5 characters, checksum and stop
+	if len(modules) != 11*7+13 {
+		t.Fatalf("expected %d modules, got %d", 11*7+13, len(modules))
+	}
+	if _, err := encodeCode128("naïve"); err == nil {
+		t.Fatal("expected an error for non-ASCII text")
+	}
+}
+
+func TestZPLRenderer(t *testing.T) {
+	labels := []domain.Label{
+		{ProductID: "p1", Name: "Cable ^ 3-core", Price: 1.5, UnitPrice: 1.5, UnitMeasure: "m", Barcode: "036000291452"},
+		{ProductID: "p2", Name: "Fuse", Price: 0.4, Barcode: "FUSE-13A", Properties: map[string]interface{}{"voltage": 240}},
+	}
+
+	var out bytes.Buffer
+	if err := NewZPLRenderer(8).Render(&out, testTemplate, labels); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	zpl := out.String()
+
+	if strings.Count(zpl, "^XA") != 2 || strings.Count(zpl, "^XZ") != 2 {
+		t.Fatalf("expected two label formats, got:\n%s", zpl)
+	}
+	for _, want := range []string{
+		"^PW480\n^LL280",              // 60x35 mm at 8 dots/mm
+		`^FDCable \5E 3-core^FS`,      // caret escaped
+		"^FD€1.50^FS",                 // custom price format
+		"^FD1.50 / m^FS",              // unit price
+		"^BEN,64,Y,N^FD003600029145^", // UPC-A printed as EAN-13 without check digit
+		"^BCN,64,Y,N,N^FH\\^FDFUSE-13A^FS",
+		"^FD240V^FS",
+	} {
+		if !strings.Contains(zpl, want) {
+			t.Errorf("expected %q in output:\n%s", want, zpl)
+		}
+	}
+}
+
+func TestPDFRenderer(t *testing.T) {
+	// 3 columns x 7 rows of 60x35 mm fit on A4, so 30 labels need 2 pages
+	labels := make([]domain.Label, 30)
+	for i := range labels {
+		labels[i] = domain.Label{ProductID: "p", Name: "Widget (large)", Price: 9.99, Barcode: "4006381333931"}
+	}
+
+	var out bytes.Buffer
+	if err := NewPDFRenderer().Render(&out, testTemplate, labels); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pdf := out.Bytes()
+
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatal("expected a PDF header and trailer")
+	}
+	if !bytes.Contains(pdf, []byte("/Count 2")) {
+		t.Fatal("expected 2 pages")
+	}
+	if !bytes.Contains(pdf, []byte(`(Widget \(large\)) Tj`)) || !bytes.Contains(pdf, []byte(`(\2009.99) Tj`)) {
+		t.Fatal("expected escaped text and a WinAnsi euro sign")
+	}
+
+	// startxref must point at the cross-reference table, and each entry at its object
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+	if m == nil {
+		t.Fatal("missing startxref")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(pdf[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point at the xref table", xref)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(pdf[xref:], -1)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		if want := strconv.Itoa(i+1) + " 0 obj"; !bytes.HasPrefix(pdf[offset:], []byte(want)) {
+			t.Fatalf("xref entry %d does not point at %q", i+1, want)
+		}
+	}
+}
+
+func TestPDFRenderer_LabelTooLarge(t *testing.T) {
+	template := domain.LabelTemplate{WidthMM: 300, HeightMM: 50}
+	if err := NewPDFRenderer().Render(&bytes.Buffer{}, template, []domain.Label{{}}); err == nil {
+		t.Fatal("expected an error for a label wider than the page")
+	}
+}
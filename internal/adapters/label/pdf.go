@@ -0,0 +1,226 @@
+package label
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+// Page geometry of PDF label sheets.
+const (
+	a4WidthMM     = 210.0
+	a4HeightMM    = 297.0
+	sheetMarginMM = 10.0
+	pointsPerMM   = 72 / 25.4
+)
+
+// barcodeTextSize is the font size of the digits printed under a barcode.
+const barcodeTextSize = 7.0
+
+// PDFRenderer renders labels as multi-up A4 PDF sheets with cut guides.
+type PDFRenderer struct {
+	pageWidthMM  float64
+	pageHeightMM float64
+	marginMM     float64
+}
+
+// NewPDFRenderer creates a PDF renderer for A4 sheets.
+func NewPDFRenderer() *PDFRenderer {
+	return &PDFRenderer{
+		pageWidthMM:  a4WidthMM,
+		pageHeightMM: a4HeightMM,
+		marginMM:     sheetMarginMM,
+	}
+}
+
+// ContentType returns the MIME type of PDF output.
+func (r *PDFRenderer) ContentType() string {
+	return "application/pdf"
+}
+
+// Render lays labels out in a grid, filling as many as fit on each page.
+func (r *PDFRenderer) Render(w io.Writer, template domain.LabelTemplate, labels []domain.Label) error {
+	columns := int((r.pageWidthMM - 2*r.marginMM) / template.WidthMM)
+	rows := int((r.pageHeightMM - 2*r.marginMM) / template.HeightMM)
+	if columns < 1 || rows < 1 {
+		return fmt.Errorf("label %gx%g mm does not fit on the page", template.WidthMM, template.HeightMM)
+	}
+	perPage := columns * rows
+
+	var pages []string
+	for start := 0; start < len(labels); start += perPage {
+		end := start + perPage
+		if end > len(labels) {
+			end = len(labels)
+		}
+
+		var content strings.Builder
+		for i, label := range labels[start:end] {
+			left := r.marginMM + float64(i%columns)*template.WidthMM
+			top := r.marginMM + float64(i/columns)*template.HeightMM
+			if err := r.drawLabel(&content, template, label, left, top); err != nil {
+				return fmt.Errorf("product %s: %w", label.ProductID, err)
+			}
+		}
+		pages = append(pages, content.String())
+	}
+	if len(pages) == 0 {
+		pages = append(pages, "")
+	}
+
+	_, err := w.Write(r.document(pages))
+	return err
+}
+
+// drawLabel appends the drawing operators for one label whose top-left
+// corner is at (left, top) mm from the page's top-left corner.
+func (r *PDFRenderer) drawLabel(out *strings.Builder, template domain.LabelTemplate, label domain.Label, left, top float64) error {
+	// Light grey cut guide
+	fmt.Fprintf(out, "0.8 G 0.2 w %.2f %.2f %.2f %.2f re S 0 G\n",
+		pt(left), r.y(top+template.HeightMM), pt(template.WidthMM), pt(template.HeightMM))
+
+	for _, field := range template.Fields {
+		x, y := left+field.X, top+field.Y
+
+		if field.Source == domain.LabelSourceBarcode {
+			if err := r.drawBarcode(out, field, x, y, label.Barcode); err != nil {
+				return err
+			}
+			continue
+		}
+
+		text := fieldText(field, label)
+		if text == "" {
+			continue
+		}
+		size := field.Size
+		if size <= 0 {
+			size = 8
+		}
+		r.drawText(out, x, y, size, text)
+	}
+	return nil
+}
+
+// drawBarcode appends filled bars for an EAN-13 or Code 128 barcode, with the
+// code printed underneath.
+func (r *PDFRenderer) drawBarcode(out *strings.Builder, field domain.LabelField, x, y float64, code string) error {
+	if code == "" {
+		return nil
+	}
+
+	height := field.Size
+	if height <= 0 {
+		height = 10
+	}
+
+	symbology, code := barcodeSymbology(code)
+	var modules string
+	if symbology == symbologyEAN13 {
+		modules = encodeEAN13(code)
+	} else {
+		var err error
+		if modules, err = encodeCode128(code); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(modules); {
+		if modules[i] != '1' {
+			i++
+			continue
+		}
+		run := 1
+		for i+run < len(modules) && modules[i+run] == '1' {
+			run++
+		}
+		fmt.Fprintf(out, "%.3f %.3f %.3f %.3f re f\n",
+			pt(x+float64(i)*barModuleMM), r.y(y+height), pt(float64(run)*barModuleMM), pt(height))
+		i += run
+	}
+
+	r.drawText(out, x, y+height+0.5, barcodeTextSize, code)
+	return nil
+}
+
+// drawText appends a line of Helvetica text whose top is at (x, y) mm.
+func (r *PDFRenderer) drawText(out *strings.Builder, x, y, size float64, text string) {
+	// Helvetica's cap height is about 0.72 em; place the baseline below it
+	baseline := r.y(y) - size*0.72
+	fmt.Fprintf(out, "BT /F1 %.1f Tf %.2f %.2f Td (%s) Tj ET\n", size, pt(x), baseline, pdfString(text))
+}
+
+// y converts a distance from the page top in mm to a PDF y coordinate.
+func (r *PDFRenderer) y(mm float64) float64 {
+	return pt(r.pageHeightMM - mm)
+}
+
+// document assembles a PDF with one page per content stream.
+func (r *PDFRenderer) document(pages []string) []byte {
+	var buf bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	// Objects 1-3 are fixed; each page then takes a page and a content object
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+
+	for i, content := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			pt(r.pageWidthMM), pt(r.pageHeightMM), 5+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.Bytes()
+}
+
+// pdfString escapes text for a PDF literal string in WinAnsi encoding.
+// Characters outside Latin-1, other than the euro sign, print as "?".
+func pdfString(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 32 && r < 127:
+			b.WriteRune(r)
+		case r == '€':
+			b.WriteString(`\200`)
+		case r >= 160 && r <= 255:
+			fmt.Fprintf(&b, "\\%03o", r)
+		case r < 32:
+			// Drop control characters
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// pt converts millimetres to PDF points.
+func pt(mm float64) float64 {
+	return math.Round(mm*pointsPerMM*1000) / 1000
+}
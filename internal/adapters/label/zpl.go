@@ -0,0 +1,104 @@
+package label
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+// defaultDotsPerMM matches 203 dpi Zebra printers.
+const defaultDotsPerMM = 8
+
+// barModuleMM is the nominal width of the narrowest bar.
+const barModuleMM = 0.3
+
+// zplEscaper hex-escapes the characters ZPL treats as commands inside ^FH fields.
+var zplEscaper = strings.NewReplacer(`\`, `\5C`, "^", `\5E`, "~", `\7E`)
+
+// ZPLRenderer renders labels as ZPL II for Zebra printers, one format per label.
+type ZPLRenderer struct {
+	dotsPerMM int
+}
+
+// NewZPLRenderer creates a ZPL renderer for a printer resolution in dots per
+// mm: 8 for 203 dpi, 12 for 300 dpi. Zero selects 8.
+func NewZPLRenderer(dotsPerMM int) *ZPLRenderer {
+	if dotsPerMM <= 0 {
+		dotsPerMM = defaultDotsPerMM
+	}
+	return &ZPLRenderer{dotsPerMM: dotsPerMM}
+}
+
+// ContentType returns the MIME type of ZPL output.
+func (r *ZPLRenderer) ContentType() string {
+	return "application/zpl"
+}
+
+// Render writes one ^XA...^XZ format per label.
+func (r *ZPLRenderer) Render(w io.Writer, template domain.LabelTemplate, labels []domain.Label) error {
+	out := bufio.NewWriter(w)
+
+	for _, label := range labels {
+		fmt.Fprintf(out, "^XA\n^CI28\n^PW%d\n^LL%d\n", r.dots(template.WidthMM), r.dots(template.HeightMM))
+
+		for _, field := range template.Fields {
+			x, y := r.dots(field.X), r.dots(field.Y)
+
+			if field.Source == domain.LabelSourceBarcode {
+				if err := r.writeBarcode(out, field, x, y, label.Barcode); err != nil {
+					return fmt.Errorf("product %s: %w", label.ProductID, err)
+				}
+				continue
+			}
+
+			text := fieldText(field, label)
+			if text == "" {
+				continue
+			}
+			height := r.dots(field.Size * 25.4 / 72)
+			fmt.Fprintf(out, "^FO%d,%d^A0N,%d,%d^FH\\^FD%s^FS\n", x, y, height, height, zplEscaper.Replace(text))
+		}
+
+		out.WriteString("^XZ\n")
+	}
+
+	return out.Flush()
+}
+
+// writeBarcode writes an EAN-13 or Code 128 barcode with its human-readable line.
+func (r *ZPLRenderer) writeBarcode(out *bufio.Writer, field domain.LabelField, x, y int, code string) error {
+	if code == "" {
+		return nil
+	}
+
+	height := field.Size
+	if height <= 0 {
+		height = 10
+	}
+	module := r.dots(barModuleMM)
+	if module < 1 {
+		module = 1
+	}
+
+	symbology, code := barcodeSymbology(code)
+	switch symbology {
+	case symbologyEAN13:
+		// ^BE computes the check digit itself
+		fmt.Fprintf(out, "^FO%d,%d^BY%d^BEN,%d,Y,N^FD%s^FS\n", x, y, module, r.dots(height), code[:12])
+	default:
+		if _, err := encodeCode128(code); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "^FO%d,%d^BY%d^BCN,%d,Y,N,N^FH\\^FD%s^FS\n", x, y, module, r.dots(height), zplEscaper.Replace(code))
+	}
+	return nil
+}
+
+// dots converts millimetres to printer dots.
+func (r *ZPLRenderer) dots(mm float64) int {
+	return int(math.Round(mm * float64(r.dotsPerMM)))
+}
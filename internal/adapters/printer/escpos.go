@@ -0,0 +1,251 @@
+// Package printer renders sale receipts for thermal receipt printers and
+// delivers them over a file or network connection.
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+// defaultColumns is the Font A line width of 80 mm paper.
+const defaultColumns = 48
+
+// dotsPerColumn is the width of one Font A character in dots.
+const dotsPerColumn = 12
+
+// code128Modules is the width of a Code 128 symbol without its data characters:
+// start, checksum and stop.
+const code128Modules = 11 + 11 + 13
+
+// code128QuietZone is the blank margin needed either side of a Code 128
+// symbol, in modules.
+const code128QuietZone = 10
+
+// qrModuleDots is the width of one QR code module in dots.
+const qrModuleDots = 6
+
+// ESC/POS command sequences.
+var (
+	cmdInit        = []byte{0x1B, '@'}
+	cmdCodePage858 = []byte{0x1B, 't', 19}
+	cmdAlignLeft   = []byte{0x1B, 'a', 0}
+	cmdAlignCenter = []byte{0x1B, 'a', 1}
+	cmdBoldOn      = []byte{0x1B, 'E', 1}
+	cmdBoldOff     = []byte{0x1B, 'E', 0}
+	cmdSizeNormal  = []byte{0x1D, '!', 0x00}
+	cmdSizeDouble  = []byte{0x1D, '!', 0x11}
+	cmdSizeTall    = []byte{0x1D, '!', 0x01}
+	cmdFeedAndCut  = []byte{0x1B, 'd', 4, 0x1D, 'V', 66, 0}
+)
+
+// pc858 maps the non-ASCII characters likely on a receipt to code page 858,
+// which is code page 850 with the euro sign.
+var pc858 = map[rune]byte{
+	'Ç': 0x80, 'ü': 0x81, 'é': 0x82, 'â': 0x83, 'ä': 0x84, 'à': 0x85, 'å': 0x86, 'ç': 0x87,
+	'ê': 0x88, 'ë': 0x89, 'è': 0x8A, 'ï': 0x8B, 'î': 0x8C, 'ì': 0x8D, 'Ä': 0x8E, 'Å': 0x8F,
+	'É': 0x90, 'æ': 0x91, 'Æ': 0x92, 'ô': 0x93, 'ö': 0x94, 'ò': 0x95, 'û': 0x96, 'ù': 0x97,
+	'ÿ': 0x98, 'Ö': 0x99, 'Ü': 0x9A, 'ø': 0x9B, '£': 0x9C, 'Ø': 0x9D, '×': 0x9E,
+	'á': 0xA0, 'í': 0xA1, 'ó': 0xA2, 'ú': 0xA3, 'ñ': 0xA4, 'Ñ': 0xA5, '¿': 0xA8, '®': 0xA9,
+	'½': 0xAB, '¼': 0xAC, '¡': 0xAD, 'Á': 0xB5, 'Â': 0xB6, 'À': 0xB7, '©': 0xB8, '¢': 0xBD,
+	'¥': 0xBE, 'ã': 0xC6, 'Ã': 0xC7, 'Ê': 0xD2, 'Ë': 0xD3, 'È': 0xD4, '€': 0xD5, 'Í': 0xD6,
+	'Î': 0xD7, 'Ï': 0xD8, 'Ì': 0xDE, 'Ó': 0xE0, 'ß': 0xE1, 'Ô': 0xE2, 'Ò': 0xE3, 'õ': 0xE4,
+	'Õ': 0xE5, 'µ': 0xE6, 'Ú': 0xE9, 'Û': 0xEA, 'Ù': 0xEB, '±': 0xF1, '°': 0xF8,
+}
+
+// ESCPOSRenderer renders receipts as ESC/POS commands for Epson-compatible
+// thermal printers.
+type ESCPOSRenderer struct {
+	columns int
+}
+
+// NewESCPOSRenderer creates a receipt renderer for a paper width in Font A
+// columns: 48 for 80 mm paper, 32 for 58 mm. Zero selects 48.
+func NewESCPOSRenderer(columns int) *ESCPOSRenderer {
+	if columns <= 0 {
+		columns = defaultColumns
+	}
+	return &ESCPOSRenderer{columns: columns}
+}
+
+// Render writes the receipt followed by a feed and partial cut.
+func (r *ESCPOSRenderer) Render(w io.Writer, receipt *domain.Receipt) error {
+	var out bytes.Buffer
+	out.Write(cmdInit)
+	out.Write(cmdCodePage858)
+
+	out.Write(cmdAlignCenter)
+	for i, line := range receipt.Header {
+		// The first header line is the shop name
+		if i == 0 {
+			out.Write(cmdBoldOn)
+			out.Write(cmdSizeDouble)
+			r.writeLine(&out, encode(line), r.columns/2)
+			out.Write(cmdSizeNormal)
+			out.Write(cmdBoldOff)
+			continue
+		}
+		r.writeLine(&out, encode(line), r.columns)
+	}
+
+	out.Write(cmdAlignLeft)
+	r.writeRule(&out)
+	r.writeLine(&out, encode("Sale: "+receipt.Sale.ID), r.columns)
+	r.writeLine(&out, encode("Date: "+receipt.Sale.CreatedAt.Format("2006-01-02 15:04")), r.columns)
+	if receipt.Sale.CashierID != "" {
+		r.writeLine(&out, encode("Cashier: "+receipt.Sale.CashierID), r.columns)
+	}
+	r.writeRule(&out)
+
+	for _, line := range receipt.Lines {
+		r.writeLine(&out, encode(line.Name), r.columns)
+		detail := "  " + formatQuantity(line.Quantity, line.Unit) + " x " + formatAmount(line.UnitPrice)
+		r.writeRow(&out, detail, formatAmount(line.Total), r.columns)
+	}
+	r.writeRule(&out)
+
+	out.Write(cmdBoldOn)
+	out.Write(cmdSizeTall)
+	r.writeRow(&out, "TOTAL", formatAmount(receipt.Sale.TotalAmount), r.columns)
+	out.Write(cmdSizeNormal)
+	out.Write(cmdBoldOff)
+	r.writeRow(&out, "Items", strconv.Itoa(len(receipt.Lines)), r.columns)
+
+	out.Write(cmdAlignCenter)
+	out.WriteByte('\n')
+	if err := r.writeBarcode(&out, receipt.Sale.ID); err != nil {
+		return fmt.Errorf("sale %s: %w", receipt.Sale.ID, err)
+	}
+	for _, line := range receipt.Footer {
+		r.writeLine(&out, encode(line), r.columns)
+	}
+
+	out.Write(cmdFeedAndCut)
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// writeBarcode prints text as Code 128 subset B with the text underneath,
+// using the widest bars that fit the paper. Text too long for a Code 128
+// symbol to fit, such as a sale ID on 58 mm paper, is printed as a QR code
+// instead, which printers would otherwise skip.
+func (r *ESCPOSRenderer) writeBarcode(out *bytes.Buffer, text string) error {
+	for _, c := range text {
+		if c < 0x20 || c > 0x7E {
+			return fmt.Errorf("cannot encode %q as Code 128", text)
+		}
+	}
+	data := "{B" + text
+	if len(data) > 255 {
+		return fmt.Errorf("barcode text %q too long", text)
+	}
+
+	width := r.columns * dotsPerColumn / (code128Modules + 11*len(text) + 2*code128QuietZone)
+	if width < 1 {
+		r.writeQRCode(out, text)
+		return nil
+	}
+	if width > 6 {
+		width = 6
+	}
+
+	out.Write([]byte{0x1D, 'h', 80})          // height in dots
+	out.Write([]byte{0x1D, 'w', byte(width)}) // module width in dots
+	out.Write([]byte{0x1D, 'H', 2})           // text below the bars
+	out.Write([]byte{0x1D, 'k', 73, byte(len(data))})
+	out.WriteString(data)
+	out.WriteByte('\n')
+	return nil
+}
+
+// writeQRCode prints text as a QR code model 2 with the text underneath,
+// wrapped to the paper width.
+func (r *ESCPOSRenderer) writeQRCode(out *bytes.Buffer, text string) {
+	size := len(text) + 3
+	out.Write([]byte{0x1D, '(', 'k', 4, 0, '1', 'A', '2', 0})                     // model 2
+	out.Write([]byte{0x1D, '(', 'k', 3, 0, '1', 'C', qrModuleDots})               // module width in dots
+	out.Write([]byte{0x1D, '(', 'k', 3, 0, '1', 'E', '1'})                        // error correction level M
+	out.Write([]byte{0x1D, '(', 'k', byte(size), byte(size >> 8), '1', 'P', '0'}) // store the data
+	out.WriteString(text)
+	out.Write([]byte{0x1D, '(', 'k', 3, 0, '1', 'Q', '0'}) // print the stored symbol
+	out.WriteByte('\n')
+	for len(text) > r.columns {
+		r.writeLine(out, []byte(text[:r.columns]), r.columns)
+		text = text[r.columns:]
+	}
+	r.writeLine(out, []byte(text), r.columns)
+}
+
+// writeLine writes text cut to the given width.
+func (r *ESCPOSRenderer) writeLine(out *bytes.Buffer, text []byte, width int) {
+	if len(text) > width {
+		text = text[:width]
+	}
+	out.Write(text)
+	out.WriteByte('\n')
+}
+
+// writeRow writes left and right text on one line, cutting the left text to
+// keep the right text aligned to the edge. Right text wider than the line,
+// such as an amount on very narrow paper, is written whole and left to wrap.
+func (r *ESCPOSRenderer) writeRow(out *bytes.Buffer, left, right string, width int) {
+	l, rt := encode(left), encode(right)
+	if room := width - len(rt) - 1; len(l) > room {
+		if room < 0 {
+			room = 0
+		}
+		l = l[:room]
+	}
+	out.Write(l)
+	if padding := width - len(l) - len(rt); padding > 0 {
+		out.Write(bytes.Repeat([]byte{' '}, padding))
+	}
+	out.Write(rt)
+	out.WriteByte('\n')
+}
+
+// writeRule writes a full-width separator line.
+func (r *ESCPOSRenderer) writeRule(out *bytes.Buffer) {
+	out.WriteString(strings.Repeat("-", r.columns))
+	out.WriteByte('\n')
+}
+
+// encode converts text to code page 858, replacing characters the printer
+// cannot show with '?' and dropping control characters.
+func encode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, c := range s {
+		switch {
+		case c >= 0x20 && c < 0x7F:
+			out = append(out, byte(c))
+		case c < 0x20 || c == 0x7F:
+			continue
+		default:
+			if b, ok := pc858[c]; ok {
+				out = append(out, b)
+			} else {
+				out = append(out, '?')
+			}
+		}
+	}
+	return out
+}
+
+// formatQuantity formats a quantity with its unit, without trailing zeros.
+func formatQuantity(quantity float64, unit string) string {
+	q := strconv.FormatFloat(quantity, 'f', -1, 64)
+	if unit == "" {
+		return q
+	}
+	return q + " " + unit
+}
+
+// formatAmount formats a money amount.
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
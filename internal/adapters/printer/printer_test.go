@@ -0,0 +1,186 @@
+package printer
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+var testReceipt = &domain.Receipt{
+	Sale: &domain.Sale{
+		ID:          "0b6f1a1e-8a0c-4d5e-9a43-2f1c7d9e6b10",
+		TotalAmount: 19.99,
+		CashierID:   "alice",
+		CreatedAt:   time.Date(2026, 3, 14, 9, 30, 0, 0, time.UTC),
+	},
+	Header: []string{"Corner Hardware", "1 High Street"},
+	Footer: []string{"Returns within 28 days with receipt", "Merci · Danke · £€"},
+	Lines: []domain.ReceiptLine{
+		{Name: "Cable 3-core", Quantity: 2.5, Unit: "m", UnitPrice: 1.50, Total: 3.75},
+		{Name: "Screws (Box of 100)", Quantity: 2, UnitPrice: 8.12, Total: 16.24},
+	},
+}
+
+func renderTestReceipt(t *testing.T) []byte {
+	t.Helper()
+	var out bytes.Buffer
+	if err := NewESCPOSRenderer(0).Render(&out, testReceipt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return out.Bytes()
+}
+
+func TestESCPOSRenderer(t *testing.T) {
+	data := renderTestReceipt(t)
+
+	if !bytes.HasPrefix(data, []byte{0x1B, '@', 0x1B, 't', 19}) {
+		t.Fatalf("expected initialise and code page 858, got % x", data[:5])
+	}
+	if !bytes.HasSuffix(data, cmdFeedAndCut) {
+		t.Fatal("expected the receipt to end with a feed and cut")
+	}
+
+	for _, want := range [][]byte{
+		[]byte("Sale: 0b6f1a1e-8a0c-4d5e-9a43-2f1c7d9e6b10\n"),
+		[]byte("Date: 2026-03-14 09:30\n"),
+		[]byte("  2.5 m x 1.50" + string(bytes.Repeat([]byte{' '}, 48-14-4)) + "3.75\n"),
+		[]byte("TOTAL" + string(bytes.Repeat([]byte{' '}, 48-5-5)) + "19.99\n"),
+		{0x9C, 0xD5}, // £€ in code page 858
+		{0x1D, 'k', 73, 38, '{', 'B', '0', 'b', '6', 'f'}, // Code 128 subset B of the sale ID
+		{0x1D, 'w', 1}, // 36 characters only fit at one dot per module
+	} {
+		if !bytes.Contains(data, want) {
+			t.Errorf("expected %q in output", want)
+		}
+	}
+}
+
+func TestESCPOSRenderer_NarrowPaper(t *testing.T) {
+	var out bytes.Buffer
+	if err := NewESCPOSRenderer(32).Render(&out, testReceipt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data := out.Bytes()
+
+	// 36 characters of Code 128 need 451 modules with quiet zones, more than 384 dots
+	if bytes.Contains(data, []byte{0x1D, 'k', 73}) {
+		t.Error("expected no Code 128 symbol on 58 mm paper")
+	}
+	for _, want := range [][]byte{
+		append([]byte{0x1D, '(', 'k', 39, 0, '1', 'P', '0'}, testReceipt.Sale.ID...), // QR code of the sale ID
+		{0x1D, '(', 'k', 3, 0, '1', 'Q', '0'},
+		[]byte("\n0b6f1a1e-8a0c-4d5e-9a43-2f1c7d9e\n6b10\n"),
+	} {
+		if !bytes.Contains(data, want) {
+			t.Errorf("expected %q in output", want)
+		}
+	}
+}
+
+func TestWriteRow_TruncatesLeft(t *testing.T) {
+	var out bytes.Buffer
+	NewESCPOSRenderer(10).writeRow(&out, "a long product name", "9.99", 10)
+	if got := out.String(); got != "a lon 9.99\n" {
+		t.Fatalf("unexpected row %q", got)
+	}
+}
+
+func TestWriteRow_RightWiderThanLine(t *testing.T) {
+	var out bytes.Buffer
+	NewESCPOSRenderer(1).writeRow(&out, "TOTAL", "123.45", 1)
+	if got := out.String(); got != "123.45\n" {
+		t.Fatalf("unexpected row %q", got)
+	}
+
+	out.Reset()
+	if err := NewESCPOSRenderer(1).Render(&out, testReceipt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "printer.bin")
+	sink, err := NewSink("file://" + path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := sink.Print(context.Background(), []byte("receipt\n")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(got) != "receipt\nreceipt\n" {
+		t.Fatalf("expected both receipts appended, got %q", got)
+	}
+}
+
+func TestTCPSink_FakePrinter(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer listener.Close()
+
+	// The fake printer reads one job per connection
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		data, _ := io.ReadAll(conn)
+		received <- data
+	}()
+
+	sink, err := NewSink("tcp://" + listener.Addr().String())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data := renderTestReceipt(t)
+	if err := sink.Print(context.Background(), data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case got := <-received:
+		if !bytes.Equal(got, data) {
+			t.Fatalf("printer received %d bytes, want %d", len(got), len(data))
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("fake printer received nothing")
+	}
+}
+
+func TestTCPSink_Unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	if err := NewTCPSink(addr, time.Second).Print(context.Background(), []byte("x")); err == nil {
+		t.Fatal("expected an error when the printer is not listening")
+	}
+}
+
+func TestNewSink_InvalidAddress(t *testing.T) {
+	if _, err := NewSink("tcp://printer"); err == nil {
+		t.Fatal("expected an error for an address without a port")
+	}
+}
@@ -0,0 +1,96 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// defaultPrintTimeout bounds connecting to and writing to a network printer.
+const defaultPrintTimeout = 5 * time.Second
+
+// FileSink prints by appending to a file, such as a USB or serial printer
+// device node or a spool file.
+type FileSink struct {
+	path string
+}
+
+// NewFileSink creates a sink that writes to the file at path.
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+// Print appends data to the file.
+func (s *FileSink) Print(_ context.Context, data []byte) error {
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("open printer %s: %w", s.path, err)
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("write to printer %s: %w", s.path, err)
+	}
+	return f.Close()
+}
+
+// TCPSink prints over a raw TCP connection, as used by network receipt
+// printers on port 9100.
+type TCPSink struct {
+	addr    string
+	timeout time.Duration
+}
+
+// NewTCPSink creates a sink for the printer at addr ("host:port"). A zero
+// timeout selects 5 seconds.
+func NewTCPSink(addr string, timeout time.Duration) *TCPSink {
+	if timeout <= 0 {
+		timeout = defaultPrintTimeout
+	}
+	return &TCPSink{addr: addr, timeout: timeout}
+}
+
+// Print opens a connection, sends data and closes it.
+func (s *TCPSink) Print(ctx context.Context, data []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("connect to printer %s: %w", s.addr, err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetWriteDeadline(deadline); err != nil {
+			return fmt.Errorf("connect to printer %s: %w", s.addr, err)
+		}
+	}
+	if _, err := conn.Write(data); err != nil {
+		return fmt.Errorf("write to printer %s: %w", s.addr, err)
+	}
+	return nil
+}
+
+// NewSink creates a sink from a printer target: "tcp://host:port" for a
+// network printer, otherwise a file path with an optional "file://" prefix.
+func NewSink(target string) (ports.PrinterSink, error) {
+	switch {
+	case target == "":
+		return nil, fmt.Errorf("empty printer target")
+	case strings.HasPrefix(target, "tcp://"):
+		addr := strings.TrimPrefix(target, "tcp://")
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return nil, fmt.Errorf("invalid printer address %q: %w", addr, err)
+		}
+		return NewTCPSink(addr, 0), nil
+	default:
+		return NewFileSink(strings.TrimPrefix(target, "file://")), nil
+	}
+}
@@ -0,0 +1,347 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+// AnalyticsRepository implements read-only sales reporting queries using SQLite.
+type AnalyticsRepository struct {
+	db sqlx.ExtContext
+}
+
+// NewAnalyticsRepository creates a new analytics repository instance.
+func NewAnalyticsRepository(db sqlx.ExtContext) *AnalyticsRepository {
+	return &AnalyticsRepository{db: db}
+}
+
+// saleLineRow is a database row representation for a sale line joined with
+// its sale, product and category.
+type saleLineRow struct {
+	SaleID       string         `db:"sale_id"`
+	SoldAt       time.Time      `db:"sold_at"`
+	CashierID    sql.NullString `db:"cashier_id"`
+	ProductID    string         `db:"product_id"`
+	ProductName  sql.NullString `db:"product_name"`
+	SKU          sql.NullString `db:"sku"`
+	CategoryID   sql.NullString `db:"category_id"`
+	CategoryName sql.NullString `db:"category_name"`
+	Quantity     float64        `db:"quantity"`
+	UnitPrice    float64        `db:"unit_price"`
+	CostPrice    float64        `db:"cost_price"`
+}
+
+// ListSaleLines returns every sale line, optionally limited to sales made
+// within [from, to], in the order they were sold.
+func (r *AnalyticsRepository) ListSaleLines(ctx context.Context, from, to *time.Time) ([]*domain.SaleLine, error) {
+	query := `
+		SELECT si.sale_id, s.created_at AS sold_at, s.cashier_id, si.product_id,
+			p.name AS product_name, p.sku, p.category_id, c.name AS category_name,
+			si.quantity, si.unit_price, si.cost_price
+		FROM sale_items si
+		JOIN sales s ON s.id = si.sale_id
+		LEFT JOIN products p ON p.id = si.product_id
+		LEFT JOIN categories c ON c.id = p.category_id
+		WHERE 1 = 1
+	`
+	var args []interface{}
+
+	// datetime() normalises timestamps written with different offsets.
+	if from != nil {
+		query += ` AND datetime(s.created_at) >= datetime(?)`
+		args = append(args, *from)
+	}
+	if to != nil {
+		query += ` AND datetime(s.created_at) <= datetime(?)`
+		args = append(args, *to)
+	}
+	query += ` ORDER BY datetime(s.created_at), si.id`
+
+	var rows []saleLineRow
+	err := sqlx.SelectContext(ctx, r.db, &rows, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]*domain.SaleLine, 0, len(rows))
+	for _, row := range rows {
+		lines = append(lines, &domain.SaleLine{
+			SaleID:       row.SaleID,
+			SoldAt:       row.SoldAt,
+			CashierID:    row.CashierID.String,
+			ProductID:    row.ProductID,
+			ProductName:  row.ProductName.String,
+			SKU:          row.SKU.String,
+			CategoryID:   row.CategoryID.String,
+			CategoryName: row.CategoryName.String,
+			Quantity:     row.Quantity,
+			UnitPrice:    row.UnitPrice,
+			CostPrice:    row.CostPrice,
+		})
+	}
+
+	return lines, nil
+}
+
+// localPeriodStarts are the SQL expressions giving the start of the local
+// hour, day, week or month a sale was made in. SQLite's localtime reads the
+// same zone as Go's time.Local, so they agree with the services' buckets.
+// Weeks start on Monday.
+var localPeriodStarts = map[string]string{
+	domain.GroupByHour:  `strftime('%Y-%m-%d %H:00:00', s.created_at, 'localtime')`,
+	domain.GroupByDay:   `strftime('%Y-%m-%d 00:00:00', s.created_at, 'localtime')`,
+	domain.GroupByWeek:  `strftime('%Y-%m-%d 00:00:00', s.created_at, 'localtime', '-6 days', 'weekday 1')`,
+	domain.GroupByMonth: `strftime('%Y-%m-01 00:00:00', s.created_at, 'localtime')`,
+}
+
+// parseLocalPeriod parses a period start from localPeriodStarts, or returns
+// the zero time for lines not split by period.
+func parseLocalPeriod(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation("2006-01-02 15:04:05", value, time.Local)
+}
+
+// saleLineSumRow is a database row representation for a group of sale lines.
+type saleLineSumRow struct {
+	GroupKey string  `db:"group_key"`
+	Label    string  `db:"label"`
+	Period   string  `db:"period"`
+	Sales    int     `db:"sales"`
+	Quantity float64 `db:"quantity"`
+	Revenue  float64 `db:"revenue"`
+	Cost     float64 `db:"cost"`
+}
+
+// SumSaleLines totals the sale lines selected by the query per group, so
+// reports read one row per product, category, cashier or period rather than
+// one per line sold. Groups come in no particular order.
+func (r *AnalyticsRepository) SumSaleLines(ctx context.Context, q domain.SaleLineQuery) ([]*domain.SaleLineSum, error) {
+	key, label := `''`, `''`
+	switch q.GroupBy {
+	case domain.GroupByProduct:
+		key, label = `si.product_id`, `MAX(p.name)`
+	case domain.GroupByCategory:
+		key, label = `COALESCE(p.category_id, '')`, `MAX(c.name)`
+	case domain.GroupByCashier:
+		key = `COALESCE(s.cashier_id, '')`
+	}
+	period := `''`
+	if q.Period != "" {
+		start, ok := localPeriodStarts[q.Period]
+		if !ok {
+			return nil, fmt.Errorf("unknown period %q", q.Period)
+		}
+		period = start
+	}
+
+	query := `
+		SELECT ` + key + ` AS group_key, COALESCE(` + label + `, '') AS label, ` + period + ` AS period,
+			COUNT(DISTINCT si.sale_id) AS sales, SUM(si.quantity) AS quantity,
+			SUM(si.quantity * si.unit_price) AS revenue, SUM(si.quantity * si.cost_price) AS cost
+		FROM sale_items si
+		JOIN sales s ON s.id = si.sale_id
+		LEFT JOIN products p ON p.id = si.product_id
+		LEFT JOIN categories c ON c.id = p.category_id
+		WHERE 1 = 1
+	`
+	var args []interface{}
+
+	// datetime() normalises timestamps written with different offsets.
+	if q.From != nil {
+		query += ` AND datetime(s.created_at) >= datetime(?)`
+		args = append(args, *q.From)
+	}
+	if q.To != nil {
+		query += ` AND datetime(s.created_at) <= datetime(?)`
+		args = append(args, *q.To)
+	}
+	if q.CategoryIDs != nil {
+		query += ` AND p.category_id IN (` + strings.TrimSuffix(strings.Repeat("?, ", len(q.CategoryIDs)), ", ") + `)`
+		for _, id := range q.CategoryIDs {
+			args = append(args, id)
+		}
+	}
+	if q.BelowCost {
+		query += ` AND si.unit_price < si.cost_price`
+	}
+	query += ` GROUP BY group_key, period`
+
+	var rows []saleLineSumRow
+	err := sqlx.SelectContext(ctx, r.db, &rows, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	sums := make([]*domain.SaleLineSum, 0, len(rows))
+	for _, row := range rows {
+		start, err := parseLocalPeriod(row.Period)
+		if err != nil {
+			return nil, err
+		}
+		sums = append(sums, &domain.SaleLineSum{
+			Key:      row.GroupKey,
+			Label:    row.Label,
+			Period:   start,
+			Sales:    row.Sales,
+			Quantity: row.Quantity,
+			Revenue:  row.Revenue,
+			Cost:     row.Cost,
+		})
+	}
+
+	return sums, nil
+}
+
+// salesBucketRow is a database row representation for the sales of a period.
+type salesBucketRow struct {
+	Period       string  `db:"period"`
+	Revenue      float64 `db:"revenue"`
+	Units        float64 `db:"units"`
+	Transactions int     `db:"transactions"`
+}
+
+// SumSales totals the sales made within [from, to] per local hour, day, week
+// or month. Only periods with sales are returned, in date order.
+func (r *AnalyticsRepository) SumSales(ctx context.Context, from, to time.Time, interval string) ([]*domain.SalesBucket, error) {
+	period, ok := localPeriodStarts[interval]
+	if !ok {
+		return nil, fmt.Errorf("unknown interval %q", interval)
+	}
+
+	// The raw created_at bounds let idx_sales_created_at narrow the scan.
+	// They are widened by a day because stored timestamps carry their own UTC
+	// offset; datetime() then applies the exact range.
+	query := `
+		SELECT ` + period + ` AS period, SUM(s.total_amount) AS revenue, COUNT(*) AS transactions,
+			COALESCE(SUM((SELECT SUM(si.quantity) FROM sale_items si WHERE si.sale_id = s.id)), 0) AS units
+		FROM sales s
+		WHERE s.created_at >= ? AND s.created_at < ?
+			AND datetime(s.created_at) >= datetime(?) AND datetime(s.created_at) <= datetime(?)
+		GROUP BY period
+		ORDER BY period
+	`
+
+	var rows []salesBucketRow
+	err := sqlx.SelectContext(ctx, r.db, &rows, query,
+		from.UTC().AddDate(0, 0, -1).Format("2006-01-02"),
+		to.UTC().AddDate(0, 0, 2).Format("2006-01-02"),
+		from,
+		to,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make([]*domain.SalesBucket, 0, len(rows))
+	for _, row := range rows {
+		start, err := parseLocalPeriod(row.Period)
+		if err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, &domain.SalesBucket{
+			Start:        start,
+			Revenue:      row.Revenue,
+			Units:        row.Units,
+			Transactions: row.Transactions,
+		})
+	}
+
+	return buckets, nil
+}
+
+// productActivityRow is a database row representation for a product's stock
+// and last sale.
+type productActivityRow struct {
+	ProductID  string         `db:"product_id"`
+	SKU        string         `db:"sku"`
+	Name       string         `db:"name"`
+	CategoryID sql.NullString `db:"category_id"`
+	Quantity   float64        `db:"quantity"`
+	CostPrice  float64        `db:"cost_price"`
+	BasePrice  float64        `db:"base_price"`
+	LastSoldAt sql.NullString `db:"last_sold_at"` // UTC, as normalised by datetime()
+}
+
+// ListProductActivity returns every product's stock position and the time
+// it last sold.
+func (r *AnalyticsRepository) ListProductActivity(ctx context.Context) ([]*domain.ProductActivity, error) {
+	query := `
+		SELECT p.id AS product_id, p.sku, p.name, p.category_id, p.quantity, p.cost_price, p.base_price,
+			(SELECT MAX(datetime(s.created_at))
+				FROM sale_items si
+				JOIN sales s ON s.id = si.sale_id
+				WHERE si.product_id = p.id) AS last_sold_at
+		FROM products p
+		ORDER BY p.sku
+	`
+
+	var rows []productActivityRow
+	err := sqlx.SelectContext(ctx, r.db, &rows, query)
+	if err != nil {
+		return nil, err
+	}
+
+	activity := make([]*domain.ProductActivity, 0, len(rows))
+	for _, row := range rows {
+		item := &domain.ProductActivity{
+			ProductID:  row.ProductID,
+			SKU:        row.SKU,
+			Name:       row.Name,
+			CategoryID: row.CategoryID.String,
+			Quantity:   row.Quantity,
+			CostPrice:  row.CostPrice,
+			BasePrice:  row.BasePrice,
+		}
+		if row.LastSoldAt.Valid {
+			lastSold, err := time.Parse("2006-01-02 15:04:05", row.LastSoldAt.String)
+			if err != nil {
+				return nil, err
+			}
+			item.LastSoldAt = &lastSold
+		}
+		activity = append(activity, item)
+	}
+
+	return activity, nil
+}
+
+// ListProductSales returns a product's sale lines made within [from, to],
+// in the order they were sold.
+func (r *AnalyticsRepository) ListProductSales(ctx context.Context, productID string, from, to time.Time) ([]*domain.SaleLine, error) {
+	query := `
+		SELECT si.sale_id, s.created_at AS sold_at, si.quantity, si.unit_price, si.cost_price
+		FROM sale_items si
+		JOIN sales s ON s.id = si.sale_id
+		WHERE si.product_id = ?
+			AND datetime(s.created_at) >= datetime(?) AND datetime(s.created_at) <= datetime(?)
+		ORDER BY datetime(s.created_at), si.id
+	`
+
+	var rows []saleLineRow
+	err := sqlx.SelectContext(ctx, r.db, &rows, query, productID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]*domain.SaleLine, 0, len(rows))
+	for _, row := range rows {
+		lines = append(lines, &domain.SaleLine{
+			SaleID:    row.SaleID,
+			SoldAt:    row.SoldAt,
+			ProductID: productID,
+			Quantity:  row.Quantity,
+			UnitPrice: row.UnitPrice,
+			CostPrice: row.CostPrice,
+		})
+	}
+
+	return lines, nil
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+func TestSumSaleLines_GroupsInSQL(t *testing.T) {
+	db, err := sqlx.Connect("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer db.Close()
+
+	// Only the columns the queries read
+	_, err = db.Exec(`
+		CREATE TABLE categories (id TEXT PRIMARY KEY, name TEXT NOT NULL);
+		CREATE TABLE products (id TEXT PRIMARY KEY, name TEXT NOT NULL, category_id TEXT);
+		CREATE TABLE sales (id TEXT PRIMARY KEY, cashier_id TEXT, total_amount REAL NOT NULL, created_at TIMESTAMP NOT NULL);
+		CREATE TABLE sale_items (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			sale_id TEXT NOT NULL,
+			product_id TEXT NOT NULL,
+			quantity REAL NOT NULL,
+			unit_price REAL NOT NULL,
+			cost_price REAL NOT NULL
+		);
+		INSERT INTO categories (id, name) VALUES ('drinks', 'Drinks');
+		INSERT INTO products (id, name, category_id) VALUES ('cola', 'Cola', 'drinks'), ('bread', 'Bread', NULL);
+	`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Monday and Sunday of one week, then the next Monday, at local noon
+	monday := time.Date(2024, 3, 4, 12, 0, 0, 0, time.Local)
+	sales := []struct {
+		id     string
+		soldAt time.Time
+		total  float64
+	}{
+		{"s1", monday, 3.50},
+		{"s2", monday.AddDate(0, 0, 6), 2.00},
+		{"s3", monday.AddDate(0, 0, 7), 1.00},
+	}
+	for _, sale := range sales {
+		if _, err := db.Exec(`INSERT INTO sales (id, cashier_id, total_amount, created_at) VALUES (?, 'alice', ?, ?)`,
+			sale.id, sale.total, sale.soldAt); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	_, err = db.Exec(`
+		INSERT INTO sale_items (sale_id, product_id, quantity, unit_price, cost_price) VALUES
+			('s1', 'cola', 2, 1.50, 0.75), ('s1', 'bread', 1, 0.50, 0.80),
+			('s2', 'cola', 1, 2.00, 0.75),
+			('s3', 'cola', 1, 0.50, 0.75)
+	`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo := NewAnalyticsRepository(db)
+	ctx := context.Background()
+
+	sums, err := repo.SumSaleLines(ctx, domain.SaleLineQuery{GroupBy: domain.GroupByProduct, Period: domain.GroupByWeek})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	weeks := make(map[string]*domain.SaleLineSum)
+	for _, sum := range sums {
+		weeks[sum.Key+" "+sum.Period.Format("2006-01-02")] = sum
+	}
+	if len(sums) != 3 {
+		t.Fatalf("expected cola in two weeks and bread in one, got %d groups", len(sums))
+	}
+	cola := weeks["cola 2024-03-04"]
+	if cola == nil || cola.Label != "Cola" || cola.Sales != 2 || cola.Quantity != 3 || cola.Revenue != 5.00 || cola.Cost != 2.25 {
+		t.Fatalf("expected cola's first week to total 2 sales of 3 for 5.00, got %+v", cola)
+	}
+	if weeks["cola 2024-03-11"] == nil || weeks["bread 2024-03-04"] == nil {
+		t.Fatalf("expected cola in the week of 11 March and bread in that of 4 March, got %v", weeks)
+	}
+
+	below, err := repo.SumSaleLines(ctx, domain.SaleLineQuery{CategoryIDs: []string{"drinks"}, BelowCost: true, GroupBy: domain.GroupByCategory})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(below) != 1 || below[0].Key != "drinks" || below[0].Label != "Drinks" || below[0].Revenue != 0.50 {
+		t.Fatalf("expected only cola's sale below cost, got %+v", below)
+	}
+
+	buckets, err := repo.SumSales(ctx, monday.Add(-time.Hour), monday.AddDate(0, 0, 8), domain.GroupByWeek)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(buckets) != 2 || !buckets[0].Start.Equal(monday.Add(-12*time.Hour)) ||
+		buckets[0].Transactions != 2 || buckets[0].Revenue != 5.50 || buckets[0].Units != 4 {
+		t.Fatalf("expected two sales totalling 5.50 in the first week, got %+v", buckets)
+	}
+}
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+// associationOrder maps report metrics to their sort order.
+var associationOrder = map[string]string{
+	"lift":       "a.lift DESC, a.confidence DESC",
+	"confidence": "a.confidence DESC, a.lift DESC",
+	"support":    "a.support DESC, a.lift DESC",
+}
+
+// AssociationRepository implements the product association repository using SQLite.
+type AssociationRepository struct {
+	db sqlx.ExtContext
+}
+
+// NewAssociationRepository creates a new product association repository instance.
+func NewAssociationRepository(db sqlx.ExtContext) *AssociationRepository {
+	return &AssociationRepository{db: db}
+}
+
+// associationRow is a database row representation for product associations.
+type associationRow struct {
+	ProductID           string         `db:"product_id"`
+	ProductName         sql.NullString `db:"product_name"`
+	AssociatedProductID string         `db:"associated_product_id"`
+	AssociatedName      sql.NullString `db:"associated_name"`
+	PairCount           int            `db:"pair_count"`
+	Support             float64        `db:"support"`
+	Confidence          float64        `db:"confidence"`
+	Lift                float64        `db:"lift"`
+	ComputedAt          time.Time      `db:"computed_at"`
+}
+
+// associationSelect selects associations with the names of both products.
+const associationSelect = `
+	SELECT a.product_id, p.name AS product_name, a.associated_product_id, q.name AS associated_name,
+		a.pair_count, a.support, a.confidence, a.lift, a.computed_at
+	FROM product_associations a
+	LEFT JOIN products p ON p.id = a.product_id
+	LEFT JOIN products q ON q.id = a.associated_product_id
+`
+
+// DeleteAll removes every stored association ahead of a recompute.
+func (r *AssociationRepository) DeleteAll(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM product_associations`)
+	return err
+}
+
+// DeleteByProduct removes the associations a product takes part in on either side.
+func (r *AssociationRepository) DeleteByProduct(ctx context.Context, productID string) error {
+	query := `DELETE FROM product_associations WHERE product_id = ? OR associated_product_id = ?`
+	_, err := r.db.ExecContext(ctx, query, productID, productID)
+	return err
+}
+
+// Create inserts a new product association.
+func (r *AssociationRepository) Create(ctx context.Context, association *domain.ProductAssociation) error {
+	query := `
+		INSERT INTO product_associations (product_id, associated_product_id, pair_count, support, confidence, lift, computed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		association.ProductID,
+		association.AssociatedProductID,
+		association.PairCount,
+		association.Support,
+		association.Confidence,
+		association.Lift,
+		association.ComputedAt,
+	)
+
+	return err
+}
+
+// ListForProduct retrieves the products most often bought with a product,
+// strongest association first.
+func (r *AssociationRepository) ListForProduct(ctx context.Context, productID string, limit int) ([]*domain.ProductAssociation, error) {
+	query := associationSelect + ` WHERE a.product_id = ? ORDER BY a.lift DESC, a.confidence DESC LIMIT ?`
+	return r.list(ctx, query, productID, limit)
+}
+
+// ListTop retrieves the strongest associations by lift, confidence or support.
+func (r *AssociationRepository) ListTop(ctx context.Context, metric string, limit int) ([]*domain.ProductAssociation, error) {
+	order, ok := associationOrder[metric]
+	if !ok {
+		order = associationOrder["lift"]
+	}
+	query := associationSelect + ` ORDER BY ` + order + `, a.product_id LIMIT ?`
+	return r.list(ctx, query, limit)
+}
+
+// list runs an association query and converts the rows.
+func (r *AssociationRepository) list(ctx context.Context, query string, args ...interface{}) ([]*domain.ProductAssociation, error) {
+	var rows []associationRow
+	err := sqlx.SelectContext(ctx, r.db, &rows, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	associations := make([]*domain.ProductAssociation, 0, len(rows))
+	for i := range rows {
+		associations = append(associations, r.toDomain(&rows[i]))
+	}
+
+	return associations, nil
+}
+
+// toDomain converts a database row to a domain product association.
+func (r *AssociationRepository) toDomain(row *associationRow) *domain.ProductAssociation {
+	return &domain.ProductAssociation{
+		ProductID:           row.ProductID,
+		ProductName:         row.ProductName.String,
+		AssociatedProductID: row.AssociatedProductID,
+		AssociatedName:      row.AssociatedName.String,
+		PairCount:           row.PairCount,
+		Support:             row.Support,
+		Confidence:          row.Confidence,
+		Lift:                row.Lift,
+		ComputedAt:          row.ComputedAt,
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -90,26 +91,45 @@ func (r *AuditLogRepository) GetLastLog(ctx context.Context) (*domain.AuditLog,
 	return r.toDomain(&row)
 }
 
-// List retrieves audit logs with pagination.
-func (r *AuditLogRepository) List(ctx context.Context, limit, offset int) ([]*domain.AuditLog, error) {
-	query := `SELECT * FROM audit_logs ORDER BY id DESC LIMIT ? OFFSET ?`
+// List retrieves a page of audit logs, newest first by default, with the
+// number of logs across every page.
+func (r *AuditLogRepository) List(ctx context.Context, req domain.PageRequest) (*domain.AuditLogPage, error) {
+	var total int
+	if err := r.db.QueryRowxContext(ctx, `SELECT COUNT(*) FROM audit_logs`).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	page := newKeysetPage(req, `id`, `id`, req.Order != domain.SortAsc)
+	query := `SELECT * FROM audit_logs`
+	var args []interface{}
+	if clause, keyArgs := page.condition(); clause != "" {
+		query += ` WHERE ` + clause
+		args = keyArgs
+	}
+	orderClause, orderArgs := page.orderAndLimit()
 
 	var rows []auditLogRow
-	err := sqlx.SelectContext(ctx, r.db, &rows, query, limit, offset)
+	err := sqlx.SelectContext(ctx, r.db, &rows, query+orderClause, append(args, orderArgs...)...)
 	if err != nil {
 		return nil, err
 	}
 
-	logs := make([]*domain.AuditLog, 0, len(rows))
-	for _, row := range rows {
-		log, err := r.toDomain(&row)
+	keys := make([]domain.PageKey, len(rows))
+	for i, row := range rows {
+		keys[i] = domain.PageKey{Value: row.ID, ID: strconv.FormatInt(row.ID, 10)}
+	}
+	order, info := page.info(keys, total)
+
+	result := &domain.AuditLogPage{Logs: make([]*domain.AuditLog, 0, len(order)), PageInfo: info}
+	for _, i := range order {
+		log, err := r.toDomain(&rows[i])
 		if err != nil {
 			return nil, err
 		}
-		logs = append(logs, log)
+		result.Logs = append(result.Logs, log)
 	}
 
-	return logs, nil
+	return result, nil
 }
 
 // VerifyChain verifies the integrity of the audit log chain.
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+// BarcodeRepository implements the product barcode repository using SQLite.
+type BarcodeRepository struct {
+	db sqlx.ExtContext
+}
+
+// NewBarcodeRepository creates a new product barcode repository instance.
+func NewBarcodeRepository(db sqlx.ExtContext) *BarcodeRepository {
+	return &BarcodeRepository{db: db}
+}
+
+// barcodeRow is a database row representation for product barcodes.
+type barcodeRow struct {
+	GTIN      string    `db:"gtin"`
+	Code      string    `db:"code"`
+	ProductID string    `db:"product_id"`
+	Format    string    `db:"format"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Create inserts a new product barcode.
+func (r *BarcodeRepository) Create(ctx context.Context, barcode *domain.ProductBarcode) error {
+	query := `INSERT INTO product_barcodes (gtin, code, product_id, format, created_at) VALUES (?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		barcode.GTIN,
+		barcode.Code,
+		barcode.ProductID,
+		barcode.Format,
+		barcode.CreatedAt,
+	)
+	return err
+}
+
+// GetByGTIN retrieves a barcode by its 14-digit GTIN using the primary key.
+// It returns nil without error when the code is not assigned.
+func (r *BarcodeRepository) GetByGTIN(ctx context.Context, gtin string) (*domain.ProductBarcode, error) {
+	query := `SELECT * FROM product_barcodes WHERE gtin = ?`
+
+	var row barcodeRow
+	err := sqlx.GetContext(ctx, r.db, &row, query, gtin)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&row), nil
+}
+
+// ListByProduct retrieves every barcode assigned to a product.
+func (r *BarcodeRepository) ListByProduct(ctx context.Context, productID string) ([]*domain.ProductBarcode, error) {
+	query := `SELECT * FROM product_barcodes WHERE product_id = ? ORDER BY created_at ASC`
+
+	var rows []barcodeRow
+	err := sqlx.SelectContext(ctx, r.db, &rows, query, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	barcodes := make([]*domain.ProductBarcode, 0, len(rows))
+	for i := range rows {
+		barcodes = append(barcodes, r.toDomain(&rows[i]))
+	}
+
+	return barcodes, nil
+}
+
+// Delete removes a barcode from a product.
+func (r *BarcodeRepository) Delete(ctx context.Context, productID, gtin string) error {
+	query := `DELETE FROM product_barcodes WHERE product_id = ? AND gtin = ?`
+
+	result, err := r.db.ExecContext(ctx, query, productID, gtin)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errors.New("barcode not found")
+	}
+
+	return nil
+}
+
+// toDomain converts a database row to a domain product barcode.
+func (r *BarcodeRepository) toDomain(row *barcodeRow) *domain.ProductBarcode {
+	return &domain.ProductBarcode{
+		Code:      row.Code,
+		GTIN:      row.GTIN,
+		ProductID: row.ProductID,
+		Format:    row.Format,
+		CreatedAt: row.CreatedAt,
+	}
+}
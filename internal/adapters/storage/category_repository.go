@@ -5,9 +5,12 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
 )
 
 // CategoryRepository implements the category repository using SQLite.
@@ -20,23 +23,60 @@ func NewCategoryRepository(db sqlx.ExtContext) *CategoryRepository {
 	return &CategoryRepository{db: db}
 }
 
+// maxCategoryDepth bounds tree walks, so that a cycle cannot recurse forever.
+const maxCategoryDepth = 64
+
+// categorySubtreeQuery selects the IDs of a category and every category
+// below it. It takes the category ID and maxCategoryDepth as arguments.
+const categorySubtreeQuery = `
+	WITH RECURSIVE subtree(id, depth) AS (
+		SELECT ?, 0
+		UNION
+		SELECT c.id, subtree.depth + 1
+		FROM categories c
+		JOIN subtree ON c.parent_id = subtree.id
+		WHERE subtree.depth < ?
+	)
+	SELECT id FROM subtree`
+
 // categoryRow is a database row representation for categories.
 type categoryRow struct {
 	ID                   string         `db:"id"`
 	Name                 string         `db:"name"`
 	AttributeDefinitions sql.NullString `db:"attribute_definitions"`
+	Version              int            `db:"version"`
+	ParentID             sql.NullString `db:"parent_id"`
 }
 
-// Create creates a new category in the database.
+// categoryVersionRow is a database row representation for category versions.
+type categoryVersionRow struct {
+	CategoryID           string         `db:"category_id"`
+	Version              int            `db:"version"`
+	Name                 string         `db:"name"`
+	AttributeDefinitions sql.NullString `db:"attribute_definitions"`
+	CreatedAt            time.Time      `db:"created_at"`
+}
+
+// Create creates a new category in the database as its first version.
 func (r *CategoryRepository) Create(ctx context.Context, category *domain.Category) error {
 	attrsJSON, err := json.Marshal(category.AttributeDefinitions)
 	if err != nil {
 		return err
 	}
 
-	query := `INSERT INTO categories (id, name, attribute_definitions) VALUES (?, ?, ?)`
-	_, err = r.db.ExecContext(ctx, query, category.ID, category.Name, string(attrsJSON))
-	return err
+	if category.Version == 0 {
+		category.Version = 1
+	}
+
+	query := `INSERT INTO categories (id, name, parent_id, attribute_definitions, version) VALUES (?, ?, ?, ?, ?)`
+	_, err = r.db.ExecContext(ctx, query, category.ID, category.Name,
+		sql.NullString{String: category.ParentID, Valid: category.ParentID != ""},
+		string(attrsJSON), category.Version)
+	if err != nil {
+		return err
+	}
+
+	return r.createVersion(ctx, category, string(attrsJSON))
 }
 
 // GetByID retrieves a category by its ID.
@@ -47,7 +87,7 @@ func (r *CategoryRepository) GetByID(ctx context.Context, id string) (*domain.Ca
 	err := sqlx.GetContext(ctx, r.db, &row, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("category not found")
+			return nil, fmt.Errorf("category %w", ports.ErrNotFound)
 		}
 		return nil, err
 	}
@@ -55,12 +95,186 @@ func (r *CategoryRepository) GetByID(ctx context.Context, id string) (*domain.Ca
 	return r.toDomain(&row)
 }
 
-// List retrieves all categories with pagination.
-func (r *CategoryRepository) List(ctx context.Context, limit, offset int) ([]*domain.Category, error) {
-	query := `SELECT * FROM categories ORDER BY name LIMIT ? OFFSET ?`
+// List retrieves a page of categories sorted by name, with the number of
+// categories across every page.
+func (r *CategoryRepository) List(ctx context.Context, req domain.PageRequest) (*domain.CategoryPage, error) {
+	var total int
+	if err := r.db.QueryRowxContext(ctx, `SELECT COUNT(*) FROM categories`).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	page := newKeysetPage(req, `name`, `id`, req.Order == domain.SortDesc)
+	query := `SELECT * FROM categories`
+	var args []interface{}
+	if clause, keyArgs := page.condition(); clause != "" {
+		query += ` WHERE ` + clause
+		args = keyArgs
+	}
+	orderClause, orderArgs := page.orderAndLimit()
+	categories, err := r.selectCategories(ctx, query+orderClause, append(args, orderArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]domain.PageKey, len(categories))
+	for i, category := range categories {
+		keys[i] = domain.PageKey{Value: category.Name, ID: category.ID}
+	}
+	order, info := page.info(keys, total)
+
+	result := &domain.CategoryPage{Categories: make([]*domain.Category, 0, len(order)), PageInfo: info}
+	for _, i := range order {
+		result.Categories = append(result.Categories, categories[i])
+	}
+	return result, nil
+}
 
+// Update saves a category's name, parent and attribute definitions under
+// the category's Version and records the revision.
+func (r *CategoryRepository) Update(ctx context.Context, category *domain.Category) error {
+	attrsJSON, err := json.Marshal(category.AttributeDefinitions)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE categories SET name = ?, parent_id = ?, attribute_definitions = ?, version = ? WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, query, category.Name,
+		sql.NullString{String: category.ParentID, Valid: category.ParentID != ""},
+		string(attrsJSON), category.Version, category.ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("category %w", ports.ErrNotFound)
+	}
+
+	return r.createVersion(ctx, category, string(attrsJSON))
+}
+
+// Delete deletes a category and its version history.
+func (r *CategoryRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM categories WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("category %w", ports.ErrNotFound)
+	}
+
+	_, err = r.db.ExecContext(ctx, `DELETE FROM category_versions WHERE category_id = ?`, id)
+	return err
+}
+
+// ListVersions retrieves every revision of a category, newest first.
+func (r *CategoryRepository) ListVersions(ctx context.Context, categoryID string) ([]*domain.CategoryVersion, error) {
+	query := `SELECT * FROM category_versions WHERE category_id = ? ORDER BY version DESC`
+
+	var rows []categoryVersionRow
+	err := sqlx.SelectContext(ctx, r.db, &rows, query, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]*domain.CategoryVersion, 0, len(rows))
+	for _, row := range rows {
+		attrs, err := r.parseAttributes(row.AttributeDefinitions)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, &domain.CategoryVersion{
+			CategoryID:           row.CategoryID,
+			Version:              row.Version,
+			Name:                 row.Name,
+			AttributeDefinitions: attrs,
+			CreatedAt:            row.CreatedAt,
+		})
+	}
+
+	return versions, nil
+}
+
+// ListAncestors retrieves a category and its ancestors, from the top level
+// down to the category itself.
+func (r *CategoryRepository) ListAncestors(ctx context.Context, id string) ([]*domain.Category, error) {
+	query := `
+		WITH RECURSIVE chain(id, parent_id, depth) AS (
+			SELECT id, parent_id, 0 FROM categories WHERE id = ?
+			UNION
+			SELECT c.id, c.parent_id, chain.depth + 1
+			FROM categories c
+			JOIN chain ON c.id = chain.parent_id
+			WHERE chain.depth < ?
+		)
+		SELECT c.* FROM chain JOIN categories c ON c.id = chain.id
+		ORDER BY chain.depth DESC
+	`
+
+	categories, err := r.selectCategories(ctx, query, id, maxCategoryDepth)
+	if err != nil {
+		return nil, err
+	}
+	if len(categories) == 0 {
+		return nil, fmt.Errorf("category %w", ports.ErrNotFound)
+	}
+
+	return categories, nil
+}
+
+// ListDescendants retrieves every category below a category in the tree,
+// nearest first.
+func (r *CategoryRepository) ListDescendants(ctx context.Context, id string) ([]*domain.Category, error) {
+	query := `
+		WITH RECURSIVE subtree(id, depth) AS (
+			SELECT id, 0 FROM categories WHERE parent_id = ?
+			UNION
+			SELECT c.id, subtree.depth + 1
+			FROM categories c
+			JOIN subtree ON c.parent_id = subtree.id
+			WHERE subtree.depth < ?
+		)
+		SELECT c.* FROM subtree JOIN categories c ON c.id = subtree.id
+		GROUP BY c.id
+		ORDER BY MIN(subtree.depth), c.name
+	`
+
+	return r.selectCategories(ctx, query, id, maxCategoryDepth)
+}
+
+// CountReferences returns how many child categories, products, sale
+// restrictions and price list rules refer to a category.
+func (r *CategoryRepository) CountReferences(ctx context.Context, id string) (int, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM categories WHERE parent_id = ?) +
+			(SELECT COUNT(*) FROM products WHERE category_id = ?) +
+			(SELECT COUNT(*) FROM sale_restrictions WHERE category_id = ?) +
+			(SELECT COUNT(*) FROM price_list_rules WHERE category_id = ?)
+	`
+
+	var count int
+	err := sqlx.GetContext(ctx, r.db, &count, query, id, id, id, id)
+	return count, err
+}
+
+// selectCategories runs a category query and converts the rows.
+func (r *CategoryRepository) selectCategories(ctx context.Context, query string, args ...interface{}) ([]*domain.Category, error) {
 	var rows []categoryRow
-	err := sqlx.SelectContext(ctx, r.db, &rows, query, limit, offset)
+	err := sqlx.SelectContext(ctx, r.db, &rows, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -77,23 +291,42 @@ func (r *CategoryRepository) List(ctx context.Context, limit, offset int) ([]*do
 	return categories, nil
 }
 
+// createVersion records a category's current name and attribute definitions
+// as a revision.
+func (r *CategoryRepository) createVersion(ctx context.Context, category *domain.Category, attrsJSON string) error {
+	query := `
+		INSERT INTO category_versions (category_id, version, name, attribute_definitions, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query, category.ID, category.Version, category.Name, attrsJSON, time.Now())
+	return err
+}
+
 // toDomain converts a database row to a domain entity.
 func (r *CategoryRepository) toDomain(row *categoryRow) (*domain.Category, error) {
-	category := &domain.Category{
-		ID:   row.ID,
-		Name: row.Name,
+	attrs, err := r.parseAttributes(row.AttributeDefinitions)
+	if err != nil {
+		return nil, err
 	}
 
-	if row.AttributeDefinitions.Valid && row.AttributeDefinitions.String != "" {
-		var attrs []domain.AttributeDefinition
-		err := json.Unmarshal([]byte(row.AttributeDefinitions.String), &attrs)
-		if err != nil {
-			return nil, err
-		}
-		category.AttributeDefinitions = attrs
-	} else {
-		category.AttributeDefinitions = []domain.AttributeDefinition{}
+	return &domain.Category{
+		ID:                   row.ID,
+		Name:                 row.Name,
+		ParentID:             row.ParentID.String,
+		AttributeDefinitions: attrs,
+		Version:              row.Version,
+	}, nil
+}
+
+// parseAttributes decodes a JSON array of attribute definitions.
+func (r *CategoryRepository) parseAttributes(value sql.NullString) ([]domain.AttributeDefinition, error) {
+	if !value.Valid || value.String == "" {
+		return []domain.AttributeDefinition{}, nil
 	}
 
-	return category, nil
+	var attrs []domain.AttributeDefinition
+	if err := json.Unmarshal([]byte(value.String), &attrs); err != nil {
+		return nil, err
+	}
+	return attrs, nil
 }
@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+// CostLayerRepository implements the cost layer repository using SQLite.
+type CostLayerRepository struct {
+	db sqlx.ExtContext
+}
+
+// NewCostLayerRepository creates a new cost layer repository instance.
+func NewCostLayerRepository(db sqlx.ExtContext) *CostLayerRepository {
+	return &CostLayerRepository{db: db}
+}
+
+// costLayerRow is a database row representation for cost layers.
+type costLayerRow struct {
+	ID                string    `db:"id"`
+	ProductID         string    `db:"product_id"`
+	QuantityReceived  float64   `db:"quantity_received"`
+	QuantityRemaining float64   `db:"quantity_remaining"`
+	UnitCost          float64   `db:"unit_cost"`
+	ReceivedAt        time.Time `db:"received_at"`
+}
+
+// Create inserts a new cost layer.
+func (r *CostLayerRepository) Create(ctx context.Context, layer *domain.CostLayer) error {
+	query := `
+		INSERT INTO cost_layers (id, product_id, quantity_received, quantity_remaining, unit_cost, received_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		layer.ID,
+		layer.ProductID,
+		layer.QuantityReceived,
+		layer.QuantityRemaining,
+		layer.UnitCost,
+		layer.ReceivedAt,
+	)
+
+	return err
+}
+
+// ListOpen retrieves a product's layers with stock remaining, oldest first.
+func (r *CostLayerRepository) ListOpen(ctx context.Context, productID string) ([]*domain.CostLayer, error) {
+	query := `
+		SELECT * FROM cost_layers
+		WHERE product_id = ? AND quantity_remaining > 0
+		ORDER BY datetime(received_at), rowid
+	`
+
+	var rows []costLayerRow
+	err := sqlx.SelectContext(ctx, r.db, &rows, query, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	layers := make([]*domain.CostLayer, 0, len(rows))
+	for i := range rows {
+		layers = append(layers, r.toDomain(&rows[i]))
+	}
+
+	return layers, nil
+}
+
+// UpdateRemaining sets the quantity still on hand from a cost layer.
+func (r *CostLayerRepository) UpdateRemaining(ctx context.Context, id string, remaining float64) error {
+	query := `UPDATE cost_layers SET quantity_remaining = ? WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, query, remaining, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("cost layer not found")
+	}
+
+	return nil
+}
+
+// DeleteByProduct deletes a product's cost layers.
+func (r *CostLayerRepository) DeleteByProduct(ctx context.Context, productID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM cost_layers WHERE product_id = ?`, productID)
+	return err
+}
+
+// toDomain converts a database row to a domain cost layer.
+func (r *CostLayerRepository) toDomain(row *costLayerRow) *domain.CostLayer {
+	return &domain.CostLayer{
+		ID:                row.ID,
+		ProductID:         row.ProductID,
+		QuantityReceived:  row.QuantityReceived,
+		QuantityRemaining: row.QuantityRemaining,
+		UnitCost:          row.UnitCost,
+		ReceivedAt:        row.ReceivedAt,
+	}
+}
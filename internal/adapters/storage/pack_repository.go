@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+// PackRepository implements the product pack repository using SQLite.
+type PackRepository struct {
+	db sqlx.ExtContext
+}
+
+// NewPackRepository creates a new product pack repository instance.
+func NewPackRepository(db sqlx.ExtContext) *PackRepository {
+	return &PackRepository{db: db}
+}
+
+// packRow is a database row representation for product packs.
+type packRow struct {
+	ID           string         `db:"id"`
+	ProductID    string         `db:"product_id"`
+	Name         string         `db:"name"`
+	Barcode      sql.NullString `db:"barcode"`
+	GTIN         sql.NullString `db:"gtin"`
+	UnitsPerPack float64        `db:"units_per_pack"`
+	Price        float64        `db:"price"`
+	CreatedAt    time.Time      `db:"created_at"`
+}
+
+// Create inserts a new product pack.
+func (r *PackRepository) Create(ctx context.Context, pack *domain.ProductPack) error {
+	query := `
+		INSERT INTO product_packs (id, product_id, name, barcode, units_per_pack, price, created_at, gtin)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		pack.ID,
+		pack.ProductID,
+		pack.Name,
+		sql.NullString{String: pack.Barcode, Valid: pack.Barcode != ""},
+		pack.UnitsPerPack,
+		pack.Price,
+		pack.CreatedAt,
+		sql.NullString{String: pack.GTIN, Valid: pack.GTIN != ""},
+	)
+
+	return err
+}
+
+// GetByID retrieves a product pack by its ID.
+func (r *PackRepository) GetByID(ctx context.Context, id string) (*domain.ProductPack, error) {
+	query := `SELECT * FROM product_packs WHERE id = ?`
+
+	var row packRow
+	err := sqlx.GetContext(ctx, r.db, &row, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("pack not found")
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&row), nil
+}
+
+// GetByGTIN retrieves the pack whose barcode has the given 14-digit GTIN.
+// It returns nil without error when no pack uses the barcode.
+func (r *PackRepository) GetByGTIN(ctx context.Context, gtin string) (*domain.ProductPack, error) {
+	query := `SELECT * FROM product_packs WHERE gtin = ?`
+
+	var row packRow
+	err := sqlx.GetContext(ctx, r.db, &row, query, gtin)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&row), nil
+}
+
+// ListByProduct retrieves every pack defined for a product, smallest first.
+func (r *PackRepository) ListByProduct(ctx context.Context, productID string) ([]*domain.ProductPack, error) {
+	query := `SELECT * FROM product_packs WHERE product_id = ? ORDER BY units_per_pack ASC`
+
+	var rows []packRow
+	err := sqlx.SelectContext(ctx, r.db, &rows, query, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	packs := make([]*domain.ProductPack, 0, len(rows))
+	for i := range rows {
+		packs = append(packs, r.toDomain(&rows[i]))
+	}
+
+	return packs, nil
+}
+
+// Delete deletes a product pack.
+func (r *PackRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM product_packs WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errors.New("pack not found")
+	}
+
+	return nil
+}
+
+// toDomain converts a database row to a domain product pack.
+func (r *PackRepository) toDomain(row *packRow) *domain.ProductPack {
+	return &domain.ProductPack{
+		ID:           row.ID,
+		ProductID:    row.ProductID,
+		Name:         row.Name,
+		Barcode:      row.Barcode.String,
+		GTIN:         row.GTIN.String,
+		UnitsPerPack: row.UnitsPerPack,
+		Price:        row.Price,
+		CreatedAt:    row.CreatedAt,
+	}
+}
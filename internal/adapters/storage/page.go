@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+// keysetPage pages a listing sorted by an expression, with an ID column to
+// break ties. Pages start from the key of a cursor when the request has one,
+// which stays stable as rows are inserted, and from an offset otherwise.
+type keysetPage struct {
+	sortExpr string
+	idExpr   string
+	desc     bool
+	limit    int
+	offset   int
+	after    *domain.PageKey
+	before   *domain.PageKey
+}
+
+// newKeysetPage creates a keyset page for a request, defaulting its limit
+// to 10.
+func newKeysetPage(req domain.PageRequest, sortExpr, idExpr string, desc bool) keysetPage {
+	page := keysetPage{sortExpr: sortExpr, idExpr: idExpr, desc: desc, limit: req.Limit, offset: req.Offset, after: req.After, before: req.Before}
+	if page.limit <= 0 {
+		page.limit = 10
+	}
+	if page.offset < 0 || page.after != nil || page.before != nil {
+		page.offset = 0
+	}
+	return page
+}
+
+// condition returns the condition for rows beyond the request's key, or an
+// empty string when there is none.
+func (k keysetPage) condition() (string, []interface{}) {
+	key, forward := k.after, true
+	if k.before != nil {
+		key, forward = k.before, false
+	}
+	if key == nil {
+		return "", nil
+	}
+
+	op := ">"
+	if k.desc == forward {
+		op = "<"
+	}
+	clause := fmt.Sprintf(`(%[1]s %[3]s ? OR (%[1]s = ? AND %[2]s %[3]s ?))`, k.sortExpr, k.idExpr, op)
+	return clause, []interface{}{key.Value, key.Value, key.ID}
+}
+
+// orderAndLimit returns the ORDER BY and LIMIT clauses. One row more than
+// the limit is fetched to tell whether another page follows, and a page
+// before a key is fetched in reverse from it.
+func (k keysetPage) orderAndLimit() (string, []interface{}) {
+	dir := "ASC"
+	if k.desc != (k.before != nil) {
+		dir = "DESC"
+	}
+	clause := fmt.Sprintf(` ORDER BY %s %s, %s %s LIMIT ? OFFSET ?`, k.sortExpr, dir, k.idExpr, dir)
+	return clause, []interface{}{k.limit + 1, k.offset}
+}
+
+// info works out a fetched page from the keys of its rows in fetch order,
+// returning the indexes of the rows on the page in listing order.
+func (k keysetPage) info(keys []domain.PageKey, total int) ([]int, domain.PageInfo) {
+	more := len(keys) > k.limit
+	if more {
+		keys = keys[:k.limit]
+	}
+
+	order := make([]int, len(keys))
+	for i := range order {
+		order[i] = i
+	}
+	info := domain.PageInfo{Total: total}
+	if k.before != nil {
+		for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+			order[i], order[j] = order[j], order[i]
+		}
+		info.HasPrev = more
+		info.HasNext = true
+	} else {
+		info.HasNext = more
+		info.HasPrev = k.after != nil || k.offset > 0
+	}
+
+	if len(order) > 0 {
+		first, last := keys[order[0]], keys[order[len(order)-1]]
+		info.First = &first
+		info.Last = &last
+	}
+	return order, info
+}
@@ -0,0 +1,233 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// PriceListRepository implements the price list repository using SQLite.
+type PriceListRepository struct {
+	db sqlx.ExtContext
+}
+
+// NewPriceListRepository creates a new price list repository instance.
+func NewPriceListRepository(db sqlx.ExtContext) *PriceListRepository {
+	return &PriceListRepository{db: db}
+}
+
+// priceListRow is a database row representation for price lists.
+type priceListRow struct {
+	ID          string    `db:"id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// priceListRuleRow is a database row representation for price list rules.
+type priceListRuleRow struct {
+	ID              string          `db:"id"`
+	PriceListID     string          `db:"price_list_id"`
+	ProductID       sql.NullString  `db:"product_id"`
+	CategoryID      sql.NullString  `db:"category_id"`
+	MinQuantity     float64         `db:"min_quantity"`
+	Price           sql.NullFloat64 `db:"price"`
+	DiscountPercent sql.NullFloat64 `db:"discount_percent"`
+	CreatedAt       time.Time       `db:"created_at"`
+}
+
+// Create inserts a new price list.
+func (r *PriceListRepository) Create(ctx context.Context, list *domain.PriceList) error {
+	query := `INSERT INTO price_lists (id, name, description, created_at) VALUES (?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		list.ID,
+		list.Name,
+		list.Description,
+		list.CreatedAt,
+	)
+	return err
+}
+
+// GetByID retrieves a price list by its ID, without its rules.
+func (r *PriceListRepository) GetByID(ctx context.Context, id string) (*domain.PriceList, error) {
+	query := `SELECT * FROM price_lists WHERE id = ?`
+
+	var row priceListRow
+	err := sqlx.GetContext(ctx, r.db, &row, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("price list %w", ports.ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&row), nil
+}
+
+// List retrieves price lists with pagination, ordered by name.
+func (r *PriceListRepository) List(ctx context.Context, limit, offset int) ([]*domain.PriceList, error) {
+	query := `SELECT * FROM price_lists ORDER BY name LIMIT ? OFFSET ?`
+
+	var rows []priceListRow
+	err := sqlx.SelectContext(ctx, r.db, &rows, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	lists := make([]*domain.PriceList, 0, len(rows))
+	for _, row := range rows {
+		lists = append(lists, r.toDomain(&row))
+	}
+
+	return lists, nil
+}
+
+// Delete deletes a price list and its rules.
+func (r *PriceListRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM price_lists WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("price list %w", ports.ErrNotFound)
+	}
+
+	return nil
+}
+
+// CreateRule inserts a new price list rule.
+func (r *PriceListRepository) CreateRule(ctx context.Context, rule *domain.PriceListRule) error {
+	query := `
+		INSERT INTO price_list_rules (id, price_list_id, product_id, category_id, min_quantity, price, discount_percent, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		rule.ID,
+		rule.PriceListID,
+		sql.NullString{String: rule.ProductID, Valid: rule.ProductID != ""},
+		sql.NullString{String: rule.CategoryID, Valid: rule.CategoryID != ""},
+		rule.MinQuantity,
+		toNullFloat(rule.Price),
+		toNullFloat(rule.DiscountPercent),
+		rule.CreatedAt,
+	)
+
+	return err
+}
+
+// ListRules retrieves every rule of a price list, grouped by product or
+// category with quantity breaks in ascending order.
+func (r *PriceListRepository) ListRules(ctx context.Context, priceListID string) ([]*domain.PriceListRule, error) {
+	query := `
+		SELECT * FROM price_list_rules
+		WHERE price_list_id = ?
+		ORDER BY COALESCE(product_id, ''), COALESCE(category_id, ''), min_quantity
+	`
+	return r.selectRules(ctx, query, priceListID)
+}
+
+// ListRulesFor retrieves the rules of a price list that apply to a product,
+// either directly or through one of the given categories, usually the
+// product's category and its ancestors.
+func (r *PriceListRepository) ListRulesFor(ctx context.Context, priceListID, productID string, categoryIDs []string) ([]*domain.PriceListRule, error) {
+	match := `product_id = ?`
+	args := []interface{}{priceListID, productID}
+	if len(categoryIDs) > 0 {
+		match += ` OR category_id IN (?` + strings.Repeat(`, ?`, len(categoryIDs)-1) + `)`
+		for _, id := range categoryIDs {
+			args = append(args, id)
+		}
+	}
+
+	query := `
+		SELECT * FROM price_list_rules
+		WHERE price_list_id = ? AND (` + match + `)
+		ORDER BY min_quantity
+	`
+	return r.selectRules(ctx, query, args...)
+}
+
+// DeleteRule deletes a rule from a price list.
+func (r *PriceListRepository) DeleteRule(ctx context.Context, priceListID, ruleID string) error {
+	query := `DELETE FROM price_list_rules WHERE price_list_id = ? AND id = ?`
+
+	result, err := r.db.ExecContext(ctx, query, priceListID, ruleID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errors.New("price list rule not found")
+	}
+
+	return nil
+}
+
+// DeleteRulesByProduct deletes the rules for a product from every price list.
+func (r *PriceListRepository) DeleteRulesByProduct(ctx context.Context, productID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM price_list_rules WHERE product_id = ?`, productID)
+	return err
+}
+
+// selectRules runs a price list rule query and converts the rows.
+func (r *PriceListRepository) selectRules(ctx context.Context, query string, args ...interface{}) ([]*domain.PriceListRule, error) {
+	var rows []priceListRuleRow
+	err := sqlx.SelectContext(ctx, r.db, &rows, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]*domain.PriceListRule, 0, len(rows))
+	for _, row := range rows {
+		rule := &domain.PriceListRule{
+			ID:          row.ID,
+			PriceListID: row.PriceListID,
+			ProductID:   row.ProductID.String,
+			CategoryID:  row.CategoryID.String,
+			MinQuantity: row.MinQuantity,
+			CreatedAt:   row.CreatedAt,
+		}
+		if row.Price.Valid {
+			price := row.Price.Float64
+			rule.Price = &price
+		}
+		if row.DiscountPercent.Valid {
+			percent := row.DiscountPercent.Float64
+			rule.DiscountPercent = &percent
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// toDomain converts a database row to a domain price list.
+func (r *PriceListRepository) toDomain(row *priceListRow) *domain.PriceList {
+	return &domain.PriceList{
+		ID:          row.ID,
+		Name:        row.Name,
+		Description: row.Description,
+		CreatedAt:   row.CreatedAt,
+	}
+}
@@ -0,0 +1,268 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+// PriceRepository implements the price history and scheduled price change
+// repository using SQLite.
+type PriceRepository struct {
+	db sqlx.ExtContext
+}
+
+// NewPriceRepository creates a new price repository instance.
+func NewPriceRepository(db sqlx.ExtContext) *PriceRepository {
+	return &PriceRepository{db: db}
+}
+
+// priceHistoryRow is a database row representation for price history.
+type priceHistoryRow struct {
+	ID        int64     `db:"id"`
+	ProductID string    `db:"product_id"`
+	Price     float64   `db:"price"`
+	ChangedAt time.Time `db:"changed_at"`
+}
+
+// scheduledPriceChangeRow is a database row representation for scheduled price changes.
+type scheduledPriceChangeRow struct {
+	ID            string          `db:"id"`
+	ProductID     string          `db:"product_id"`
+	Price         float64         `db:"price"`
+	EffectiveFrom time.Time       `db:"effective_from"`
+	EffectiveTo   sql.NullTime    `db:"effective_to"`
+	Reason        string          `db:"reason"`
+	Status        string          `db:"status"`
+	PreviousPrice sql.NullFloat64 `db:"previous_price"`
+	CreatedBy     string          `db:"created_by"`
+	CreatedAt     time.Time       `db:"created_at"`
+	AppliedAt     sql.NullTime    `db:"applied_at"`
+	RevertedAt    sql.NullTime    `db:"reverted_at"`
+}
+
+// ListHistory retrieves a product's price history with pagination, newest first.
+func (r *PriceRepository) ListHistory(ctx context.Context, productID string, limit, offset int) ([]*domain.PriceHistoryEntry, error) {
+	query := `SELECT * FROM price_history WHERE product_id = ? ORDER BY changed_at DESC, id DESC LIMIT ? OFFSET ?`
+
+	var rows []priceHistoryRow
+	err := sqlx.SelectContext(ctx, r.db, &rows, query, productID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*domain.PriceHistoryEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, r.historyToDomain(&row))
+	}
+
+	return entries, nil
+}
+
+// GetPriceAt retrieves the history entry in effect for a product at the given time.
+func (r *PriceRepository) GetPriceAt(ctx context.Context, productID string, at time.Time) (*domain.PriceHistoryEntry, error) {
+	query := `
+		SELECT * FROM price_history
+		WHERE product_id = ? AND changed_at <= datetime(?)
+		ORDER BY changed_at DESC, id DESC
+		LIMIT 1
+	`
+
+	var row priceHistoryRow
+	err := sqlx.GetContext(ctx, r.db, &row, query, productID, at)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Product did not exist yet
+		}
+		return nil, err
+	}
+
+	return r.historyToDomain(&row), nil
+}
+
+// SetLatestChangeTime dates a product's most recent price history entry,
+// replacing the time the trigger recorded it with when the price actually
+// took effect.
+func (r *PriceRepository) SetLatestChangeTime(ctx context.Context, productID string, at time.Time) error {
+	query := `
+		UPDATE price_history SET changed_at = datetime(?)
+		WHERE id = (SELECT MAX(id) FROM price_history WHERE product_id = ?)
+	`
+	_, err := r.db.ExecContext(ctx, query, at, productID)
+	return err
+}
+
+// DeleteByProduct deletes a product's price history and scheduled price changes.
+func (r *PriceRepository) DeleteByProduct(ctx context.Context, productID string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM scheduled_price_changes WHERE product_id = ?`, productID); err != nil {
+		return err
+	}
+	_, err := r.db.ExecContext(ctx, `DELETE FROM price_history WHERE product_id = ?`, productID)
+	return err
+}
+
+// CreateScheduled inserts a new scheduled price change.
+func (r *PriceRepository) CreateScheduled(ctx context.Context, change *domain.ScheduledPriceChange) error {
+	query := `
+		INSERT INTO scheduled_price_changes (id, product_id, price, effective_from, effective_to, reason, status, previous_price, created_by, created_at, applied_at, reverted_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		change.ID,
+		change.ProductID,
+		change.Price,
+		change.EffectiveFrom,
+		toNullTime(change.EffectiveTo),
+		change.Reason,
+		change.Status,
+		toNullFloat(change.PreviousPrice),
+		change.CreatedBy,
+		change.CreatedAt,
+		toNullTime(change.AppliedAt),
+		toNullTime(change.RevertedAt),
+	)
+
+	return err
+}
+
+// GetScheduled retrieves a scheduled price change by its ID.
+func (r *PriceRepository) GetScheduled(ctx context.Context, id string) (*domain.ScheduledPriceChange, error) {
+	query := `SELECT * FROM scheduled_price_changes WHERE id = ?`
+
+	var row scheduledPriceChangeRow
+	err := sqlx.GetContext(ctx, r.db, &row, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("scheduled price change not found")
+		}
+		return nil, err
+	}
+
+	return r.scheduledToDomain(&row), nil
+}
+
+// ListScheduled retrieves scheduled price changes with pagination, ordered by
+// start. productID and status are optional filters.
+func (r *PriceRepository) ListScheduled(ctx context.Context, productID, status string, limit, offset int) ([]*domain.ScheduledPriceChange, error) {
+	query := `SELECT * FROM scheduled_price_changes WHERE 1=1`
+	var args []interface{}
+
+	if productID != "" {
+		query += ` AND product_id = ?`
+		args = append(args, productID)
+	}
+	if status != "" {
+		query += ` AND status = ?`
+		args = append(args, status)
+	}
+	query += ` ORDER BY datetime(effective_from), created_at LIMIT ? OFFSET ?`
+	args = append(args, limit, offset)
+
+	return r.selectScheduled(ctx, query, args...)
+}
+
+// ListOpenByProduct retrieves a product's pending and active scheduled price changes.
+func (r *PriceRepository) ListOpenByProduct(ctx context.Context, productID string) ([]*domain.ScheduledPriceChange, error) {
+	query := `
+		SELECT * FROM scheduled_price_changes
+		WHERE product_id = ? AND status IN (?, ?)
+		ORDER BY datetime(effective_from)
+	`
+	return r.selectScheduled(ctx, query, productID, domain.PriceChangeStatusPending, domain.PriceChangeStatusActive)
+}
+
+// ListDueToStart retrieves pending changes whose window has started by now,
+// oldest first.
+func (r *PriceRepository) ListDueToStart(ctx context.Context, now time.Time) ([]*domain.ScheduledPriceChange, error) {
+	query := `
+		SELECT * FROM scheduled_price_changes
+		WHERE status = ? AND datetime(effective_from) <= datetime(?)
+		ORDER BY datetime(effective_from), created_at
+	`
+	return r.selectScheduled(ctx, query, domain.PriceChangeStatusPending, now)
+}
+
+// ListDueToEnd retrieves active temporary changes whose window has ended by
+// now, oldest first.
+func (r *PriceRepository) ListDueToEnd(ctx context.Context, now time.Time) ([]*domain.ScheduledPriceChange, error) {
+	query := `
+		SELECT * FROM scheduled_price_changes
+		WHERE status = ? AND effective_to IS NOT NULL AND datetime(effective_to) <= datetime(?)
+		ORDER BY datetime(effective_to), created_at
+	`
+	return r.selectScheduled(ctx, query, domain.PriceChangeStatusActive, now)
+}
+
+// UpdateScheduled updates the mutable fields of a scheduled price change.
+func (r *PriceRepository) UpdateScheduled(ctx context.Context, change *domain.ScheduledPriceChange) error {
+	query := `UPDATE scheduled_price_changes SET status = ?, previous_price = ?, applied_at = ?, reverted_at = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query,
+		change.Status,
+		toNullFloat(change.PreviousPrice),
+		toNullTime(change.AppliedAt),
+		toNullTime(change.RevertedAt),
+		change.ID,
+	)
+	return err
+}
+
+// selectScheduled runs a scheduled price change query and converts the rows.
+func (r *PriceRepository) selectScheduled(ctx context.Context, query string, args ...interface{}) ([]*domain.ScheduledPriceChange, error) {
+	var rows []scheduledPriceChangeRow
+	err := sqlx.SelectContext(ctx, r.db, &rows, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*domain.ScheduledPriceChange, 0, len(rows))
+	for _, row := range rows {
+		changes = append(changes, r.scheduledToDomain(&row))
+	}
+
+	return changes, nil
+}
+
+// historyToDomain converts a price history row to a domain entity.
+func (r *PriceRepository) historyToDomain(row *priceHistoryRow) *domain.PriceHistoryEntry {
+	return &domain.PriceHistoryEntry{
+		ID:        row.ID,
+		ProductID: row.ProductID,
+		Price:     row.Price,
+		ChangedAt: row.ChangedAt,
+	}
+}
+
+// scheduledToDomain converts a scheduled price change row to a domain entity.
+func (r *PriceRepository) scheduledToDomain(row *scheduledPriceChangeRow) *domain.ScheduledPriceChange {
+	change := &domain.ScheduledPriceChange{
+		ID:            row.ID,
+		ProductID:     row.ProductID,
+		Price:         row.Price,
+		EffectiveFrom: row.EffectiveFrom,
+		EffectiveTo:   fromNullTime(row.EffectiveTo),
+		Reason:        row.Reason,
+		Status:        row.Status,
+		CreatedBy:     row.CreatedBy,
+		CreatedAt:     row.CreatedAt,
+		AppliedAt:     fromNullTime(row.AppliedAt),
+		RevertedAt:    fromNullTime(row.RevertedAt),
+	}
+	if row.PreviousPrice.Valid {
+		previous := row.PreviousPrice.Float64
+		change.PreviousPrice = &previous
+	}
+	return change
+}
+
+// toNullFloat converts an optional float to a nullable column value.
+func toNullFloat(f *float64) sql.NullFloat64 {
+	if f == nil {
+		return sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: *f, Valid: true}
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
 )
 
 // ProductRepository implements the product repository using SQLite.
@@ -31,11 +32,22 @@ type productRow struct {
 	SKU        string         `db:"sku"`
 	CategoryID sql.NullString `db:"category_id"`
 	BasePrice  float64        `db:"base_price"`
-	Quantity   int            `db:"quantity"`
+	Quantity   float64        `db:"quantity"`
 	CostPrice  float64        `db:"cost_price"`
 	Properties sql.NullString `db:"properties"`
 	CreatedAt  time.Time      `db:"created_at"`
 	UpdatedAt  time.Time      `db:"updated_at"`
+
+	QuarantinedQuantity float64 `db:"quarantined_quantity"`
+	UnitOfMeasure       string  `db:"unit_of_measure"`
+	QuantityPrecision   int     `db:"quantity_precision"`
+
+	LabelChangedAt sql.NullTime `db:"label_changed_at"`
+
+	PropertyOriginals sql.NullString `db:"property_originals"`
+
+	Description string `db:"description"`
+	SearchTerms string `db:"search_terms"`
 }
 
 // Create creates a new product in the database.
@@ -46,9 +58,15 @@ func (r *ProductRepository) Create(ctx context.Context, product *domain.Product)
 		return err
 	}
 
+	originalsJSON, err := marshalPropertyOriginals(product.PropertyOriginals)
+	if err != nil {
+		return err
+	}
+
 	query := `
-		INSERT INTO products (id, name, sku, category_id, base_price, quantity, cost_price, properties, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO products (id, name, sku, category_id, base_price, quantity, cost_price, properties, created_at, updated_at, quarantined_quantity, unit_of_measure, quantity_precision,
+			property_originals, description, search_terms)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	_, err = r.db.ExecContext(ctx, query,
@@ -62,6 +80,12 @@ func (r *ProductRepository) Create(ctx context.Context, product *domain.Product)
 		string(propertiesJSON),
 		product.CreatedAt,
 		product.UpdatedAt,
+		product.QuarantinedQuantity,
+		unitOfMeasureOrDefault(product.UnitOfMeasure),
+		product.QuantityPrecision,
+		originalsJSON,
+		product.Description,
+		product.SearchTerms,
 	)
 
 	return err
@@ -75,7 +99,7 @@ func (r *ProductRepository) GetByID(ctx context.Context, id string) (*domain.Pro
 	err := sqlx.GetContext(ctx, r.db, &row, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("product not found")
+			return nil, fmt.Errorf("product %w", ports.ErrNotFound)
 		}
 		return nil, err
 	}
@@ -91,7 +115,7 @@ func (r *ProductRepository) GetBySKU(ctx context.Context, sku string) (*domain.P
 	err := sqlx.GetContext(ctx, r.db, &row, query, sku)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("product not found")
+			return nil, fmt.Errorf("product %w", ports.ErrNotFound)
 		}
 		return nil, err
 	}
@@ -99,36 +123,328 @@ func (r *ProductRepository) GetBySKU(ctx context.Context, sku string) (*domain.P
 	return r.toDomain(&row)
 }
 
-// List retrieves all products with pagination.
-func (r *ProductRepository) List(ctx context.Context, limit, offset int) ([]*domain.Product, error) {
-	query := `SELECT * FROM products ORDER BY created_at DESC LIMIT ? OFFSET ?`
+// validPropertyKey matches only safe JSON key names (alphanumeric + underscore).
+var validPropertyKey = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+
+// productSorts maps each product sort to the expression it orders by and
+// whether it runs descending by default. datetime() normalises
+// trigger-written and driver-written timestamps.
+var productSorts = map[string]struct {
+	expr string
+	desc bool
+}{
+	domain.SortRelevance: {`COALESCE(fts.rank, 0)`, false}, // Misspelt matches rank last
+	domain.SortName:      {`p.name COLLATE NOCASE`, false},
+	domain.SortPrice:     {`p.base_price`, false},
+	domain.SortQuantity:  {`p.quantity`, false},
+	domain.SortUpdatedAt: {`datetime(p.updated_at)`, true},
+	domain.SortCreatedAt: {`datetime(p.created_at)`, true},
+}
+
+// productRank ranks full-text matches by bm25, weighting the products_fts
+// columns so that a match in the name counts most, then the SKU, then a
+// searchable attribute, then the description.
+const productRank = `bm25(products_fts, 10.0, 5.0, 1.0, 3.0)`
+
+// productPageRow is a product row with the value it is sorted by.
+type productPageRow struct {
+	productRow
+	SortKey interface{} `db:"sort_key"`
+}
+
+// Search retrieves a page of products matching the given filter options,
+// sorted as requested and newest first by default, with the number matching
+// across every page. Relevance ranks full-text matches by bm25 and needs a
+// Query.
+// allowedKeys is the safelist of property keys from the category blueprint;
+// any Properties or Filters key not in this list is silently ignored to
+// prevent SQL injection via json_extract paths.
+func (r *ProductRepository) Search(ctx context.Context, opts domain.FilterOptions, allowedKeys []string) (*domain.ProductPage, error) {
+	clauses, args := searchConditions(opts, allowedKeys)
+	where := ""
+	if len(clauses) > 0 {
+		where = ` WHERE ` + strings.Join(clauses, " AND ")
+	}
 
-	var rows []productRow
-	err := sqlx.SelectContext(ctx, r.db, &rows, query, limit, offset)
+	var total int
+	if err := r.db.QueryRowxContext(ctx, `SELECT COUNT(*) FROM products p`+where, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	ranked := opts.Sort == domain.SortRelevance && opts.Query != ""
+	sort, ok := productSorts[opts.Sort]
+	if !ok || (opts.Sort == domain.SortRelevance && !ranked) {
+		sort = productSorts[domain.SortCreatedAt]
+	}
+	desc := sort.desc
+	if opts.Order != "" {
+		desc = opts.Order == domain.SortDesc
+	}
+	page := newKeysetPage(opts.PageRequest, sort.expr, `p.id`, desc)
+
+	query := `SELECT p.*, ` + sort.expr + ` AS sort_key FROM products p`
+	var queryArgs []interface{}
+	if ranked {
+		query += ` LEFT JOIN (SELECT rowid, ` + productRank + ` AS rank FROM products_fts WHERE products_fts MATCH ?) fts ON fts.rowid = p.rowid`
+		queryArgs = append(queryArgs, opts.Query)
+	}
+	if keyClause, keyArgs := page.condition(); keyClause != "" {
+		clauses = append(clauses, keyClause)
+		args = append(args, keyArgs...)
+	}
+	if len(clauses) > 0 {
+		query += ` WHERE ` + strings.Join(clauses, " AND ")
+	}
+	orderClause, orderArgs := page.orderAndLimit()
+	query += orderClause
+	queryArgs = append(append(queryArgs, args...), orderArgs...)
+
+	var rows []productPageRow
+	err := sqlx.SelectContext(ctx, r.db, &rows, query, queryArgs...)
 	if err != nil {
 		return nil, err
 	}
 
-	products := make([]*domain.Product, 0, len(rows))
-	for _, row := range rows {
-		product, err := r.toDomain(&row)
+	keys := make([]domain.PageKey, len(rows))
+	for i, row := range rows {
+		value := row.SortKey
+		if text, ok := value.([]byte); ok {
+			value = string(text)
+		}
+		keys[i] = domain.PageKey{Value: value, ID: row.ID}
+	}
+	order, info := page.info(keys, total)
+
+	result := &domain.ProductPage{Products: make([]*domain.Product, 0, len(order)), PageInfo: info}
+	for _, i := range order {
+		product, err := r.toDomain(&rows[i].productRow)
 		if err != nil {
 			return nil, err
 		}
-		products = append(products, product)
+		result.Products = append(result.Products, product)
 	}
 
-	return products, nil
+	return result, nil
 }
 
-// validPropertyKey matches only safe JSON key names (alphanumeric + underscore).
-var validPropertyKey = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+// suggestionRow is a database row representation for search suggestions.
+type suggestionRow struct {
+	ID   string `db:"id"`
+	Name string `db:"name"`
+	SKU  string `db:"sku"`
+}
 
-// Search retrieves products matching the given filter options.
-// allowedKeys is the safelist of property keys from the category blueprint;
-// any Properties filter key not in this list is silently ignored to prevent
-// SQL injection via json_extract paths.
-func (r *ProductRepository) Search(ctx context.Context, opts domain.FilterOptions, allowedKeys []string) ([]*domain.Product, error) {
+// Suggest retrieves up to limit products whose name or SKU matches an FTS5
+// expression, best match first. It reads only what a suggestion shows, to
+// keep as-you-type lookups fast.
+func (r *ProductRepository) Suggest(ctx context.Context, match string, limit int) ([]domain.ProductSuggestion, error) {
+	query := `
+		SELECT p.id, p.name, p.sku FROM products_fts f
+		JOIN products p ON p.rowid = f.rowid
+		WHERE products_fts MATCH ?
+		ORDER BY ` + productRank + `, p.name
+		LIMIT ?
+	`
+	return r.selectSuggestions(ctx, query, `{name sku} : (`+match+`)`, limit)
+}
+
+// MatchTrigrams retrieves up to limit products whose name or SKU contains
+// any of the trigrams in an FTS5 expression, those sharing the most first.
+// They are candidates for a misspelt search, for the caller to score.
+func (r *ProductRepository) MatchTrigrams(ctx context.Context, match string, limit int) ([]domain.ProductSuggestion, error) {
+	query := `
+		SELECT p.id, p.name, p.sku FROM products_trigram t
+		JOIN products p ON p.rowid = t.rowid
+		WHERE products_trigram MATCH ?
+		ORDER BY bm25(products_trigram)
+		LIMIT ?
+	`
+	return r.selectSuggestions(ctx, query, match, limit)
+}
+
+// RebuildSearchIndex rebuilds the full-text and trigram indexes from the
+// products table.
+func (r *ProductRepository) RebuildSearchIndex(ctx context.Context) error {
+	for _, table := range []string{"products_fts", "products_trigram"} {
+		query := fmt.Sprintf(`INSERT INTO %s(%s) VALUES('rebuild')`, table, table)
+		if _, err := r.db.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("rebuild %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// selectSuggestions runs a query for suggestion rows.
+func (r *ProductRepository) selectSuggestions(ctx context.Context, query string, args ...interface{}) ([]domain.ProductSuggestion, error) {
+	var rows []suggestionRow
+	if err := sqlx.SelectContext(ctx, r.db, &rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]domain.ProductSuggestion, 0, len(rows))
+	for _, row := range rows {
+		suggestions = append(suggestions, domain.ProductSuggestion{ID: row.ID, Name: row.Name, SKU: row.SKU})
+	}
+	return suggestions, nil
+}
+
+// facetValueRow holds one value of a facet and its product count.
+type facetValueRow struct {
+	Value string `db:"value"`
+	Label string `db:"label"`
+	Count int    `db:"count"`
+}
+
+// CountFacets counts the products matching a search by category, by the
+// values of the requested properties, by price bucket and by stock. Each
+// property's counts drop the search's filters on that property, and the
+// price buckets drop its price range.
+func (r *ProductRepository) CountFacets(ctx context.Context, opts domain.FilterOptions, allowedKeys []string, request domain.FacetRequest) (*domain.SearchFacets, error) {
+	facets := &domain.SearchFacets{
+		Categories: []domain.FacetValue{},
+		Attributes: []domain.AttributeFacet{},
+		Prices:     []domain.PriceBucket{},
+	}
+
+	clauses, args := searchConditions(opts, allowedKeys)
+	where := ``
+	if len(clauses) > 0 {
+		where = ` WHERE ` + strings.Join(clauses, " AND ")
+	}
+
+	var stock struct {
+		Total   int `db:"total"`
+		InStock int `db:"in_stock"`
+	}
+	query := `SELECT COUNT(*) AS total, COALESCE(SUM(p.quantity > 0), 0) AS in_stock FROM products p` + where
+	if err := sqlx.GetContext(ctx, r.db, &stock, query, args...); err != nil {
+		return nil, fmt.Errorf("count stock facet: %w", err)
+	}
+	facets.Total = stock.Total
+	facets.InStock = stock.InStock
+	facets.OutOfStock = stock.Total - stock.InStock
+
+	// Categories the matching products sit in directly
+	categoryClauses := append(append([]string{}, clauses...), `p.category_id IS NOT NULL`)
+	query = `
+		SELECT p.category_id AS value, COALESCE(c.name, '') AS label, COUNT(*) AS count
+		FROM products p
+		LEFT JOIN categories c ON c.id = p.category_id
+		WHERE ` + strings.Join(categoryClauses, " AND ") + `
+		GROUP BY p.category_id, c.name
+		ORDER BY count DESC, label`
+	var categories []facetValueRow
+	if err := sqlx.SelectContext(ctx, r.db, &categories, query, args...); err != nil {
+		return nil, fmt.Errorf("count category facet: %w", err)
+	}
+	for _, row := range categories {
+		facets.Categories = append(facets.Categories, domain.FacetValue{Value: row.Value, Label: row.Label, Count: row.Count})
+	}
+
+	// Price buckets, ignoring the price range
+	priceOpts := opts
+	priceOpts.MinPrice, priceOpts.MaxPrice = nil, nil
+	priceClauses, priceArgs := searchConditions(priceOpts, allowedKeys)
+	columns := make([]string, 0, len(request.PriceBounds)+1)
+	var bucketArgs []interface{}
+	for i := 0; i <= len(request.PriceBounds); i++ {
+		var conditions []string
+		if i > 0 {
+			conditions = append(conditions, `p.base_price >= ?`)
+			bucketArgs = append(bucketArgs, request.PriceBounds[i-1])
+		}
+		if i < len(request.PriceBounds) {
+			conditions = append(conditions, `p.base_price < ?`)
+			bucketArgs = append(bucketArgs, request.PriceBounds[i])
+		}
+		if len(conditions) == 0 {
+			conditions = append(conditions, `1`)
+		}
+		columns = append(columns, `COALESCE(SUM(`+strings.Join(conditions, " AND ")+`), 0)`)
+	}
+	query = `SELECT ` + strings.Join(columns, ", ") + ` FROM products p`
+	if len(priceClauses) > 0 {
+		query += ` WHERE ` + strings.Join(priceClauses, " AND ")
+	}
+	counts := make([]int, len(columns))
+	dest := make([]interface{}, len(counts))
+	for i := range counts {
+		dest[i] = &counts[i]
+	}
+	if err := r.db.QueryRowxContext(ctx, query, append(bucketArgs, priceArgs...)...).Scan(dest...); err != nil {
+		return nil, fmt.Errorf("count price facet: %w", err)
+	}
+	for i, count := range counts {
+		bucket := domain.PriceBucket{Count: count}
+		if i > 0 {
+			bucket.Min = &request.PriceBounds[i-1]
+		}
+		if i < len(request.PriceBounds) {
+			bucket.Max = &request.PriceBounds[i]
+		}
+		facets.Prices = append(facets.Prices, bucket)
+	}
+
+	// Property values, each ignoring the search's filters on that property
+	allowed := make(map[string]bool, len(allowedKeys))
+	for _, k := range allowedKeys {
+		allowed[k] = true
+	}
+	for _, key := range request.AttributeKeys {
+		if !allowed[key] || !validPropertyKey.MatchString(key) {
+			continue
+		}
+		keyClauses, keyArgs := searchConditions(withoutPropertyFilters(opts, key), allowedKeys)
+		path := fmt.Sprintf(`'$.%s'`, key)
+		keyClauses = append(keyClauses, `json_type(p.properties, `+path+`) IN ('text', 'true', 'false')`)
+		query = `
+			SELECT CASE json_type(p.properties, ` + path + `)
+					WHEN 'true' THEN 'true'
+					WHEN 'false' THEN 'false'
+					ELSE json_extract(p.properties, ` + path + `)
+				END AS value,
+				'' AS label, COUNT(*) AS count
+			FROM products p
+			WHERE ` + strings.Join(keyClauses, " AND ") + `
+			GROUP BY value
+			ORDER BY count DESC, value`
+		var values []facetValueRow
+		if err := sqlx.SelectContext(ctx, r.db, &values, query, keyArgs...); err != nil {
+			return nil, fmt.Errorf("count %s facet: %w", key, err)
+		}
+		facet := domain.AttributeFacet{Key: key, Values: make([]domain.FacetValue, 0, len(values))}
+		for _, row := range values {
+			facet.Values = append(facet.Values, domain.FacetValue{Value: row.Value, Count: row.Count})
+		}
+		facets.Attributes = append(facets.Attributes, facet)
+	}
+
+	return facets, nil
+}
+
+// withoutPropertyFilters returns search options without the filters on one
+// property.
+func withoutPropertyFilters(opts domain.FilterOptions, key string) domain.FilterOptions {
+	properties := make(map[string]string, len(opts.Properties))
+	for k, v := range opts.Properties {
+		if k != key {
+			properties[k] = v
+		}
+	}
+	filters := make([]domain.PropertyFilter, 0, len(opts.Filters))
+	for _, f := range opts.Filters {
+		if f.Key != key {
+			filters = append(filters, f)
+		}
+	}
+	opts.Properties = properties
+	opts.Filters = filters
+	return opts
+}
+
+// searchConditions builds the WHERE conditions of a product search, aliasing
+// products as p. Property filters on keys outside allowedKeys are ignored.
+func searchConditions(opts domain.FilterOptions, allowedKeys []string) ([]string, []interface{}) {
 	allowed := make(map[string]bool, len(allowedKeys))
 	for _, k := range allowedKeys {
 		allowed[k] = true
@@ -137,16 +453,23 @@ func (r *ProductRepository) Search(ctx context.Context, opts domain.FilterOption
 	var clauses []string
 	var args []interface{}
 
-	// Full-text search via FTS5
+	// Full-text search via FTS5, with any misspelt matches the service found
 	if opts.Query != "" {
-		clauses = append(clauses, `p.rowid IN (SELECT rowid FROM products_fts WHERE products_fts MATCH ?)`)
+		clause := `p.rowid IN (SELECT rowid FROM products_fts WHERE products_fts MATCH ?)`
 		args = append(args, opts.Query)
+		if len(opts.FuzzyIDs) > 0 {
+			clause = `(` + clause + ` OR p.id IN (?` + strings.Repeat(`, ?`, len(opts.FuzzyIDs)-1) + `))`
+			for _, id := range opts.FuzzyIDs {
+				args = append(args, id)
+			}
+		}
+		clauses = append(clauses, clause)
 	}
 
-	// Category filter
+	// Category filter, including every category below it in the tree
 	if opts.CategoryID != "" {
-		clauses = append(clauses, `p.category_id = ?`)
-		args = append(args, opts.CategoryID)
+		clauses = append(clauses, `p.category_id IN (`+categorySubtreeQuery+`)`)
+		args = append(args, opts.CategoryID, maxCategoryDepth)
 	}
 
 	// Price range
@@ -159,6 +482,13 @@ func (r *ProductRepository) Search(ctx context.Context, opts domain.FilterOption
 		args = append(args, *opts.MaxPrice)
 	}
 
+	// Label-relevant changes; products never changed count from creation.
+	// datetime() normalises trigger-written and driver-written timestamps.
+	if opts.ChangedSince != nil {
+		clauses = append(clauses, `datetime(COALESCE(p.label_changed_at, p.created_at)) >= datetime(?)`)
+		args = append(args, opts.ChangedSince.UTC().Format("2006-01-02 15:04:05"))
+	}
+
 	// Dynamic JSON property filters (safelisted keys only)
 	for key, val := range opts.Properties {
 		if !allowed[key] || !validPropertyKey.MatchString(key) {
@@ -168,57 +498,110 @@ func (r *ProductRepository) Search(ctx context.Context, opts domain.FilterOption
 		args = append(args, val)
 	}
 
-	query := `SELECT p.* FROM products p`
-	if len(clauses) > 0 {
-		query += ` WHERE ` + strings.Join(clauses, " AND ")
+	// Typed property filters (safelisted keys only)
+	for _, filter := range opts.Filters {
+		if !allowed[filter.Key] || !validPropertyKey.MatchString(filter.Key) {
+			continue
+		}
+		clause, filterArgs := propertyFilterClause(filter)
+		clauses = append(clauses, clause)
+		args = append(args, filterArgs...)
 	}
-	query += ` ORDER BY p.created_at DESC`
 
-	limit := opts.Limit
-	if limit <= 0 {
-		limit = 10
-	}
-	offset := opts.Offset
-	if offset < 0 {
-		offset = 0
+	return clauses, args
+}
+
+// filterComparisons maps the comparison filter operators to SQL.
+var filterComparisons = map[string]string{
+	domain.FilterGt:  ">",
+	domain.FilterGte: ">=",
+	domain.FilterLt:  "<",
+	domain.FilterLte: "<=",
+}
+
+// propertyFilterClause builds the condition for a typed property filter.
+// Values of a different JSON type never match, so "220v" stored as text is
+// not compared with numbers. The key must already be validated.
+func propertyFilterClause(filter domain.PropertyFilter) (string, []interface{}) {
+	path := fmt.Sprintf(`'$.%s'`, filter.Key)
+	value := `json_extract(p.properties, ` + path + `)`
+
+	// Multiselect values are arrays: match products with any of the options
+	if filter.Type == "multiselect" {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(filter.Operands)), ", ")
+		return `EXISTS (SELECT 1 FROM json_each(p.properties, ` + path + `) WHERE json_each.value IN (` + placeholders + `))`,
+			filter.Operands
 	}
-	query += ` LIMIT ? OFFSET ?`
-	args = append(args, limit, offset)
 
-	var rows []productRow
-	err := sqlx.SelectContext(ctx, r.db, &rows, query, args...)
-	if err != nil {
-		return nil, err
+	var conditions []string
+	switch filter.Type {
+	case "number":
+		conditions = append(conditions, `json_type(p.properties, `+path+`) IN ('integer', 'real')`)
+	case "boolean":
+		conditions = append(conditions, `json_type(p.properties, `+path+`) IN ('true', 'false')`)
+	default:
+		conditions = append(conditions, `json_type(p.properties, `+path+`) = 'text'`)
 	}
 
-	products := make([]*domain.Product, 0, len(rows))
-	for _, row := range rows {
-		product, err := r.toDomain(&row)
-		if err != nil {
-			return nil, err
+	var args []interface{}
+	switch filter.Op {
+	case domain.FilterGt, domain.FilterGte, domain.FilterLt, domain.FilterLte:
+		conditions = append(conditions, value+` `+filterComparisons[filter.Op]+` ?`)
+		args = append(args, filter.Operands[0])
+	case domain.FilterBetween:
+		if filter.Operands[0] != nil {
+			conditions = append(conditions, value+` >= ?`)
+			args = append(args, filter.Operands[0])
 		}
-		products = append(products, product)
+		if filter.Operands[1] != nil {
+			conditions = append(conditions, value+` <= ?`)
+			args = append(args, filter.Operands[1])
+		}
+	case domain.FilterIn:
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(filter.Operands)), ", ")
+		conditions = append(conditions, value+` IN (`+placeholders+`)`)
+		args = append(args, filter.Operands...)
+	case domain.FilterPrefix:
+		conditions = append(conditions, value+` LIKE ? ESCAPE '\'`)
+		args = append(args, escapeLike(fmt.Sprint(filter.Operands[0]))+"%")
+	case domain.FilterContains:
+		conditions = append(conditions, value+` LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+escapeLike(fmt.Sprint(filter.Operands[0]))+"%")
+	default:
+		conditions = append(conditions, value+` = ?`)
+		args = append(args, filter.Operands[0])
 	}
+	return strings.Join(conditions, " AND "), args
+}
 
-	return products, nil
+// escapeLike escapes the LIKE wildcards in a value matched literally.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
 }
 
 // inventorySummaryRow holds a row from the inventory summary query.
 type inventorySummaryRow struct {
-	CategoryID   sql.NullString `db:"category_id"`
-	CategoryName sql.NullString `db:"category_name"`
-	Count        int            `db:"count"`
-	TotalValue   float64        `db:"total_value"`
+	CategoryID       sql.NullString `db:"category_id"`
+	CategoryName     sql.NullString `db:"category_name"`
+	Count            int            `db:"count"`
+	Quantity         float64        `db:"quantity"`
+	TotalValue       float64        `db:"total_value"`
+	RetailValue      float64        `db:"retail_value"`
+	QuarantinedValue float64        `db:"quarantined_value"`
 }
 
-// GetInventorySummary returns aggregated inventory analytics.
+// GetInventorySummary returns aggregated inventory analytics, valuing the
+// stock on hand at cost and at selling price.
 func (r *ProductRepository) GetInventorySummary(ctx context.Context) (*domain.InventorySummary, error) {
 	query := `
 		SELECT
 			p.category_id,
 			c.name AS category_name,
 			COUNT(*) AS count,
-			COALESCE(SUM(p.base_price), 0) AS total_value
+			COALESCE(SUM(p.quantity), 0) AS quantity,
+			COALESCE(SUM(p.quantity * p.cost_price), 0) AS total_value,
+			COALESCE(SUM(p.quantity * p.base_price), 0) AS retail_value,
+			COALESCE(SUM(p.quarantined_quantity * p.cost_price), 0) AS quarantined_value
 		FROM products p
 		LEFT JOIN categories c ON p.category_id = c.id
 		GROUP BY p.category_id
@@ -233,22 +616,49 @@ func (r *ProductRepository) GetInventorySummary(ctx context.Context) (*domain.In
 	summary := &domain.InventorySummary{}
 	for _, row := range rows {
 		bd := domain.CategoryBreakdown{
-			CategoryID:   row.CategoryID.String,
-			CategoryName: row.CategoryName.String,
-			Count:        row.Count,
-			TotalValue:   row.TotalValue,
+			CategoryID:      row.CategoryID.String,
+			CategoryName:    row.CategoryName.String,
+			Count:           row.Count,
+			Quantity:        row.Quantity,
+			TotalValue:      row.TotalValue,
+			RetailValue:     row.RetailValue,
+			PotentialProfit: row.RetailValue - row.TotalValue,
 		}
 		if !row.CategoryID.Valid {
 			bd.CategoryName = "Uncategorized"
 		}
 		summary.CategoryBreakdown = append(summary.CategoryBreakdown, bd)
 		summary.TotalItems += row.Count
+		summary.TotalQuantity += row.Quantity
 		summary.TotalValue += row.TotalValue
+		summary.RetailValue += row.RetailValue
+		summary.QuarantinedValue += row.QuarantinedValue
 	}
 
+	summary.PotentialProfit = summary.RetailValue - summary.TotalValue
+
 	return summary, nil
 }
 
+// ExistsWithProperty reports whether a product in the category, other than
+// excludeID, has the given value for a property.
+func (r *ProductRepository) ExistsWithProperty(ctx context.Context, categoryID, key string, value interface{}, excludeID string) (bool, error) {
+	if !validPropertyKey.MatchString(key) {
+		return false, fmt.Errorf("invalid property key %q", key)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT EXISTS (
+			SELECT 1 FROM products
+			WHERE category_id = ? AND id <> ? AND json_extract(properties, '$.%s') = ?
+		)
+	`, key)
+
+	var exists bool
+	err := sqlx.GetContext(ctx, r.db, &exists, query, categoryID, excludeID, value)
+	return exists, err
+}
+
 // Update updates an existing product.
 func (r *ProductRepository) Update(ctx context.Context, product *domain.Product) error {
 	// Serialize properties to JSON
@@ -257,9 +667,15 @@ func (r *ProductRepository) Update(ctx context.Context, product *domain.Product)
 		return err
 	}
 
+	originalsJSON, err := marshalPropertyOriginals(product.PropertyOriginals)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE products
-		SET name = ?, sku = ?, category_id = ?, base_price = ?, quantity = ?, cost_price = ?, properties = ?
+		SET name = ?, sku = ?, category_id = ?, base_price = ?, quantity = ?, cost_price = ?, properties = ?, quarantined_quantity = ?,
+			unit_of_measure = ?, quantity_precision = ?, property_originals = ?, description = ?, search_terms = ?
 		WHERE id = ?
 	`
 
@@ -271,6 +687,12 @@ func (r *ProductRepository) Update(ctx context.Context, product *domain.Product)
 		product.Quantity,
 		product.CostPrice,
 		string(propertiesJSON),
+		product.QuarantinedQuantity,
+		unitOfMeasureOrDefault(product.UnitOfMeasure),
+		product.QuantityPrecision,
+		originalsJSON,
+		product.Description,
+		product.SearchTerms,
 		product.ID,
 	)
 
@@ -296,6 +718,16 @@ func (r *ProductRepository) toDomain(row *productRow) (*domain.Product, error) {
 		CostPrice:  row.CostPrice,
 		CreatedAt:  row.CreatedAt,
 		UpdatedAt:  row.UpdatedAt,
+
+		QuarantinedQuantity: row.QuarantinedQuantity,
+		UnitOfMeasure:       row.UnitOfMeasure,
+		QuantityPrecision:   row.QuantityPrecision,
+
+		Description: row.Description,
+		SearchTerms: row.SearchTerms,
+	}
+	if row.LabelChangedAt.Valid {
+		product.LabelChangedAt = &row.LabelChangedAt.Time
 	}
 
 	// Deserialize properties from JSON
@@ -310,5 +742,32 @@ func (r *ProductRepository) toDomain(row *productRow) (*domain.Product, error) {
 		product.Properties = make(map[string]interface{})
 	}
 
+	if row.PropertyOriginals.Valid && row.PropertyOriginals.String != "" {
+		if err := json.Unmarshal([]byte(row.PropertyOriginals.String), &product.PropertyOriginals); err != nil {
+			return nil, err
+		}
+	}
+
 	return product, nil
 }
+
+// unitOfMeasureOrDefault returns the unit of measure to store, defaulting to each.
+func unitOfMeasureOrDefault(unit string) string {
+	if unit == "" {
+		return domain.UnitEach
+	}
+	return unit
+}
+
+// marshalPropertyOriginals serializes the text measured properties were
+// entered as, storing NULL when there is none.
+func marshalPropertyOriginals(originals map[string]string) (sql.NullString, error) {
+	if len(originals) == 0 {
+		return sql.NullString{}, nil
+	}
+	originalsJSON, err := json.Marshal(originals)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(originalsJSON), Valid: true}, nil
+}
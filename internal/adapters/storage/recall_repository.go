@@ -0,0 +1,200 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+// RecallRepository implements the recall repository using SQLite.
+type RecallRepository struct {
+	db sqlx.ExtContext
+}
+
+// NewRecallRepository creates a new recall repository instance.
+func NewRecallRepository(db sqlx.ExtContext) *RecallRepository {
+	return &RecallRepository{db: db}
+}
+
+// recallRow is a database row representation for recalls.
+type recallRow struct {
+	ID                  string       `db:"id"`
+	ProductID           string       `db:"product_id"`
+	Reason              string       `db:"reason"`
+	SoldFrom            sql.NullTime `db:"sold_from"`
+	SoldTo              sql.NullTime `db:"sold_to"`
+	Status              string       `db:"status"`
+	QuarantinedQuantity float64      `db:"quarantined_quantity"`
+	CreatedAt           time.Time    `db:"created_at"`
+	ClosedAt            sql.NullTime `db:"closed_at"`
+}
+
+// recallAffectedSaleRow holds a row from the affected sales query.
+type recallAffectedSaleRow struct {
+	SaleID    string    `db:"sale_id"`
+	SoldAt    time.Time `db:"sold_at"`
+	Quantity  float64   `db:"quantity"`
+	UnitPrice float64   `db:"unit_price"`
+}
+
+// Create inserts a new recall record.
+func (r *RecallRepository) Create(ctx context.Context, recall *domain.Recall) error {
+	query := `
+		INSERT INTO recalls (id, product_id, reason, sold_from, sold_to, status, quarantined_quantity, created_at, closed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		recall.ID,
+		recall.ProductID,
+		recall.Reason,
+		toNullTime(recall.SoldFrom),
+		toNullTime(recall.SoldTo),
+		recall.Status,
+		recall.QuarantinedQuantity,
+		recall.CreatedAt,
+		toNullTime(recall.ClosedAt),
+	)
+
+	return err
+}
+
+// GetByID retrieves a recall by its ID.
+func (r *RecallRepository) GetByID(ctx context.Context, id string) (*domain.Recall, error) {
+	query := `SELECT * FROM recalls WHERE id = ?`
+
+	var row recallRow
+	err := sqlx.GetContext(ctx, r.db, &row, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("recall not found")
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&row), nil
+}
+
+// GetActiveByProduct retrieves the active recall for a product, if any.
+func (r *RecallRepository) GetActiveByProduct(ctx context.Context, productID string) (*domain.Recall, error) {
+	query := `SELECT * FROM recalls WHERE product_id = ? AND status = ? ORDER BY created_at DESC LIMIT 1`
+
+	var row recallRow
+	err := sqlx.GetContext(ctx, r.db, &row, query, productID, domain.RecallStatusActive)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Product is not under recall
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&row), nil
+}
+
+// List retrieves recalls with pagination, newest first.
+func (r *RecallRepository) List(ctx context.Context, limit, offset int) ([]*domain.Recall, error) {
+	query := `SELECT * FROM recalls ORDER BY created_at DESC LIMIT ? OFFSET ?`
+
+	var rows []recallRow
+	err := sqlx.SelectContext(ctx, r.db, &rows, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	recalls := make([]*domain.Recall, 0, len(rows))
+	for _, row := range rows {
+		recalls = append(recalls, r.toDomain(&row))
+	}
+
+	return recalls, nil
+}
+
+// Update updates the mutable fields of a recall.
+func (r *RecallRepository) Update(ctx context.Context, recall *domain.Recall) error {
+	query := `UPDATE recalls SET reason = ?, status = ?, quarantined_quantity = ?, closed_at = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query,
+		recall.Reason,
+		recall.Status,
+		recall.QuarantinedQuantity,
+		toNullTime(recall.ClosedAt),
+		recall.ID,
+	)
+	return err
+}
+
+// ListAffectedSales returns every sale line for a product, optionally limited
+// to sales made within [from, to].
+func (r *RecallRepository) ListAffectedSales(ctx context.Context, productID string, from, to *time.Time) ([]domain.RecallAffectedSale, error) {
+	query := `
+		SELECT si.sale_id, s.created_at AS sold_at, si.quantity, si.unit_price
+		FROM sale_items si
+		JOIN sales s ON s.id = si.sale_id
+		WHERE si.product_id = ?
+	`
+	args := []interface{}{productID}
+
+	// datetime() normalises timestamps written with different offsets.
+	if from != nil {
+		query += ` AND datetime(s.created_at) >= datetime(?)`
+		args = append(args, *from)
+	}
+	if to != nil {
+		query += ` AND datetime(s.created_at) <= datetime(?)`
+		args = append(args, *to)
+	}
+	query += ` ORDER BY datetime(s.created_at), si.id`
+
+	var rows []recallAffectedSaleRow
+	err := sqlx.SelectContext(ctx, r.db, &rows, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	sales := make([]domain.RecallAffectedSale, 0, len(rows))
+	for _, row := range rows {
+		sales = append(sales, domain.RecallAffectedSale{
+			SaleID:    row.SaleID,
+			SoldAt:    row.SoldAt,
+			Quantity:  row.Quantity,
+			UnitPrice: row.UnitPrice,
+		})
+	}
+
+	return sales, nil
+}
+
+// toDomain converts a database row to a domain entity.
+func (r *RecallRepository) toDomain(row *recallRow) *domain.Recall {
+	return &domain.Recall{
+		ID:                  row.ID,
+		ProductID:           row.ProductID,
+		Reason:              row.Reason,
+		SoldFrom:            fromNullTime(row.SoldFrom),
+		SoldTo:              fromNullTime(row.SoldTo),
+		Status:              row.Status,
+		QuarantinedQuantity: row.QuarantinedQuantity,
+		CreatedAt:           row.CreatedAt,
+		ClosedAt:            fromNullTime(row.ClosedAt),
+	}
+}
+
+// toNullTime converts an optional time to a nullable column value.
+func toNullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+// fromNullTime converts a nullable column value to an optional time.
+func fromNullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	v := t.Time
+	return &v
+}
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestListAffectedSales_AcrossOffsets(t *testing.T) {
+	db, err := sqlx.Connect("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer db.Close()
+
+	// Only the columns the query reads, with sales stored in different offsets
+	_, err = db.Exec(`
+		CREATE TABLE sales (id TEXT PRIMARY KEY, created_at TIMESTAMP NOT NULL);
+		CREATE TABLE sale_items (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			sale_id TEXT NOT NULL,
+			product_id TEXT NOT NULL,
+			quantity REAL NOT NULL,
+			unit_price REAL NOT NULL
+		);
+		INSERT INTO sales (id, created_at) VALUES
+			('before', '2026-05-01 23:30:00+02:00'),
+			('inside', '2026-05-02 00:30:00+02:00'),
+			('after', '2026-05-01 21:00:00-03:00');
+		INSERT INTO sale_items (sale_id, product_id, quantity, unit_price) VALUES
+			('before', 'p1', 1, 5), ('inside', 'p1', 1, 5), ('after', 'p1', 1, 5);
+	`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 21:30, 22:30 and 00:00 UTC against a 22:00-23:59 UTC range
+	from := time.Date(2026, 5, 1, 22, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 5, 1, 23, 59, 0, 0, time.UTC)
+	sales, err := NewRecallRepository(db).ListAffectedSales(context.Background(), "p1", &from, &to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sales) != 1 || sales[0].SaleID != "inside" {
+		t.Fatalf("expected only the sale made inside the range, got %+v", sales)
+	}
+}
@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+// RestrictionRepository implements the sale restriction repository using SQLite.
+type RestrictionRepository struct {
+	db sqlx.ExtContext
+}
+
+// NewRestrictionRepository creates a new sale restriction repository instance.
+func NewRestrictionRepository(db sqlx.ExtContext) *RestrictionRepository {
+	return &RestrictionRepository{db: db}
+}
+
+// restrictionRow is a database row representation for sale restrictions.
+type restrictionRow struct {
+	ID                        string         `db:"id"`
+	CategoryID                sql.NullString `db:"category_id"`
+	ProductID                 sql.NullString `db:"product_id"`
+	MinimumAge                int            `db:"minimum_age"`
+	SaleWindowStart           sql.NullString `db:"sale_window_start"`
+	SaleWindowEnd             sql.NullString `db:"sale_window_end"`
+	MaxQuantityPerTransaction float64        `db:"max_quantity_per_transaction"`
+	CreatedAt                 time.Time      `db:"created_at"`
+}
+
+// Create inserts a new sale restriction.
+func (r *RestrictionRepository) Create(ctx context.Context, restriction *domain.SaleRestriction) error {
+	query := `
+		INSERT INTO sale_restrictions (id, category_id, product_id, minimum_age, sale_window_start, sale_window_end, max_quantity_per_transaction, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		restriction.ID,
+		sql.NullString{String: restriction.CategoryID, Valid: restriction.CategoryID != ""},
+		sql.NullString{String: restriction.ProductID, Valid: restriction.ProductID != ""},
+		restriction.MinimumAge,
+		sql.NullString{String: restriction.SaleWindowStart, Valid: restriction.SaleWindowStart != ""},
+		sql.NullString{String: restriction.SaleWindowEnd, Valid: restriction.SaleWindowEnd != ""},
+		restriction.MaxQuantityPerTransaction,
+		restriction.CreatedAt,
+	)
+
+	return err
+}
+
+// GetByID retrieves a sale restriction by its ID.
+func (r *RestrictionRepository) GetByID(ctx context.Context, id string) (*domain.SaleRestriction, error) {
+	query := `SELECT * FROM sale_restrictions WHERE id = ?`
+
+	var row restrictionRow
+	err := sqlx.GetContext(ctx, r.db, &row, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("restriction not found")
+		}
+		return nil, err
+	}
+
+	return r.toDomain(&row), nil
+}
+
+// List retrieves sale restrictions with pagination.
+func (r *RestrictionRepository) List(ctx context.Context, limit, offset int) ([]*domain.SaleRestriction, error) {
+	query := `SELECT * FROM sale_restrictions ORDER BY created_at DESC LIMIT ? OFFSET ?`
+
+	var rows []restrictionRow
+	err := sqlx.SelectContext(ctx, r.db, &rows, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.toDomainList(rows), nil
+}
+
+// ListForProduct retrieves every restriction that applies to a product,
+// either directly or through one of the given categories, usually the
+// product's category and its ancestors.
+func (r *RestrictionRepository) ListForProduct(ctx context.Context, productID string, categoryIDs []string) ([]*domain.SaleRestriction, error) {
+	query := `SELECT * FROM sale_restrictions WHERE product_id = ?`
+	args := []interface{}{productID}
+	if len(categoryIDs) > 0 {
+		query += ` OR category_id IN (?` + strings.Repeat(`, ?`, len(categoryIDs)-1) + `)`
+		for _, id := range categoryIDs {
+			args = append(args, id)
+		}
+	}
+
+	var rows []restrictionRow
+	err := sqlx.SelectContext(ctx, r.db, &rows, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.toDomainList(rows), nil
+}
+
+// Delete deletes a sale restriction by its ID.
+func (r *RestrictionRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM sale_restrictions WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// toDomainList converts database rows to domain entities.
+func (r *RestrictionRepository) toDomainList(rows []restrictionRow) []*domain.SaleRestriction {
+	restrictions := make([]*domain.SaleRestriction, 0, len(rows))
+	for _, row := range rows {
+		restrictions = append(restrictions, r.toDomain(&row))
+	}
+	return restrictions
+}
+
+// toDomain converts a database row to a domain entity.
+func (r *RestrictionRepository) toDomain(row *restrictionRow) *domain.SaleRestriction {
+	return &domain.SaleRestriction{
+		ID:                        row.ID,
+		CategoryID:                row.CategoryID.String,
+		ProductID:                 row.ProductID.String,
+		MinimumAge:                row.MinimumAge,
+		SaleWindowStart:           row.SaleWindowStart.String,
+		SaleWindowEnd:             row.SaleWindowEnd.String,
+		MaxQuantityPerTransaction: row.MaxQuantityPerTransaction,
+		CreatedAt:                 row.CreatedAt,
+	}
+}
@@ -2,9 +2,14 @@ package storage
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
 )
 
 // SaleRepository implements the sale repository using SQLite.
@@ -17,16 +22,98 @@ func NewSaleRepository(db sqlx.ExtContext) *SaleRepository {
 	return &SaleRepository{db: db}
 }
 
+// saleRow is a database row representation for sales.
+type saleRow struct {
+	ID          string         `db:"id"`
+	TotalAmount float64        `db:"total_amount"`
+	CashierID   sql.NullString `db:"cashier_id"`
+	IDVerified  bool           `db:"id_verified"`
+	PriceListID sql.NullString `db:"price_list_id"`
+	CreatedAt   time.Time      `db:"created_at"`
+}
+
+// saleItemRow is a database row representation for sale items.
+type saleItemRow struct {
+	SaleID    string         `db:"sale_id"`
+	ProductID string         `db:"product_id"`
+	PackID    sql.NullString `db:"pack_id"`
+	Quantity  float64        `db:"quantity"`
+	UnitPrice float64        `db:"unit_price"`
+	CostPrice float64        `db:"cost_price"`
+}
+
 // CreateSale inserts a new sale record.
 func (r *SaleRepository) CreateSale(ctx context.Context, sale *domain.Sale) error {
-	query := `INSERT INTO sales (id, total_amount, created_at) VALUES (?, ?, ?)`
-	_, err := r.db.ExecContext(ctx, query, sale.ID, sale.TotalAmount, sale.CreatedAt)
+	query := `INSERT INTO sales (id, total_amount, cashier_id, id_verified, price_list_id, created_at) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		sale.ID,
+		sale.TotalAmount,
+		sql.NullString{String: sale.CashierID, Valid: sale.CashierID != ""},
+		sale.IDVerified,
+		sql.NullString{String: sale.PriceListID, Valid: sale.PriceListID != ""},
+		sale.CreatedAt,
+	)
 	return err
 }
 
 // CreateSaleItem inserts a new sale item record.
 func (r *SaleRepository) CreateSaleItem(ctx context.Context, item *domain.SaleItem) error {
-	query := `INSERT INTO sale_items (sale_id, product_id, quantity, unit_price, cost_price) VALUES (?, ?, ?, ?, ?)`
-	_, err := r.db.ExecContext(ctx, query, item.SaleID, item.ProductID, item.Quantity, item.UnitPrice, item.CostPrice)
+	query := `INSERT INTO sale_items (sale_id, product_id, pack_id, quantity, unit_price, cost_price) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		item.SaleID,
+		item.ProductID,
+		sql.NullString{String: item.PackID, Valid: item.PackID != ""},
+		item.Quantity,
+		item.UnitPrice,
+		item.CostPrice,
+	)
 	return err
 }
+
+// GetSale retrieves a sale by its ID.
+func (r *SaleRepository) GetSale(ctx context.Context, id string) (*domain.Sale, error) {
+	query := `SELECT id, total_amount, cashier_id, id_verified, price_list_id, created_at FROM sales WHERE id = ?`
+
+	var row saleRow
+	err := sqlx.GetContext(ctx, r.db, &row, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("sale %w", ports.ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return &domain.Sale{
+		ID:          row.ID,
+		TotalAmount: row.TotalAmount,
+		CashierID:   row.CashierID.String,
+		IDVerified:  row.IDVerified,
+		PriceListID: row.PriceListID.String,
+		CreatedAt:   row.CreatedAt,
+	}, nil
+}
+
+// ListSaleItems retrieves the items of a sale in the order they were rung up.
+func (r *SaleRepository) ListSaleItems(ctx context.Context, saleID string) ([]*domain.SaleItem, error) {
+	query := `SELECT sale_id, product_id, pack_id, quantity, unit_price, cost_price FROM sale_items WHERE sale_id = ? ORDER BY id ASC`
+
+	var rows []saleItemRow
+	err := sqlx.SelectContext(ctx, r.db, &rows, query, saleID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*domain.SaleItem, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, &domain.SaleItem{
+			SaleID:    row.SaleID,
+			ProductID: row.ProductID,
+			PackID:    row.PackID.String,
+			Quantity:  row.Quantity,
+			UnitPrice: row.UnitPrice,
+			CostPrice: row.CostPrice,
+		})
+	}
+
+	return items, nil
+}
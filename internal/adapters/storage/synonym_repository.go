@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+// SynonymRepository implements the search synonym repository using SQLite.
+type SynonymRepository struct {
+	db sqlx.ExtContext
+}
+
+// NewSynonymRepository creates a new search synonym repository instance.
+func NewSynonymRepository(db sqlx.ExtContext) *SynonymRepository {
+	return &SynonymRepository{db: db}
+}
+
+// synonymRow is a database row representation for search synonyms.
+type synonymRow struct {
+	ID        string    `db:"id"`
+	Term      string    `db:"term"`
+	Synonym   string    `db:"synonym"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Create inserts a new search synonym.
+func (r *SynonymRepository) Create(ctx context.Context, synonym *domain.SearchSynonym) error {
+	query := `INSERT INTO search_synonyms (id, term, synonym, created_at) VALUES (?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, synonym.ID, synonym.Term, synonym.Synonym, synonym.CreatedAt)
+	return err
+}
+
+// List retrieves every search synonym, ordered by term.
+func (r *SynonymRepository) List(ctx context.Context) ([]*domain.SearchSynonym, error) {
+	query := `SELECT * FROM search_synonyms ORDER BY term, synonym`
+
+	var rows []synonymRow
+	err := sqlx.SelectContext(ctx, r.db, &rows, query)
+	if err != nil {
+		return nil, err
+	}
+
+	synonyms := make([]*domain.SearchSynonym, 0, len(rows))
+	for _, row := range rows {
+		synonyms = append(synonyms, &domain.SearchSynonym{
+			ID:        row.ID,
+			Term:      row.Term,
+			Synonym:   row.Synonym,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return synonyms, nil
+}
+
+// Delete deletes a search synonym by its ID.
+func (r *SynonymRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM search_synonyms WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
@@ -34,10 +34,18 @@ func (m *SQLTransactionManager) WithTx(ctx context.Context, fn func(tx ports.Por
 	}()
 
 	txPorts := ports.Ports{
-		ProductRepo:  NewProductRepository(tx),
-		CategoryRepo: NewCategoryRepository(tx),
-		AuditRepo:    NewAuditLogRepository(tx),
-		SaleRepo:     NewSaleRepository(tx),
+		ProductRepo:     NewProductRepository(tx),
+		CategoryRepo:    NewCategoryRepository(tx),
+		AuditRepo:       NewAuditLogRepository(tx),
+		SaleRepo:        NewSaleRepository(tx),
+		RecallRepo:      NewRecallRepository(tx),
+		RestrictionRepo: NewRestrictionRepository(tx),
+		PackRepo:        NewPackRepository(tx),
+		BarcodeRepo:     NewBarcodeRepository(tx),
+		PriceRepo:       NewPriceRepository(tx),
+		PriceListRepo:   NewPriceListRepository(tx),
+		CostLayerRepo:   NewCostLayerRepository(tx),
+		AssociationRepo: NewAssociationRepository(tx),
 	}
 
 	if err := fn(txPorts); err != nil {
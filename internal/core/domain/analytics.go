@@ -0,0 +1,189 @@
+package domain
+
+import "time"
+
+// Report groupings. Margin reports accept all but hour; sales time series
+// use hour, day, week and month as bucket intervals.
+const (
+	GroupByProduct  = "product"
+	GroupByCategory = "category"
+	GroupByCashier  = "cashier"
+	GroupByHour     = "hour"
+	GroupByDay      = "day"
+	GroupByWeek     = "week"
+	GroupByMonth    = "month"
+)
+
+// SaleLine is a sold line item joined with its sale, product and category,
+// the raw input for sales analytics. Prices are the snapshots taken at sale
+// time, not current product data.
+type SaleLine struct {
+	SaleID       string
+	SoldAt       time.Time
+	CashierID    string
+	ProductID    string
+	ProductName  string
+	SKU          string
+	CategoryID   string
+	CategoryName string
+	Quantity     float64
+	UnitPrice    float64
+	CostPrice    float64
+}
+
+// MarginRow holds the gross profit of one group in a margin report.
+type MarginRow struct {
+	Key           string // Product, category or cashier ID, or the period start date
+	Label         string // Display name for the group
+	Sales         int    // Number of distinct sales
+	Quantity      float64
+	Revenue       float64
+	Cost          float64
+	Profit        float64
+	MarginPercent float64 // Profit as a percentage of revenue
+}
+
+// MarginReport summarises gross profit over a date range.
+type MarginReport struct {
+	GroupBy       string
+	From          *time.Time
+	To            *time.Time
+	Revenue       float64
+	Cost          float64
+	Profit        float64
+	MarginPercent float64
+	Rows          []MarginRow
+}
+
+// SalesBucket holds sales totals for one period of a time series.
+type SalesBucket struct {
+	Start         time.Time
+	Revenue       float64
+	Units         float64
+	Transactions  int
+	AverageBasket float64 // Revenue per transaction
+}
+
+// SalesPeriod holds sales totals over a date range and their breakdown into
+// buckets of equal interval.
+type SalesPeriod struct {
+	From          time.Time
+	To            time.Time
+	Revenue       float64
+	Units         float64
+	Transactions  int
+	AverageBasket float64
+	Buckets       []SalesBucket
+}
+
+// SalesComparison compares a baseline period with the reported one. Changes
+// are percentages relative to the baseline, nil when the baseline is zero.
+type SalesComparison struct {
+	Period              SalesPeriod
+	RevenueChange       *float64
+	UnitsChange         *float64
+	TransactionsChange  *float64
+	AverageBasketChange *float64
+}
+
+// SalesTimeSeries reports sales over a date range bucketed by interval,
+// compared with the period before it and the same period a year earlier.
+type SalesTimeSeries struct {
+	Interval       string // GroupByHour, GroupByDay, GroupByWeek or GroupByMonth
+	Current        SalesPeriod
+	PreviousPeriod SalesComparison
+	LastYear       SalesComparison
+}
+
+// SaleLineQuery selects the sale lines to total and how to group them.
+type SaleLineQuery struct {
+	From        *time.Time
+	To          *time.Time
+	CategoryIDs []string // Only lines of products in these categories; nil for all
+	BelowCost   bool     // Only lines sold below their cost price
+	GroupBy     string   // GroupByProduct, GroupByCategory, GroupByCashier, or empty for one group
+	Period      string   // Also split groups by GroupByHour, GroupByDay, GroupByWeek or GroupByMonth
+}
+
+// SaleLineSum totals the sale lines of one group, the input for margin
+// reports and product classification.
+type SaleLineSum struct {
+	Key      string    // Product, category or cashier ID; empty for lines without one
+	Label    string    // Product or category name
+	Period   time.Time // Start of the local period, when split by period
+	Sales    int       // Number of distinct sales
+	Quantity float64
+	Revenue  float64
+	Cost     float64
+}
+
+// ABC classes rank products by their share of revenue; XYZ classes rank them
+// by how steady their demand is.
+const (
+	ClassA = "A" // Top products making up most of the revenue
+	ClassB = "B"
+	ClassC = "C"
+	ClassX = "X" // Steady demand
+	ClassY = "Y" // Variable demand
+	ClassZ = "Z" // Erratic or no demand
+)
+
+// ProductActivity is a product's stock position and the time it last sold,
+// nil if it never has.
+type ProductActivity struct {
+	ProductID  string
+	SKU        string
+	Name       string
+	CategoryID string
+	Quantity   float64
+	CostPrice  float64
+	BasePrice  float64
+	LastSoldAt *time.Time
+}
+
+// ProductClassification holds a product's ABC and XYZ classes and the
+// figures they were derived from.
+type ProductClassification struct {
+	ProductID       string
+	SKU             string
+	Name            string
+	Revenue         float64
+	RevenueShare    float64 // Percentage of total revenue
+	CumulativeShare float64 // Percentage of revenue from this and every higher-ranked product
+	ABCClass        string
+	Units           float64
+	MeanDemand      float64 // Average units sold per week
+	DemandCV        float64 // Coefficient of variation of weekly demand
+	XYZClass        string
+}
+
+// ClassificationReport classifies every product over a date range.
+type ClassificationReport struct {
+	From     time.Time
+	To       time.Time
+	Weeks    int // Demand periods the XYZ classes were measured over
+	Revenue  float64
+	Counts   map[string]int // Products per combined class, e.g. "AX"
+	Products []ProductClassification
+}
+
+// DeadStockItem is a product with stock on hand that has not sold recently.
+type DeadStockItem struct {
+	ProductID     string
+	SKU           string
+	Name          string
+	CategoryID    string
+	Quantity      float64
+	CostPrice     float64
+	TiedUpCapital float64 // Quantity * CostPrice
+	LastSoldAt    *time.Time
+	DaysSinceSale *int // nil if never sold
+}
+
+// DeadStockReport lists the stock that has not sold since a cutoff.
+type DeadStockReport struct {
+	Days          int
+	Cutoff        time.Time
+	TiedUpCapital float64
+	Items         []DeadStockItem
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+// Kinds of value carried by a price-embedded barcode.
+const (
+	EmbeddedWeight = "weight"
+	EmbeddedPrice  = "price"
+)
+
+// EmbeddedBarcodeRule describes an in-store EAN-13 layout that carries a
+// weight or price, as printed by scales and labellers:
+// prefix + item code + embedded value + check digit.
+type EmbeddedBarcodeRule struct {
+	Prefix         string // Leading digits identifying the layout, e.g. "21"
+	Kind           string // EmbeddedWeight or EmbeddedPrice
+	ItemCodeLength int    // Digits after the prefix identifying the product (matched against SKU)
+	ValueDecimals  int    // Implied decimal places of the embedded value
+}
+
+// EmbeddedBarcode is the decoded content of a price-embedded barcode.
+type EmbeddedBarcode struct {
+	ItemCode string
+	Kind     string
+	Value    float64 // Weight in kg or price, depending on Kind
+}
+
+// Barcode formats accepted for product barcodes.
+const (
+	BarcodeEAN8   = "EAN-8"
+	BarcodeUPCA   = "UPC-A"
+	BarcodeEAN13  = "EAN-13"
+	BarcodeGTIN14 = "GTIN-14"
+)
+
+// ProductBarcode is one of the scannable codes assigned to a product.
+type ProductBarcode struct {
+	Code      string // As printed on the product
+	GTIN      string // Code zero-padded to 14 digits; unique across products
+	ProductID string
+	Format    string // One of the Barcode* formats
+	CreatedAt time.Time
+}
+
+// BarcodeMatch is the result of a barcode lookup. Pack is set when the code
+// belongs to one of the product's packs.
+type BarcodeMatch struct {
+	Product *Product
+	Pack    *ProductPack
+}
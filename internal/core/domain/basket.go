@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// ProductAssociation is an association rule mined from sales: customers who
+// bought ProductID also bought AssociatedProductID.
+type ProductAssociation struct {
+	ProductID           string
+	ProductName         string
+	AssociatedProductID string
+	AssociatedName      string
+	PairCount           int     // Sales containing both products
+	Support             float64 // Share of sales containing both products
+	Confidence          float64 // Share of sales with ProductID that also had AssociatedProductID
+	Lift                float64 // Confidence relative to how often AssociatedProductID sells anyway
+	ComputedAt          time.Time
+}
+
+// BasketAnalysis summarises one run of market basket mining.
+type BasketAnalysis struct {
+	From         time.Time
+	To           time.Time
+	Transactions int
+	Associations int
+	ComputedAt   time.Time
+}
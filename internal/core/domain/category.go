@@ -1,17 +1,76 @@
 package domain
 
+import "time"
+
 // AttributeDefinition defines a single rule for a product property.
 type AttributeDefinition struct {
 	Key      string   // e.g., "voltage"
-	Type     string   // "string", "number", "boolean", "select"
+	Type     string   // "string", "number", "boolean", "select", "multiselect", "date"
 	Required bool
-	Options  []string // for "select" types, e.g., ["Red", "Blue"]
+	Options  []string // for "select" and "multiselect" types, e.g., ["Red", "Blue"]
 	Unit     string   // e.g., "Volts", "Ohms"
+
+	Min        *float64    // Lowest allowed number
+	Max        *float64    // Highest allowed number
+	Step       *float64    // Numbers must be a whole number of steps above Min, or zero
+	Integer    bool        // Numbers must be whole
+	MinLength  *int        // Fewest characters in a string, or options in a multiselect
+	MaxLength  *int        // Most characters in a string, or options in a multiselect
+	Pattern    string      // Regular expression a whole string must match
+	Default    interface{} // Value given to new products that omit the property
+	Unique     bool        // No two products in the category may share a value
+	Searchable bool        // Values are indexed for full-text product search
 }
 
 // Category defines the blueprint (schema) for product properties.
+// Categories form a tree: a category inherits the attribute definitions of
+// its ancestors and may override them by key.
 type Category struct {
 	ID                   string
 	Name                 string // e.g., "Electrical", "Liquor"
+	ParentID             string // Empty for a top-level category
+	AttributeDefinitions []AttributeDefinition
+	Version              int // Incremented on every update, starting at 1
+
+	// Resolved from the tree by the category service
+	Path                []CategoryRef         // Ancestors from the top level down to and including this category
+	EffectiveAttributes []AttributeDefinition // Own definitions merged over inherited ones
+}
+
+// CategoryRef identifies a category in a breadcrumb path.
+type CategoryRef struct {
+	ID   string
+	Name string
+}
+
+// CategoryVersion is a revision of a category's name and attribute
+// definitions, kept so that schema changes can be traced.
+type CategoryVersion struct {
+	CategoryID           string
+	Version              int
+	Name                 string
 	AttributeDefinitions []AttributeDefinition
+	CreatedAt            time.Time
+}
+
+// PropertyViolation records a product whose properties fail its category's
+// attribute definitions.
+type PropertyViolation struct {
+	ProductID string
+	SKU       string
+	Error     string
+}
+
+// SchemaEvolution reports the effect of a category schema change on the
+// products already in the category.
+type SchemaEvolution struct {
+	CategoryID  string
+	FromVersion int
+	ToVersion   int
+	Products    int // Products in the category that were checked
+	Backfilled  int // Products given a default for a missing property
+	Migrated    int // Products with a property renamed or converted to its new type
+	Reindexed   int // Products whose searchable attribute values changed
+	Violations  []PropertyViolation
+	Applied     bool // False for a dry run or when violations blocked the change
 }
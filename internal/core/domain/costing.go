@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// Costing methods used to carry stock at cost.
+const (
+	CostingWeightedAverage = "average" // Moving average of every unit received
+	CostingFIFO            = "fifo"    // Oldest received units are sold first
+	CostingLastCost        = "last"    // Latest receipt cost applies to all stock
+)
+
+// CostLayer is a quantity of a product received at one unit cost. Layers are
+// consumed oldest first as stock leaves, so the open layers are the units
+// still on hand.
+type CostLayer struct {
+	ID                string
+	ProductID         string
+	QuantityReceived  float64
+	QuantityRemaining float64
+	UnitCost          float64 // Per unit of measure
+	ReceivedAt        time.Time
+}
@@ -0,0 +1,42 @@
+package domain
+
+// FacetRequest lists the facets to count alongside a product search.
+type FacetRequest struct {
+	AttributeKeys []string  // Properties to count the values of
+	PriceBounds   []float64 // Ascending bounds between price buckets
+}
+
+// FacetValue counts the products with one value of a facet.
+type FacetValue struct {
+	Value string
+	Label string // Display name where the value is an ID, e.g. a category's name
+	Count int
+}
+
+// AttributeFacet counts products by the values of a select or boolean
+// attribute.
+type AttributeFacet struct {
+	Key    string
+	Type   string
+	Values []FacetValue
+}
+
+// PriceBucket counts the products priced from Min up to, but not including,
+// Max. The lowest bucket has no Min and the highest no Max.
+type PriceBucket struct {
+	Min   *float64
+	Max   *float64
+	Count int
+}
+
+// SearchFacets counts the products matching a search along each facet.
+// Attribute and price facets ignore the search's own filter on them, so the
+// values not chosen still show how many products choosing them would give.
+type SearchFacets struct {
+	Total      int
+	Categories []FacetValue
+	Attributes []AttributeFacet
+	Prices     []PriceBucket
+	InStock    int // Products with sellable stock
+	OutOfStock int
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// Demand forecasting models.
+const (
+	ForecastAuto          = "auto"           // Whichever model was most accurate on recent sales
+	ForecastMovingAverage = "moving_average" // Average daily units over the last week
+	ForecastSeasonal      = "seasonal"       // Exponential smoothing with a weekly pattern
+)
+
+// ForecastPoint is the forecast unit sales for one day, with a 95% band.
+type ForecastPoint struct {
+	Date  time.Time
+	Units float64
+	Lower float64
+	Upper float64
+}
+
+// ForecastAccuracy measures a model by forecasting days whose sales are
+// already known and comparing with the actuals.
+type ForecastAccuracy struct {
+	Model       string
+	HoldoutDays int
+	MAPE        *float64 // Mean absolute percentage error over days with sales; nil if none sold
+	MAE         float64  // Mean absolute error in units
+}
+
+// DemandForecast is a product's forecast daily unit sales.
+type DemandForecast struct {
+	ProductID   string
+	Model       string
+	HistoryDays int // Days of sales the model was fitted to
+	GeneratedAt time.Time
+	Points      []ForecastPoint
+	Accuracy    []ForecastAccuracy
+}
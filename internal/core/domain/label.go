@@ -0,0 +1,43 @@
+package domain
+
+// Sources a label field can print.
+const (
+	LabelSourceName      = "name"
+	LabelSourceSKU       = "sku"
+	LabelSourcePrice     = "price"      // BasePrice
+	LabelSourceUnitPrice = "unit_price" // Price per kg, l or m
+	LabelSourceBarcode   = "barcode"    // Scannable barcode of the product's GTIN or SKU
+	LabelSourceText      = "text"       // Fixed text from the field
+	LabelSourceProperty  = "property:"  // Prefix for a Properties key, e.g. "property:voltage"
+)
+
+// LabelField places one piece of product data on a label.
+type LabelField struct {
+	Source string  // One of the LabelSource* values
+	Text   string  // Fixed text for LabelSourceText
+	Format string  // Optional fmt format for the value, e.g. "$%.2f"
+	X      float64 // Distance from the label's left edge in mm
+	Y      float64 // Distance from the label's top edge in mm
+	Size   float64 // Font size in points; bar height in mm for barcodes
+}
+
+// LabelTemplate describes the size and layout of a shelf label or sticker.
+type LabelTemplate struct {
+	Name          string
+	WidthMM       float64
+	HeightMM      float64
+	Fields        []LabelField
+	NetContentKey string // Properties key holding pack contents such as "500 g", used for unit prices of items sold each
+}
+
+// Label is the product data printed on one label.
+type Label struct {
+	ProductID   string
+	Name        string
+	SKU         string
+	Price       float64
+	UnitPrice   float64 // Price per UnitMeasure; zero when not applicable
+	UnitMeasure string  // kg, l or m
+	Barcode     string  // First GTIN assigned to the product, or its SKU
+	Properties  map[string]interface{}
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// ProductPack is a multi-unit pack of a product, such as a case of 24 or a
+// 100 m drum, with its own barcode and price.
+type ProductPack struct {
+	ID           string
+	ProductID    string
+	Name         string
+	Barcode      string
+	GTIN         string  // Barcode zero-padded to 14 digits; unique across products and packs
+	UnitsPerPack float64 // Pack size in the product's unit of measure
+	Price        float64 // Price of a whole pack; zero means UnitsPerPack × BasePrice
+	CreatedAt    time.Time
+}
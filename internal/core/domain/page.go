@@ -0,0 +1,71 @@
+package domain
+
+// Sort orders.
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// Sorts. Products support all of them; categories sort by name and audit
+// logs by ID.
+const (
+	SortRelevance = "relevance" // Full-text match quality; needs a search query
+	SortName      = "name"
+	SortPrice     = "price"
+	SortQuantity  = "quantity"
+	SortUpdatedAt = "updated_at"
+	SortCreatedAt = "created_at"
+	SortID        = "id"
+)
+
+// PageKey is the position of an item in a sorted listing: its sort value
+// and, to break ties, its ID.
+type PageKey struct {
+	Value interface{}
+	ID    string
+}
+
+// PageRequest selects a page of a listing. Cursor is an opaque cursor from a
+// previous page, which services decode into After or Before; a page with
+// either starts from that key instead of Offset.
+type PageRequest struct {
+	Limit  int
+	Offset int
+	Sort   string
+	Order  string // SortAsc or SortDesc; defaults per sort
+	Cursor string
+	After  *PageKey // Items sorted after this key
+	Before *PageKey // Items sorted before this key, for the previous page
+}
+
+// PageInfo describes a page of a listing. Repositories fill in the keys of
+// the page's first and last items and whether there are items either side;
+// services encode those as the cursors of the neighbouring pages.
+type PageInfo struct {
+	Total      int // Items across every page
+	NextCursor string
+	PrevCursor string
+
+	First   *PageKey
+	Last    *PageKey
+	HasNext bool
+	HasPrev bool
+}
+
+// ProductPage is a page of products.
+type ProductPage struct {
+	Products []*Product
+	PageInfo
+}
+
+// CategoryPage is a page of categories.
+type CategoryPage struct {
+	Categories []*Category
+	PageInfo
+}
+
+// AuditLogPage is a page of audit logs.
+type AuditLogPage struct {
+	Logs []*AuditLog
+	PageInfo
+}
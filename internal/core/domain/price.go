@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// Scheduled price change statuses.
+const (
+	PriceChangeStatusPending   = "pending"   // Waiting for EffectiveFrom
+	PriceChangeStatusActive    = "active"    // Temporary price in effect until EffectiveTo
+	PriceChangeStatusCompleted = "completed" // Applied for good, or reverted at the end of its window
+	PriceChangeStatusCancelled = "cancelled"
+)
+
+// PriceHistoryEntry records a product's base price from the moment it was set.
+type PriceHistoryEntry struct {
+	ID        int64
+	ProductID string
+	Price     float64
+	ChangedAt time.Time
+}
+
+// ScheduledPriceChange is a base price change applied by the scheduler at
+// EffectiveFrom. When EffectiveTo is set the change is temporary and the
+// previous price is restored at the end of the window.
+type ScheduledPriceChange struct {
+	ID            string
+	ProductID     string
+	Price         float64
+	EffectiveFrom time.Time
+	EffectiveTo   *time.Time
+	Reason        string
+	Status        string
+	PreviousPrice *float64 // Price replaced when the change was applied
+	CreatedBy     string
+	CreatedAt     time.Time
+	AppliedAt     *time.Time
+	RevertedAt    *time.Time
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// PriceList is a named set of customer prices, e.g. for trade customers,
+// that can be assigned to a sale in place of base prices.
+type PriceList struct {
+	ID          string
+	Name        string
+	Description string
+	Rules       []*PriceListRule // Populated when a single list is fetched
+	CreatedAt   time.Time
+}
+
+// PriceListRule overrides the unit price of a product, or of every product in
+// a category, from a minimum quantity upwards. Several rules for the same
+// product or category with different MinQuantity values form quantity breaks.
+type PriceListRule struct {
+	ID              string
+	PriceListID     string
+	ProductID       string   // Applies to a single product
+	CategoryID      string   // Applies to every product in the category
+	MinQuantity     float64  // Lowest quantity in the sale the rule applies to
+	Price           *float64 // Fixed unit price
+	DiscountPercent *float64 // Percentage off the base price
+	CreatedAt       time.Time
+}
@@ -2,6 +2,18 @@ package domain
 
 import "time"
 
+// Units of measure a product can be stocked and sold in.
+const (
+	UnitEach     = "each"
+	UnitKilogram = "kg"
+	UnitMetre    = "m"
+	UnitLitre    = "l"
+)
+
+// DefaultQuantityPrecision asks for the unit of measure's usual precision
+// instead of an explicit number of decimal places.
+const DefaultQuantityPrecision = -1
+
 // Product represents a product entity in the system.
 // This is a pure business entity with no framework tags.
 type Product struct {
@@ -9,36 +21,85 @@ type Product struct {
 	Name       string
 	SKU        string
 	CategoryID string
-	BasePrice  float64
-	Quantity   int
+	BasePrice  float64 // Price per unit of measure
+	Quantity   float64 // On-hand stock in the unit of measure
 	CostPrice  float64
 	Properties map[string]interface{} // Flexible attributes (voltage, amperage, etc.)
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
+
+	QuarantinedQuantity float64 // Non-sellable stock held back by a recall
+	UnitOfMeasure       string  // One of the Unit* constants
+	QuantityPrecision   int     // Decimal places allowed in quantities, e.g. 3 for grams of a kg item, or DefaultQuantityPrecision
+
+	LabelChangedAt *time.Time // Last change to a field printed on labels; nil if unchanged since creation
+
+	PropertyOriginals map[string]string // Measured properties as entered, e.g. "0.22 kV", keyed like Properties
+
+	Description string // Long free-text description, searched with the name
+	SearchTerms string // Values of the category's searchable attributes, kept by the product service
 }
 
 // FilterOptions holds the parameters for searching and filtering products.
 type FilterOptions struct {
-	Query      string            // Full-text search query on name/sku
+	Query      string            // Full-text search query on name, sku, description and searchable attributes
+	Mode       string            // How Query is read; a SearchMode, FTS5 syntax by default
+	FuzzyIDs   []string          // Products also matching Query as misspellings, found by the service
 	CategoryID string            // Filter by category
 	MinPrice   *float64          // Minimum base_price
 	MaxPrice   *float64          // Maximum base_price
 	Properties map[string]string // Dynamic JSON property filters (key -> value)
-	Limit      int
-	Offset     int
+	Filters    []PropertyFilter  // Typed property comparisons, e.g. voltage gte 200
+	PageRequest
+
+	ChangedSince *time.Time // Name, SKU, price, unit or properties changed at or after this time
+}
+
+// Property filter operators. Which apply depends on the attribute's type.
+const (
+	FilterEq       = "eq"
+	FilterGt       = "gt"
+	FilterGte      = "gte"
+	FilterLt       = "lt"
+	FilterLte      = "lte"
+	FilterBetween  = "between"  // Value "min..max"; either end may be open
+	FilterIn       = "in"       // Comma-separated values, any of which matches
+	FilterPrefix   = "prefix"   // Case-insensitive
+	FilterContains = "contains" // Case-insensitive
+)
+
+// PropertyFilter compares a product property with a value. Value is the text
+// as given; the product service resolves it against the category's attribute
+// definitions into Type and Operands.
+type PropertyFilter struct {
+	Key   string
+	Op    string // One of the Filter* constants
+	Value string
+
+	Type     string        // Type of the attribute the filter applies to
+	Operands []interface{} // Typed values: one, two for between (nil for an open end) or any number for in
 }
 
 // CategoryBreakdown holds aggregated data for a single category.
 type CategoryBreakdown struct {
-	CategoryID   string
-	CategoryName string
-	Count        int
-	TotalValue   float64
+	CategoryID      string
+	CategoryName    string
+	Count           int
+	Quantity        float64
+	TotalValue      float64 // Stock valued at cost, quantity * cost_price
+	RetailValue     float64 // Stock valued at selling price, quantity * base_price
+	PotentialProfit float64 // RetailValue - TotalValue
 }
 
 // InventorySummary holds aggregated inventory analytics.
 type InventorySummary struct {
-	TotalItems      int
-	TotalValue      float64
+	CostingMethod     string // One of the Costing* constants the cost values were carried at
+	TotalItems        int
+	TotalQuantity     float64
+	TotalValue        float64 // Stock valued at cost
+	RetailValue       float64 // Stock valued at selling price
+	PotentialProfit   float64
+	MarginPercent     float64 // PotentialProfit as a percentage of RetailValue
+	QuarantinedValue  float64 // Recalled stock at cost, excluded from the totals above
 	CategoryBreakdown []CategoryBreakdown
 }
@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// Recall statuses.
+const (
+	RecallStatusActive = "active"
+	RecallStatusClosed = "closed"
+)
+
+// Recall represents a recall declared against a product. While a recall is
+// active the product cannot be sold and its on-hand stock is quarantined.
+type Recall struct {
+	ID                  string
+	ProductID           string
+	Reason              string
+	SoldFrom            *time.Time // Optional start of the affected sales window
+	SoldTo              *time.Time // Optional end of the affected sales window
+	Status              string
+	QuarantinedQuantity float64 // Stock moved out of Quantity when the recall was declared
+	CreatedAt           time.Time
+	ClosedAt            *time.Time
+}
+
+// RecallAffectedSale is a single sale line covered by a recall.
+type RecallAffectedSale struct {
+	SaleID    string
+	SoldAt    time.Time
+	Quantity  float64
+	UnitPrice float64
+}
+
+// RecallReport lists every sale and quantity affected by a recall.
+type RecallReport struct {
+	Recall        *Recall
+	Sales         []RecallAffectedSale
+	TotalQuantity float64
+	TotalValue    float64
+}
@@ -0,0 +1,18 @@
+package domain
+
+// Receipt is a printable view of a stored sale.
+type Receipt struct {
+	Sale   *Sale
+	Header []string // Shop name, address and similar lines printed at the top
+	Footer []string // Returns policy, thanks and similar lines printed at the bottom
+	Lines  []ReceiptLine
+}
+
+// ReceiptLine is one line item on a receipt.
+type ReceiptLine struct {
+	Name      string
+	Quantity  float64 // Units, or packs for pack lines
+	Unit      string  // Unit of measure for weighed and measured items; empty for items sold each
+	UnitPrice float64 // Price per unit or pack
+	Total     float64
+}
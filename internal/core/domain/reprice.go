@@ -0,0 +1,43 @@
+package domain
+
+// Bulk repricing rule types.
+const (
+	RepricePercentage   = "percentage"    // Value is a percentage added to the current price, negative to reduce
+	RepriceFixedAmount  = "fixed_amount"  // Value is added to the current price, negative to reduce
+	RepriceTargetMargin = "target_margin" // Value is the gross margin percentage over CostPrice
+)
+
+// Bulk repricing rounding modes.
+const (
+	RepriceRoundCents = ""    // Round to whole cents
+	RepriceRound99    = ".99" // Round up to the next price ending in .99
+)
+
+// RepriceRule describes how new base prices are calculated.
+type RepriceRule struct {
+	Type     string // One of the Reprice* rule types
+	Value    float64
+	Rounding string // One of the RepriceRound* modes
+}
+
+// RepriceChange is the before and after price of one product in a bulk
+// repricing. Skipped products keep their price and carry the reason.
+type RepriceChange struct {
+	ProductID  string
+	SKU        string
+	Name       string
+	OldPrice   float64
+	NewPrice   float64
+	CostPrice  float64
+	BelowCost  bool   // New price is below CostPrice
+	SkipReason string // Set when the product is left unchanged
+}
+
+// RepriceResult reports the outcome of a bulk repricing, or its preview.
+type RepriceResult struct {
+	BatchID string // Ties the audit entries of a committed run together; empty for a dry run
+	DryRun  bool
+	Changes []RepriceChange
+	Updated int
+	Skipped int
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// SaleRestriction is a regulatory rule enforced at checkout for a category or
+// a single product, e.g. liquor, tobacco or hazardous chemicals.
+type SaleRestriction struct {
+	ID                        string
+	CategoryID                string  // Applies to every product in the category
+	ProductID                 string  // Applies to a single product
+	MinimumAge                int     // Requires an ID check when greater than zero
+	SaleWindowStart           string  // Local time of day "HH:MM" from which sales are allowed
+	SaleWindowEnd             string  // Local time of day "HH:MM" until which sales are allowed
+	MaxQuantityPerTransaction float64 // Zero means unlimited
+	CreatedAt                 time.Time
+}
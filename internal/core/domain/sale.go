@@ -6,6 +6,9 @@ import "time"
 type Sale struct {
 	ID          string
 	TotalAmount float64
+	CashierID   string
+	IDVerified  bool   // Cashier attested that the customer's ID was checked
+	PriceListID string // Price list the sale was charged at; empty for base prices
 	CreatedAt   time.Time
 }
 
@@ -13,7 +16,8 @@ type Sale struct {
 type SaleItem struct {
 	SaleID    string
 	ProductID string
-	Quantity  int
-	UnitPrice float64 // Snapshot of BasePrice at time of sale
+	PackID    string  // Set when the line was sold as a whole pack
+	Quantity  float64 // In the product's unit of measure
+	UnitPrice float64 // Price per unit charged, a snapshot of BasePrice unless sold at a pack or label price
 	CostPrice float64 // Snapshot of CostPrice at time of sale
 }
@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// Search modes, which say how a product search's Query is read.
+const (
+	SearchModeFTS    = "fts"    // FTS5 query syntax, used as given (default)
+	SearchModeText   = "text"   // Plain text: punctuation is ignored, synonyms apply and every word must match
+	SearchModePrefix = "prefix" // As text, with every word matching as a prefix, for as-you-type lookup
+	SearchModeFuzzy  = "fuzzy"  // As text, also matching names and SKUs that look like misspellings
+)
+
+// SearchSynonym makes plain-text searches for a term also match its synonym
+// and the other way round, e.g. "mcb" and "circuit breaker".
+type SearchSynonym struct {
+	ID        string
+	Term      string
+	Synonym   string
+	CreatedAt time.Time
+}
+
+// ProductSuggestion is a product offered while a search is typed.
+type ProductSuggestion struct {
+	ID    string
+	Name  string
+	SKU   string
+	Fuzzy bool // Matched as a likely misspelling
+}
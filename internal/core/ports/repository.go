@@ -2,17 +2,26 @@ package ports
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/torantous1337/retail-management/internal/core/domain"
 )
 
+// ErrNotFound is wrapped by repositories when a requested record does not exist.
+var ErrNotFound = errors.New("not found")
+
 // ProductRepository defines the interface for product data access.
 type ProductRepository interface {
 	Create(ctx context.Context, product *domain.Product) error
 	GetByID(ctx context.Context, id string) (*domain.Product, error)
 	GetBySKU(ctx context.Context, sku string) (*domain.Product, error)
-	List(ctx context.Context, limit, offset int) ([]*domain.Product, error)
-	Search(ctx context.Context, opts domain.FilterOptions, allowedKeys []string) ([]*domain.Product, error)
+	Search(ctx context.Context, opts domain.FilterOptions, allowedKeys []string) (*domain.ProductPage, error)
+	CountFacets(ctx context.Context, opts domain.FilterOptions, allowedKeys []string, request domain.FacetRequest) (*domain.SearchFacets, error)
+	Suggest(ctx context.Context, match string, limit int) ([]domain.ProductSuggestion, error)
+	MatchTrigrams(ctx context.Context, match string, limit int) ([]domain.ProductSuggestion, error)
+	RebuildSearchIndex(ctx context.Context) error
+	ExistsWithProperty(ctx context.Context, categoryID, key string, value interface{}, excludeID string) (bool, error)
 	GetInventorySummary(ctx context.Context) (*domain.InventorySummary, error)
 	Update(ctx context.Context, product *domain.Product) error
 	Delete(ctx context.Context, id string) error
@@ -22,29 +31,142 @@ type ProductRepository interface {
 type CategoryRepository interface {
 	Create(ctx context.Context, category *domain.Category) error
 	GetByID(ctx context.Context, id string) (*domain.Category, error)
-	List(ctx context.Context, limit, offset int) ([]*domain.Category, error)
+	List(ctx context.Context, page domain.PageRequest) (*domain.CategoryPage, error)
+	Update(ctx context.Context, category *domain.Category) error
+	Delete(ctx context.Context, id string) error
+	ListVersions(ctx context.Context, categoryID string) ([]*domain.CategoryVersion, error)
+	ListAncestors(ctx context.Context, id string) ([]*domain.Category, error)
+	ListDescendants(ctx context.Context, id string) ([]*domain.Category, error)
+	CountReferences(ctx context.Context, id string) (int, error)
 }
 
 // AuditLogRepository defines the interface for audit log data access.
 type AuditLogRepository interface {
 	Create(ctx context.Context, log *domain.AuditLog) error
 	GetLastLog(ctx context.Context) (*domain.AuditLog, error)
-	List(ctx context.Context, limit, offset int) ([]*domain.AuditLog, error)
+	List(ctx context.Context, page domain.PageRequest) (*domain.AuditLogPage, error)
 	VerifyChain(ctx context.Context) (bool, error)
 }
 
+// SynonymRepository defines the interface for search synonym data access.
+type SynonymRepository interface {
+	Create(ctx context.Context, synonym *domain.SearchSynonym) error
+	List(ctx context.Context) ([]*domain.SearchSynonym, error)
+	Delete(ctx context.Context, id string) error
+}
+
 // SaleRepository defines the interface for sale data access.
 type SaleRepository interface {
 	CreateSale(ctx context.Context, sale *domain.Sale) error
 	CreateSaleItem(ctx context.Context, item *domain.SaleItem) error
+	GetSale(ctx context.Context, id string) (*domain.Sale, error)
+	ListSaleItems(ctx context.Context, saleID string) ([]*domain.SaleItem, error)
+}
+
+// RecallRepository defines the interface for product recall data access.
+type RecallRepository interface {
+	Create(ctx context.Context, recall *domain.Recall) error
+	GetByID(ctx context.Context, id string) (*domain.Recall, error)
+	GetActiveByProduct(ctx context.Context, productID string) (*domain.Recall, error)
+	List(ctx context.Context, limit, offset int) ([]*domain.Recall, error)
+	Update(ctx context.Context, recall *domain.Recall) error
+	ListAffectedSales(ctx context.Context, productID string, from, to *time.Time) ([]domain.RecallAffectedSale, error)
+}
+
+// RestrictionRepository defines the interface for sale restriction data access.
+type RestrictionRepository interface {
+	Create(ctx context.Context, restriction *domain.SaleRestriction) error
+	GetByID(ctx context.Context, id string) (*domain.SaleRestriction, error)
+	List(ctx context.Context, limit, offset int) ([]*domain.SaleRestriction, error)
+	ListForProduct(ctx context.Context, productID string, categoryIDs []string) ([]*domain.SaleRestriction, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// PackRepository defines the interface for product pack data access.
+type PackRepository interface {
+	Create(ctx context.Context, pack *domain.ProductPack) error
+	GetByID(ctx context.Context, id string) (*domain.ProductPack, error)
+	GetByGTIN(ctx context.Context, gtin string) (*domain.ProductPack, error)
+	ListByProduct(ctx context.Context, productID string) ([]*domain.ProductPack, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// BarcodeRepository defines the interface for product barcode data access.
+type BarcodeRepository interface {
+	Create(ctx context.Context, barcode *domain.ProductBarcode) error
+	GetByGTIN(ctx context.Context, gtin string) (*domain.ProductBarcode, error)
+	ListByProduct(ctx context.Context, productID string) ([]*domain.ProductBarcode, error)
+	Delete(ctx context.Context, productID, gtin string) error
+}
+
+// PriceRepository defines the interface for price history and scheduled price change data access.
+type PriceRepository interface {
+	ListHistory(ctx context.Context, productID string, limit, offset int) ([]*domain.PriceHistoryEntry, error)
+	GetPriceAt(ctx context.Context, productID string, at time.Time) (*domain.PriceHistoryEntry, error)
+	SetLatestChangeTime(ctx context.Context, productID string, at time.Time) error
+	CreateScheduled(ctx context.Context, change *domain.ScheduledPriceChange) error
+	GetScheduled(ctx context.Context, id string) (*domain.ScheduledPriceChange, error)
+	ListScheduled(ctx context.Context, productID, status string, limit, offset int) ([]*domain.ScheduledPriceChange, error)
+	ListOpenByProduct(ctx context.Context, productID string) ([]*domain.ScheduledPriceChange, error)
+	ListDueToStart(ctx context.Context, now time.Time) ([]*domain.ScheduledPriceChange, error)
+	ListDueToEnd(ctx context.Context, now time.Time) ([]*domain.ScheduledPriceChange, error)
+	UpdateScheduled(ctx context.Context, change *domain.ScheduledPriceChange) error
+	DeleteByProduct(ctx context.Context, productID string) error
+}
+
+// PriceListRepository defines the interface for price list data access.
+type PriceListRepository interface {
+	Create(ctx context.Context, list *domain.PriceList) error
+	GetByID(ctx context.Context, id string) (*domain.PriceList, error)
+	List(ctx context.Context, limit, offset int) ([]*domain.PriceList, error)
+	Delete(ctx context.Context, id string) error
+	CreateRule(ctx context.Context, rule *domain.PriceListRule) error
+	ListRules(ctx context.Context, priceListID string) ([]*domain.PriceListRule, error)
+	ListRulesFor(ctx context.Context, priceListID, productID string, categoryIDs []string) ([]*domain.PriceListRule, error)
+	DeleteRule(ctx context.Context, priceListID, ruleID string) error
+	DeleteRulesByProduct(ctx context.Context, productID string) error
+}
+
+// CostLayerRepository defines the interface for stock cost layer data access.
+type CostLayerRepository interface {
+	Create(ctx context.Context, layer *domain.CostLayer) error
+	ListOpen(ctx context.Context, productID string) ([]*domain.CostLayer, error)
+	UpdateRemaining(ctx context.Context, id string, remaining float64) error
+	DeleteByProduct(ctx context.Context, productID string) error
+}
+
+// AnalyticsRepository defines the interface for reading sales data for reports.
+type AnalyticsRepository interface {
+	ListSaleLines(ctx context.Context, from, to *time.Time) ([]*domain.SaleLine, error)
+	SumSaleLines(ctx context.Context, query domain.SaleLineQuery) ([]*domain.SaleLineSum, error)
+	SumSales(ctx context.Context, from, to time.Time, interval string) ([]*domain.SalesBucket, error)
+	ListProductActivity(ctx context.Context) ([]*domain.ProductActivity, error)
+	ListProductSales(ctx context.Context, productID string, from, to time.Time) ([]*domain.SaleLine, error)
+}
+
+// AssociationRepository defines the interface for mined product association data access.
+type AssociationRepository interface {
+	DeleteAll(ctx context.Context) error
+	Create(ctx context.Context, association *domain.ProductAssociation) error
+	ListForProduct(ctx context.Context, productID string, limit int) ([]*domain.ProductAssociation, error)
+	ListTop(ctx context.Context, metric string, limit int) ([]*domain.ProductAssociation, error)
+	DeleteByProduct(ctx context.Context, productID string) error
 }
 
 // Ports bundles all repository interfaces for use in transactions.
 type Ports struct {
-	ProductRepo  ProductRepository
-	CategoryRepo CategoryRepository
-	AuditRepo    AuditLogRepository
-	SaleRepo     SaleRepository
+	ProductRepo     ProductRepository
+	CategoryRepo    CategoryRepository
+	AuditRepo       AuditLogRepository
+	SaleRepo        SaleRepository
+	RecallRepo      RecallRepository
+	RestrictionRepo RestrictionRepository
+	PackRepo        PackRepository
+	BarcodeRepo     BarcodeRepository
+	PriceRepo       PriceRepository
+	PriceListRepo   PriceListRepository
+	CostLayerRepo   CostLayerRepository
+	AssociationRepo AssociationRepository
 }
 
 // TransactionManager provides atomic transaction support.
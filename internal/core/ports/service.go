@@ -3,6 +3,7 @@ package ports
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/torantous1337/retail-management/internal/core/domain"
 )
@@ -12,39 +13,248 @@ type ProductService interface {
 	CreateProduct(ctx context.Context, product *domain.Product) error
 	GetProduct(ctx context.Context, id string) (*domain.Product, error)
 	GetProductBySKU(ctx context.Context, sku string) (*domain.Product, error)
-	ListProducts(ctx context.Context, limit, offset int) ([]*domain.Product, error)
-	SearchProducts(ctx context.Context, opts domain.FilterOptions) ([]*domain.Product, error)
+	ListProducts(ctx context.Context, page domain.PageRequest) (*domain.ProductPage, error)
+	SearchProducts(ctx context.Context, opts domain.FilterOptions) (*domain.ProductPage, error)
+	FacetProducts(ctx context.Context, opts domain.FilterOptions) (*domain.SearchFacets, error)
+	SuggestProducts(ctx context.Context, query string, limit int) ([]domain.ProductSuggestion, error)
 	UpdateProduct(ctx context.Context, product *domain.Product) error
 	DeleteProduct(ctx context.Context, id string) error
 	ImportProducts(ctx context.Context, categoryID string, csvReader io.Reader) (int, error)
+	RebuildSearchIndex(ctx context.Context) (int, error)
 }
 
 // CategoryService defines the interface for category business logic.
 type CategoryService interface {
 	CreateCategory(ctx context.Context, category *domain.Category) error
 	GetCategory(ctx context.Context, id string) (*domain.Category, error)
-	ListCategories(ctx context.Context, limit, offset int) ([]*domain.Category, error)
+	ListCategories(ctx context.Context, page domain.PageRequest) (*domain.CategoryPage, error)
+	UpdateCategory(ctx context.Context, id string, update CategoryUpdate) (*domain.SchemaEvolution, error)
+	DeleteCategory(ctx context.Context, id string) error
+	ListCategoryVersions(ctx context.Context, id string) ([]*domain.CategoryVersion, error)
+}
+
+// CategoryUpdate describes a change to a category's name and attribute
+// definitions, and how the properties of products already in the category
+// are brought in line with it.
+type CategoryUpdate struct {
+	Name                 string
+	ParentID             *string                       // Moves the category; an empty ID makes it top-level, nil keeps its parent
+	AttributeDefinitions *[]domain.AttributeDefinition // Replaces the category's own definitions; nil keeps them
+	ExpectedVersion      int                           // Rejects the update if the category has changed since; 0 skips the check
+	Renames              map[string]string             // Old property key -> new key
+	Defaults             map[string]interface{}        // Values for products missing a property
+	ConvertTypes         bool                          // Convert values to their attribute's type where possible
+	DryRun               bool                          // Report the effect without changing anything
 }
 
 // AuditService defines the interface for audit logging with tamper-proofing.
 type AuditService interface {
 	LogAction(ctx context.Context, action, userID string, payload map[string]interface{}) error
 	VerifyAuditChain(ctx context.Context) (bool, error)
-	GetAuditLogs(ctx context.Context, limit, offset int) ([]*domain.AuditLog, error)
+	GetAuditLogs(ctx context.Context, page domain.PageRequest) (*domain.AuditLogPage, error)
 }
 
-// SaleItemRequest represents a request to purchase a product.
+// SaleItemRequest represents a request to purchase a product, identified
+// either by ID or by a scanned barcode.
 type SaleItemRequest struct {
 	ProductID string
-	Quantity  int
+	PackID    string  // Sell whole packs; Quantity then counts packs
+	Barcode   string  // Scanned code, used when ProductID and PackID are empty
+	Quantity  float64 // In the product's unit of measure; taken from the label for price-embedded barcodes
+}
+
+// SaleRequest represents a checkout request from the till.
+type SaleRequest struct {
+	CashierID         string
+	IDVerified        bool       // Cashier attests the customer's ID was checked
+	CustomerBirthDate *time.Time // Optional, taken from the checked ID
+	PriceListID       string     // Optional price list to charge at instead of base prices
+	Items             []SaleItemRequest
 }
 
 // SaleService defines the interface for sale processing.
 type SaleService interface {
-	ProcessSale(ctx context.Context, items []SaleItemRequest) (*domain.Sale, error)
+	ProcessSale(ctx context.Context, req SaleRequest) (*domain.Sale, error)
+}
+
+// BarcodeService defines the interface for product barcodes and scanner lookups.
+type BarcodeService interface {
+	AddBarcode(ctx context.Context, productID, code string) (*domain.ProductBarcode, error)
+	ListBarcodes(ctx context.Context, productID string) ([]*domain.ProductBarcode, error)
+	RemoveBarcode(ctx context.Context, productID, code string) error
+	LookupBarcode(ctx context.Context, code string) (*domain.BarcodeMatch, error)
+}
+
+// LabelRenderer defines the interface for rendering labels in a printer format.
+type LabelRenderer interface {
+	ContentType() string
+	Render(w io.Writer, template domain.LabelTemplate, labels []domain.Label) error
+}
+
+// LabelRequest selects products and a layout for label printing. Products are
+// taken from ProductIDs when given, otherwise from Filter.
+type LabelRequest struct {
+	ProductIDs []string
+	Filter     domain.FilterOptions  // Search filter, including ChangedSince for reprints
+	Template   string                // Name of a built-in template
+	Custom     *domain.LabelTemplate // Overrides Template when set
+	Format     string                // Renderer name, e.g. "zpl" or "pdf"
+	Copies     int                   // Labels per product, defaults to 1
+}
+
+// LabelService defines the interface for shelf label and barcode sticker printing.
+type LabelService interface {
+	RenderLabels(ctx context.Context, req LabelRequest, w io.Writer) (contentType string, err error)
+	ListTemplates() []domain.LabelTemplate
+}
+
+// RepriceRequest selects products by search filter and a rule to reprice them by.
+type RepriceRequest struct {
+	Filter domain.FilterOptions
+	Rule   domain.RepriceRule
+	DryRun bool // Preview the before and after prices without saving
+	UserID string
+}
+
+// RepriceService defines the interface for bulk product repricing.
+type RepriceService interface {
+	RepriceProducts(ctx context.Context, req RepriceRequest) (*domain.RepriceResult, error)
+}
+
+// ReceiveStockRequest represents a delivery of stock for a product.
+type ReceiveStockRequest struct {
+	ProductID string
+	PackID    string   // Optional; when set Quantity counts packs
+	Quantity  float64  // In the product's unit of measure unless PackID is set
+	UnitCost  *float64 // Cost per unit received, per pack when PackID is set; nil keeps the product's cost
+	UserID    string
+}
+
+// InventoryService defines the interface for pack definitions and stock receiving.
+type InventoryService interface {
+	CreatePack(ctx context.Context, pack *domain.ProductPack) error
+	ListPacks(ctx context.Context, productID string) ([]*domain.ProductPack, error)
+	DeletePack(ctx context.Context, productID, packID string) error
+	ReceiveStock(ctx context.Context, req ReceiveStockRequest) (*domain.Product, error)
+}
+
+// ReceiptRenderer defines the interface for rendering a receipt in a printer format.
+type ReceiptRenderer interface {
+	Render(w io.Writer, receipt *domain.Receipt) error
+}
+
+// PrinterSink defines the interface for sending rendered output to a printer.
+type PrinterSink interface {
+	Print(ctx context.Context, data []byte) error
+}
+
+// ReceiptService defines the interface for rendering and printing sale receipts.
+type ReceiptService interface {
+	RenderReceipt(ctx context.Context, saleID string, w io.Writer) error
+	PrintReceipt(ctx context.Context, saleID string) error
+}
+
+// RestrictionService defines the interface for managing checkout restriction rules.
+type RestrictionService interface {
+	CreateRestriction(ctx context.Context, restriction *domain.SaleRestriction) error
+	GetRestriction(ctx context.Context, id string) (*domain.SaleRestriction, error)
+	ListRestrictions(ctx context.Context, limit, offset int) ([]*domain.SaleRestriction, error)
+	DeleteRestriction(ctx context.Context, id string) error
+}
+
+// SynonymService defines the interface for managing search synonyms.
+type SynonymService interface {
+	AddSynonym(ctx context.Context, synonym *domain.SearchSynonym) error
+	ListSynonyms(ctx context.Context) ([]*domain.SearchSynonym, error)
+	RemoveSynonym(ctx context.Context, id string) error
+}
+
+// RecallService defines the interface for the product recall workflow.
+type RecallService interface {
+	DeclareRecall(ctx context.Context, recall *domain.Recall) (*domain.RecallReport, error)
+	GetRecall(ctx context.Context, id string) (*domain.Recall, error)
+	ListRecalls(ctx context.Context, limit, offset int) ([]*domain.Recall, error)
+	GetRecallReport(ctx context.Context, id string) (*domain.RecallReport, error)
+	CloseRecall(ctx context.Context, id string, releaseStock bool) (*domain.Recall, error)
+}
+
+// PricingService defines the interface for price history and scheduled price changes.
+type PricingService interface {
+	GetPriceHistory(ctx context.Context, productID string, limit, offset int) ([]*domain.PriceHistoryEntry, error)
+	GetPriceAt(ctx context.Context, productID string, at time.Time) (*domain.PriceHistoryEntry, error)
+	SchedulePriceChange(ctx context.Context, change *domain.ScheduledPriceChange) error
+	GetScheduledChange(ctx context.Context, id string) (*domain.ScheduledPriceChange, error)
+	ListScheduledChanges(ctx context.Context, productID, status string, limit, offset int) ([]*domain.ScheduledPriceChange, error)
+	CancelScheduledChange(ctx context.Context, id, userID string) (*domain.ScheduledPriceChange, error)
+	ApplyDueChanges(ctx context.Context) (int, error)
+}
+
+// PriceListService defines the interface for customer price lists.
+type PriceListService interface {
+	CreatePriceList(ctx context.Context, list *domain.PriceList) error
+	GetPriceList(ctx context.Context, id string) (*domain.PriceList, error)
+	ListPriceLists(ctx context.Context, limit, offset int) ([]*domain.PriceList, error)
+	DeletePriceList(ctx context.Context, id string) error
+	AddRule(ctx context.Context, rule *domain.PriceListRule) error
+	RemoveRule(ctx context.Context, priceListID, ruleID string) error
+	QuotePrice(ctx context.Context, priceListID, productID string, quantity float64) (float64, error)
+}
+
+// MarginQuery selects the sales and grouping for a margin report.
+type MarginQuery struct {
+	GroupBy    string // One of the domain.GroupBy* constants
+	From       *time.Time
+	To         *time.Time
+	CategoryID string // Limits the report to a category and the categories below it
+}
+
+// SalesSeriesQuery selects the date range and bucket interval of a sales
+// time series.
+type SalesSeriesQuery struct {
+	Interval string // domain.GroupByHour, GroupByDay, GroupByWeek or GroupByMonth
+	From     time.Time
+	To       time.Time
+}
+
+// ClassificationQuery selects the date range and class boundaries of an
+// ABC/XYZ classification. Zero values select the defaults.
+type ClassificationQuery struct {
+	From       time.Time // Defaults to 90 days before To
+	To         time.Time // Defaults to now
+	AThreshold float64   // Cumulative revenue percentage covered by class A, default 80
+	BThreshold float64   // Cumulative revenue percentage covered by classes A and B, default 95
+	XThreshold float64   // Highest demand variation in class X, default 0.5
+	YThreshold float64   // Highest demand variation in class Y, default 1.0
+	CategoryID string    // Limits the classification to a category and the categories below it
 }
 
 // AnalyticsService defines the interface for analytics and reporting.
 type AnalyticsService interface {
 	GetInventorySummary(ctx context.Context) (*domain.InventorySummary, error)
+	GetMarginReport(ctx context.Context, query MarginQuery) (*domain.MarginReport, error)
+	GetPerformers(ctx context.Context, query MarginQuery, metric string, limit int) (top, bottom []domain.MarginRow, err error)
+	GetNegativeMargins(ctx context.Context, from, to *time.Time) ([]domain.MarginRow, error)
+	GetSalesTimeSeries(ctx context.Context, query SalesSeriesQuery) (*domain.SalesTimeSeries, error)
+	ClassifyProducts(ctx context.Context, query ClassificationQuery) (*domain.ClassificationReport, error)
+	GetDeadStock(ctx context.Context, days int, categoryID string) (*domain.DeadStockReport, error)
+}
+
+// BasketService defines the interface for market basket analysis.
+type BasketService interface {
+	RecomputeAssociations(ctx context.Context) (*domain.BasketAnalysis, error)
+	FrequentlyBoughtWith(ctx context.Context, productID string, limit int) ([]*domain.ProductAssociation, error)
+	TopAssociations(ctx context.Context, metric string, limit int) ([]*domain.ProductAssociation, error)
+}
+
+// ForecastQuery selects the product, horizon and model of a demand forecast.
+type ForecastQuery struct {
+	ProductID   string
+	Days        int    // Days ahead to forecast, default 14
+	HistoryDays int    // Days of past sales to fit, default 90
+	Model       string // One of the domain.Forecast* constants, default auto
+}
+
+// ForecastService defines the interface for demand forecasting.
+type ForecastService interface {
+	ForecastProduct(ctx context.Context, query ForecastQuery) (*domain.DemandForecast, error)
 }
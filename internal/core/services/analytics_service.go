@@ -2,24 +2,644 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
 
 	"github.com/torantous1337/retail-management/internal/core/domain"
 	"github.com/torantous1337/retail-management/internal/core/ports"
 )
 
+// ErrInvalidAnalyticsQuery is returned when a report's grouping, metric or
+// date range is not usable.
+var ErrInvalidAnalyticsQuery = errors.New("invalid analytics query")
+
+// maxSeriesBuckets caps the buckets in one sales time series.
+const maxSeriesBuckets = 5000
+
+// Performer ranking metrics.
+const (
+	MetricProfit  = "profit"
+	MetricMargin  = "margin"
+	MetricRevenue = "revenue"
+)
+
 // AnalyticsService implements the analytics business logic.
 type AnalyticsService struct {
-	productRepo ports.ProductRepository
+	productRepo   ports.ProductRepository
+	categoryRepo  ports.CategoryRepository
+	analyticsRepo ports.AnalyticsRepository
+	costingMethod string
+	now           func() time.Time // clock used for default report ranges
 }
 
 // NewAnalyticsService creates a new analytics service instance.
-func NewAnalyticsService(productRepo ports.ProductRepository) *AnalyticsService {
+func NewAnalyticsService(productRepo ports.ProductRepository, categoryRepo ports.CategoryRepository, analyticsRepo ports.AnalyticsRepository) *AnalyticsService {
 	return &AnalyticsService{
-		productRepo: productRepo,
+		productRepo:   productRepo,
+		categoryRepo:  categoryRepo,
+		analyticsRepo: analyticsRepo,
+		costingMethod: domain.CostingWeightedAverage,
+		now:           time.Now,
 	}
 }
 
-// GetInventorySummary returns aggregated inventory analytics.
+// SetCostingMethod records the costing method product cost prices are
+// carried at, reported alongside inventory valuations.
+func (s *AnalyticsService) SetCostingMethod(method string) {
+	s.costingMethod = method
+}
+
+// GetInventorySummary returns the stock on hand valued at cost and at
+// selling price, with the potential margin, per category and in total.
 func (s *AnalyticsService) GetInventorySummary(ctx context.Context) (*domain.InventorySummary, error) {
-	return s.productRepo.GetInventorySummary(ctx)
+	summary, err := s.productRepo.GetInventorySummary(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	summary.CostingMethod = s.costingMethod
+	summary.TotalValue = roundMoney(summary.TotalValue)
+	summary.RetailValue = roundMoney(summary.RetailValue)
+	summary.PotentialProfit = roundMoney(summary.PotentialProfit)
+	summary.QuarantinedValue = roundMoney(summary.QuarantinedValue)
+	summary.MarginPercent = marginPercent(summary.PotentialProfit, summary.RetailValue)
+	for i := range summary.CategoryBreakdown {
+		bd := &summary.CategoryBreakdown[i]
+		bd.TotalValue = roundMoney(bd.TotalValue)
+		bd.RetailValue = roundMoney(bd.RetailValue)
+		bd.PotentialProfit = roundMoney(bd.PotentialProfit)
+	}
+
+	return summary, nil
+}
+
+// GetMarginReport returns gross profit and margin for sales in the query's
+// date range, grouped by product, category, cashier or period. Figures come
+// from the prices snapshotted on each sale line. A category limits the report
+// to products in it and the categories below it. Period rows are in date
+// order; other rows are ordered by profit, highest first.
+func (s *AnalyticsService) GetMarginReport(ctx context.Context, query ports.MarginQuery) (*domain.MarginReport, error) {
+	if query.GroupBy == "" {
+		query.GroupBy = domain.GroupByProduct
+	}
+	if err := validateMarginQuery(query); err != nil {
+		return nil, err
+	}
+
+	scope, err := s.categoryScope(ctx, query.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	lineQuery := domain.SaleLineQuery{From: query.From, To: query.To, GroupBy: query.GroupBy}
+	if isPeriodGrouping(query.GroupBy) {
+		lineQuery.GroupBy, lineQuery.Period = "", query.GroupBy
+	}
+	if scope != nil {
+		lineQuery.CategoryIDs = make([]string, 0, len(scope))
+		for id := range scope {
+			lineQuery.CategoryIDs = append(lineQuery.CategoryIDs, id)
+		}
+		sort.Strings(lineQuery.CategoryIDs)
+	}
+	sums, err := s.analyticsRepo.SumSaleLines(ctx, lineQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.MarginReport{
+		GroupBy: query.GroupBy,
+		From:    query.From,
+		To:      query.To,
+		Rows:    marginRows(sums, query.GroupBy),
+	}
+	for _, row := range report.Rows {
+		report.Revenue += row.Revenue
+		report.Cost += row.Cost
+	}
+	report.Revenue = roundMoney(report.Revenue)
+	report.Cost = roundMoney(report.Cost)
+	report.Profit = roundMoney(report.Revenue - report.Cost)
+	report.MarginPercent = marginPercent(report.Profit, report.Revenue)
+
+	if isPeriodGrouping(query.GroupBy) {
+		sort.SliceStable(report.Rows, func(i, j int) bool { return report.Rows[i].Key < report.Rows[j].Key })
+	} else {
+		sortMargins(report.Rows, MetricProfit)
+	}
+
+	return report, nil
+}
+
+// GetPerformers returns the best and worst groups in a margin report ranked
+// by profit, margin or revenue. Each list holds at most limit rows.
+func (s *AnalyticsService) GetPerformers(ctx context.Context, query ports.MarginQuery, metric string, limit int) ([]domain.MarginRow, []domain.MarginRow, error) {
+	if metric == "" {
+		metric = MetricProfit
+	}
+	if metric != MetricProfit && metric != MetricMargin && metric != MetricRevenue {
+		return nil, nil, fmt.Errorf("%w: unknown metric %q", ErrInvalidAnalyticsQuery, metric)
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+
+	report, err := s.GetMarginReport(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows := report.Rows
+	sortMargins(rows, metric)
+
+	n := limit
+	if n > len(rows) {
+		n = len(rows)
+	}
+	top := append([]domain.MarginRow(nil), rows[:n]...)
+	bottom := make([]domain.MarginRow, 0, n)
+	for i := len(rows) - 1; i >= len(rows)-n; i-- {
+		bottom = append(bottom, rows[i])
+	}
+
+	return top, bottom, nil
+}
+
+// GetNegativeMargins returns the products that were sold below their cost
+// price in the date range, with the quantity and loss from those lines only.
+// The largest losses come first.
+func (s *AnalyticsService) GetNegativeMargins(ctx context.Context, from, to *time.Time) ([]domain.MarginRow, error) {
+	if err := validateMarginQuery(ports.MarginQuery{GroupBy: domain.GroupByProduct, From: from, To: to}); err != nil {
+		return nil, err
+	}
+
+	sums, err := s.analyticsRepo.SumSaleLines(ctx, domain.SaleLineQuery{
+		From:      from,
+		To:        to,
+		BelowCost: true,
+		GroupBy:   domain.GroupByProduct,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows := marginRows(sums, domain.GroupByProduct)
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Profit < rows[j].Profit })
+	return rows, nil
+}
+
+// GetSalesTimeSeries returns revenue, units, transactions and average basket
+// value over the query's date range, bucketed by hour, day, week or month in
+// local time. Empty buckets are included. The same figures are reported for
+// the period of equal length just before the range and for the range one year
+// earlier, with the percentage change from each.
+func (s *AnalyticsService) GetSalesTimeSeries(ctx context.Context, query ports.SalesSeriesQuery) (*domain.SalesTimeSeries, error) {
+	if query.Interval == "" {
+		query.Interval = domain.GroupByDay
+	}
+	if query.Interval != domain.GroupByHour && !isPeriodGrouping(query.Interval) {
+		return nil, fmt.Errorf("%w: unknown interval %q", ErrInvalidAnalyticsQuery, query.Interval)
+	}
+	if query.From.IsZero() || query.To.IsZero() {
+		return nil, fmt.Errorf("%w: from and to are required", ErrInvalidAnalyticsQuery)
+	}
+	if query.From.After(query.To) {
+		return nil, fmt.Errorf("%w: from must not be after to", ErrInvalidAnalyticsQuery)
+	}
+
+	current, err := s.salesPeriod(ctx, query.From, query.To, query.Interval)
+	if err != nil {
+		return nil, err
+	}
+
+	previousTo := query.From.Add(-time.Nanosecond)
+	previous, err := s.salesPeriod(ctx, previousTo.Add(-query.To.Sub(query.From)), previousTo, query.Interval)
+	if err != nil {
+		return nil, err
+	}
+
+	lastYear, err := s.salesPeriod(ctx, query.From.AddDate(-1, 0, 0), query.To.AddDate(-1, 0, 0), query.Interval)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.SalesTimeSeries{
+		Interval:       query.Interval,
+		Current:        *current,
+		PreviousPeriod: compareSales(current, previous),
+		LastYear:       compareSales(current, lastYear),
+	}, nil
+}
+
+// ClassifyProducts assigns every product an ABC class by its share of
+// revenue and an XYZ class by the variation of its weekly demand over the
+// query's date range. Products are listed by revenue, highest first; products
+// without sales are class C and Z.
+func (s *AnalyticsService) ClassifyProducts(ctx context.Context, query ports.ClassificationQuery) (*domain.ClassificationReport, error) {
+	query = classificationDefaults(query, s.now())
+	if query.From.After(query.To) {
+		return nil, fmt.Errorf("%w: from must not be after to", ErrInvalidAnalyticsQuery)
+	}
+	if query.AThreshold <= 0 || query.BThreshold < query.AThreshold || query.BThreshold > 100 {
+		return nil, fmt.Errorf("%w: ABC thresholds must satisfy 0 < a <= b <= 100", ErrInvalidAnalyticsQuery)
+	}
+	if query.XThreshold <= 0 || query.YThreshold < query.XThreshold {
+		return nil, fmt.Errorf("%w: XYZ thresholds must satisfy 0 < x <= y", ErrInvalidAnalyticsQuery)
+	}
+
+	products, err := s.productActivity(ctx, query.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	sums, err := s.analyticsRepo.SumSaleLines(ctx, domain.SaleLineQuery{
+		From:    &query.From,
+		To:      &query.To,
+		GroupBy: domain.GroupByProduct,
+		Period:  domain.GroupByWeek,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Weekly demand buckets covering the range
+	weeks := make(map[int64]int)
+	for start := periodStart(query.From, domain.GroupByWeek); !start.After(query.To); start = nextPeriod(start, domain.GroupByWeek) {
+		weeks[start.Unix()] = len(weeks)
+	}
+
+	index := make(map[string]int, len(products))
+	rows := make([]domain.ProductClassification, 0, len(products))
+	demand := make([][]float64, 0, len(products))
+	for _, product := range products {
+		index[product.ProductID] = len(rows)
+		rows = append(rows, domain.ProductClassification{
+			ProductID: product.ProductID,
+			SKU:       product.SKU,
+			Name:      product.Name,
+		})
+		demand = append(demand, make([]float64, len(weeks)))
+	}
+
+	report := &domain.ClassificationReport{
+		From:   query.From,
+		To:     query.To,
+		Weeks:  len(weeks),
+		Counts: make(map[string]int),
+	}
+	for _, sum := range sums {
+		i, ok := index[sum.Key]
+		if !ok {
+			continue
+		}
+		rows[i].Revenue += sum.Revenue
+		rows[i].Units += sum.Quantity
+		if week, ok := weeks[periodStart(sum.Period, domain.GroupByWeek).Unix()]; ok {
+			demand[i][week] += sum.Quantity
+		}
+		report.Revenue += sum.Revenue
+	}
+
+	for i := range rows {
+		rows[i].Revenue = roundMoney(rows[i].Revenue)
+		rows[i].Units = roundQuantity(rows[i].Units, 3)
+		rows[i].MeanDemand, rows[i].DemandCV = demandVariation(demand[i])
+		rows[i].XYZClass = classifyXYZ(rows[i].MeanDemand, rows[i].DemandCV, query.XThreshold, query.YThreshold)
+	}
+	report.Revenue = roundMoney(report.Revenue)
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].Revenue != rows[j].Revenue {
+			return rows[i].Revenue > rows[j].Revenue
+		}
+		return rows[i].SKU < rows[j].SKU
+	})
+	classifyABC(rows, report.Revenue, query.AThreshold, query.BThreshold)
+
+	for _, row := range rows {
+		report.Counts[row.ABCClass+row.XYZClass]++
+	}
+	report.Products = rows
+
+	return report, nil
+}
+
+// GetDeadStock returns the products with stock on hand that have not sold
+// in the last days days, including those never sold, with the capital tied up
+// in them at cost. A category limits the report to products in it and the
+// categories below it. The most capital comes first.
+func (s *AnalyticsService) GetDeadStock(ctx context.Context, days int, categoryID string) (*domain.DeadStockReport, error) {
+	if days <= 0 {
+		return nil, fmt.Errorf("%w: days must be positive", ErrInvalidAnalyticsQuery)
+	}
+
+	products, err := s.productActivity(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	report := &domain.DeadStockReport{
+		Days:   days,
+		Cutoff: now.AddDate(0, 0, -days),
+		Items:  []domain.DeadStockItem{},
+	}
+	for _, product := range products {
+		if product.Quantity <= 0 {
+			continue
+		}
+		if product.LastSoldAt != nil && !product.LastSoldAt.Before(report.Cutoff) {
+			continue
+		}
+
+		item := domain.DeadStockItem{
+			ProductID:     product.ProductID,
+			SKU:           product.SKU,
+			Name:          product.Name,
+			CategoryID:    product.CategoryID,
+			Quantity:      product.Quantity,
+			CostPrice:     product.CostPrice,
+			TiedUpCapital: roundMoney(product.Quantity * product.CostPrice),
+			LastSoldAt:    product.LastSoldAt,
+		}
+		if product.LastSoldAt != nil {
+			since := int(now.Sub(*product.LastSoldAt).Hours() / 24)
+			item.DaysSinceSale = &since
+		}
+		report.Items = append(report.Items, item)
+		report.TiedUpCapital += item.TiedUpCapital
+	}
+	report.TiedUpCapital = roundMoney(report.TiedUpCapital)
+
+	sort.SliceStable(report.Items, func(i, j int) bool {
+		return report.Items[i].TiedUpCapital > report.Items[j].TiedUpCapital
+	})
+
+	return report, nil
+}
+
+// productActivity lists every product's stock and last sale, limited to a
+// category and the categories below it when categoryID is set.
+func (s *AnalyticsService) productActivity(ctx context.Context, categoryID string) ([]*domain.ProductActivity, error) {
+	scope, err := s.categoryScope(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	products, err := s.analyticsRepo.ListProductActivity(ctx)
+	if err != nil || scope == nil {
+		return products, err
+	}
+
+	var inScope []*domain.ProductActivity
+	for _, product := range products {
+		if scope[product.CategoryID] {
+			inScope = append(inScope, product)
+		}
+	}
+	return inScope, nil
+}
+
+// categoryScope returns the IDs of a category and every category below it,
+// or nil when id is empty and reports cover all categories.
+func (s *AnalyticsService) categoryScope(ctx context.Context, id string) (map[string]bool, error) {
+	if id == "" {
+		return nil, nil
+	}
+
+	if _, err := s.categoryRepo.GetByID(ctx, id); err != nil {
+		return nil, fmt.Errorf("%w: category %s: %v", ErrInvalidAnalyticsQuery, id, err)
+	}
+	descendants, err := s.categoryRepo.ListDescendants(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := map[string]bool{id: true}
+	for _, d := range descendants {
+		scope[d.ID] = true
+	}
+	return scope, nil
+}
+
+// salesPeriod totals the sales made within [from, to] and buckets them by
+// interval.
+func (s *AnalyticsService) salesPeriod(ctx context.Context, from, to time.Time, interval string) (*domain.SalesPeriod, error) {
+	period := &domain.SalesPeriod{From: from, To: to}
+	index := make(map[int64]int)
+	for start := periodStart(from, interval); !start.After(to); start = nextPeriod(start, interval) {
+		if len(period.Buckets) == maxSeriesBuckets {
+			return nil, fmt.Errorf("%w: range has more than %d %s buckets", ErrInvalidAnalyticsQuery, maxSeriesBuckets, interval)
+		}
+		index[start.Unix()] = len(period.Buckets)
+		period.Buckets = append(period.Buckets, domain.SalesBucket{Start: start})
+	}
+
+	sums, err := s.analyticsRepo.SumSales(ctx, from, to, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sum := range sums {
+		i, ok := index[periodStart(sum.Start, interval).Unix()]
+		if !ok {
+			continue
+		}
+		bucket := &period.Buckets[i]
+		bucket.Revenue += sum.Revenue
+		bucket.Units += sum.Units
+		bucket.Transactions += sum.Transactions
+	}
+
+	for i := range period.Buckets {
+		bucket := &period.Buckets[i]
+		period.Revenue += bucket.Revenue
+		period.Units += bucket.Units
+		period.Transactions += bucket.Transactions
+
+		bucket.Revenue = roundMoney(bucket.Revenue)
+		bucket.Units = roundQuantity(bucket.Units, 3)
+		bucket.AverageBasket = averageBasket(bucket.Revenue, bucket.Transactions)
+	}
+	period.Revenue = roundMoney(period.Revenue)
+	period.Units = roundQuantity(period.Units, 3)
+	period.AverageBasket = averageBasket(period.Revenue, period.Transactions)
+
+	return period, nil
+}
+
+// compareSales reports a baseline period with the percentage change from it
+// to the current period.
+func compareSales(current, baseline *domain.SalesPeriod) domain.SalesComparison {
+	return domain.SalesComparison{
+		Period:              *baseline,
+		RevenueChange:       percentChange(current.Revenue, baseline.Revenue),
+		UnitsChange:         percentChange(current.Units, baseline.Units),
+		TransactionsChange:  percentChange(float64(current.Transactions), float64(baseline.Transactions)),
+		AverageBasketChange: percentChange(current.AverageBasket, baseline.AverageBasket),
+	}
+}
+
+// percentChange returns the change from baseline to value as a percentage,
+// or nil when the baseline is zero.
+func percentChange(value, baseline float64) *float64 {
+	if baseline == 0 {
+		return nil
+	}
+	change := roundMoney((value - baseline) / baseline * 100)
+	return &change
+}
+
+// averageBasket returns revenue per transaction, or 0 without transactions.
+func averageBasket(revenue float64, transactions int) float64 {
+	if transactions == 0 {
+		return 0
+	}
+	return roundMoney(revenue / float64(transactions))
+}
+
+// periodStart returns the start of the hour, day, week or month containing t,
+// in local time. Weeks start on Monday.
+func periodStart(t time.Time, interval string) time.Time {
+	t = t.In(time.Local)
+	year, month, day := t.Date()
+
+	switch interval {
+	case domain.GroupByHour:
+		return time.Date(year, month, day, t.Hour(), 0, 0, 0, time.Local)
+	case domain.GroupByWeek:
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, time.Local)
+	case domain.GroupByMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	}
+}
+
+// nextPeriod returns the start of the period after the one starting at start.
+func nextPeriod(start time.Time, interval string) time.Time {
+	switch interval {
+	case domain.GroupByHour:
+		return start.Add(time.Hour)
+	case domain.GroupByWeek:
+		return start.AddDate(0, 0, 7)
+	case domain.GroupByMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// marginRows converts sale line totals into margin rows, keyed and labelled
+// by the report's grouping. Totals of the same group are merged.
+func marginRows(sums []*domain.SaleLineSum, groupBy string) []domain.MarginRow {
+	var rows []domain.MarginRow
+	index := make(map[string]int)
+
+	for _, sum := range sums {
+		key, label := marginGroup(sum, groupBy)
+
+		i, ok := index[key]
+		if !ok {
+			i = len(rows)
+			index[key] = i
+			rows = append(rows, domain.MarginRow{Key: key, Label: label})
+		}
+
+		row := &rows[i]
+		row.Sales += sum.Sales
+		row.Quantity += sum.Quantity
+		row.Revenue += sum.Revenue
+		row.Cost += sum.Cost
+	}
+
+	for i := range rows {
+		row := &rows[i]
+		row.Quantity = roundQuantity(row.Quantity, 3)
+		row.Revenue = roundMoney(row.Revenue)
+		row.Cost = roundMoney(row.Cost)
+		row.Profit = roundMoney(row.Revenue - row.Cost)
+		row.MarginPercent = marginPercent(row.Profit, row.Revenue)
+	}
+
+	return rows
+}
+
+// marginGroup returns the group key and display label of a sale line total.
+// Periods are keyed by their local start date, with weeks starting on Monday.
+func marginGroup(sum *domain.SaleLineSum, groupBy string) (string, string) {
+	switch groupBy {
+	case domain.GroupByCategory:
+		if sum.Key == "" {
+			return "", "Uncategorized"
+		}
+		return sum.Key, sum.Label
+	case domain.GroupByCashier:
+		if sum.Key == "" {
+			return "", "Unknown"
+		}
+		return sum.Key, sum.Key
+	case domain.GroupByDay:
+		day := sum.Period.Format("2006-01-02")
+		return day, day
+	case domain.GroupByWeek:
+		year, week := sum.Period.ISOWeek()
+		return sum.Period.Format("2006-01-02"), fmt.Sprintf("%d-W%02d", year, week)
+	case domain.GroupByMonth:
+		month := sum.Period.Format("2006-01")
+		return month, month
+	default:
+		return sum.Key, sum.Label
+	}
+}
+
+// sortMargins orders rows by a metric, highest first, breaking ties by key so
+// reports are stable.
+func sortMargins(rows []domain.MarginRow, metric string) {
+	value := func(row domain.MarginRow) float64 {
+		switch metric {
+		case MetricMargin:
+			return row.MarginPercent
+		case MetricRevenue:
+			return row.Revenue
+		default:
+			return row.Profit
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := value(rows[i]), value(rows[j])
+		if a != b {
+			return a > b
+		}
+		return rows[i].Key < rows[j].Key
+	})
+}
+
+// marginPercent returns profit as a percentage of revenue, or 0 without revenue.
+func marginPercent(profit, revenue float64) float64 {
+	if revenue == 0 {
+		return 0
+	}
+	return roundMoney(profit / revenue * 100)
+}
+
+// isPeriodGrouping reports whether a grouping buckets sales by date.
+func isPeriodGrouping(groupBy string) bool {
+	return groupBy == domain.GroupByDay || groupBy == domain.GroupByWeek || groupBy == domain.GroupByMonth
+}
+
+// validateMarginQuery checks a report's grouping and date range.
+func validateMarginQuery(query ports.MarginQuery) error {
+	switch query.GroupBy {
+	case domain.GroupByProduct, domain.GroupByCategory, domain.GroupByCashier,
+		domain.GroupByDay, domain.GroupByWeek, domain.GroupByMonth:
+	default:
+		return fmt.Errorf("%w: unknown group_by %q", ErrInvalidAnalyticsQuery, query.GroupBy)
+	}
+
+	if query.From != nil && query.To != nil && query.From.After(*query.To) {
+		return fmt.Errorf("%w: from must not be after to", ErrInvalidAnalyticsQuery)
+	}
+	return nil
 }
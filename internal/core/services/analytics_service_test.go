@@ -0,0 +1,506 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// --- Mock AnalyticsRepository ---
+
+type mockAnalyticsRepository struct {
+	lines    []*domain.SaleLine
+	totals   []*mockSaleTotal
+	activity []*domain.ProductActivity
+}
+
+// mockSaleTotal is a sale's total and unit count, summed by SumSales.
+type mockSaleTotal struct {
+	SaleID      string
+	SoldAt      time.Time
+	TotalAmount float64
+	Units       float64
+}
+
+func (m *mockAnalyticsRepository) ListSaleLines(_ context.Context, from, to *time.Time) ([]*domain.SaleLine, error) {
+	var result []*domain.SaleLine
+	for _, line := range m.lines {
+		if from != nil && line.SoldAt.Before(*from) {
+			continue
+		}
+		if to != nil && line.SoldAt.After(*to) {
+			continue
+		}
+		result = append(result, line)
+	}
+	return result, nil
+}
+
+func (m *mockAnalyticsRepository) SumSaleLines(ctx context.Context, query domain.SaleLineQuery) ([]*domain.SaleLineSum, error) {
+	lines, _ := m.ListSaleLines(ctx, query.From, query.To)
+	var sums []*domain.SaleLineSum
+	index := make(map[string]int)
+	sales := make(map[string]map[string]bool)
+	for _, line := range lines {
+		if query.CategoryIDs != nil && !contains(query.CategoryIDs, line.CategoryID) {
+			continue
+		}
+		if query.BelowCost && line.UnitPrice >= line.CostPrice {
+			continue
+		}
+
+		var sum domain.SaleLineSum
+		switch query.GroupBy {
+		case domain.GroupByProduct:
+			sum.Key, sum.Label = line.ProductID, line.ProductName
+		case domain.GroupByCategory:
+			sum.Key, sum.Label = line.CategoryID, line.CategoryName
+		case domain.GroupByCashier:
+			sum.Key = line.CashierID
+		}
+		if query.Period != "" {
+			sum.Period = periodStart(line.SoldAt, query.Period)
+		}
+
+		group := sum.Key + "|" + sum.Period.String()
+		i, ok := index[group]
+		if !ok {
+			i = len(sums)
+			index[group] = i
+			sales[group] = make(map[string]bool)
+			sums = append(sums, &sum)
+		}
+		sums[i].Quantity += line.Quantity
+		sums[i].Revenue += line.Quantity * line.UnitPrice
+		sums[i].Cost += line.Quantity * line.CostPrice
+		if !sales[group][line.SaleID] {
+			sales[group][line.SaleID] = true
+			sums[i].Sales++
+		}
+	}
+	return sums, nil
+}
+
+func (m *mockAnalyticsRepository) SumSales(_ context.Context, from, to time.Time, interval string) ([]*domain.SalesBucket, error) {
+	var buckets []*domain.SalesBucket
+	index := make(map[int64]int)
+	for _, sale := range m.totals {
+		if sale.SoldAt.Before(from) || sale.SoldAt.After(to) {
+			continue
+		}
+		start := periodStart(sale.SoldAt, interval)
+		i, ok := index[start.Unix()]
+		if !ok {
+			i = len(buckets)
+			index[start.Unix()] = i
+			buckets = append(buckets, &domain.SalesBucket{Start: start})
+		}
+		buckets[i].Revenue += sale.TotalAmount
+		buckets[i].Units += sale.Units
+		buckets[i].Transactions++
+	}
+	return buckets, nil
+}
+
+func (m *mockAnalyticsRepository) ListProductActivity(_ context.Context) ([]*domain.ProductActivity, error) {
+	return m.activity, nil
+}
+
+func (m *mockAnalyticsRepository) ListProductSales(_ context.Context, productID string, from, to time.Time) ([]*domain.SaleLine, error) {
+	var result []*domain.SaleLine
+	for _, line := range m.lines {
+		if line.ProductID == productID && !line.SoldAt.Before(from) && !line.SoldAt.After(to) {
+			result = append(result, line)
+		}
+	}
+	return result, nil
+}
+
+func newMarginTestService() *AnalyticsService {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 12, 0, 0, 0, time.Local) }
+	return NewAnalyticsService(&mockProductRepository{}, &mockCategoryRepository{}, &mockAnalyticsRepository{lines: []*domain.SaleLine{
+		// Friday 1 March
+		{SaleID: "s1", SoldAt: day(1), CashierID: "alice", ProductID: "p1", ProductName: "Cola", CategoryID: "drinks", CategoryName: "Drinks", Quantity: 2, UnitPrice: 1.50, CostPrice: 0.75},
+		{SaleID: "s1", SoldAt: day(1), CashierID: "alice", ProductID: "p2", ProductName: "Chips", CategoryID: "snacks", CategoryName: "Snacks", Quantity: 1, UnitPrice: 2.00, CostPrice: 1.50},
+		// Monday 4 March, chips on clearance below cost
+		{SaleID: "s2", SoldAt: day(4), CashierID: "bob", ProductID: "p2", ProductName: "Chips", CategoryID: "snacks", CategoryName: "Snacks", Quantity: 4, UnitPrice: 1.00, CostPrice: 1.50},
+		{SaleID: "s3", SoldAt: day(4), ProductID: "p3", ProductName: "Bread", Quantity: 1, UnitPrice: 3.00, CostPrice: 2.00},
+	}})
+}
+
+// --- Margin Tests ---
+
+func TestGetMarginReport_ByProduct(t *testing.T) {
+	svc := newMarginTestService()
+
+	report, err := svc.GetMarginReport(context.Background(), ports.MarginQuery{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.GroupBy != domain.GroupByProduct {
+		t.Errorf("expected default grouping by product, got %q", report.GroupBy)
+	}
+	if report.Revenue != 12.00 || report.Cost != 11.00 || report.Profit != 1.00 {
+		t.Errorf("unexpected totals %.2f/%.2f/%.2f", report.Revenue, report.Cost, report.Profit)
+	}
+	if len(report.Rows) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(report.Rows))
+	}
+
+	cola := report.Rows[0]
+	if cola.Key != "p1" || cola.Profit != 1.50 || cola.MarginPercent != 50 {
+		t.Errorf("expected cola first with 50%% margin, got %+v", cola)
+	}
+	chips := report.Rows[2]
+	if chips.Key != "p2" || chips.Sales != 2 || chips.Quantity != 5 || chips.Profit != -1.50 {
+		t.Errorf("expected chips last at a loss, got %+v", chips)
+	}
+}
+
+func TestGetMarginReport_CategorySubtree(t *testing.T) {
+	svc := newMarginTestService()
+	svc.categoryRepo = &mockCategoryRepository{categories: map[string]*domain.Category{
+		"food":   {ID: "food", Name: "Food"},
+		"snacks": {ID: "snacks", Name: "Snacks", ParentID: "food"},
+		"drinks": {ID: "drinks", Name: "Drinks"},
+	}}
+
+	report, err := svc.GetMarginReport(context.Background(), ports.MarginQuery{CategoryID: "food"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(report.Rows) != 1 || report.Rows[0].Key != "p2" {
+		t.Fatalf("expected only chips from the snacks subcategory, got %+v", report.Rows)
+	}
+	if report.Revenue != 6.00 || report.Cost != 7.50 {
+		t.Errorf("unexpected totals %.2f/%.2f", report.Revenue, report.Cost)
+	}
+
+	if _, err := svc.GetMarginReport(context.Background(), ports.MarginQuery{CategoryID: "missing"}); !errors.Is(err, ErrInvalidAnalyticsQuery) {
+		t.Errorf("expected ErrInvalidAnalyticsQuery for an unknown category, got %v", err)
+	}
+}
+
+func TestGetMarginReport_Groupings(t *testing.T) {
+	svc := newMarginTestService()
+
+	tests := []struct {
+		groupBy string
+		keys    []string
+	}{
+		{domain.GroupByCategory, []string{"drinks", "", "snacks"}},
+		{domain.GroupByCashier, []string{"alice", "", "bob"}},
+		{domain.GroupByDay, []string{"2024-03-01", "2024-03-04"}},
+		{domain.GroupByWeek, []string{"2024-02-26", "2024-03-04"}},
+		{domain.GroupByMonth, []string{"2024-03"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.groupBy, func(t *testing.T) {
+			report, err := svc.GetMarginReport(context.Background(), ports.MarginQuery{GroupBy: tt.groupBy})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(report.Rows) != len(tt.keys) {
+				t.Fatalf("expected %d rows, got %+v", len(tt.keys), report.Rows)
+			}
+			for i, key := range tt.keys {
+				if report.Rows[i].Key != key {
+					t.Errorf("row %d: expected key %q, got %q", i, key, report.Rows[i].Key)
+				}
+			}
+		})
+	}
+}
+
+func TestGetMarginReport_DateRange(t *testing.T) {
+	svc := newMarginTestService()
+	from := time.Date(2024, 3, 2, 0, 0, 0, 0, time.Local)
+
+	report, err := svc.GetMarginReport(context.Background(), ports.MarginQuery{GroupBy: domain.GroupByDay, From: &from})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Rows) != 1 || report.Revenue != 7.00 {
+		t.Errorf("expected only 4 March with revenue 7.00, got %+v", report)
+	}
+}
+
+func TestGetMarginReport_InvalidQuery(t *testing.T) {
+	svc := newMarginTestService()
+	from := time.Date(2024, 3, 5, 0, 0, 0, 0, time.Local)
+	to := time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local)
+
+	_, err := svc.GetMarginReport(context.Background(), ports.MarginQuery{GroupBy: "year"})
+	if !errors.Is(err, ErrInvalidAnalyticsQuery) {
+		t.Errorf("expected ErrInvalidAnalyticsQuery for unknown grouping, got %v", err)
+	}
+	_, err = svc.GetMarginReport(context.Background(), ports.MarginQuery{From: &from, To: &to})
+	if !errors.Is(err, ErrInvalidAnalyticsQuery) {
+		t.Errorf("expected ErrInvalidAnalyticsQuery for reversed range, got %v", err)
+	}
+	_, _, err = svc.GetPerformers(context.Background(), ports.MarginQuery{}, "volume", 5)
+	if !errors.Is(err, ErrInvalidAnalyticsQuery) {
+		t.Errorf("expected ErrInvalidAnalyticsQuery for unknown metric, got %v", err)
+	}
+}
+
+func TestGetPerformers(t *testing.T) {
+	svc := newMarginTestService()
+
+	top, bottom, err := svc.GetPerformers(context.Background(), ports.MarginQuery{}, MetricRevenue, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(top) != 1 || top[0].Key != "p2" {
+		t.Errorf("expected chips as top revenue, got %+v", top)
+	}
+	if len(bottom) != 1 || bottom[0].Key != "p3" {
+		t.Errorf("expected bread as bottom revenue, got %+v", bottom)
+	}
+}
+
+func TestGetNegativeMargins(t *testing.T) {
+	svc := newMarginTestService()
+
+	rows, err := svc.GetNegativeMargins(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Only the clearance line counts, not the profitable chips sale
+	if len(rows) != 1 || rows[0].Key != "p2" || rows[0].Quantity != 4 || rows[0].Profit != -2.00 {
+		t.Errorf("expected chips with a 2.00 loss on 4 units, got %+v", rows)
+	}
+}
+
+// --- Sales Time Series Tests ---
+
+func newSeriesTestService() *AnalyticsService {
+	at := func(year, month, day, hour int) time.Time {
+		return time.Date(year, time.Month(month), day, hour, 0, 0, 0, time.Local)
+	}
+	return NewAnalyticsService(&mockProductRepository{}, &mockCategoryRepository{}, &mockAnalyticsRepository{totals: []*mockSaleTotal{
+		// Same week last year
+		{SaleID: "y1", SoldAt: at(2023, 3, 11, 10), TotalAmount: 50, Units: 5},
+		// Previous week
+		{SaleID: "w1", SoldAt: at(2024, 3, 4, 9), TotalAmount: 20, Units: 2},
+		{SaleID: "w2", SoldAt: at(2024, 3, 8, 18), TotalAmount: 60, Units: 4},
+		// Reported week
+		{SaleID: "c1", SoldAt: at(2024, 3, 11, 9), TotalAmount: 30, Units: 3},
+		{SaleID: "c2", SoldAt: at(2024, 3, 11, 17), TotalAmount: 10, Units: 1.5},
+		{SaleID: "c3", SoldAt: at(2024, 3, 13, 12), TotalAmount: 20, Units: 2},
+	}})
+}
+
+func TestGetSalesTimeSeries_DailyBuckets(t *testing.T) {
+	svc := newSeriesTestService()
+	from := time.Date(2024, 3, 11, 0, 0, 0, 0, time.Local)
+	to := time.Date(2024, 3, 17, 23, 59, 59, 0, time.Local)
+
+	series, err := svc.GetSalesTimeSeries(context.Background(), ports.SalesSeriesQuery{From: from, To: to})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	current := series.Current
+	if series.Interval != domain.GroupByDay || len(current.Buckets) != 7 {
+		t.Fatalf("expected 7 daily buckets, got %q with %d", series.Interval, len(current.Buckets))
+	}
+	if current.Revenue != 60 || current.Units != 6.5 || current.Transactions != 3 || current.AverageBasket != 20 {
+		t.Errorf("unexpected totals %+v", current)
+	}
+	monday := current.Buckets[0]
+	if !monday.Start.Equal(from) || monday.Revenue != 40 || monday.Transactions != 2 || monday.AverageBasket != 20 {
+		t.Errorf("unexpected Monday bucket %+v", monday)
+	}
+	if current.Buckets[1].Transactions != 0 || current.Buckets[1].AverageBasket != 0 {
+		t.Errorf("expected an empty Tuesday bucket, got %+v", current.Buckets[1])
+	}
+}
+
+func TestGetSalesTimeSeries_Comparisons(t *testing.T) {
+	svc := newSeriesTestService()
+	from := time.Date(2024, 3, 11, 0, 0, 0, 0, time.Local)
+	to := time.Date(2024, 3, 17, 23, 59, 59, 0, time.Local)
+
+	series, err := svc.GetSalesTimeSeries(context.Background(), ports.SalesSeriesQuery{Interval: domain.GroupByWeek, From: from, To: to})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	previous := series.PreviousPeriod
+	if previous.Period.Revenue != 80 || previous.Period.Transactions != 2 {
+		t.Fatalf("expected previous week revenue 80 over 2 sales, got %+v", previous.Period)
+	}
+	if previous.RevenueChange == nil || *previous.RevenueChange != -25 {
+		t.Errorf("expected revenue change -25%%, got %v", previous.RevenueChange)
+	}
+	if previous.TransactionsChange == nil || *previous.TransactionsChange != 50 {
+		t.Errorf("expected transactions change 50%%, got %v", previous.TransactionsChange)
+	}
+
+	lastYear := series.LastYear
+	if lastYear.Period.Revenue != 50 || lastYear.RevenueChange == nil || *lastYear.RevenueChange != 20 {
+		t.Errorf("expected last year revenue 50 and change 20%%, got %+v", lastYear)
+	}
+}
+
+func TestGetSalesTimeSeries_HourlyAndEmptyBaseline(t *testing.T) {
+	svc := newSeriesTestService()
+	from := time.Date(2024, 3, 13, 0, 0, 0, 0, time.Local)
+	to := time.Date(2024, 3, 13, 23, 59, 59, 0, time.Local)
+
+	series, err := svc.GetSalesTimeSeries(context.Background(), ports.SalesSeriesQuery{Interval: domain.GroupByHour, From: from, To: to})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(series.Current.Buckets) != 24 || series.Current.Buckets[12].Revenue != 20 {
+		t.Errorf("expected 20 in the noon bucket of 24, got %+v", series.Current.Buckets)
+	}
+	if series.PreviousPeriod.RevenueChange != nil {
+		t.Errorf("expected no change against an empty day, got %v", *series.PreviousPeriod.RevenueChange)
+	}
+}
+
+func TestGetSalesTimeSeries_InvalidQuery(t *testing.T) {
+	svc := newSeriesTestService()
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(2024, 3, 31, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name  string
+		query ports.SalesSeriesQuery
+	}{
+		{"unknown interval", ports.SalesSeriesQuery{Interval: "quarter", From: from, To: to}},
+		{"missing range", ports.SalesSeriesQuery{Interval: domain.GroupByDay}},
+		{"reversed range", ports.SalesSeriesQuery{From: to, To: from}},
+		{"too many buckets", ports.SalesSeriesQuery{Interval: domain.GroupByHour, From: from.AddDate(-1, 0, 0), To: to}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.GetSalesTimeSeries(context.Background(), tt.query)
+			if !errors.Is(err, ErrInvalidAnalyticsQuery) {
+				t.Errorf("expected ErrInvalidAnalyticsQuery, got %v", err)
+			}
+		})
+	}
+}
+
+// --- Classification Tests ---
+
+func newClassificationTestService() (*AnalyticsService, *mockAnalyticsRepository) {
+	// Four weeks starting Monday 4 March 2024
+	week := func(w int) time.Time { return time.Date(2024, 3, 4+7*w, 12, 0, 0, 0, time.Local) }
+	lastSold := time.Date(2024, 1, 10, 12, 0, 0, 0, time.Local)
+	recent := week(3)
+
+	repo := &mockAnalyticsRepository{
+		lines: []*domain.SaleLine{
+			// Steady seller, 70% of revenue
+			{SaleID: "s1", SoldAt: week(0), ProductID: "steady", Quantity: 10, UnitPrice: 1.75},
+			{SaleID: "s2", SoldAt: week(1), ProductID: "steady", Quantity: 10, UnitPrice: 1.75},
+			{SaleID: "s3", SoldAt: week(2), ProductID: "steady", Quantity: 10, UnitPrice: 1.75},
+			{SaleID: "s4", SoldAt: week(3), ProductID: "steady", Quantity: 10, UnitPrice: 1.75},
+			// One large order, 25% of revenue
+			{SaleID: "s5", SoldAt: week(1), ProductID: "lumpy", Quantity: 5, UnitPrice: 5},
+			// Variable seller, 5% of revenue
+			{SaleID: "s6", SoldAt: week(0), ProductID: "variable", Quantity: 2, UnitPrice: 1},
+			{SaleID: "s7", SoldAt: week(1), ProductID: "variable", Quantity: 1, UnitPrice: 1},
+			{SaleID: "s8", SoldAt: week(3), ProductID: "variable", Quantity: 2, UnitPrice: 1},
+		},
+		activity: []*domain.ProductActivity{
+			{ProductID: "steady", SKU: "A1", Quantity: 20, CostPrice: 1, LastSoldAt: &recent},
+			{ProductID: "lumpy", SKU: "B1", Quantity: 0, CostPrice: 3, LastSoldAt: &recent},
+			{ProductID: "variable", SKU: "C1", Quantity: 4, CostPrice: 0.5, LastSoldAt: &recent},
+			{ProductID: "stale", SKU: "D1", Quantity: 10, CostPrice: 2, LastSoldAt: &lastSold},
+			{ProductID: "never", SKU: "E1", Quantity: 3, CostPrice: 10},
+		},
+	}
+
+	svc := NewAnalyticsService(&mockProductRepository{}, &mockCategoryRepository{}, repo)
+	svc.now = func() time.Time { return time.Date(2024, 3, 31, 23, 0, 0, 0, time.Local) }
+	return svc, repo
+}
+
+func TestClassifyProducts(t *testing.T) {
+	svc, _ := newClassificationTestService()
+
+	report, err := svc.ClassifyProducts(context.Background(), ports.ClassificationQuery{
+		From: time.Date(2024, 3, 4, 0, 0, 0, 0, time.Local),
+		To:   time.Date(2024, 3, 31, 23, 59, 59, 0, time.Local),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.Weeks != 4 || report.Revenue != 100 || len(report.Products) != 5 {
+		t.Fatalf("expected 4 weeks, revenue 100 over 5 products, got %d, %.2f, %d", report.Weeks, report.Revenue, len(report.Products))
+	}
+
+	want := []struct {
+		id    string
+		class string
+	}{
+		{"steady", "AX"},   // 70% share, same demand every week
+		{"lumpy", "AZ"},    // crosses the 80% boundary, sold in one week
+		{"variable", "CY"}, // starts after 95%, demand 2, 1, 0, 2
+		{"stale", "CZ"},
+		{"never", "CZ"},
+	}
+	for i, w := range want {
+		row := report.Products[i]
+		if row.ProductID != w.id || row.ABCClass+row.XYZClass != w.class {
+			t.Errorf("row %d: expected %s %s, got %s %s%s (cv %.3f)", i, w.id, w.class, row.ProductID, row.ABCClass, row.XYZClass, row.DemandCV)
+		}
+	}
+	if report.Products[0].RevenueShare != 70 || report.Products[1].CumulativeShare != 95 {
+		t.Errorf("unexpected shares %+v, %+v", report.Products[0], report.Products[1])
+	}
+	if report.Counts["CZ"] != 2 {
+		t.Errorf("expected 2 CZ products, got %v", report.Counts)
+	}
+}
+
+func TestClassifyProducts_InvalidThresholds(t *testing.T) {
+	svc, _ := newClassificationTestService()
+
+	_, err := svc.ClassifyProducts(context.Background(), ports.ClassificationQuery{AThreshold: 90, BThreshold: 80})
+	if !errors.Is(err, ErrInvalidAnalyticsQuery) {
+		t.Errorf("expected ErrInvalidAnalyticsQuery, got %v", err)
+	}
+}
+
+func TestGetDeadStock(t *testing.T) {
+	svc, _ := newClassificationTestService()
+
+	report, err := svc.GetDeadStock(context.Background(), 30, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Out-of-stock and recently sold products are left out
+	if len(report.Items) != 2 || report.TiedUpCapital != 50 {
+		t.Fatalf("expected 2 items tying up 50.00, got %+v", report)
+	}
+	never, stale := report.Items[0], report.Items[1]
+	if never.ProductID != "never" || never.TiedUpCapital != 30 || never.DaysSinceSale != nil {
+		t.Errorf("expected never-sold stock first, got %+v", never)
+	}
+	if stale.ProductID != "stale" || stale.DaysSinceSale == nil || *stale.DaysSinceSale != 81 {
+		t.Errorf("expected stale stock 81 days since sale, got %+v", stale)
+	}
+
+	if _, err := svc.GetDeadStock(context.Background(), 0, ""); !errors.Is(err, ErrInvalidAnalyticsQuery) {
+		t.Errorf("expected ErrInvalidAnalyticsQuery, got %v", err)
+	}
+}
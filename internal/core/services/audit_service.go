@@ -64,7 +64,15 @@ func (s *AuditService) LogAction(ctx context.Context, action, userID string, pay
 
 	// Store the log entry
 	s.lastHash = log.CurrentHash
-	return s.auditRepo.Create(ctx, log)
+	if err := s.auditRepo.Create(ctx, log); err != nil {
+		return err
+	}
+
+	// Keep an explicit chain moving forward for subsequent entries
+	if s.prevHash != nil {
+		s.prevHash = &log.CurrentHash
+	}
+	return nil
 }
 
 // VerifyAuditChain verifies the integrity of the audit log chain.
@@ -72,9 +80,36 @@ func (s *AuditService) VerifyAuditChain(ctx context.Context) (bool, error) {
 	return s.auditRepo.VerifyChain(ctx)
 }
 
-// GetAuditLogs retrieves audit logs with pagination.
-func (s *AuditService) GetAuditLogs(ctx context.Context, limit, offset int) ([]*domain.AuditLog, error) {
-	return s.auditRepo.List(ctx, limit, offset)
+// auditLogSorts maps each audit log sort to its default order.
+var auditLogSorts = map[string]string{domain.SortID: domain.SortDesc}
+
+// GetAuditLogs retrieves a page of audit logs, newest first by default, with
+// the total and the cursors of the pages either side.
+func (s *AuditService) GetAuditLogs(ctx context.Context, page domain.PageRequest) (*domain.AuditLogPage, error) {
+	page, err := resolvePage(page, domain.SortID, auditLogSorts)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.auditRepo.List(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	setPageCursors(&result.PageInfo, page)
+	return result, nil
+}
+
+// newTxAuditService creates an audit service bound to a transaction's audit
+// repository, chained onto the last entry visible inside that transaction.
+func newTxAuditService(ctx context.Context, auditRepo ports.AuditLogRepository) *AuditService {
+	prevHash := ""
+	lastLog, err := auditRepo.GetLastLog(ctx)
+	if err == nil && lastLog != nil {
+		prevHash = lastLog.CurrentHash
+	}
+
+	svc := NewAuditService(auditRepo)
+	svc.SetPrevHash(prevHash)
+	return svc
 }
 
 // calculateHash computes SHA256(payload + timestamp + prev_hash).
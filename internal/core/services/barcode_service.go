@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// ErrBarcodeInUse is returned when a barcode is already assigned to a product or pack.
+var ErrBarcodeInUse = errors.New("barcode already in use")
+
+// ErrBarcodeNotFound is returned when a scanned barcode matches no product or pack.
+var ErrBarcodeNotFound = errors.New("barcode not found")
+
+// BarcodeService implements product barcode management and scanner lookups.
+type BarcodeService struct {
+	barcodeRepo ports.BarcodeRepository
+	productRepo ports.ProductRepository
+	packRepo    ports.PackRepository
+	txManager   ports.TransactionManager
+}
+
+// NewBarcodeService creates a new barcode service instance.
+func NewBarcodeService(barcodeRepo ports.BarcodeRepository, productRepo ports.ProductRepository, packRepo ports.PackRepository, txManager ports.TransactionManager) *BarcodeService {
+	return &BarcodeService{
+		barcodeRepo: barcodeRepo,
+		productRepo: productRepo,
+		packRepo:    packRepo,
+		txManager:   txManager,
+	}
+}
+
+// AddBarcode validates a GTIN and assigns it to a product.
+func (s *BarcodeService) AddBarcode(ctx context.Context, productID, code string) (*domain.ProductBarcode, error) {
+	format, err := ValidateGTIN(code)
+	if err != nil {
+		return nil, err
+	}
+
+	barcode := &domain.ProductBarcode{
+		Code:      code,
+		GTIN:      NormalizeGTIN(code),
+		ProductID: productID,
+		Format:    format,
+		CreatedAt: time.Now(),
+	}
+
+	err = s.txManager.WithTx(ctx, func(tx ports.Ports) error {
+		if _, err := tx.ProductRepo.GetByID(ctx, productID); err != nil {
+			return fmt.Errorf("product %s: %w", productID, err)
+		}
+		if err := checkBarcodeAvailable(ctx, tx, code); err != nil {
+			return err
+		}
+		return tx.BarcodeRepo.Create(ctx, barcode)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return barcode, nil
+}
+
+// ListBarcodes retrieves every barcode assigned to a product.
+func (s *BarcodeService) ListBarcodes(ctx context.Context, productID string) ([]*domain.ProductBarcode, error) {
+	return s.barcodeRepo.ListByProduct(ctx, productID)
+}
+
+// RemoveBarcode unassigns a barcode from a product.
+func (s *BarcodeService) RemoveBarcode(ctx context.Context, productID, code string) error {
+	return s.barcodeRepo.Delete(ctx, productID, NormalizeGTIN(code))
+}
+
+// LookupBarcode resolves a scanned code to a product, or to one of its packs.
+func (s *BarcodeService) LookupBarcode(ctx context.Context, code string) (*domain.BarcodeMatch, error) {
+	match, err := lookupBarcode(ctx, s.barcodeRepo, s.packRepo, s.productRepo, code)
+	if err != nil {
+		return nil, err
+	}
+	if match == nil {
+		return nil, fmt.Errorf("%w: %s", ErrBarcodeNotFound, code)
+	}
+	return match, nil
+}
+
+// lookupBarcode matches a code against product barcodes by GTIN, then against
+// pack barcodes. It returns nil without error when nothing matches, including
+// codes left behind by a product that no longer exists.
+func lookupBarcode(ctx context.Context, barcodeRepo ports.BarcodeRepository, packRepo ports.PackRepository, productRepo ports.ProductRepository, code string) (*domain.BarcodeMatch, error) {
+	if _, err := ValidateGTIN(code); err == nil {
+		barcode, err := barcodeRepo.GetByGTIN(ctx, NormalizeGTIN(code))
+		if err != nil {
+			return nil, fmt.Errorf("barcode lookup %s: %w", code, err)
+		}
+		if barcode != nil {
+			product, err := productRepo.GetByID(ctx, barcode.ProductID)
+			if errors.Is(err, ports.ErrNotFound) {
+				return nil, nil
+			}
+			if err != nil {
+				return nil, fmt.Errorf("product %s: %w", barcode.ProductID, err)
+			}
+			return &domain.BarcodeMatch{Product: product}, nil
+		}
+	}
+
+	pack, err := packRepo.GetByGTIN(ctx, NormalizeGTIN(code))
+	if err != nil {
+		return nil, fmt.Errorf("pack lookup %s: %w", code, err)
+	}
+	if pack == nil {
+		return nil, nil
+	}
+
+	product, err := productRepo.GetByID(ctx, pack.ProductID)
+	if errors.Is(err, ports.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("product %s: %w", pack.ProductID, err)
+	}
+	return &domain.BarcodeMatch{Product: product, Pack: pack}, nil
+}
+
+// checkBarcodeAvailable returns ErrBarcodeInUse when a code is already
+// assigned to a product or a pack.
+func checkBarcodeAvailable(ctx context.Context, tx ports.Ports, code string) error {
+	existing, err := tx.BarcodeRepo.GetByGTIN(ctx, NormalizeGTIN(code))
+	if err != nil {
+		return fmt.Errorf("barcode lookup %s: %w", code, err)
+	}
+	if existing != nil {
+		return fmt.Errorf("%w: %s is assigned to product %s", ErrBarcodeInUse, code, existing.ProductID)
+	}
+
+	pack, err := tx.PackRepo.GetByGTIN(ctx, NormalizeGTIN(code))
+	if err != nil {
+		return fmt.Errorf("pack lookup %s: %w", code, err)
+	}
+	if pack != nil {
+		return fmt.Errorf("%w: %s is assigned to pack %s", ErrBarcodeInUse, code, pack.ID)
+	}
+
+	return nil
+}
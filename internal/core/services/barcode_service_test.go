@@ -0,0 +1,300 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// --- Mock BarcodeRepository ---
+
+type mockBarcodeRepository struct {
+	barcodes []*domain.ProductBarcode
+}
+
+func (m *mockBarcodeRepository) Create(_ context.Context, barcode *domain.ProductBarcode) error {
+	m.barcodes = append(m.barcodes, barcode)
+	return nil
+}
+func (m *mockBarcodeRepository) GetByGTIN(_ context.Context, gtin string) (*domain.ProductBarcode, error) {
+	for _, b := range m.barcodes {
+		if b.GTIN == gtin {
+			return b, nil
+		}
+	}
+	return nil, nil
+}
+func (m *mockBarcodeRepository) ListByProduct(_ context.Context, productID string) ([]*domain.ProductBarcode, error) {
+	var out []*domain.ProductBarcode
+	for _, b := range m.barcodes {
+		if b.ProductID == productID {
+			out = append(out, b)
+		}
+	}
+	return out, nil
+}
+func (m *mockBarcodeRepository) Delete(_ context.Context, productID, gtin string) error {
+	for i, b := range m.barcodes {
+		if b.ProductID == productID && b.GTIN == gtin {
+			m.barcodes = append(m.barcodes[:i], m.barcodes[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("barcode not found")
+}
+
+func newBarcodeTestService(products []*domain.Product, packs []*domain.ProductPack) (*BarcodeService, *mockSaleTxManager) {
+	txManager := newPackTestTxManager(products, packs)
+	txManager.barcodeRepo = &mockBarcodeRepository{}
+	return NewBarcodeService(txManager.barcodeRepo, txManager.productRepo, txManager.packRepo, txManager), txManager
+}
+
+func TestPackBarcodes_KeyedByGTIN(t *testing.T) {
+	svc, txManager := newBarcodeTestService([]*domain.Product{
+		{ID: "p1", Name: "Beer", SKU: "BEER", BasePrice: 2},
+		{ID: "p2", Name: "Cider", SKU: "CIDER", BasePrice: 2},
+	}, nil)
+	inventorySvc := NewInventoryService(txManager.packRepo, txManager.productRepo, txManager)
+
+	// A UPC-A pack barcode takes its EAN-13 form from products
+	pack := &domain.ProductPack{ProductID: "p1", Name: "Case", Barcode: "036000291452", UnitsPerPack: 24}
+	if err := inventorySvc.CreatePack(context.Background(), pack); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pack.GTIN != "00036000291452" {
+		t.Errorf("expected the pack keyed by GTIN 00036000291452, got %q", pack.GTIN)
+	}
+	if _, err := svc.AddBarcode(context.Background(), "p2", "0036000291452"); !errors.Is(err, ErrBarcodeInUse) {
+		t.Errorf("expected ErrBarcodeInUse for the pack's EAN-13 form, got %v", err)
+	}
+
+	// and is found when scanned in either form
+	match, err := svc.LookupBarcode(context.Background(), "0036000291452")
+	if err != nil || match.Pack == nil || match.Pack.ID != pack.ID {
+		t.Fatalf("expected the EAN-13 scan to find the pack, got %+v (%v)", match, err)
+	}
+
+	// An EAN-13 product barcode takes its UPC-A form from packs
+	if _, err := svc.AddBarcode(context.Background(), "p2", "0012345678905"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = inventorySvc.CreatePack(context.Background(), &domain.ProductPack{ProductID: "p1", Name: "Crate", Barcode: "012345678905", UnitsPerPack: 12})
+	if !errors.Is(err, ErrBarcodeInUse) {
+		t.Errorf("expected ErrBarcodeInUse for the product barcode's UPC-A form, got %v", err)
+	}
+}
+
+// --- GTIN Tests ---
+
+func TestValidateGTIN(t *testing.T) {
+	valid := map[string]string{
+		"96385074":       domain.BarcodeEAN8,
+		"036000291452":   domain.BarcodeUPCA,
+		"4006381333931":  domain.BarcodeEAN13,
+		"15000000000019": domain.BarcodeGTIN14,
+	}
+	for code, want := range valid {
+		format, err := ValidateGTIN(code)
+		if err != nil || format != want {
+			t.Errorf("ValidateGTIN(%s) = %q, %v; want %q", code, format, err, want)
+		}
+	}
+
+	for _, code := range []string{"4006381333932", "12345", "40063813339ab", ""} {
+		if _, err := ValidateGTIN(code); !errors.Is(err, ErrInvalidBarcode) {
+			t.Errorf("ValidateGTIN(%q): expected ErrInvalidBarcode, got %v", code, err)
+		}
+	}
+
+	// UPC-A and its 13-digit EAN form share a key
+	if NormalizeGTIN("036000291452") != NormalizeGTIN("0036000291452") {
+		t.Fatal("expected UPC-A and EAN-13 forms to normalise to the same GTIN")
+	}
+}
+
+// --- BarcodeService Tests ---
+
+func TestAddBarcode_UniqueAcrossProducts(t *testing.T) {
+	svc, _ := newBarcodeTestService([]*domain.Product{
+		{ID: "p1", Name: "Cola", SKU: "COLA"},
+		{ID: "p2", Name: "Lemonade", SKU: "LEMON"},
+	}, []*domain.ProductPack{
+		{ID: "case", ProductID: "p1", Name: "Case", Barcode: "15000000000019", GTIN: "15000000000019", UnitsPerPack: 24},
+	})
+
+	barcode, err := svc.AddBarcode(context.Background(), "p1", "036000291452")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if barcode.Format != domain.BarcodeUPCA || barcode.GTIN != "00036000291452" {
+		t.Fatalf("expected UPC-A with GTIN 00036000291452, got %+v", barcode)
+	}
+
+	// The same code in EAN-13 form belongs to p1 already
+	_, err = svc.AddBarcode(context.Background(), "p2", "0036000291452")
+	if !errors.Is(err, ErrBarcodeInUse) {
+		t.Fatalf("expected ErrBarcodeInUse, got %v", err)
+	}
+
+	_, err = svc.AddBarcode(context.Background(), "p2", "15000000000019")
+	if !errors.Is(err, ErrBarcodeInUse) {
+		t.Fatalf("expected ErrBarcodeInUse for a pack barcode, got %v", err)
+	}
+
+	_, err = svc.AddBarcode(context.Background(), "p2", "4006381333932")
+	if !errors.Is(err, ErrInvalidBarcode) {
+		t.Fatalf("expected ErrInvalidBarcode, got %v", err)
+	}
+}
+
+func TestLookupBarcode(t *testing.T) {
+	svc, _ := newBarcodeTestService([]*domain.Product{
+		{ID: "p1", Name: "Cola", SKU: "COLA"},
+	}, []*domain.ProductPack{
+		{ID: "case", ProductID: "p1", Name: "Case", Barcode: "15000000000019", GTIN: "15000000000019", UnitsPerPack: 24},
+	})
+	if _, err := svc.AddBarcode(context.Background(), "p1", "96385074"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	match, err := svc.LookupBarcode(context.Background(), "96385074")
+	if err != nil || match.Product.ID != "p1" || match.Pack != nil {
+		t.Fatalf("expected product p1, got %+v, %v", match, err)
+	}
+
+	match, err = svc.LookupBarcode(context.Background(), "15000000000019")
+	if err != nil || match.Pack == nil || match.Pack.ID != "case" {
+		t.Fatalf("expected pack case, got %+v, %v", match, err)
+	}
+
+	_, err = svc.LookupBarcode(context.Background(), "4006381333931")
+	if !errors.Is(err, ErrBarcodeNotFound) {
+		t.Fatalf("expected ErrBarcodeNotFound, got %v", err)
+	}
+}
+
+func TestProcessSale_ScanProductBarcode(t *testing.T) {
+	txManager := newPackTestTxManager([]*domain.Product{
+		{ID: "p1", Name: "Cola", SKU: "COLA", BasePrice: 1.25, Quantity: 10},
+	}, nil)
+	txManager.barcodeRepo = &mockBarcodeRepository{barcodes: []*domain.ProductBarcode{
+		{Code: "036000291452", GTIN: "00036000291452", ProductID: "p1", Format: domain.BarcodeUPCA},
+	}}
+	svc := NewSaleService(txManager)
+
+	// Scanners often report UPC-A with a leading zero
+	sale, err := svc.ProcessSale(context.Background(), ports.SaleRequest{Items: []ports.SaleItemRequest{
+		{Barcode: "0036000291452", Quantity: 2},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sale.TotalAmount != 2.5 {
+		t.Fatalf("expected total 2.50, got %v", sale.TotalAmount)
+	}
+}
+
+func TestProcessSale_RegisteredBarcodeWithEmbeddedPrefix(t *testing.T) {
+	txManager := newPackTestTxManager([]*domain.Product{
+		{ID: "p1", Name: "Cheddar", SKU: "12345", BasePrice: 12.00, Quantity: 5, UnitOfMeasure: domain.UnitKilogram, QuantityPrecision: 3},
+		{ID: "p2", Name: "Gift Box", SKU: "GIFT", BasePrice: 8.00, Quantity: 3},
+	}, nil)
+	// Read as a weight label, this would be 1.25 kg of item 12345
+	txManager.barcodeRepo = &mockBarcodeRepository{barcodes: []*domain.ProductBarcode{
+		{Code: "2112345012506", GTIN: "02112345012506", ProductID: "p2", Format: domain.BarcodeEAN13},
+	}}
+
+	sale, err := NewSaleService(txManager).ProcessSale(context.Background(), ports.SaleRequest{Items: []ports.SaleItemRequest{
+		{Barcode: "2112345012506"},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sale.TotalAmount != 8.00 || txManager.productRepo.products[1].Quantity != 2 {
+		t.Fatalf("expected one gift box at 8.00, got total %v", sale.TotalAmount)
+	}
+	if got := txManager.productRepo.products[0].Quantity; got != 5 {
+		t.Fatalf("expected cheddar untouched, got %v kg left", got)
+	}
+}
+
+func TestImportProducts_WithBarcodes(t *testing.T) {
+	productRepo := &mockProductRepository{}
+	categoryRepo := &mockCategoryRepository{categories: make(map[string]*domain.Category)}
+	auditRepo := &mockAuditLogRepository{}
+	barcodeRepo := &mockBarcodeRepository{}
+	txManager := &mockTransactionManager{
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+		auditRepo:    auditRepo,
+		packRepo:     &mockPackRepository{},
+		barcodeRepo:  barcodeRepo,
+	}
+	svc := NewProductService(productRepo, categoryRepo, NewAuditService(auditRepo), txManager, &mockSynonymRepository{})
+
+	csv := "name,sku,base_price,barcode\nCola,COLA,1.25,036000291452|96385074\nLemonade,LEMON,1.50,\n"
+	count, err := svc.ImportProducts(context.Background(), "", strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 2 || len(barcodeRepo.barcodes) != 2 {
+		t.Fatalf("expected 2 products and 2 barcodes, got %d and %d", count, len(barcodeRepo.barcodes))
+	}
+	if _, ok := productRepo.products[1].Properties["barcode"]; ok {
+		t.Fatal("expected barcode column not to be stored as a property")
+	}
+
+	csv = "name,sku,base_price,barcode\nCola,COLA2,1.25,036000291452\n"
+	_, err = svc.ImportProducts(context.Background(), "", strings.NewReader(csv))
+	if !errors.Is(err, ErrBarcodeInUse) {
+		t.Fatalf("expected ErrBarcodeInUse for duplicate barcode, got %v", err)
+	}
+}
+
+func TestDeleteProduct_ReleasesBarcodes(t *testing.T) {
+	productRepo := &mockProductRepository{products: []*domain.Product{
+		{ID: "p1", Name: "Cola", SKU: "COLA", BasePrice: 1.25},
+	}}
+	categoryRepo := &mockCategoryRepository{categories: make(map[string]*domain.Category)}
+	auditRepo := &mockAuditLogRepository{}
+	barcodeRepo := &mockBarcodeRepository{barcodes: []*domain.ProductBarcode{
+		{Code: "036000291452", GTIN: NormalizeGTIN("036000291452"), ProductID: "p1", Format: domain.BarcodeUPCA},
+	}}
+	packRepo := &mockPackRepository{packs: []*domain.ProductPack{
+		{ID: "pk1", ProductID: "p1", Barcode: "96385074", GTIN: NormalizeGTIN("96385074"), UnitsPerPack: 6},
+	}}
+	txManager := &mockTransactionManager{
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+		auditRepo:    auditRepo,
+		packRepo:     packRepo,
+		barcodeRepo:  barcodeRepo,
+	}
+	svc := NewProductService(productRepo, categoryRepo, NewAuditService(auditRepo), txManager, &mockSynonymRepository{})
+
+	if err := svc.DeleteProduct(context.Background(), "p1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(barcodeRepo.barcodes) != 0 || len(packRepo.packs) != 0 {
+		t.Fatalf("expected barcodes and packs to be deleted, got %d and %d", len(barcodeRepo.barcodes), len(packRepo.packs))
+	}
+}
+
+func TestLookupBarcode_ProductDeleted(t *testing.T) {
+	svc, txManager := newBarcodeTestService(nil, []*domain.ProductPack{
+		{ID: "pk1", ProductID: "gone", Barcode: "96385074", GTIN: NormalizeGTIN("96385074"), UnitsPerPack: 6},
+	})
+	txManager.barcodeRepo.barcodes = []*domain.ProductBarcode{
+		{Code: "036000291452", GTIN: NormalizeGTIN("036000291452"), ProductID: "gone", Format: domain.BarcodeUPCA},
+	}
+
+	for _, code := range []string{"036000291452", "96385074"} {
+		if _, err := svc.LookupBarcode(context.Background(), code); !errors.Is(err, ErrBarcodeNotFound) {
+			t.Fatalf("expected ErrBarcodeNotFound for %s of a deleted product, got %v", code, err)
+		}
+	}
+}
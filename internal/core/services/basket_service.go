@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// Association report metrics.
+const (
+	MetricLift       = "lift"
+	MetricConfidence = "confidence"
+	MetricSupport    = "support"
+)
+
+// Default market basket mining settings.
+const (
+	defaultBasketWindowDays   = 90
+	defaultBasketMinPairCount = 3
+)
+
+// BasketService implements market basket analysis: it mines recent sales for
+// products bought together and stores the resulting association rules.
+type BasketService struct {
+	analyticsRepo   ports.AnalyticsRepository
+	associationRepo ports.AssociationRepository
+	txManager       ports.TransactionManager
+	windowDays      int
+	minPairCount    int
+	now             func() time.Time
+}
+
+// NewBasketService creates a new market basket service instance.
+func NewBasketService(analyticsRepo ports.AnalyticsRepository, associationRepo ports.AssociationRepository, txManager ports.TransactionManager) *BasketService {
+	return &BasketService{
+		analyticsRepo:   analyticsRepo,
+		associationRepo: associationRepo,
+		txManager:       txManager,
+		windowDays:      defaultBasketWindowDays,
+		minPairCount:    defaultBasketMinPairCount,
+		now:             time.Now,
+	}
+}
+
+// SetMiningOptions sets how many days of sales are mined and how many sales
+// a pair must appear in to be kept. Non-positive values keep the current
+// setting.
+func (s *BasketService) SetMiningOptions(windowDays, minPairCount int) {
+	if windowDays > 0 {
+		s.windowDays = windowDays
+	}
+	if minPairCount > 0 {
+		s.minPairCount = minPairCount
+	}
+}
+
+// RecomputeAssociations mines the sales of the mining window and atomically
+// replaces the stored associations with the result.
+func (s *BasketService) RecomputeAssociations(ctx context.Context) (*domain.BasketAnalysis, error) {
+	now := s.now()
+	analysis := &domain.BasketAnalysis{
+		From:       now.AddDate(0, 0, -s.windowDays),
+		To:         now,
+		ComputedAt: now,
+	}
+
+	lines, err := s.analyticsRepo.ListSaleLines(ctx, &analysis.From, &analysis.To)
+	if err != nil {
+		return nil, err
+	}
+
+	associations, transactions := mineAssociations(lines, s.minPairCount, now)
+	analysis.Transactions = transactions
+	analysis.Associations = len(associations)
+
+	err = s.txManager.WithTx(ctx, func(tx ports.Ports) error {
+		if err := tx.AssociationRepo.DeleteAll(ctx); err != nil {
+			return fmt.Errorf("clear associations: %w", err)
+		}
+		for _, association := range associations {
+			if err := tx.AssociationRepo.Create(ctx, association); err != nil {
+				return fmt.Errorf("store association %s -> %s: %w", association.ProductID, association.AssociatedProductID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return analysis, nil
+}
+
+// FrequentlyBoughtWith returns the products most often bought with a
+// product, strongest association first.
+func (s *BasketService) FrequentlyBoughtWith(ctx context.Context, productID string, limit int) ([]*domain.ProductAssociation, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	return s.associationRepo.ListForProduct(ctx, productID, limit)
+}
+
+// TopAssociations returns the strongest associations across all products by
+// lift, confidence or support.
+func (s *BasketService) TopAssociations(ctx context.Context, metric string, limit int) ([]*domain.ProductAssociation, error) {
+	if metric == "" {
+		metric = MetricLift
+	}
+	if metric != MetricLift && metric != MetricConfidence && metric != MetricSupport {
+		return nil, fmt.Errorf("%w: unknown metric %q", ErrInvalidAnalyticsQuery, metric)
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	return s.associationRepo.ListTop(ctx, metric, limit)
+}
+
+// mineAssociations counts the product pairs sold together in the same sale
+// and returns an association rule in each direction for every pair seen in
+// at least minPairCount sales, with the number of sales mined.
+func mineAssociations(lines []*domain.SaleLine, minPairCount int, computedAt time.Time) ([]*domain.ProductAssociation, int) {
+	// Distinct products per sale; a product on several lines counts once
+	baskets := make(map[string]map[string]bool)
+	for _, line := range lines {
+		if baskets[line.SaleID] == nil {
+			baskets[line.SaleID] = make(map[string]bool)
+		}
+		baskets[line.SaleID][line.ProductID] = true
+	}
+
+	itemCounts := make(map[string]int)
+	pairCounts := make(map[[2]string]int)
+	for _, basket := range baskets {
+		products := make([]string, 0, len(basket))
+		for productID := range basket {
+			products = append(products, productID)
+			itemCounts[productID]++
+		}
+		sort.Strings(products)
+		for i := range products {
+			for j := i + 1; j < len(products); j++ {
+				pairCounts[[2]string{products[i], products[j]}]++
+			}
+		}
+	}
+
+	transactions := float64(len(baskets))
+	var associations []*domain.ProductAssociation
+	for pair, count := range pairCounts {
+		if count < minPairCount {
+			continue
+		}
+		support := float64(count) / transactions
+		for _, rule := range [][2]string{pair, {pair[1], pair[0]}} {
+			confidence := float64(count) / float64(itemCounts[rule[0]])
+			associations = append(associations, &domain.ProductAssociation{
+				ProductID:           rule[0],
+				AssociatedProductID: rule[1],
+				PairCount:           count,
+				Support:             roundQuantity(support, 4),
+				Confidence:          roundQuantity(confidence, 4),
+				Lift:                roundQuantity(confidence/(float64(itemCounts[rule[1]])/transactions), 4),
+				ComputedAt:          computedAt,
+			})
+		}
+	}
+
+	sort.Slice(associations, func(i, j int) bool {
+		a, b := associations[i], associations[j]
+		if a.ProductID != b.ProductID {
+			return a.ProductID < b.ProductID
+		}
+		return a.AssociatedProductID < b.AssociatedProductID
+	})
+
+	return associations, len(baskets)
+}
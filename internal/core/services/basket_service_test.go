@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+// --- Mock AssociationRepository ---
+
+type mockAssociationRepository struct {
+	associations []*domain.ProductAssociation
+}
+
+func (m *mockAssociationRepository) DeleteAll(_ context.Context) error {
+	m.associations = nil
+	return nil
+}
+func (m *mockAssociationRepository) Create(_ context.Context, association *domain.ProductAssociation) error {
+	m.associations = append(m.associations, association)
+	return nil
+}
+func (m *mockAssociationRepository) ListForProduct(_ context.Context, productID string, limit int) ([]*domain.ProductAssociation, error) {
+	var result []*domain.ProductAssociation
+	for _, a := range m.associations {
+		if a.ProductID == productID {
+			result = append(result, a)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Lift > result[j].Lift })
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+func (m *mockAssociationRepository) ListTop(_ context.Context, _ string, limit int) ([]*domain.ProductAssociation, error) {
+	result := append([]*domain.ProductAssociation(nil), m.associations...)
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Lift > result[j].Lift })
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+func (m *mockAssociationRepository) DeleteByProduct(_ context.Context, productID string) error {
+	var kept []*domain.ProductAssociation
+	for _, a := range m.associations {
+		if a.ProductID != productID && a.AssociatedProductID != productID {
+			kept = append(kept, a)
+		}
+	}
+	m.associations = kept
+	return nil
+}
+
+func newBasketTestService() (*BasketService, *mockSaleTxManager) {
+	soldAt := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	line := func(saleID, productID string) *domain.SaleLine {
+		return &domain.SaleLine{SaleID: saleID, SoldAt: soldAt, ProductID: productID, Quantity: 1}
+	}
+
+	txManager := newPackTestTxManager(nil, nil)
+	txManager.associationRepo = &mockAssociationRepository{associations: []*domain.ProductAssociation{
+		{ProductID: "stale", AssociatedProductID: "old"},
+	}}
+	svc := NewBasketService(&mockAnalyticsRepository{lines: []*domain.SaleLine{
+		line("s1", "bread"), line("s1", "butter"), line("s1", "bread"),
+		line("s2", "bread"), line("s2", "butter"), line("s2", "jam"),
+		line("s3", "bread"), line("s3", "milk"),
+		line("s4", "butter"), line("s4", "jam"),
+		line("s5", "milk"),
+	}}, txManager.associationRepo, txManager)
+	svc.SetMiningOptions(0, 2)
+	svc.now = func() time.Time { return time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC) }
+	return svc, txManager
+}
+
+// --- BasketService Tests ---
+
+func TestRecomputeAssociations(t *testing.T) {
+	svc, txManager := newBasketTestService()
+
+	analysis, err := svc.RecomputeAssociations(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Pairs in fewer than 2 sales are dropped; each kept pair gives 2 rules
+	if analysis.Transactions != 5 || analysis.Associations != 4 {
+		t.Fatalf("expected 4 rules from 5 sales, got %+v", analysis)
+	}
+	if !analysis.From.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected a 90-day window, got from %v", analysis.From)
+	}
+
+	stored := txManager.associationRepo.associations
+	if len(stored) != 4 {
+		t.Fatalf("expected stale associations replaced by 4, got %d", len(stored))
+	}
+
+	want := map[[2]string][3]float64{
+		{"bread", "butter"}: {0.4, 0.6667, 1.1111},
+		{"butter", "bread"}: {0.4, 0.6667, 1.1111},
+		{"butter", "jam"}:   {0.4, 0.6667, 1.6667},
+		{"jam", "butter"}:   {0.4, 1, 1.6667},
+	}
+	for _, a := range stored {
+		w, ok := want[[2]string{a.ProductID, a.AssociatedProductID}]
+		if !ok {
+			t.Errorf("unexpected association %s -> %s", a.ProductID, a.AssociatedProductID)
+			continue
+		}
+		if a.PairCount != 2 || a.Support != w[0] || a.Confidence != w[1] || a.Lift != w[2] {
+			t.Errorf("%s -> %s: expected %v, got support %v confidence %v lift %v",
+				a.ProductID, a.AssociatedProductID, w, a.Support, a.Confidence, a.Lift)
+		}
+	}
+}
+
+func TestFrequentlyBoughtWith(t *testing.T) {
+	svc, _ := newBasketTestService()
+	if _, err := svc.RecomputeAssociations(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	associations, err := svc.FrequentlyBoughtWith(context.Background(), "butter", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(associations) != 2 || associations[0].AssociatedProductID != "jam" {
+		t.Errorf("expected jam then bread for butter, got %+v", associations)
+	}
+}
+
+func TestTopAssociations_InvalidMetric(t *testing.T) {
+	svc, _ := newBasketTestService()
+
+	if _, err := svc.TopAssociations(context.Background(), "popularity", 5); !errors.Is(err, ErrInvalidAnalyticsQuery) {
+		t.Errorf("expected ErrInvalidAnalyticsQuery, got %v", err)
+	}
+}
@@ -4,7 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/torantous1337/retail-management/internal/core/domain"
 	"github.com/torantous1337/retail-management/internal/core/ports"
@@ -13,89 +18,887 @@ import (
 // ErrInvalidProperty is returned when product properties fail category validation.
 var ErrInvalidProperty = errors.New("invalid property")
 
+// PropertyError describes one property that failed validation.
+type PropertyError struct {
+	Key     string
+	Message string
+}
+
+// ValidationError reports every property that failed validation at once. It
+// matches ErrInvalidProperty with errors.Is.
+type ValidationError struct {
+	Errors []PropertyError
+}
+
+// Error lists the message of every failing property.
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, pe := range e.Errors {
+		messages = append(messages, pe.Message)
+	}
+	return fmt.Sprintf("%v: %s", ErrInvalidProperty, strings.Join(messages, "; "))
+}
+
+// Unwrap makes a ValidationError match ErrInvalidProperty.
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidProperty
+}
+
+// add records a failing property.
+func (e *ValidationError) add(key, format string, args ...interface{}) {
+	e.Errors = append(e.Errors, PropertyError{Key: key, Message: fmt.Sprintf(format, args...)})
+}
+
+// has reports whether a property has already failed.
+func (e *ValidationError) has(key string) bool {
+	for _, pe := range e.Errors {
+		if pe.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// errOrNil returns the error if any property failed, otherwise nil.
+func (e *ValidationError) errOrNil() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+// ErrInvalidCategory is returned when a category's attribute definitions are
+// malformed.
+var ErrInvalidCategory = errors.New("invalid category")
+
+// ErrCategoryVersionConflict is returned when a category update was based on
+// a version that has since been replaced.
+var ErrCategoryVersionConflict = errors.New("category version conflict")
+
+// ErrCategoryInUse is returned when deleting a category that child
+// categories, products, sale restrictions or price list rules still refer to.
+var ErrCategoryInUse = errors.New("category in use")
+
+// categoryPageSize is the number of products fetched per page when checking
+// a category's products against a new schema.
+const categoryPageSize = 500
+
+// attributeTypes are the supported attribute definition types.
+var attributeTypes = map[string]bool{
+	"string":      true,
+	"number":      true,
+	"boolean":     true,
+	"select":      true,
+	"multiselect": true,
+	"date":        true,
+}
+
+// attributeDateLayout is the form of date attribute values.
+const attributeDateLayout = "2006-01-02"
+
 // CategoryService implements the category business logic.
 type CategoryService struct {
 	categoryRepo ports.CategoryRepository
+	txManager    ports.TransactionManager
 }
 
 // NewCategoryService creates a new category service instance.
-func NewCategoryService(categoryRepo ports.CategoryRepository) *CategoryService {
+func NewCategoryService(categoryRepo ports.CategoryRepository, txManager ports.TransactionManager) *CategoryService {
 	return &CategoryService{
 		categoryRepo: categoryRepo,
+		txManager:    txManager,
 	}
 }
 
-// CreateCategory creates a new category.
+// CreateCategory creates a new category. Its parent, if any, must already
+// exist.
 func (s *CategoryService) CreateCategory(ctx context.Context, category *domain.Category) error {
+	if err := validateAttributeDefinitions(category.AttributeDefinitions); err != nil {
+		return err
+	}
+	if category.ParentID != "" {
+		if _, err := s.categoryRepo.GetByID(ctx, category.ParentID); err != nil {
+			return fmt.Errorf("%w: parent %s: %v", ErrInvalidCategory, category.ParentID, err)
+		}
+	}
 	return s.categoryRepo.Create(ctx, category)
 }
 
-// GetCategory retrieves a category by ID.
+// GetCategory retrieves a category by ID with its path and inherited
+// attribute definitions resolved.
 func (s *CategoryService) GetCategory(ctx context.Context, id string) (*domain.Category, error) {
-	return s.categoryRepo.GetByID(ctx, id)
+	return resolveCategory(ctx, s.categoryRepo, id)
 }
 
-// ListCategories retrieves all categories with pagination.
-func (s *CategoryService) ListCategories(ctx context.Context, limit, offset int) ([]*domain.Category, error) {
-	return s.categoryRepo.List(ctx, limit, offset)
+// categorySorts maps each category sort to its default order.
+var categorySorts = map[string]string{domain.SortName: domain.SortAsc}
+
+// ListCategories retrieves a page of categories by name, with their paths
+// and inherited attribute definitions resolved, the total and the cursors of
+// the pages either side.
+func (s *CategoryService) ListCategories(ctx context.Context, page domain.PageRequest) (*domain.CategoryPage, error) {
+	page, err := resolvePage(page, domain.SortName, categorySorts)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.categoryRepo.List(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, category := range result.Categories {
+		resolved, err := resolveCategory(ctx, s.categoryRepo, category.ID)
+		if err != nil {
+			return nil, err
+		}
+		result.Categories[i] = resolved
+	}
+	setPageCursors(&result.PageInfo, page)
+	return result, nil
 }
 
-// ValidateProperties validates product properties against the category's attribute definitions.
-func ValidateProperties(category *domain.Category, properties map[string]interface{}) error {
-	if category == nil {
+// UpdateCategory changes a category's name, parent and attribute definitions
+// as a new version; those the update omits are kept. Products in the category
+// and the categories below it are brought in line in the same transaction:
+// properties are renamed, missing ones are given the update's defaults,
+// measured values are rescaled when their attribute's unit changes and, if
+// asked, values are converted to their attribute's new type. Products that
+// would still fail validation against their category's merged schema are
+// reported and block the change, as does a dry run; the report is returned
+// either way.
+func (s *CategoryService) UpdateCategory(ctx context.Context, id string, update ports.CategoryUpdate) (*domain.SchemaEvolution, error) {
+	if update.AttributeDefinitions != nil {
+		if err := validateAttributeDefinitions(*update.AttributeDefinitions); err != nil {
+			return nil, err
+		}
+	}
+	for from, to := range update.Renames {
+		if from == "" || to == "" || from == to {
+			return nil, fmt.Errorf("%w: invalid rename of %q to %q", ErrInvalidCategory, from, to)
+		}
+	}
+
+	var evolution *domain.SchemaEvolution
+	var violations error
+	err := s.txManager.WithTx(ctx, func(tx ports.Ports) error {
+		current, err := tx.CategoryRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if update.ExpectedVersion != 0 && update.ExpectedVersion != current.Version {
+			return fmt.Errorf("%w: category %s is at version %d, not %d",
+				ErrCategoryVersionConflict, id, current.Version, update.ExpectedVersion)
+		}
+
+		name := update.Name
+		if name == "" {
+			name = current.Name
+		}
+		category := &domain.Category{
+			ID:                   id,
+			Name:                 name,
+			ParentID:             current.ParentID,
+			AttributeDefinitions: current.AttributeDefinitions,
+			Version:              current.Version + 1,
+		}
+		if update.ParentID != nil {
+			category.ParentID = *update.ParentID
+		}
+		if update.AttributeDefinitions != nil {
+			category.AttributeDefinitions = *update.AttributeDefinitions
+		}
+
+		// Resolve the merged schema of the category and everything below it
+		descendants, err := tx.CategoryRepo.ListDescendants(ctx, id)
+		if err != nil {
+			return err
+		}
+		var ancestors []*domain.Category
+		if category.ParentID != "" {
+			if category.ParentID == id {
+				return fmt.Errorf("%w: category %s cannot be its own parent", ErrInvalidCategory, id)
+			}
+			for _, d := range descendants {
+				if d.ID == category.ParentID {
+					return fmt.Errorf("%w: category %s cannot move below its descendant %s", ErrInvalidCategory, id, d.ID)
+				}
+			}
+			if ancestors, err = tx.CategoryRepo.ListAncestors(ctx, category.ParentID); err != nil {
+				return fmt.Errorf("%w: parent %s: %v", ErrInvalidCategory, category.ParentID, err)
+			}
+		}
+		schemas := resolveSubtree(ancestors, category, descendants)
+
+		// The schemas before the update, to rescale values whose unit changes
+		previousAncestors := ancestors
+		if current.ParentID != category.ParentID {
+			previousAncestors = nil
+			if current.ParentID != "" {
+				if previousAncestors, err = tx.CategoryRepo.ListAncestors(ctx, current.ParentID); err != nil {
+					return err
+				}
+			}
+		}
+		previousSchemas := resolveSubtree(previousAncestors, current, descendants)
+
+		evolution = &domain.SchemaEvolution{
+			CategoryID:  id,
+			FromVersion: current.Version,
+			ToVersion:   category.Version,
+			Violations:  []domain.PropertyViolation{},
+		}
+
+		products, err := categoryProducts(ctx, tx.ProductRepo, id)
+		if err != nil {
+			return err
+		}
+
+		changed := make(map[*domain.Product]*domain.Product)
+		used := make(map[uniqueValue]string)
+		for _, product := range products {
+			schema, ok := schemas[product.CategoryID]
+			if !ok {
+				continue
+			}
+			evolution.Products++
+			evolved, backfilled, migrated := evolveProduct(product, previousSchemas[product.CategoryID], schema, update)
+			if backfilled {
+				evolution.Backfilled++
+			}
+			if migrated {
+				evolution.Migrated++
+			}
+
+			if err := ValidateProperties(schema, evolved.Properties); err != nil {
+				evolution.Violations = append(evolution.Violations, domain.PropertyViolation{
+					ProductID: product.ID,
+					SKU:       product.SKU,
+					Error:     err.Error(),
+				})
+				continue
+			}
+			if taken := claimUniqueValues(used, schema, evolved); len(taken) > 0 {
+				evolution.Violations = append(evolution.Violations, domain.PropertyViolation{
+					ProductID: product.ID,
+					SKU:       product.SKU,
+					Error:     fmt.Sprintf("%v: %s", ErrInvalidProperty, strings.Join(taken, "; ")),
+				})
+				continue
+			}
+			evolved.SearchTerms = searchTerms(schema, evolved)
+			reindexed := evolved.SearchTerms != product.SearchTerms
+			if reindexed {
+				evolution.Reindexed++
+			}
+			if backfilled || migrated || reindexed {
+				changed[product] = evolved
+			}
+		}
+
+		if len(evolution.Violations) > 0 {
+			violations = fmt.Errorf("%w: %d product(s) would fail validation against version %d",
+				ErrInvalidProperty, len(evolution.Violations), category.Version)
+			return nil
+		}
+		if update.DryRun {
+			return nil
+		}
+
+		for _, product := range products {
+			evolved, ok := changed[product]
+			if !ok {
+				continue
+			}
+			if err := tx.ProductRepo.Update(ctx, evolved); err != nil {
+				return fmt.Errorf("update product %s: %w", product.ID, err)
+			}
+		}
+		if err := tx.CategoryRepo.Update(ctx, category); err != nil {
+			return err
+		}
+
+		payload := map[string]interface{}{
+			"category_id":  id,
+			"from_version": evolution.FromVersion,
+			"to_version":   evolution.ToVersion,
+			"backfilled":   evolution.Backfilled,
+			"migrated":     evolution.Migrated,
+			"reindexed":    evolution.Reindexed,
+		}
+		if err := newTxAuditService(ctx, tx.AuditRepo).LogAction(ctx, "UPDATE_CATEGORY", "system", payload); err != nil {
+			return fmt.Errorf("audit log: %w", err)
+		}
+
+		evolution.Applied = true
 		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	for _, attr := range category.AttributeDefinitions {
-		val, exists := properties[attr.Key]
+	return evolution, violations
+}
 
-		// Check required fields
-		if attr.Required && !exists {
-			return fmt.Errorf("%w: missing required property %q", ErrInvalidProperty, attr.Key)
+// DeleteCategory deletes a category that nothing refers to any more.
+func (s *CategoryService) DeleteCategory(ctx context.Context, id string) error {
+	return s.txManager.WithTx(ctx, func(tx ports.Ports) error {
+		if _, err := tx.CategoryRepo.GetByID(ctx, id); err != nil {
+			return err
 		}
 
-		if !exists {
+		refs, err := tx.CategoryRepo.CountReferences(ctx, id)
+		if err != nil {
+			return err
+		}
+		if refs > 0 {
+			return fmt.Errorf("%w: category %s is referred to %d time(s) by child categories, products, restrictions or price list rules",
+				ErrCategoryInUse, id, refs)
+		}
+
+		if err := tx.CategoryRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+
+		payload := map[string]interface{}{
+			"category_id": id,
+		}
+		if err := newTxAuditService(ctx, tx.AuditRepo).LogAction(ctx, "DELETE_CATEGORY", "system", payload); err != nil {
+			return fmt.Errorf("audit log: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListCategoryVersions retrieves every version of a category, newest first.
+func (s *CategoryService) ListCategoryVersions(ctx context.Context, id string) ([]*domain.CategoryVersion, error) {
+	if _, err := s.categoryRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.categoryRepo.ListVersions(ctx, id)
+}
+
+// validateAttributeDefinitions checks that attribute keys are present and
+// unique, that each type is supported and that each attribute's rules are
+// consistent, including with its default.
+func validateAttributeDefinitions(attrs []domain.AttributeDefinition) error {
+	seen := make(map[string]bool, len(attrs))
+	for _, attr := range attrs {
+		if attr.Key == "" {
+			return fmt.Errorf("%w: attribute key is required", ErrInvalidCategory)
+		}
+		if seen[attr.Key] {
+			return fmt.Errorf("%w: duplicate attribute %q", ErrInvalidCategory, attr.Key)
+		}
+		seen[attr.Key] = true
+		if !attributeTypes[attr.Type] {
+			return fmt.Errorf("%w: attribute %q has unknown type %q", ErrInvalidCategory, attr.Key, attr.Type)
+		}
+
+		if attr.Min != nil && attr.Max != nil && *attr.Min > *attr.Max {
+			return fmt.Errorf("%w: attribute %q has min above max", ErrInvalidCategory, attr.Key)
+		}
+		if attr.Step != nil && *attr.Step <= 0 {
+			return fmt.Errorf("%w: attribute %q step must be positive", ErrInvalidCategory, attr.Key)
+		}
+		if (attr.MinLength != nil && *attr.MinLength < 0) || (attr.MaxLength != nil && *attr.MaxLength < 0) ||
+			(attr.MinLength != nil && attr.MaxLength != nil && *attr.MinLength > *attr.MaxLength) {
+			return fmt.Errorf("%w: attribute %q has an invalid length range", ErrInvalidCategory, attr.Key)
+		}
+		if attr.Pattern != "" {
+			if _, err := regexp.Compile(attr.Pattern); err != nil {
+				return fmt.Errorf("%w: attribute %q pattern: %v", ErrInvalidCategory, attr.Key, err)
+			}
+		}
+		if attr.Unique && attr.Type == "multiselect" {
+			return fmt.Errorf("%w: multiselect attribute %q cannot be unique", ErrInvalidCategory, attr.Key)
+		}
+		if attr.Searchable && attr.Type == "boolean" {
+			return fmt.Errorf("%w: boolean attribute %q cannot be searchable", ErrInvalidCategory, attr.Key)
+		}
+		if attr.Default != nil {
+			if msg := checkAttribute(attr, attr.Default); msg != "" {
+				return fmt.Errorf("%w: attribute %q default: %s", ErrInvalidCategory, attr.Key, msg)
+			}
+		}
+	}
+	return nil
+}
+
+// applyAttributeDefaults gives properties missing from a new product the
+// defaults of their attribute definitions, returning the properties.
+func applyAttributeDefaults(category *domain.Category, properties map[string]interface{}) map[string]interface{} {
+	if category == nil {
+		return properties
+	}
+	for _, attr := range effectiveAttributes(category) {
+		if attr.Default == nil {
+			continue
+		}
+		if _, exists := properties[attr.Key]; exists {
 			continue
 		}
+		if properties == nil {
+			properties = make(map[string]interface{})
+		}
+		properties[attr.Key] = attr.Default
+	}
+	return properties
+}
 
-		// Check data types
+// coercePropertyTypes stores number and boolean properties given as text,
+// such as "220" from a CSV import, as JSON numbers and booleans so that
+// typed property filters match them. Text that does not parse is left for
+// validation to report.
+func coercePropertyTypes(category *domain.Category, properties map[string]interface{}) {
+	if category == nil {
+		return
+	}
+	for _, attr := range effectiveAttributes(category) {
+		text, ok := properties[attr.Key].(string)
+		if !ok {
+			continue
+		}
 		switch attr.Type {
-		case "string":
-			if _, ok := val.(string); !ok {
-				return fmt.Errorf("%w: property %q must be a string", ErrInvalidProperty, attr.Key)
-			}
 		case "number":
-			if !isNumeric(val) {
-				return fmt.Errorf("%w: property %q must be a number", ErrInvalidProperty, attr.Key)
+			if n, err := strconv.ParseFloat(strings.TrimSpace(text), 64); err == nil {
+				properties[attr.Key] = n
 			}
 		case "boolean":
-			if _, ok := val.(bool); !ok {
-				return fmt.Errorf("%w: property %q must be a boolean", ErrInvalidProperty, attr.Key)
+			if b, err := strconv.ParseBool(strings.TrimSpace(text)); err == nil {
+				properties[attr.Key] = b
 			}
-		case "select":
-			strVal, ok := val.(string)
+		}
+	}
+}
+
+// resolveCategory loads a category with its path and the attribute
+// definitions it inherits merged under its own.
+func resolveCategory(ctx context.Context, categoryRepo ports.CategoryRepository, id string) (*domain.Category, error) {
+	chain, err := categoryRepo.ListAncestors(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return inheritFrom(chain), nil
+}
+
+// categoryLineage returns the IDs of a category and its ancestors, nearest
+// first, or nil when there is no category.
+func categoryLineage(ctx context.Context, categoryRepo ports.CategoryRepository, id string) ([]string, error) {
+	if id == "" {
+		return nil, nil
+	}
+	chain, err := categoryRepo.ListAncestors(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(chain))
+	for i := len(chain) - 1; i >= 0; i-- {
+		ids = append(ids, chain[i].ID)
+	}
+	return ids, nil
+}
+
+// resolveSubtree resolves a category and every category below it, given the
+// category's ancestors from the top level down. It returns the categories by
+// ID.
+func resolveSubtree(ancestors []*domain.Category, category *domain.Category, descendants []*domain.Category) map[string]*domain.Category {
+	byID := make(map[string]*domain.Category, len(ancestors)+len(descendants)+1)
+	for _, c := range ancestors {
+		byID[c.ID] = c
+	}
+	byID[category.ID] = category
+	for _, c := range descendants {
+		byID[c.ID] = c
+	}
+
+	resolved := make(map[string]*domain.Category, len(descendants)+1)
+	for _, c := range append([]*domain.Category{category}, descendants...) {
+		var chain []*domain.Category
+		for node := c; node != nil && len(chain) <= len(byID); node = byID[node.ParentID] {
+			chain = append([]*domain.Category{node}, chain...)
+		}
+		resolved[c.ID] = inheritFrom(chain)
+	}
+	return resolved
+}
+
+// inheritFrom returns a copy of the last category in a top-down chain with
+// its path and effective attribute definitions filled in. A descendant's
+// definition replaces an inherited one with the same key in place; new keys
+// follow the inherited ones.
+func inheritFrom(chain []*domain.Category) *domain.Category {
+	category := *chain[len(chain)-1]
+	category.Path = make([]domain.CategoryRef, 0, len(chain))
+	category.EffectiveAttributes = []domain.AttributeDefinition{}
+
+	index := make(map[string]int)
+	for _, c := range chain {
+		category.Path = append(category.Path, domain.CategoryRef{ID: c.ID, Name: c.Name})
+		for _, attr := range c.AttributeDefinitions {
+			if i, ok := index[attr.Key]; ok {
+				category.EffectiveAttributes[i] = attr
+				continue
+			}
+			index[attr.Key] = len(category.EffectiveAttributes)
+			category.EffectiveAttributes = append(category.EffectiveAttributes, attr)
+		}
+	}
+	return &category
+}
+
+// effectiveAttributes returns the attribute definitions that apply to a
+// category's products: the merged ones when the category has been resolved
+// against its ancestors, otherwise its own.
+func effectiveAttributes(category *domain.Category) []domain.AttributeDefinition {
+	if category.EffectiveAttributes != nil {
+		return category.EffectiveAttributes
+	}
+	return category.AttributeDefinitions
+}
+
+// categoryProducts returns every product in a category and the categories
+// below it, or every product when categoryID is empty.
+func categoryProducts(ctx context.Context, productRepo ports.ProductRepository, categoryID string) ([]*domain.Product, error) {
+	filter := domain.FilterOptions{CategoryID: categoryID, PageRequest: domain.PageRequest{Limit: categoryPageSize}}
+
+	var products []*domain.Product
+	for {
+		page, err := productRepo.Search(ctx, filter, nil)
+		if err != nil {
+			return nil, fmt.Errorf("list products: %w", err)
+		}
+		products = append(products, page.Products...)
+		if !page.HasNext || page.Last == nil {
+			return products, nil
+		}
+		filter.After = page.Last
+	}
+}
+
+// uniqueValue identifies the value of a unique property within a category.
+type uniqueValue struct {
+	categoryID string
+	key        string
+	value      interface{}
+}
+
+// claimUniqueValues records the values of a product's unique properties as
+// used, returning a message for each one another product already uses.
+func claimUniqueValues(used map[uniqueValue]string, category *domain.Category, product *domain.Product) []string {
+	var taken []string
+	for _, attr := range effectiveAttributes(category) {
+		val, exists := product.Properties[attr.Key]
+		if !attr.Unique || !exists {
+			continue
+		}
+		switch v := val.(type) {
+		case string, bool:
+		default:
+			n, ok := toNumber(v)
 			if !ok {
-				return fmt.Errorf("%w: property %q must be a string for select type", ErrInvalidProperty, attr.Key)
+				continue
 			}
-			if len(attr.Options) > 0 && !contains(attr.Options, strVal) {
-				return fmt.Errorf("%w: property %q value %q is not in allowed options %v", ErrInvalidProperty, attr.Key, strVal, attr.Options)
+			val = n
+		}
+
+		key := uniqueValue{categoryID: product.CategoryID, key: attr.Key, value: val}
+		if other, ok := used[key]; ok {
+			taken = append(taken, fmt.Sprintf("property %q value %v is already used by product %s", attr.Key, val, other))
+			continue
+		}
+		used[key] = product.ID
+	}
+	return taken
+}
+
+// evolveProduct returns a copy of a product with its properties brought in
+// line with a category update, reporting whether a default was filled in and
+// whether a value was renamed, rescaled to a new unit or converted. previous
+// is the product's category schema before the update, if it had one.
+func evolveProduct(product *domain.Product, previous, category *domain.Category, update ports.CategoryUpdate) (*domain.Product, bool, bool) {
+	evolved := *product
+	evolved.Properties = make(map[string]interface{}, len(product.Properties))
+	for key, val := range product.Properties {
+		evolved.Properties[key] = val
+	}
+	evolved.PropertyOriginals = make(map[string]string, len(product.PropertyOriginals))
+	for key, text := range product.PropertyOriginals {
+		evolved.PropertyOriginals[key] = text
+	}
+
+	var backfilled, migrated bool
+	renamedFrom := make(map[string]string, len(update.Renames))
+	for from, to := range update.Renames {
+		renamedFrom[to] = from
+		val, exists := evolved.Properties[from]
+		if !exists {
+			continue
+		}
+		if _, taken := evolved.Properties[to]; !taken {
+			evolved.Properties[to] = val
+			if text, ok := evolved.PropertyOriginals[from]; ok {
+				evolved.PropertyOriginals[to] = text
 			}
 		}
+		delete(evolved.Properties, from)
+		delete(evolved.PropertyOriginals, from)
+		migrated = true
 	}
 
-	return nil
+	previousAttrs := make(map[string]domain.AttributeDefinition)
+	if previous != nil {
+		for _, attr := range effectiveAttributes(previous) {
+			previousAttrs[attr.Key] = attr
+		}
+	}
+
+	for _, attr := range effectiveAttributes(category) {
+		val, exists := evolved.Properties[attr.Key]
+		if !exists {
+			if def, ok := update.Defaults[attr.Key]; ok {
+				evolved.Properties[attr.Key] = def
+				backfilled = true
+			}
+			continue
+		}
+		key := attr.Key
+		if from, ok := renamedFrom[key]; ok {
+			key = from
+		}
+		if rescaled, ok := rescaleMeasure(val, previousAttrs[key], attr); ok {
+			evolved.Properties[attr.Key] = rescaled
+			migrated = true
+		}
+		if update.ConvertTypes {
+			if converted, ok := convertProperty(val, attr.Type); ok {
+				evolved.Properties[attr.Key] = converted
+				migrated = true
+			}
+		}
+	}
+
+	if update.ConvertTypes && normalizeMeasures(category, &evolved) {
+		migrated = true
+	}
+	return &evolved, backfilled, migrated
+}
+
+// convertProperty converts a property value to an attribute type, reporting
+// false when the value already has that type or cannot be converted.
+func convertProperty(val interface{}, attrType string) (interface{}, bool) {
+	switch attrType {
+	case "string", "select":
+		switch v := val.(type) {
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), true
+		case int:
+			return strconv.Itoa(v), true
+		case bool:
+			return strconv.FormatBool(v), true
+		}
+	case "number":
+		if v, ok := val.(string); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return f, true
+			}
+		}
+	case "boolean":
+		if v, ok := val.(string); ok {
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// ValidateProperties validates product properties against the category's
+// attribute definitions, including those inherited when the category has
+// been resolved against its ancestors. Every failing property is reported
+// in a *ValidationError.
+func ValidateProperties(category *domain.Category, properties map[string]interface{}) error {
+	if category == nil {
+		return nil
+	}
+
+	verr := &ValidationError{}
+	for _, attr := range effectiveAttributes(category) {
+		val, exists := properties[attr.Key]
+
+		// Check required fields
+		if !exists {
+			if attr.Required {
+				verr.add(attr.Key, "missing required property %q", attr.Key)
+			}
+			continue
+		}
+
+		if msg := checkAttribute(attr, val); msg != "" {
+			verr.add(attr.Key, "property %q %s", attr.Key, msg)
+		}
+	}
+
+	return verr.errOrNil()
 }
 
-// isNumeric checks if a value is a numeric type.
-func isNumeric(val interface{}) bool {
+// checkAttribute checks a property value against its attribute definition,
+// returning what is wrong with it or an empty string if it is valid.
+func checkAttribute(attr domain.AttributeDefinition, val interface{}) string {
+	switch attr.Type {
+	case "string":
+		strVal, ok := val.(string)
+		if !ok {
+			return "must be a string"
+		}
+		return checkString(attr, strVal)
+	case "number":
+		num, ok := toNumber(val)
+		if text, isText := val.(string); !ok && isText {
+			if _, measured := attributeUnit(attr); measured {
+				var msg string
+				if num, msg = parseMeasure(attr, text); msg != "" {
+					return msg
+				}
+				ok = true
+			}
+		}
+		if !ok {
+			return "must be a number"
+		}
+		return checkNumber(attr, num)
+	case "boolean":
+		if _, ok := val.(bool); !ok {
+			return "must be a boolean"
+		}
+	case "select":
+		strVal, ok := val.(string)
+		if !ok {
+			return "must be a string for select type"
+		}
+		if len(attr.Options) > 0 && !contains(attr.Options, strVal) {
+			return fmt.Sprintf("value %q is not in allowed options %v", strVal, attr.Options)
+		}
+	case "multiselect":
+		values, ok := toStringList(val)
+		if !ok {
+			return "must be a list of strings for multiselect type"
+		}
+		return checkMultiselect(attr, values)
+	case "date":
+		strVal, ok := val.(string)
+		if !ok {
+			return "must be a date string"
+		}
+		if _, err := time.Parse(attributeDateLayout, strVal); err != nil {
+			return fmt.Sprintf("value %q is not a date in YYYY-MM-DD form", strVal)
+		}
+	}
+	return ""
+}
+
+// checkString applies an attribute's length and pattern rules to a string.
+func checkString(attr domain.AttributeDefinition, val string) string {
+	length := utf8.RuneCountInString(val)
+	if attr.MinLength != nil && length < *attr.MinLength {
+		return fmt.Sprintf("must be at least %d characters", *attr.MinLength)
+	}
+	if attr.MaxLength != nil && length > *attr.MaxLength {
+		return fmt.Sprintf("must be at most %d characters", *attr.MaxLength)
+	}
+	if attr.Pattern != "" {
+		re, err := regexp.Compile(`^(?:` + attr.Pattern + `)$`)
+		if err != nil || !re.MatchString(val) {
+			return fmt.Sprintf("value %q does not match pattern %q", val, attr.Pattern)
+		}
+	}
+	return ""
+}
+
+// checkNumber applies an attribute's integer, range and step rules to a number.
+func checkNumber(attr domain.AttributeDefinition, val float64) string {
+	if attr.Integer && val != math.Trunc(val) {
+		return "must be a whole number"
+	}
+	if attr.Min != nil && val < *attr.Min {
+		return fmt.Sprintf("must be at least %v", *attr.Min)
+	}
+	if attr.Max != nil && val > *attr.Max {
+		return fmt.Sprintf("must be at most %v", *attr.Max)
+	}
+	if attr.Step != nil {
+		var base float64
+		if attr.Min != nil {
+			base = *attr.Min
+		}
+		steps := (val - base) / *attr.Step
+		if math.Abs(steps-math.Round(steps)) > 1e-9 {
+			return fmt.Sprintf("must be in steps of %v from %v", *attr.Step, base)
+		}
+	}
+	return ""
+}
+
+// checkMultiselect applies an attribute's options and count rules to a
+// multiselect value.
+func checkMultiselect(attr domain.AttributeDefinition, values []string) string {
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		if len(attr.Options) > 0 && !contains(attr.Options, v) {
+			return fmt.Sprintf("value %q is not in allowed options %v", v, attr.Options)
+		}
+		if seen[v] {
+			return fmt.Sprintf("value %q is selected more than once", v)
+		}
+		seen[v] = true
+	}
+	if attr.MinLength != nil && len(values) < *attr.MinLength {
+		return fmt.Sprintf("must have at least %d options selected", *attr.MinLength)
+	}
+	if attr.MaxLength != nil && len(values) > *attr.MaxLength {
+		return fmt.Sprintf("must have at most %d options selected", *attr.MaxLength)
+	}
+	return ""
+}
+
+// toNumber converts a numeric value, or a string holding one, to a float64.
+func toNumber(val interface{}) (float64, bool) {
 	switch v := val.(type) {
-	case float64, float32, int, int32, int64:
-		return true
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
 	case string:
-		_, err := strconv.ParseFloat(v, 64)
-		return err == nil
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// toStringList converts a list of strings, as decoded from JSON or built in
+// Go, to a []string.
+func toStringList(val interface{}) ([]string, bool) {
+	switch v := val.(type) {
+	case []string:
+		return v, true
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, s)
+		}
+		return values, true
 	default:
-		return false
+		return nil, false
 	}
 }
 
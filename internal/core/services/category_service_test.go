@@ -1,10 +1,13 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
 )
 
 func TestValidateProperties_NilCategory(t *testing.T) {
@@ -177,3 +180,548 @@ func TestValidateProperties_MultipleAttributes(t *testing.T) {
 		t.Fatalf("expected ErrInvalidProperty for missing required field, got %v", err)
 	}
 }
+
+// --- Category Update Tests ---
+
+// newCategoryTestService returns a category service with a "bulbs" category
+// at version 2 holding a bulb with a typo'd key and a bulb with a string wattage.
+func newCategoryTestService() (*CategoryService, *mockProductRepository, *mockCategoryRepository, *mockAuditLogRepository) {
+	categoryRepo := &mockCategoryRepository{categories: map[string]*domain.Category{
+		"bulbs": {ID: "bulbs", Name: "Bulbs", Version: 2, AttributeDefinitions: []domain.AttributeDefinition{
+			{Key: "watage", Type: "string"},
+		}},
+	}}
+	productRepo := &mockProductRepository{products: []*domain.Product{
+		{ID: "p1", SKU: "B-1", CategoryID: "bulbs", Properties: map[string]interface{}{"watage": "60"}},
+		{ID: "p2", SKU: "B-2", CategoryID: "bulbs", Properties: map[string]interface{}{"watage": "bright"}},
+	}}
+	auditRepo := &mockAuditLogRepository{}
+	txManager := &mockSaleTxManager{productRepo: productRepo, categoryRepo: categoryRepo, auditRepo: auditRepo}
+	return NewCategoryService(categoryRepo, txManager), productRepo, categoryRepo, auditRepo
+}
+
+// wattageUpdate renames watage to wattage as a number and adds a required base.
+func wattageUpdate() ports.CategoryUpdate {
+	return ports.CategoryUpdate{
+		AttributeDefinitions: &[]domain.AttributeDefinition{
+			{Key: "wattage", Type: "number"},
+			{Key: "base", Type: "select", Required: true, Options: []string{"E27", "B22"}},
+		},
+		ExpectedVersion: 2,
+		Renames:         map[string]string{"watage": "wattage"},
+		Defaults:        map[string]interface{}{"base": "E27"},
+		ConvertTypes:    true,
+	}
+}
+
+func TestUpdateCategory_ReportsViolations(t *testing.T) {
+	svc, productRepo, categoryRepo, _ := newCategoryTestService()
+
+	evolution, err := svc.UpdateCategory(context.Background(), "bulbs", wattageUpdate())
+	if !errors.Is(err, ErrInvalidProperty) {
+		t.Fatalf("expected ErrInvalidProperty, got %v", err)
+	}
+
+	if evolution == nil || evolution.Applied {
+		t.Fatalf("expected an unapplied report, got %+v", evolution)
+	}
+	if evolution.Products != 2 || evolution.Backfilled != 2 || evolution.Migrated != 2 {
+		t.Errorf("expected 2 products backfilled and migrated, got %+v", evolution)
+	}
+	if len(evolution.Violations) != 1 || evolution.Violations[0].ProductID != "p2" {
+		t.Errorf("expected only p2 to fail, got %+v", evolution.Violations)
+	}
+
+	if categoryRepo.categories["bulbs"].Version != 2 {
+		t.Error("expected the category to be unchanged")
+	}
+	if _, ok := productRepo.products[0].Properties["watage"]; !ok {
+		t.Error("expected product properties to be unchanged")
+	}
+}
+
+func TestUpdateCategory_BackfillsAndMigrates(t *testing.T) {
+	svc, productRepo, categoryRepo, auditRepo := newCategoryTestService()
+	productRepo.products[1].Properties["watage"] = "40"
+
+	evolution, err := svc.UpdateCategory(context.Background(), "bulbs", wattageUpdate())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !evolution.Applied || evolution.FromVersion != 2 || evolution.ToVersion != 3 {
+		t.Errorf("expected version 2 to become 3, got %+v", evolution)
+	}
+
+	props := productRepo.products[0].Properties
+	if props["wattage"] != 60.0 || props["base"] != "E27" {
+		t.Errorf("expected wattage 60 and base E27, got %v", props)
+	}
+	if _, ok := props["watage"]; ok {
+		t.Error("expected the misspelt key to be removed")
+	}
+
+	category := categoryRepo.categories["bulbs"]
+	if category.Version != 3 || category.Name != "Bulbs" || len(category.AttributeDefinitions) != 2 {
+		t.Errorf("expected version 3 with the new attributes, got %+v", category)
+	}
+	versions, _ := svc.ListCategoryVersions(context.Background(), "bulbs")
+	if len(versions) != 1 || versions[0].Version != 3 {
+		t.Errorf("expected version 3 to be recorded, got %+v", versions)
+	}
+	if len(auditRepo.logs) != 1 || auditRepo.logs[0].Action != "UPDATE_CATEGORY" {
+		t.Errorf("expected an UPDATE_CATEGORY audit log, got %+v", auditRepo.logs)
+	}
+}
+
+func TestUpdateCategory_DryRun(t *testing.T) {
+	svc, productRepo, categoryRepo, _ := newCategoryTestService()
+	productRepo.products[1].Properties["watage"] = "40"
+	update := wattageUpdate()
+	update.DryRun = true
+
+	evolution, err := svc.UpdateCategory(context.Background(), "bulbs", update)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if evolution.Applied || len(evolution.Violations) != 0 || evolution.Migrated != 2 {
+		t.Errorf("expected a clean unapplied report, got %+v", evolution)
+	}
+	if categoryRepo.categories["bulbs"].Version != 2 || productRepo.products[0].Properties["watage"] != "60" {
+		t.Error("expected a dry run to change nothing")
+	}
+}
+
+func TestUpdateCategory_ChecksUniqueValues(t *testing.T) {
+	svc, productRepo, categoryRepo, _ := newCategoryTestService()
+	productRepo.products[1].Properties["watage"] = "60"
+
+	// Marking an attribute unique when two products share a value
+	update := ports.CategoryUpdate{
+		AttributeDefinitions: &[]domain.AttributeDefinition{{Key: "watage", Type: "string", Unique: true}},
+		ExpectedVersion:      2,
+	}
+	evolution, err := svc.UpdateCategory(context.Background(), "bulbs", update)
+	if !errors.Is(err, ErrInvalidProperty) {
+		t.Fatalf("expected ErrInvalidProperty, got %v", err)
+	}
+	if len(evolution.Violations) != 1 || evolution.Violations[0].ProductID != "p2" {
+		t.Errorf("expected p2 to repeat p1's value, got %+v", evolution.Violations)
+	}
+
+	// Backfilling one default into every product of a unique attribute
+	update.AttributeDefinitions = &[]domain.AttributeDefinition{
+		{Key: "watage", Type: "string"},
+		{Key: "serial", Type: "string", Unique: true},
+	}
+	update.Defaults = map[string]interface{}{"serial": "S-1"}
+	evolution, err = svc.UpdateCategory(context.Background(), "bulbs", update)
+	if !errors.Is(err, ErrInvalidProperty) {
+		t.Fatalf("expected ErrInvalidProperty, got %v", err)
+	}
+	if len(evolution.Violations) != 1 || !strings.Contains(evolution.Violations[0].Error, "serial") {
+		t.Errorf("expected the backfilled serial to repeat, got %+v", evolution.Violations)
+	}
+	if categoryRepo.categories["bulbs"].Version != 2 {
+		t.Error("expected the category to be unchanged")
+	}
+}
+
+func TestUpdateCategory_RenameKeepsAttributes(t *testing.T) {
+	svc, _, categoryRepo, _ := newCategoryTestService()
+
+	if _, err := svc.UpdateCategory(context.Background(), "bulbs", ports.CategoryUpdate{Name: "Light Bulbs"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	category := categoryRepo.categories["bulbs"]
+	if category.Name != "Light Bulbs" {
+		t.Errorf("expected the category renamed, got %q", category.Name)
+	}
+	if len(category.AttributeDefinitions) != 1 || category.AttributeDefinitions[0].Key != "watage" {
+		t.Errorf("expected the attribute definitions kept, got %+v", category.AttributeDefinitions)
+	}
+}
+
+func TestUpdateCategory_Invalid(t *testing.T) {
+	svc, _, _, _ := newCategoryTestService()
+	ctx := context.Background()
+
+	stale := wattageUpdate()
+	stale.ExpectedVersion = 1
+	if _, err := svc.UpdateCategory(ctx, "bulbs", stale); !errors.Is(err, ErrCategoryVersionConflict) {
+		t.Errorf("expected ErrCategoryVersionConflict, got %v", err)
+	}
+
+	updates := []ports.CategoryUpdate{
+		{AttributeDefinitions: &[]domain.AttributeDefinition{{Key: "", Type: "string"}}},
+		{AttributeDefinitions: &[]domain.AttributeDefinition{{Key: "a", Type: "string"}, {Key: "a", Type: "number"}}},
+		{AttributeDefinitions: &[]domain.AttributeDefinition{{Key: "a", Type: "colour"}}},
+		{Renames: map[string]string{"a": ""}},
+	}
+	for _, update := range updates {
+		if _, err := svc.UpdateCategory(ctx, "bulbs", update); !errors.Is(err, ErrInvalidCategory) {
+			t.Errorf("update %+v: expected ErrInvalidCategory, got %v", update, err)
+		}
+	}
+
+	if _, err := svc.UpdateCategory(ctx, "missing", ports.CategoryUpdate{}); err == nil {
+		t.Error("expected error for unknown category")
+	}
+}
+
+func TestDeleteCategory(t *testing.T) {
+	svc, _, categoryRepo, auditRepo := newCategoryTestService()
+	ctx := context.Background()
+
+	categoryRepo.references = map[string]int{"bulbs": 2}
+	if err := svc.DeleteCategory(ctx, "bulbs"); !errors.Is(err, ErrCategoryInUse) {
+		t.Fatalf("expected ErrCategoryInUse, got %v", err)
+	}
+
+	categoryRepo.references = nil
+	if err := svc.DeleteCategory(ctx, "bulbs"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := categoryRepo.categories["bulbs"]; ok {
+		t.Error("expected the category to be deleted")
+	}
+	if len(auditRepo.logs) != 1 || auditRepo.logs[0].Action != "DELETE_CATEGORY" {
+		t.Errorf("expected a DELETE_CATEGORY audit log, got %+v", auditRepo.logs)
+	}
+
+	if err := svc.DeleteCategory(ctx, "bulbs"); err == nil {
+		t.Error("expected error deleting a missing category")
+	}
+}
+
+// --- Category Hierarchy Tests ---
+
+// newCategoryTreeRepo returns Electrical > Cables > Armoured, where Cables
+// adds a core count and Armoured narrows the inherited voltage to a select.
+func newCategoryTreeRepo() *mockCategoryRepository {
+	return &mockCategoryRepository{categories: map[string]*domain.Category{
+		"electrical": {ID: "electrical", Name: "Electrical", Version: 1, AttributeDefinitions: []domain.AttributeDefinition{
+			{Key: "voltage", Type: "number", Required: true},
+		}},
+		"cables": {ID: "cables", Name: "Cables", ParentID: "electrical", Version: 1, AttributeDefinitions: []domain.AttributeDefinition{
+			{Key: "cores", Type: "number"},
+		}},
+		"armoured": {ID: "armoured", Name: "Armoured", ParentID: "cables", Version: 1, AttributeDefinitions: []domain.AttributeDefinition{
+			{Key: "voltage", Type: "select", Required: true, Options: []string{"600", "1000"}},
+		}},
+	}}
+}
+
+func TestGetCategory_InheritsAttributes(t *testing.T) {
+	svc := NewCategoryService(newCategoryTreeRepo(), &mockSaleTxManager{})
+
+	category, err := svc.GetCategory(context.Background(), "armoured")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(category.Path) != 3 || category.Path[0].Name != "Electrical" || category.Path[2].ID != "armoured" {
+		t.Errorf("expected path Electrical > Cables > Armoured, got %+v", category.Path)
+	}
+	attrs := category.EffectiveAttributes
+	if len(attrs) != 2 || attrs[0].Key != "voltage" || attrs[0].Type != "select" || attrs[1].Key != "cores" {
+		t.Errorf("expected overridden voltage then cores, got %+v", attrs)
+	}
+	if len(category.AttributeDefinitions) != 1 {
+		t.Errorf("expected own definitions to be kept apart, got %+v", category.AttributeDefinitions)
+	}
+
+	if err := ValidateProperties(category, map[string]interface{}{"voltage": "600", "cores": 3.0}); err != nil {
+		t.Errorf("expected valid properties, got %v", err)
+	}
+	if err := ValidateProperties(category, map[string]interface{}{"voltage": 240.0}); !errors.Is(err, ErrInvalidProperty) {
+		t.Errorf("expected the overriding select to apply, got %v", err)
+	}
+	if err := ValidateProperties(category, map[string]interface{}{"voltage": "600", "cores": "many"}); !errors.Is(err, ErrInvalidProperty) {
+		t.Errorf("expected the inherited number to apply, got %v", err)
+	}
+}
+
+func TestCreateProduct_ValidatesInheritedAttributes(t *testing.T) {
+	productRepo := &mockProductRepository{}
+	svc := NewProductService(productRepo, newCategoryTreeRepo(), NewAuditService(&mockAuditLogRepository{}), &mockSaleTxManager{productRepo: productRepo}, &mockSynonymRepository{})
+
+	err := svc.CreateProduct(context.Background(), &domain.Product{ID: "p1", SKU: "CAB-1", CategoryID: "cables", Properties: map[string]interface{}{"cores": 3.0}})
+	if !errors.Is(err, ErrInvalidProperty) {
+		t.Fatalf("expected the inherited required voltage to be enforced, got %v", err)
+	}
+}
+
+func TestUpdateCategory_ChecksDescendantProducts(t *testing.T) {
+	categoryRepo := newCategoryTreeRepo()
+	productRepo := &mockProductRepository{products: []*domain.Product{
+		{ID: "p1", SKU: "CAB-1", CategoryID: "cables", Properties: map[string]interface{}{"voltage": 240.0}},
+		{ID: "p2", SKU: "ARM-1", CategoryID: "armoured", Properties: map[string]interface{}{"voltage": "600"}},
+	}}
+	txManager := &mockSaleTxManager{productRepo: productRepo, categoryRepo: categoryRepo, auditRepo: &mockAuditLogRepository{}}
+	svc := NewCategoryService(categoryRepo, txManager)
+
+	// A new required attribute on Electrical reaches both products
+	evolution, err := svc.UpdateCategory(context.Background(), "electrical", ports.CategoryUpdate{
+		AttributeDefinitions: &[]domain.AttributeDefinition{
+			{Key: "voltage", Type: "number", Required: true},
+			{Key: "rating", Type: "string", Required: true},
+		},
+		Defaults: map[string]interface{}{"rating": "IP20"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if evolution.Products != 2 || evolution.Backfilled != 2 || !evolution.Applied {
+		t.Errorf("expected both products backfilled, got %+v", evolution)
+	}
+	if productRepo.products[1].Properties["rating"] != "IP20" {
+		t.Errorf("expected the armoured product to be backfilled, got %v", productRepo.products[1].Properties)
+	}
+}
+
+func TestUpdateCategory_RejectsCycles(t *testing.T) {
+	categoryRepo := newCategoryTreeRepo()
+	txManager := &mockSaleTxManager{productRepo: &mockProductRepository{}, categoryRepo: categoryRepo, auditRepo: &mockAuditLogRepository{}}
+	svc := NewCategoryService(categoryRepo, txManager)
+	ctx := context.Background()
+
+	for _, parent := range []string{"electrical", "armoured", "missing"} {
+		parent := parent
+		_, err := svc.UpdateCategory(ctx, "electrical", ports.CategoryUpdate{ParentID: &parent})
+		if !errors.Is(err, ErrInvalidCategory) {
+			t.Errorf("parent %s: expected ErrInvalidCategory, got %v", parent, err)
+		}
+	}
+
+	// Moving Armoured directly under Electrical is fine
+	parent := "electrical"
+	evolution, err := svc.UpdateCategory(ctx, "armoured", ports.CategoryUpdate{
+		ParentID:             &parent,
+		AttributeDefinitions: &categoryRepo.categories["armoured"].AttributeDefinitions,
+	})
+	if err != nil || !evolution.Applied {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if categoryRepo.categories["armoured"].ParentID != "electrical" {
+		t.Errorf("expected armoured to move, got parent %q", categoryRepo.categories["armoured"].ParentID)
+	}
+
+	if err := svc.CreateCategory(ctx, &domain.Category{ID: "x", Name: "X", ParentID: "missing"}); !errors.Is(err, ErrInvalidCategory) {
+		t.Errorf("expected ErrInvalidCategory for a missing parent, got %v", err)
+	}
+}
+
+// --- Attribute Rule Tests ---
+
+func intPtr(v int) *int { return &v }
+
+func TestValidateProperties_ReportsEveryFailure(t *testing.T) {
+	cat := &domain.Category{
+		AttributeDefinitions: []domain.AttributeDefinition{
+			{Key: "voltage", Type: "number", Required: true},
+			{Key: "brand", Type: "string"},
+			{Key: "certified", Type: "boolean"},
+		},
+	}
+
+	err := ValidateProperties(cat, map[string]interface{}{"brand": 1.0, "certified": "yes"})
+	if !errors.Is(err, ErrInvalidProperty) {
+		t.Fatalf("expected ErrInvalidProperty, got %v", err)
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a *ValidationError, got %T", err)
+	}
+	if len(verr.Errors) != 3 {
+		t.Fatalf("expected 3 failing properties, got %+v", verr.Errors)
+	}
+	for i, key := range []string{"voltage", "brand", "certified"} {
+		if verr.Errors[i].Key != key {
+			t.Errorf("expected failure %d to be %q, got %q", i, key, verr.Errors[i].Key)
+		}
+	}
+}
+
+func TestValidateProperties_NumberRules(t *testing.T) {
+	cat := &domain.Category{
+		AttributeDefinitions: []domain.AttributeDefinition{
+			{Key: "wattage", Type: "number", Min: floatPtr(5), Max: floatPtr(100), Step: floatPtr(5)},
+			{Key: "cores", Type: "number", Integer: true},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		props map[string]interface{}
+		valid bool
+	}{
+		{"in range on step", map[string]interface{}{"wattage": 60.0, "cores": 3.0}, true},
+		{"numeric string", map[string]interface{}{"wattage": "25"}, true},
+		{"below min", map[string]interface{}{"wattage": 0.0}, false},
+		{"above max", map[string]interface{}{"wattage": 105.0}, false},
+		{"off step", map[string]interface{}{"wattage": 62.0}, false},
+		{"fractional integer", map[string]interface{}{"cores": 2.5}, false},
+	}
+	for _, tt := range tests {
+		err := ValidateProperties(cat, tt.props)
+		if tt.valid && err != nil {
+			t.Errorf("%s: expected no error, got %v", tt.name, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidProperty) {
+			t.Errorf("%s: expected ErrInvalidProperty, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestValidateProperties_StringRules(t *testing.T) {
+	cat := &domain.Category{
+		AttributeDefinitions: []domain.AttributeDefinition{
+			{Key: "code", Type: "string", MinLength: intPtr(3), MaxLength: intPtr(6), Pattern: `[A-Z]+-\d+`},
+		},
+	}
+
+	if err := ValidateProperties(cat, map[string]interface{}{"code": "AB-12"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, code := range []string{"A-", "ABCD-123", "ab-12", "XAB-12x"} {
+		if err := ValidateProperties(cat, map[string]interface{}{"code": code}); !errors.Is(err, ErrInvalidProperty) {
+			t.Errorf("expected %q to be rejected, got %v", code, err)
+		}
+	}
+}
+
+func TestValidateProperties_MultiselectType(t *testing.T) {
+	cat := &domain.Category{
+		AttributeDefinitions: []domain.AttributeDefinition{
+			{Key: "colours", Type: "multiselect", Options: []string{"red", "green", "blue"}, MaxLength: intPtr(2)},
+		},
+	}
+
+	if err := ValidateProperties(cat, map[string]interface{}{"colours": []interface{}{"red", "blue"}}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	invalid := []interface{}{
+		"red",
+		[]interface{}{"red", 1.0},
+		[]interface{}{"pink"},
+		[]interface{}{"red", "red"},
+		[]string{"red", "green", "blue"},
+	}
+	for _, val := range invalid {
+		if err := ValidateProperties(cat, map[string]interface{}{"colours": val}); !errors.Is(err, ErrInvalidProperty) {
+			t.Errorf("expected %v to be rejected, got %v", val, err)
+		}
+	}
+}
+
+func TestValidateProperties_DateType(t *testing.T) {
+	cat := &domain.Category{
+		AttributeDefinitions: []domain.AttributeDefinition{
+			{Key: "expires", Type: "date"},
+		},
+	}
+
+	if err := ValidateProperties(cat, map[string]interface{}{"expires": "2026-02-28"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, val := range []interface{}{"2026-02-30", "28/02/2026", 20260228.0} {
+		if err := ValidateProperties(cat, map[string]interface{}{"expires": val}); !errors.Is(err, ErrInvalidProperty) {
+			t.Errorf("expected %v to be rejected, got %v", val, err)
+		}
+	}
+}
+
+func TestValidateAttributeDefinitions_Rules(t *testing.T) {
+	invalid := map[string]domain.AttributeDefinition{
+		"min above max":      {Key: "w", Type: "number", Min: floatPtr(10), Max: floatPtr(1)},
+		"zero step":          {Key: "w", Type: "number", Step: floatPtr(0)},
+		"length range":       {Key: "s", Type: "string", MinLength: intPtr(5), MaxLength: intPtr(2)},
+		"bad pattern":        {Key: "s", Type: "string", Pattern: "[a-"},
+		"unique multiselect": {Key: "m", Type: "multiselect", Unique: true},
+		"searchable boolean": {Key: "f", Type: "boolean", Searchable: true},
+		"invalid default":    {Key: "b", Type: "select", Options: []string{"E27"}, Default: "B22"},
+	}
+	for name, attr := range invalid {
+		if err := validateAttributeDefinitions([]domain.AttributeDefinition{attr}); !errors.Is(err, ErrInvalidCategory) {
+			t.Errorf("%s: expected ErrInvalidCategory, got %v", name, err)
+		}
+	}
+
+	valid := domain.AttributeDefinition{Key: "b", Type: "select", Options: []string{"E27"}, Default: "E27"}
+	if err := validateAttributeDefinitions([]domain.AttributeDefinition{valid}); err != nil {
+		t.Errorf("expected a valid default to be accepted, got %v", err)
+	}
+}
+
+// newRulesTestService returns a product service with a "lamps" category
+// whose base defaults to E27 and whose model number is unique.
+func newRulesTestService(products ...*domain.Product) (*ProductService, *mockProductRepository) {
+	categoryRepo := &mockCategoryRepository{categories: map[string]*domain.Category{
+		"lamps": {ID: "lamps", Name: "Lamps", AttributeDefinitions: []domain.AttributeDefinition{
+			{Key: "base", Type: "select", Required: true, Options: []string{"E27", "B22"}, Default: "E27"},
+			{Key: "model", Type: "string", Unique: true},
+			{Key: "finishes", Type: "multiselect", Options: []string{"brass", "chrome", "black"}},
+		}},
+	}}
+	productRepo := &mockProductRepository{products: products}
+	auditRepo := &mockAuditLogRepository{}
+	txManager := &mockSaleTxManager{productRepo: productRepo, categoryRepo: categoryRepo, auditRepo: auditRepo}
+	return NewProductService(productRepo, categoryRepo, NewAuditService(auditRepo), txManager, &mockSynonymRepository{}), productRepo
+}
+
+func TestCreateProduct_AppliesDefaults(t *testing.T) {
+	svc, _ := newRulesTestService()
+
+	product := &domain.Product{ID: "p1", SKU: "L-1", CategoryID: "lamps"}
+	if err := svc.CreateProduct(context.Background(), product); err != nil {
+		t.Fatalf("expected the default to satisfy the required base, got %v", err)
+	}
+	if product.Properties["base"] != "E27" {
+		t.Errorf("expected base to default to E27, got %v", product.Properties["base"])
+	}
+}
+
+func TestCreateProduct_RejectsDuplicateUniqueProperty(t *testing.T) {
+	existing := &domain.Product{ID: "p1", SKU: "L-1", CategoryID: "lamps", Properties: map[string]interface{}{"base": "E27", "model": "LX-1"}}
+	svc, _ := newRulesTestService(existing)
+
+	product := &domain.Product{ID: "p2", SKU: "L-2", CategoryID: "lamps", Properties: map[string]interface{}{"base": "GU10", "model": "LX-1"}}
+	err := svc.CreateProduct(context.Background(), product)
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a *ValidationError, got %v", err)
+	}
+	if len(verr.Errors) != 2 || verr.Errors[0].Key != "base" || verr.Errors[1].Key != "model" {
+		t.Fatalf("expected base and model to fail together, got %+v", verr.Errors)
+	}
+
+	// The product may keep its own unique value on update
+	existing.Properties["base"] = "B22"
+	if err := svc.UpdateProduct(context.Background(), existing); err != nil {
+		t.Fatalf("expected a product to keep its own unique value, got %v", err)
+	}
+}
+
+func TestImportProducts_SplitsMultiselect(t *testing.T) {
+	svc, productRepo := newRulesTestService()
+
+	csv := "name,sku,base_price,finishes\nLamp,L-1,20.00,brass|chrome\n"
+	if _, err := svc.ImportProducts(context.Background(), "lamps", strings.NewReader(csv)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(productRepo.products) != 1 {
+		t.Fatalf("expected 1 imported product, got %d", len(productRepo.products))
+	}
+	props := productRepo.products[0].Properties
+	finishes, ok := props["finishes"].([]string)
+	if !ok || len(finishes) != 2 || finishes[0] != "brass" || finishes[1] != "chrome" {
+		t.Errorf("expected finishes [brass chrome], got %#v", props["finishes"])
+	}
+	if props["base"] != "E27" {
+		t.Errorf("expected the imported base to default to E27, got %v", props["base"])
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"math"
+	"time"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// Default ABC/XYZ class boundaries and range.
+const (
+	defaultClassificationDays = 90
+	defaultAThreshold         = 80.0
+	defaultBThreshold         = 95.0
+	defaultXThreshold         = 0.5
+	defaultYThreshold         = 1.0
+)
+
+// classificationDefaults fills in the zero fields of a classification query.
+func classificationDefaults(query ports.ClassificationQuery, now time.Time) ports.ClassificationQuery {
+	if query.To.IsZero() {
+		query.To = now
+	}
+	if query.From.IsZero() {
+		query.From = query.To.AddDate(0, 0, -defaultClassificationDays)
+	}
+	if query.AThreshold == 0 {
+		query.AThreshold = defaultAThreshold
+	}
+	if query.BThreshold == 0 {
+		query.BThreshold = defaultBThreshold
+	}
+	if query.XThreshold == 0 {
+		query.XThreshold = defaultXThreshold
+	}
+	if query.YThreshold == 0 {
+		query.YThreshold = defaultYThreshold
+	}
+	return query
+}
+
+// classifyABC assigns ABC classes to rows ordered by revenue, highest first.
+// A product whose revenue starts before the A threshold is reached is class
+// A, so the product crossing the boundary still counts as A; likewise for B.
+// Products without revenue are always class C.
+func classifyABC(rows []domain.ProductClassification, total, aThreshold, bThreshold float64) {
+	var cumulative float64
+	for i := range rows {
+		row := &rows[i]
+		if total <= 0 || row.Revenue <= 0 {
+			row.ABCClass = domain.ClassC
+			if total > 0 {
+				row.CumulativeShare = roundMoney(cumulative / total * 100)
+			}
+			continue
+		}
+
+		before := cumulative / total * 100
+		cumulative += row.Revenue
+		row.RevenueShare = roundMoney(row.Revenue / total * 100)
+		row.CumulativeShare = roundMoney(cumulative / total * 100)
+
+		switch {
+		case before < aThreshold:
+			row.ABCClass = domain.ClassA
+		case before < bThreshold:
+			row.ABCClass = domain.ClassB
+		default:
+			row.ABCClass = domain.ClassC
+		}
+	}
+}
+
+// classifyXYZ assigns an XYZ class from the mean and coefficient of
+// variation of a product's demand. Products with no demand are class Z.
+func classifyXYZ(mean, cv, xThreshold, yThreshold float64) string {
+	switch {
+	case mean == 0:
+		return domain.ClassZ
+	case cv <= xThreshold:
+		return domain.ClassX
+	case cv <= yThreshold:
+		return domain.ClassY
+	default:
+		return domain.ClassZ
+	}
+}
+
+// demandVariation returns the mean of a demand series and its coefficient of
+// variation, the population standard deviation divided by the mean.
+func demandVariation(demand []float64) (float64, float64) {
+	if len(demand) == 0 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, d := range demand {
+		sum += d
+	}
+	mean := sum / float64(len(demand))
+	if mean == 0 {
+		return 0, 0
+	}
+
+	var variance float64
+	for _, d := range demand {
+		variance += (d - mean) * (d - mean)
+	}
+	variance /= float64(len(demand))
+
+	return roundQuantity(mean, 3), roundQuantity(math.Sqrt(variance)/mean, 3)
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// ErrInvalidCostingMethod is returned for an unknown costing method.
+var ErrInvalidCostingMethod = errors.New("invalid costing method")
+
+// ErrInvalidUnitCost is returned when received stock has a negative cost.
+var ErrInvalidUnitCost = errors.New("invalid unit cost")
+
+// quantityEpsilon absorbs float error when comparing stock quantities.
+const quantityEpsilon = 1e-9
+
+// ParseCostingMethod validates a costing method name. An empty name selects
+// weighted average.
+func ParseCostingMethod(method string) (string, error) {
+	switch method {
+	case "":
+		return domain.CostingWeightedAverage, nil
+	case domain.CostingWeightedAverage, domain.CostingFIFO, domain.CostingLastCost:
+		return method, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidCostingMethod, method)
+	}
+}
+
+// openCostLayers returns a product's open cost layers, oldest first, after
+// reconciling them with the stock on hand. Stock without a layer, such as an
+// opening balance or a manual correction, gets a layer at the product's
+// current cost; layers beyond the stock on hand are consumed oldest first.
+func openCostLayers(ctx context.Context, repo ports.CostLayerRepository, product *domain.Product, now time.Time) ([]*domain.CostLayer, error) {
+	layers, err := repo.ListOpen(ctx, product.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cost layers for product %s: %w", product.ID, err)
+	}
+
+	var layered float64
+	for _, layer := range layers {
+		layered += layer.QuantityRemaining
+	}
+	onHand := product.Quantity + product.QuarantinedQuantity
+
+	switch {
+	case onHand-layered > quantityEpsilon:
+		missing := roundQuantity(onHand-layered, product.QuantityPrecision)
+		receivedAt := product.CreatedAt
+		if receivedAt.IsZero() {
+			receivedAt = now
+		}
+		opening := &domain.CostLayer{
+			ID:                uuid.New().String(),
+			ProductID:         product.ID,
+			QuantityReceived:  missing,
+			QuantityRemaining: missing,
+			UnitCost:          product.CostPrice,
+			ReceivedAt:        receivedAt,
+		}
+		if err := repo.Create(ctx, opening); err != nil {
+			return nil, fmt.Errorf("create opening cost layer for product %s: %w", product.ID, err)
+		}
+		layers = append([]*domain.CostLayer{opening}, layers...)
+	case layered-onHand > quantityEpsilon:
+		if _, layers, err = consumeCostLayers(ctx, repo, layers, layered-onHand); err != nil {
+			return nil, err
+		}
+	}
+
+	return layers, nil
+}
+
+// consumeCostLayers removes a quantity from layers oldest first and returns
+// the cost of the units removed and the layers left open. Stock beyond the
+// layers is costed at the last layer's cost.
+func consumeCostLayers(ctx context.Context, repo ports.CostLayerRepository, layers []*domain.CostLayer, quantity float64) (float64, []*domain.CostLayer, error) {
+	var cost float64
+	remaining := quantity
+
+	for len(layers) > 0 && remaining > quantityEpsilon {
+		layer := layers[0]
+		take := layer.QuantityRemaining
+		if take > remaining {
+			take = remaining
+		}
+
+		layer.QuantityRemaining -= take
+		if layer.QuantityRemaining < quantityEpsilon {
+			layer.QuantityRemaining = 0
+		}
+		if err := repo.UpdateRemaining(ctx, layer.ID, layer.QuantityRemaining); err != nil {
+			return 0, nil, fmt.Errorf("update cost layer %s: %w", layer.ID, err)
+		}
+
+		cost += take * layer.UnitCost
+		remaining -= take
+		if layer.QuantityRemaining == 0 {
+			layers = layers[1:]
+		}
+
+		if remaining > quantityEpsilon && len(layers) == 0 {
+			cost += remaining * layer.UnitCost
+			remaining = 0
+		}
+	}
+
+	return cost, layers, nil
+}
+
+// layerUnitCost returns the average unit cost of the stock in open layers,
+// or false when no stock is layered.
+func layerUnitCost(layers []*domain.CostLayer) (float64, bool) {
+	var quantity, value float64
+	for _, layer := range layers {
+		quantity += layer.QuantityRemaining
+		value += layer.QuantityRemaining * layer.UnitCost
+	}
+	if quantity <= quantityEpsilon {
+		return 0, false
+	}
+	return value / quantity, true
+}
+
+// receiptUnitCost returns a product's carrying cost after receiving units at
+// unitCost, under the given costing method. layers are the open layers
+// including the new receipt.
+func receiptUnitCost(method string, product *domain.Product, layers []*domain.CostLayer, units, unitCost float64) float64 {
+	switch method {
+	case domain.CostingLastCost:
+		return roundCost(unitCost)
+	case domain.CostingFIFO:
+		if cost, ok := layerUnitCost(layers); ok {
+			return roundCost(cost)
+		}
+		return roundCost(unitCost)
+	default:
+		onHand := product.Quantity + product.QuarantinedQuantity
+		if onHand <= 0 {
+			return roundCost(unitCost)
+		}
+		return roundCost((onHand*product.CostPrice + units*unitCost) / (onHand + units))
+	}
+}
+
+// roundCost rounds a unit cost to four decimal places, enough to carry
+// averages without drifting.
+func roundCost(cost float64) float64 {
+	return roundQuantity(cost, 4)
+}
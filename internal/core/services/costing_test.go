@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// --- Mock CostLayerRepository ---
+
+type mockCostLayerRepository struct {
+	layers []*domain.CostLayer
+}
+
+func (m *mockCostLayerRepository) Create(_ context.Context, layer *domain.CostLayer) error {
+	m.layers = append(m.layers, layer)
+	return nil
+}
+func (m *mockCostLayerRepository) ListOpen(_ context.Context, productID string) ([]*domain.CostLayer, error) {
+	var result []*domain.CostLayer
+	for _, l := range m.layers {
+		if l.ProductID == productID && l.QuantityRemaining > 0 {
+			result = append(result, l)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].ReceivedAt.Before(result[j].ReceivedAt) })
+	return result, nil
+}
+func (m *mockCostLayerRepository) UpdateRemaining(_ context.Context, id string, remaining float64) error {
+	for _, l := range m.layers {
+		if l.ID == id {
+			l.QuantityRemaining = remaining
+			return nil
+		}
+	}
+	return errors.New("cost layer not found")
+}
+func (m *mockCostLayerRepository) DeleteByProduct(_ context.Context, productID string) error {
+	var kept []*domain.CostLayer
+	for _, l := range m.layers {
+		if l.ProductID != productID {
+			kept = append(kept, l)
+		}
+	}
+	m.layers = kept
+	return nil
+}
+
+func newCostingTestProduct() *domain.Product {
+	return &domain.Product{
+		ID: "p1", Name: "Coffee", SKU: "COF", BasePrice: 8, CostPrice: 2, Quantity: 10,
+		UnitOfMeasure: domain.UnitEach, CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func receiveAt(t *testing.T, svc *InventoryService, quantity, unitCost float64) *domain.Product {
+	t.Helper()
+	product, err := svc.ReceiveStock(context.Background(), ports.ReceiveStockRequest{
+		ProductID: "p1",
+		Quantity:  quantity,
+		UnitCost:  &unitCost,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return product
+}
+
+// --- Costing Tests ---
+
+func TestParseCostingMethod(t *testing.T) {
+	if method, err := ParseCostingMethod(""); err != nil || method != domain.CostingWeightedAverage {
+		t.Errorf("expected weighted average by default, got %q, %v", method, err)
+	}
+	if method, err := ParseCostingMethod("fifo"); err != nil || method != domain.CostingFIFO {
+		t.Errorf("expected fifo, got %q, %v", method, err)
+	}
+	if _, err := ParseCostingMethod("lifo"); !errors.Is(err, ErrInvalidCostingMethod) {
+		t.Errorf("expected ErrInvalidCostingMethod, got %v", err)
+	}
+}
+
+func TestReceiveStock_CostingMethods(t *testing.T) {
+	tests := []struct {
+		method string
+		cost   float64
+	}{
+		{domain.CostingWeightedAverage, 3.5}, // (10*2 + 30*4) / 40
+		{domain.CostingFIFO, 3.5},            // same layers, none consumed yet
+		{domain.CostingLastCost, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			txManager := newPackTestTxManager([]*domain.Product{newCostingTestProduct()}, nil)
+			svc := NewInventoryService(txManager.packRepo, txManager.productRepo, txManager)
+			svc.SetCostingMethod(tt.method)
+
+			product := receiveAt(t, svc, 30, 4)
+
+			if product.Quantity != 40 || product.CostPrice != tt.cost {
+				t.Errorf("expected 40 at %.2f, got %v at %.4f", tt.cost, product.Quantity, product.CostPrice)
+			}
+			// Opening stock gets a layer at the old cost ahead of the delivery
+			layers := txManager.costLayerRepo.layers
+			if len(layers) != 2 || layers[0].UnitCost != 2 || layers[0].QuantityRemaining != 10 || layers[1].UnitCost != 4 {
+				t.Errorf("unexpected layers %+v, %+v", layers[0], layers[len(layers)-1])
+			}
+		})
+	}
+}
+
+func TestReceiveStock_PackCost(t *testing.T) {
+	txManager := newPackTestTxManager([]*domain.Product{newCostingTestProduct()}, []*domain.ProductPack{
+		{ID: "case", ProductID: "p1", Name: "Case", UnitsPerPack: 10},
+	})
+	svc := NewInventoryService(txManager.packRepo, txManager.productRepo, txManager)
+	svc.SetCostingMethod(domain.CostingLastCost)
+
+	caseCost := 25.0
+	product, err := svc.ReceiveStock(context.Background(), ports.ReceiveStockRequest{
+		ProductID: "p1", PackID: "case", Quantity: 2, UnitCost: &caseCost,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if product.Quantity != 30 || product.CostPrice != 2.5 {
+		t.Errorf("expected 30 units at 2.50, got %v at %.4f", product.Quantity, product.CostPrice)
+	}
+
+	negative := -1.0
+	_, err = svc.ReceiveStock(context.Background(), ports.ReceiveStockRequest{ProductID: "p1", Quantity: 1, UnitCost: &negative})
+	if !errors.Is(err, ErrInvalidUnitCost) {
+		t.Errorf("expected ErrInvalidUnitCost, got %v", err)
+	}
+}
+
+func TestProcessSale_FIFOCostsOldestLayers(t *testing.T) {
+	txManager := newPackTestTxManager([]*domain.Product{newCostingTestProduct()}, nil)
+	inventorySvc := NewInventoryService(txManager.packRepo, txManager.productRepo, txManager)
+	inventorySvc.SetCostingMethod(domain.CostingFIFO)
+	receiveAt(t, inventorySvc, 10, 4)
+
+	saleSvc := NewSaleService(txManager)
+	saleSvc.SetCostingMethod(domain.CostingFIFO)
+	_, err := saleSvc.ProcessSale(context.Background(), ports.SaleRequest{
+		Items: []ports.SaleItemRequest{{ProductID: "p1", Quantity: 15}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 10 opening units at 2.00 and 5 received at 4.00
+	item := txManager.saleRepo.saleItems[0]
+	if math.Abs(item.CostPrice-2.6667) > 1e-9 {
+		t.Errorf("expected line cost 2.6667, got %.4f", item.CostPrice)
+	}
+	product := txManager.productRepo.products[0]
+	if product.Quantity != 5 || product.CostPrice != 4 {
+		t.Errorf("expected 5 left at 4.00, got %v at %.4f", product.Quantity, product.CostPrice)
+	}
+}
+
+func TestProcessSale_AverageCostKeepsCostPrice(t *testing.T) {
+	txManager := newPackTestTxManager([]*domain.Product{newCostingTestProduct()}, nil)
+	inventorySvc := NewInventoryService(txManager.packRepo, txManager.productRepo, txManager)
+	receiveAt(t, inventorySvc, 10, 4)
+
+	_, err := NewSaleService(txManager).ProcessSale(context.Background(), ports.SaleRequest{
+		Items: []ports.SaleItemRequest{{ProductID: "p1", Quantity: 15}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if item := txManager.saleRepo.saleItems[0]; item.CostPrice != 3 {
+		t.Errorf("expected line cost 3.00, got %.4f", item.CostPrice)
+	}
+	if product := txManager.productRepo.products[0]; product.CostPrice != 3 {
+		t.Errorf("expected cost price to stay 3.00, got %.4f", product.CostPrice)
+	}
+	if open, _ := txManager.costLayerRepo.ListOpen(context.Background(), "p1"); len(open) != 1 || open[0].QuantityRemaining != 5 {
+		t.Errorf("expected 5 units left in the newest layer, got %+v", open)
+	}
+}
+
+func TestOpenCostLayers_ConsumesExcessLayers(t *testing.T) {
+	product := newCostingTestProduct()
+	product.Quantity = 4
+	repo := &mockCostLayerRepository{layers: []*domain.CostLayer{
+		{ID: "l1", ProductID: "p1", QuantityReceived: 5, QuantityRemaining: 5, UnitCost: 1, ReceivedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{ID: "l2", ProductID: "p1", QuantityReceived: 5, QuantityRemaining: 5, UnitCost: 3, ReceivedAt: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)},
+	}}
+
+	layers, err := openCostLayers(context.Background(), repo, product, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(layers) != 1 || layers[0].ID != "l2" || layers[0].QuantityRemaining != 4 {
+		t.Errorf("expected 4 units left in l2, got %+v", layers)
+	}
+}
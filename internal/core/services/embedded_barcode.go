@@ -0,0 +1,92 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+// ErrInvalidBarcode is returned when a barcode is malformed or fails its check digit.
+var ErrInvalidBarcode = errors.New("invalid barcode")
+
+// DefaultEmbeddedBarcodeRules are the in-store EAN-13 layouts recognised at
+// checkout: prefixes 21-22 carry a weight in grams, 23-24 a price in cents.
+var DefaultEmbeddedBarcodeRules = []domain.EmbeddedBarcodeRule{
+	{Prefix: "21", Kind: domain.EmbeddedWeight, ItemCodeLength: 5, ValueDecimals: 3},
+	{Prefix: "22", Kind: domain.EmbeddedWeight, ItemCodeLength: 5, ValueDecimals: 3},
+	{Prefix: "23", Kind: domain.EmbeddedPrice, ItemCodeLength: 5, ValueDecimals: 2},
+	{Prefix: "24", Kind: domain.EmbeddedPrice, ItemCodeLength: 5, ValueDecimals: 2},
+}
+
+// ParseEmbeddedBarcode decodes an EAN-13 barcode against the given layouts.
+// It returns nil without error when the code does not match any layout.
+func ParseEmbeddedBarcode(code string, rules []domain.EmbeddedBarcodeRule) (*domain.EmbeddedBarcode, error) {
+	if len(code) != 13 || !isDigits(code) {
+		return nil, nil
+	}
+
+	for _, rule := range rules {
+		if !strings.HasPrefix(code, rule.Prefix) {
+			continue
+		}
+
+		valueStart := len(rule.Prefix) + rule.ItemCodeLength
+		if rule.ItemCodeLength <= 0 || valueStart >= len(code)-1 {
+			return nil, fmt.Errorf("%w: embedded layout %q does not fit EAN-13", ErrInvalidBarcode, rule.Prefix)
+		}
+		if !validCheckDigit(code) {
+			return nil, fmt.Errorf("%w: check digit mismatch in %s", ErrInvalidBarcode, code)
+		}
+
+		raw, err := strconv.Atoi(code[valueStart : len(code)-1])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidBarcode, code)
+		}
+
+		return &domain.EmbeddedBarcode{
+			ItemCode: code[len(rule.Prefix):valueStart],
+			Kind:     rule.Kind,
+			Value:    float64(raw) / math.Pow10(rule.ValueDecimals),
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// validCheckDigit verifies the GS1 mod-10 check digit of a numeric code.
+func validCheckDigit(code string) bool {
+	if len(code) < 2 || !isDigits(code) {
+		return false
+	}
+
+	sum := 0
+	body := code[:len(code)-1]
+	for i := len(body) - 1; i >= 0; i-- {
+		digit := int(body[i] - '0')
+		// Weights alternate 3,1,3,... starting from the digit next to the check digit
+		if (len(body)-1-i)%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+
+	check := (10 - sum%10) % 10
+	return check == int(code[len(code)-1]-'0')
+}
+
+// isDigits reports whether s is non-empty and contains only ASCII digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// ErrInvalidForecast is returned when a forecast's horizon, history or model
+// is not usable.
+var ErrInvalidForecast = errors.New("invalid forecast")
+
+// Demand forecasting settings.
+const (
+	defaultForecastDays    = 14
+	maxForecastDays        = 90
+	defaultForecastHistory = 90
+	maxForecastHistory     = 730
+	forecastSeasonLength   = 7    // Days in the weekly sales pattern
+	forecastHoldoutDays    = 7    // Most recent days held back to measure accuracy
+	forecastLevelWeight    = 0.3  // Smoothing weight of the newest day in the level
+	forecastSeasonWeight   = 0.1  // Smoothing weight of the newest day in its weekday effect
+	forecastBandWidth      = 1.96 // Residual standard errors either side of a forecast (95%)
+	forecastUnitPrecision  = 3
+)
+
+// forecastModel fits a model to a daily unit series and returns the next
+// horizon days' forecasts with the model's one-step-ahead residuals over the
+// history. ok is false when the series is too short for the model.
+type forecastModel func(series []float64, horizon int) (forecast, residuals []float64, ok bool)
+
+// forecastModels lists the available models in order of preference when
+// they are equally accurate.
+var forecastModels = []struct {
+	name string
+	fit  forecastModel
+}{
+	{domain.ForecastMovingAverage, movingAverageForecast},
+	{domain.ForecastSeasonal, seasonalForecast},
+}
+
+// ForecastService implements demand forecasting from past daily unit sales.
+type ForecastService struct {
+	analyticsRepo ports.AnalyticsRepository
+	productRepo   ports.ProductRepository
+	now           func() time.Time
+}
+
+// NewForecastService creates a new forecasting service instance.
+func NewForecastService(analyticsRepo ports.AnalyticsRepository, productRepo ports.ProductRepository) *ForecastService {
+	return &ForecastService{
+		analyticsRepo: analyticsRepo,
+		productRepo:   productRepo,
+		now:           time.Now,
+	}
+}
+
+// ForecastProduct forecasts a product's daily unit sales from today for the
+// query's number of days. Models are fitted to the complete days of sales
+// before today, going back no further than the product's creation. Each
+// model's accuracy is measured by refitting without the last week and
+// comparing its forecasts with what actually sold; the auto model picks the
+// most accurate one.
+func (s *ForecastService) ForecastProduct(ctx context.Context, query ports.ForecastQuery) (*domain.DemandForecast, error) {
+	query, err := validateForecastQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.productRepo.GetByID(ctx, query.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	today := periodStart(now, domain.GroupByDay)
+	start := today.AddDate(0, 0, -query.HistoryDays)
+	if created := periodStart(product.CreatedAt, domain.GroupByDay); !product.CreatedAt.IsZero() && created.After(start) {
+		start = created
+	}
+	if start.After(today) {
+		start = today
+	}
+
+	lines, err := s.analyticsRepo.ListProductSales(ctx, product.ID, start, today.Add(-time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sales: %w", err)
+	}
+	series := dailyUnits(lines, start, today)
+
+	accuracy := make([]domain.ForecastAccuracy, 0, len(forecastModels))
+	model := query.Model
+	var bestMAE float64
+	for _, m := range forecastModels {
+		acc, ok := backtestForecast(m.fit, series)
+		if !ok {
+			continue
+		}
+		acc.Model = m.name
+		accuracy = append(accuracy, acc)
+		if query.Model == domain.ForecastAuto && (model == domain.ForecastAuto || acc.MAE < bestMAE) {
+			model, bestMAE = m.name, acc.MAE
+		}
+	}
+	if model == domain.ForecastAuto {
+		model = domain.ForecastMovingAverage
+	}
+
+	var fit forecastModel
+	for _, m := range forecastModels {
+		if m.name == model {
+			fit = m.fit
+		}
+	}
+	forecast, residuals, ok := fit(series, query.Days)
+	if !ok {
+		return nil, fmt.Errorf("%w: the %s model needs at least %d days of sales history, have %d",
+			ErrInvalidForecast, model, 2*forecastSeasonLength, len(series))
+	}
+
+	band := forecastBandWidth * rootMeanSquare(residuals)
+	points := make([]domain.ForecastPoint, 0, len(forecast))
+	for i, units := range forecast {
+		units = math.Max(units, 0)
+		points = append(points, domain.ForecastPoint{
+			Date:  today.AddDate(0, 0, i),
+			Units: roundQuantity(units, forecastUnitPrecision),
+			Lower: roundQuantity(math.Max(units-band, 0), forecastUnitPrecision),
+			Upper: roundQuantity(units+band, forecastUnitPrecision),
+		})
+	}
+
+	return &domain.DemandForecast{
+		ProductID:   product.ID,
+		Model:       model,
+		HistoryDays: len(series),
+		GeneratedAt: now,
+		Points:      points,
+		Accuracy:    accuracy,
+	}, nil
+}
+
+// validateForecastQuery checks a forecast query and fills in its defaults.
+func validateForecastQuery(query ports.ForecastQuery) (ports.ForecastQuery, error) {
+	if query.Days == 0 {
+		query.Days = defaultForecastDays
+	}
+	if query.HistoryDays == 0 {
+		query.HistoryDays = defaultForecastHistory
+	}
+	if query.Model == "" {
+		query.Model = domain.ForecastAuto
+	}
+
+	if query.Days < 1 || query.Days > maxForecastDays {
+		return query, fmt.Errorf("%w: days must be between 1 and %d", ErrInvalidForecast, maxForecastDays)
+	}
+	if query.HistoryDays < 1 || query.HistoryDays > maxForecastHistory {
+		return query, fmt.Errorf("%w: history must be between 1 and %d days", ErrInvalidForecast, maxForecastHistory)
+	}
+	switch query.Model {
+	case domain.ForecastAuto, domain.ForecastMovingAverage, domain.ForecastSeasonal:
+	default:
+		return query, fmt.Errorf("%w: unknown model %q", ErrInvalidForecast, query.Model)
+	}
+	return query, nil
+}
+
+// dailyUnits totals sale line quantities into one value per local day from
+// start up to, but not including, end. Days without sales are zero.
+func dailyUnits(lines []*domain.SaleLine, start, end time.Time) []float64 {
+	index := make(map[time.Time]int)
+	for day := start; day.Before(end); day = nextPeriod(day, domain.GroupByDay) {
+		index[day] = len(index)
+	}
+
+	series := make([]float64, len(index))
+	for _, line := range lines {
+		if i, ok := index[periodStart(line.SoldAt, domain.GroupByDay)]; ok {
+			series[i] += line.Quantity
+		}
+	}
+	return series
+}
+
+// movingAverageForecast forecasts every day as the average of the last week
+// of sales, or of the whole history when it is shorter.
+func movingAverageForecast(series []float64, horizon int) ([]float64, []float64, bool) {
+	var residuals []float64
+	for i := 1; i < len(series); i++ {
+		residuals = append(residuals, series[i]-trailingMean(series[:i]))
+	}
+
+	average := trailingMean(series)
+	forecast := make([]float64, horizon)
+	for i := range forecast {
+		forecast[i] = average
+	}
+	return forecast, residuals, true
+}
+
+// trailingMean returns the mean of the last season's values in series.
+func trailingMean(series []float64) float64 {
+	if len(series) > forecastSeasonLength {
+		series = series[len(series)-forecastSeasonLength:]
+	}
+	if len(series) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range series {
+		sum += v
+	}
+	return sum / float64(len(series))
+}
+
+// seasonalForecast fits exponential smoothing with an additive weekday
+// effect: each day is forecast as the smoothed level plus how far that
+// weekday usually sits above or below it. It needs two full weeks of
+// history, one to seed the weekday effects and one to fit.
+func seasonalForecast(series []float64, horizon int) ([]float64, []float64, bool) {
+	if len(series) < 2*forecastSeasonLength {
+		return nil, nil, false
+	}
+
+	level := trailingMean(series[:forecastSeasonLength])
+	season := make([]float64, forecastSeasonLength)
+	for i := range season {
+		season[i] = series[i] - level
+	}
+
+	var residuals []float64
+	for t := forecastSeasonLength; t < len(series); t++ {
+		s := t % forecastSeasonLength
+		residuals = append(residuals, series[t]-(level+season[s]))
+
+		newLevel := forecastLevelWeight*(series[t]-season[s]) + (1-forecastLevelWeight)*level
+		season[s] = forecastSeasonWeight*(series[t]-newLevel) + (1-forecastSeasonWeight)*season[s]
+		level = newLevel
+	}
+
+	forecast := make([]float64, horizon)
+	for i := range forecast {
+		forecast[i] = level + season[(len(series)+i)%forecastSeasonLength]
+	}
+	return forecast, residuals, true
+}
+
+// backtestForecast fits a model to all but the last week of the series and
+// measures its forecasts against that week's actual sales. ok is false when
+// the remaining history is too short for the model.
+func backtestForecast(fit forecastModel, series []float64) (domain.ForecastAccuracy, bool) {
+	acc := domain.ForecastAccuracy{HoldoutDays: forecastHoldoutDays}
+	training := len(series) - forecastHoldoutDays
+	if training < forecastSeasonLength {
+		return acc, false
+	}
+
+	forecast, _, ok := fit(series[:training], forecastHoldoutDays)
+	if !ok {
+		return acc, false
+	}
+
+	var absError, pctError float64
+	var sold int
+	for i, predicted := range forecast {
+		actual := series[training+i]
+		diff := math.Abs(actual - math.Max(predicted, 0))
+		absError += diff
+		if actual > 0 {
+			pctError += diff / actual * 100
+			sold++
+		}
+	}
+
+	acc.MAE = roundQuantity(absError/forecastHoldoutDays, forecastUnitPrecision)
+	if sold > 0 {
+		mape := roundMoney(pctError / float64(sold))
+		acc.MAPE = &mape
+	}
+	return acc, true
+}
+
+// rootMeanSquare returns the root mean square of values, or zero if empty.
+func rootMeanSquare(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v * v
+	}
+	return math.Sqrt(sum / float64(len(values)))
+}
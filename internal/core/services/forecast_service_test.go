@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// newForecastTestService returns a forecast service for product p1, created
+// 1 January 2024, with one sale line per day of units from 1 January, and
+// a clock on the day after the last sale.
+func newForecastTestService(units []float64) *ForecastService {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	var lines []*domain.SaleLine
+	for i, u := range units {
+		if u == 0 {
+			continue
+		}
+		lines = append(lines, &domain.SaleLine{
+			SaleID:    "s",
+			SoldAt:    start.AddDate(0, 0, i).Add(10 * time.Hour),
+			ProductID: "p1",
+			Quantity:  u,
+		})
+	}
+
+	svc := NewForecastService(&mockAnalyticsRepository{lines: lines}, &mockProductRepository{products: []*domain.Product{
+		{ID: "p1", Name: "Cola", CreatedAt: start},
+	}})
+	svc.now = func() time.Time { return start.AddDate(0, 0, len(units)).Add(9 * time.Hour) }
+	return svc
+}
+
+// weeklyUnits repeats a week's unit sales for the given number of weeks.
+func weeklyUnits(week []float64, weeks int) []float64 {
+	var units []float64
+	for i := 0; i < weeks; i++ {
+		units = append(units, week...)
+	}
+	return units
+}
+
+func TestForecastProduct_MovingAverage(t *testing.T) {
+	svc := newForecastTestService([]float64{4, 4, 4, 4, 4, 4, 4, 4, 4, 4})
+
+	forecast, err := svc.ForecastProduct(context.Background(), ports.ForecastQuery{ProductID: "p1", Days: 3, Model: domain.ForecastMovingAverage})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if forecast.Model != domain.ForecastMovingAverage || forecast.HistoryDays != 10 {
+		t.Errorf("expected moving average over 10 days, got %s over %d", forecast.Model, forecast.HistoryDays)
+	}
+	if len(forecast.Points) != 3 {
+		t.Fatalf("expected 3 points, got %d", len(forecast.Points))
+	}
+	first := forecast.Points[0]
+	if !first.Date.Equal(time.Date(2024, 1, 11, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("expected forecast to start today, got %v", first.Date)
+	}
+	if first.Units != 4 || first.Lower != 4 || first.Upper != 4 {
+		t.Errorf("expected a flat 4 units with no band, got %+v", first)
+	}
+}
+
+func TestForecastProduct_SeasonalFollowsWeeklyPattern(t *testing.T) {
+	// Quiet weekdays, busy weekends: 1 January 2024 is a Monday
+	svc := newForecastTestService(weeklyUnits([]float64{2, 2, 2, 2, 2, 10, 10}, 4))
+
+	forecast, err := svc.ForecastProduct(context.Background(), ports.ForecastQuery{ProductID: "p1", Days: 7})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if forecast.Model != domain.ForecastSeasonal {
+		t.Errorf("expected auto to pick the seasonal model, got %s", forecast.Model)
+	}
+	for i, p := range forecast.Points {
+		want := 2.0
+		if p.Date.Weekday() == time.Saturday || p.Date.Weekday() == time.Sunday {
+			want = 10
+		}
+		if math.Abs(p.Units-want) > 0.01 {
+			t.Errorf("point %d (%s): expected %v units, got %v", i, p.Date.Weekday(), want, p.Units)
+		}
+	}
+
+	if len(forecast.Accuracy) != 2 {
+		t.Fatalf("expected accuracy for both models, got %d", len(forecast.Accuracy))
+	}
+	for _, acc := range forecast.Accuracy {
+		if acc.HoldoutDays != 7 || acc.MAPE == nil {
+			t.Fatalf("expected a 7-day holdout with MAPE, got %+v", acc)
+		}
+		switch acc.Model {
+		case domain.ForecastSeasonal:
+			if acc.MAE > 0.01 || *acc.MAPE > 1 {
+				t.Errorf("expected the seasonal model to fit the pattern, got %+v", acc)
+			}
+		case domain.ForecastMovingAverage:
+			// Average of 30/7 against weekdays of 2 and weekends of 10
+			if math.Abs(acc.MAE-3.265) > 0.001 {
+				t.Errorf("expected moving average MAE 3.265, got %v", acc.MAE)
+			}
+		}
+	}
+}
+
+func TestForecastProduct_BandsAndFloor(t *testing.T) {
+	svc := newForecastTestService([]float64{0, 6, 0, 6, 0, 6, 0, 6, 0, 6, 0, 6, 0, 6})
+
+	forecast, err := svc.ForecastProduct(context.Background(), ports.ForecastQuery{ProductID: "p1", Days: 1, Model: domain.ForecastMovingAverage})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	p := forecast.Points[0]
+	if p.Lower < 0 || p.Lower > p.Units || p.Upper <= p.Units {
+		t.Errorf("expected a band around the forecast floored at zero, got %+v", p)
+	}
+	if p.Lower != 0 {
+		t.Errorf("expected the noisy series' band to reach zero, got %v", p.Lower)
+	}
+}
+
+func TestForecastProduct_ExcludesToday(t *testing.T) {
+	svc := newForecastTestService([]float64{1, 1, 1, 1, 1, 1, 1})
+	repo := svc.analyticsRepo.(*mockAnalyticsRepository)
+	repo.lines = append(repo.lines, &domain.SaleLine{SaleID: "today", SoldAt: svc.now().Add(-time.Hour), ProductID: "p1", Quantity: 50})
+
+	forecast, err := svc.ForecastProduct(context.Background(), ports.ForecastQuery{ProductID: "p1", Days: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if forecast.Points[0].Units != 1 {
+		t.Errorf("expected today's partial sales to be ignored, got %v", forecast.Points[0].Units)
+	}
+	if len(forecast.Accuracy) != 0 {
+		t.Errorf("expected no backtest with a week of history, got %+v", forecast.Accuracy)
+	}
+}
+
+func TestForecastProduct_NoSalesMAPE(t *testing.T) {
+	svc := newForecastTestService(make([]float64, 21))
+
+	forecast, err := svc.ForecastProduct(context.Background(), ports.ForecastQuery{ProductID: "p1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(forecast.Points) != 14 {
+		t.Errorf("expected the default 14 days, got %d", len(forecast.Points))
+	}
+	for _, acc := range forecast.Accuracy {
+		if acc.MAPE != nil || acc.MAE != 0 {
+			t.Errorf("expected no MAPE without sales, got %+v", acc)
+		}
+	}
+}
+
+func TestForecastProduct_Invalid(t *testing.T) {
+	svc := newForecastTestService([]float64{1, 2, 3})
+
+	queries := []ports.ForecastQuery{
+		{ProductID: "p1", Days: 91},
+		{ProductID: "p1", Days: -1},
+		{ProductID: "p1", HistoryDays: 1000},
+		{ProductID: "p1", Model: "arima"},
+		{ProductID: "p1", Model: domain.ForecastSeasonal}, // needs two weeks of history
+	}
+	for _, q := range queries {
+		if _, err := svc.ForecastProduct(context.Background(), q); !errors.Is(err, ErrInvalidForecast) {
+			t.Errorf("query %+v: expected ErrInvalidForecast, got %v", q, err)
+		}
+	}
+
+	if _, err := svc.ForecastProduct(context.Background(), ports.ForecastQuery{ProductID: "missing"}); err == nil {
+		t.Error("expected error for unknown product")
+	}
+}
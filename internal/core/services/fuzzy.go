@@ -0,0 +1,83 @@
+package services
+
+import "strings"
+
+// fuzzyThreshold is how alike, from 0 to 1, a product's name or SKU must be
+// to a search to match it as a misspelling.
+const fuzzyThreshold = 0.4
+
+// fuzzyCandidateLimit caps the products scored for a misspelt search.
+const fuzzyCandidateLimit = 200
+
+// fuzzyMatchLimit caps the misspelt matches added to a search.
+const fuzzyMatchLimit = 50
+
+// trigramMatch builds an FTS5 expression matching any three-character run
+// of the words, or returns an empty string if every word is shorter.
+func trigramMatch(words []string) string {
+	seen := make(map[string]bool)
+	var parts []string
+	for _, word := range words {
+		runes := []rune(word)
+		for i := 0; i+3 <= len(runes); i++ {
+			trigram := string(runes[i : i+3])
+			if !seen[trigram] {
+				seen[trigram] = true
+				parts = append(parts, `"`+trigram+`"`)
+			}
+		}
+	}
+	return strings.Join(parts, " OR ")
+}
+
+// wordTrigrams returns the trigrams of a word padded as "  word ", so that
+// words sharing their first and last letters score higher.
+func wordTrigrams(word string) map[string]bool {
+	runes := []rune("  " + word + " ")
+	trigrams := make(map[string]bool, len(runes))
+	for i := 0; i+3 <= len(runes); i++ {
+		trigrams[string(runes[i:i+3])] = true
+	}
+	return trigrams
+}
+
+// trigramSimilarity returns the share of two words' trigrams they have in
+// common.
+func trigramSimilarity(a, b map[string]bool) float64 {
+	shared := 0
+	for trigram := range a {
+		if b[trigram] {
+			shared++
+		}
+	}
+	union := len(a) + len(b) - shared
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+// textSimilarity scores how alike text is to searched words: each word's
+// similarity to the word of the text most like it, averaged.
+func textSimilarity(words []string, text string) float64 {
+	if len(words) == 0 {
+		return 0
+	}
+	var targets []map[string]bool
+	for _, word := range searchWords(text) {
+		targets = append(targets, wordTrigrams(word))
+	}
+
+	var total float64
+	for _, word := range words {
+		trigrams := wordTrigrams(word)
+		best := 0.0
+		for _, target := range targets {
+			if similarity := trigramSimilarity(trigrams, target); similarity > best {
+				best = similarity
+			}
+		}
+		total += best
+	}
+	return total / float64(len(words))
+}
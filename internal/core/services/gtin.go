@@ -0,0 +1,38 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+// gtinFormats maps a code length to its GTIN format.
+var gtinFormats = map[int]string{
+	8:  domain.BarcodeEAN8,
+	12: domain.BarcodeUPCA,
+	13: domain.BarcodeEAN13,
+	14: domain.BarcodeGTIN14,
+}
+
+// ValidateGTIN checks the length and check digit of an EAN-8, UPC-A, EAN-13
+// or GTIN-14 code and returns its format.
+func ValidateGTIN(code string) (string, error) {
+	format, ok := gtinFormats[len(code)]
+	if !ok || !isDigits(code) {
+		return "", fmt.Errorf("%w: %q is not an 8, 12, 13 or 14 digit GTIN", ErrInvalidBarcode, code)
+	}
+	if !validCheckDigit(code) {
+		return "", fmt.Errorf("%w: check digit mismatch in %s", ErrInvalidBarcode, code)
+	}
+	return format, nil
+}
+
+// NormalizeGTIN zero-pads a GTIN to 14 digits. Padding leaves the check digit
+// valid, so a UPC-A scanned as a 13-digit EAN normalises to the same key.
+func NormalizeGTIN(code string) string {
+	if len(code) >= 14 {
+		return code
+	}
+	return strings.Repeat("0", 14-len(code)) + code
+}
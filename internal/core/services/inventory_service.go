@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// ErrInvalidPack is returned when a pack definition or pack quantity is invalid.
+var ErrInvalidPack = errors.New("invalid pack")
+
+// InventoryService implements pack definitions and stock receiving.
+type InventoryService struct {
+	packRepo      ports.PackRepository
+	productRepo   ports.ProductRepository
+	txManager     ports.TransactionManager
+	costingMethod string
+}
+
+// NewInventoryService creates a new inventory service instance.
+func NewInventoryService(packRepo ports.PackRepository, productRepo ports.ProductRepository, txManager ports.TransactionManager) *InventoryService {
+	return &InventoryService{
+		packRepo:      packRepo,
+		productRepo:   productRepo,
+		txManager:     txManager,
+		costingMethod: domain.CostingWeightedAverage,
+	}
+}
+
+// SetCostingMethod selects how received stock updates a product's cost price.
+func (s *InventoryService) SetCostingMethod(method string) {
+	s.costingMethod = method
+}
+
+// CreatePack validates and stores a pack definition for a product. A pack
+// barcode must be a valid GTIN not already used by a product or another pack.
+func (s *InventoryService) CreatePack(ctx context.Context, pack *domain.ProductPack) error {
+	if pack.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidPack)
+	}
+	if pack.Price < 0 {
+		return fmt.Errorf("%w: price must not be negative", ErrInvalidPack)
+	}
+	if pack.Barcode != "" {
+		if _, err := ValidateGTIN(pack.Barcode); err != nil {
+			return err
+		}
+	}
+
+	product, err := s.productRepo.GetByID(ctx, pack.ProductID)
+	if err != nil {
+		return fmt.Errorf("product %s: %w", pack.ProductID, err)
+	}
+	if err := checkQuantity(product, pack.UnitsPerPack); err != nil {
+		return fmt.Errorf("%w: units_per_pack: %v", ErrInvalidPack, err)
+	}
+
+	pack.ID = uuid.New().String()
+	pack.CreatedAt = time.Now()
+	if pack.Barcode != "" {
+		pack.GTIN = NormalizeGTIN(pack.Barcode)
+	}
+
+	if pack.Barcode == "" {
+		return s.packRepo.Create(ctx, pack)
+	}
+	return s.txManager.WithTx(ctx, func(tx ports.Ports) error {
+		if err := checkBarcodeAvailable(ctx, tx, pack.Barcode); err != nil {
+			return err
+		}
+		return tx.PackRepo.Create(ctx, pack)
+	})
+}
+
+// ListPacks retrieves every pack defined for a product.
+func (s *InventoryService) ListPacks(ctx context.Context, productID string) ([]*domain.ProductPack, error) {
+	return s.packRepo.ListByProduct(ctx, productID)
+}
+
+// DeletePack deletes one of a product's packs.
+func (s *InventoryService) DeletePack(ctx context.Context, productID, packID string) error {
+	pack, err := s.packRepo.GetByID(ctx, packID)
+	if err != nil {
+		return err
+	}
+	if pack.ProductID != productID {
+		return errors.New("pack not found")
+	}
+	return s.packRepo.Delete(ctx, packID)
+}
+
+// ReceiveStock atomically adds a delivery to a product's stock, converting
+// packs to the product's unit of measure, and records it in the audit chain.
+// The delivery opens a cost layer, and the product's cost price is updated
+// under the costing method. Deliveries of a product under an active recall
+// are quarantined with the rest of its stock.
+func (s *InventoryService) ReceiveStock(ctx context.Context, req ports.ReceiveStockRequest) (*domain.Product, error) {
+	var product *domain.Product
+
+	err := s.txManager.WithTx(ctx, func(tx ports.Ports) error {
+		var err error
+		product, err = tx.ProductRepo.GetByID(ctx, req.ProductID)
+		if err != nil {
+			return fmt.Errorf("product %s: %w", req.ProductID, err)
+		}
+
+		if req.UnitCost != nil && *req.UnitCost < 0 {
+			return fmt.Errorf("%w: %v", ErrInvalidUnitCost, *req.UnitCost)
+		}
+
+		units := req.Quantity
+		unitCost := product.CostPrice
+		if req.UnitCost != nil {
+			unitCost = *req.UnitCost
+		}
+		if req.PackID != "" {
+			pack, err := tx.PackRepo.GetByID(ctx, req.PackID)
+			if err != nil {
+				return fmt.Errorf("pack %s: %w", req.PackID, err)
+			}
+			if pack.ProductID != product.ID {
+				return fmt.Errorf("%w: pack %s belongs to another product", ErrInvalidPack, pack.ID)
+			}
+			if err := checkPackCount(pack, req.Quantity); err != nil {
+				return err
+			}
+			units = req.Quantity * pack.UnitsPerPack
+			if req.UnitCost != nil {
+				unitCost = *req.UnitCost / pack.UnitsPerPack
+			}
+		}
+		if err := checkQuantity(product, units); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		layers, err := openCostLayers(ctx, tx.CostLayerRepo, product, now)
+		if err != nil {
+			return err
+		}
+		layer := &domain.CostLayer{
+			ID:                uuid.New().String(),
+			ProductID:         product.ID,
+			QuantityReceived:  units,
+			QuantityRemaining: units,
+			UnitCost:          roundCost(unitCost),
+			ReceivedAt:        now,
+		}
+		if err := tx.CostLayerRepo.Create(ctx, layer); err != nil {
+			return fmt.Errorf("create cost layer for product %s: %w", product.ID, err)
+		}
+
+		recall, err := tx.RecallRepo.GetActiveByProduct(ctx, product.ID)
+		if err != nil {
+			return fmt.Errorf("active recall lookup: %w", err)
+		}
+
+		product.CostPrice = receiptUnitCost(s.costingMethod, product, append(layers, layer), units, unitCost)
+		if recall != nil {
+			// Held with the recall's stock, so closing it releases the delivery too
+			product.QuarantinedQuantity = roundQuantity(product.QuarantinedQuantity+units, product.QuantityPrecision)
+			recall.QuarantinedQuantity = roundQuantity(recall.QuarantinedQuantity+units, product.QuantityPrecision)
+			if err := tx.RecallRepo.Update(ctx, recall); err != nil {
+				return fmt.Errorf("quarantine delivery under recall %s: %w", recall.ID, err)
+			}
+		} else {
+			product.Quantity = roundQuantity(product.Quantity+units, product.QuantityPrecision)
+		}
+		if err := tx.ProductRepo.Update(ctx, product); err != nil {
+			return fmt.Errorf("update stock for product %s: %w", product.ID, err)
+		}
+
+		userID := req.UserID
+		if userID == "" {
+			userID = "system"
+		}
+		payload := map[string]interface{}{
+			"product_id":     product.ID,
+			"pack_id":        req.PackID,
+			"quantity":       req.Quantity,
+			"units_received": units,
+			"unit_cost":      layer.UnitCost,
+			"new_quantity":   product.Quantity,
+			"cost_price":     product.CostPrice,
+		}
+		if recall != nil {
+			payload["recall_id"] = recall.ID
+			payload["quarantined_quantity"] = product.QuarantinedQuantity
+		}
+		if err := newTxAuditService(ctx, tx.AuditRepo).LogAction(ctx, "STOCK_RECEIVED", userID, payload); err != nil {
+			return fmt.Errorf("audit log: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// checkPackCount validates that a number of packs is a positive whole number.
+func checkPackCount(pack *domain.ProductPack, packs float64) error {
+	if packs <= 0 || packs != math.Trunc(packs) {
+		return fmt.Errorf("%w: pack %s must be counted in whole packs, got %v", ErrInvalidPack, pack.ID, packs)
+	}
+	return nil
+}
+
+// packPrice returns the price of one whole pack.
+func packPrice(pack *domain.ProductPack, product *domain.Product) float64 {
+	if pack.Price > 0 {
+		return pack.Price
+	}
+	return pack.UnitsPerPack * product.BasePrice
+}
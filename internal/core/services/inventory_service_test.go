@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// --- Mock PackRepository ---
+
+type mockPackRepository struct {
+	packs []*domain.ProductPack
+}
+
+func (m *mockPackRepository) Create(_ context.Context, pack *domain.ProductPack) error {
+	m.packs = append(m.packs, pack)
+	return nil
+}
+func (m *mockPackRepository) GetByID(_ context.Context, id string) (*domain.ProductPack, error) {
+	for _, p := range m.packs {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return nil, errors.New("pack not found")
+}
+func (m *mockPackRepository) GetByGTIN(_ context.Context, gtin string) (*domain.ProductPack, error) {
+	for _, p := range m.packs {
+		if p.GTIN == gtin {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+func (m *mockPackRepository) ListByProduct(_ context.Context, productID string) ([]*domain.ProductPack, error) {
+	var out []*domain.ProductPack
+	for _, p := range m.packs {
+		if p.ProductID == productID {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+func (m *mockPackRepository) Delete(_ context.Context, id string) error {
+	for i, p := range m.packs {
+		if p.ID == id {
+			m.packs = append(m.packs[:i], m.packs[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("pack not found")
+}
+
+func newPackTestTxManager(products []*domain.Product, packs []*domain.ProductPack) *mockSaleTxManager {
+	return &mockSaleTxManager{
+		productRepo:  &mockProductRepository{products: products},
+		categoryRepo: &mockCategoryRepository{categories: make(map[string]*domain.Category)},
+		auditRepo:    &mockAuditLogRepository{},
+		saleRepo:     &mockSaleRepository{},
+		packRepo:     &mockPackRepository{packs: packs},
+	}
+}
+
+// --- InventoryService Tests ---
+
+func TestCreatePack_Validation(t *testing.T) {
+	txManager := newPackTestTxManager([]*domain.Product{
+		{ID: "p1", Name: "Beer", SKU: "BEER", BasePrice: 2},
+	}, nil)
+	svc := NewInventoryService(txManager.packRepo, txManager.productRepo, txManager)
+
+	err := svc.CreatePack(context.Background(), &domain.ProductPack{ProductID: "p1", Name: "Half case", UnitsPerPack: 12.5})
+	if !errors.Is(err, ErrInvalidPack) {
+		t.Fatalf("expected ErrInvalidPack for a fractional pack of whole units, got %v", err)
+	}
+
+	pack := &domain.ProductPack{ProductID: "p1", Name: "Case", Barcode: "5000000000012", UnitsPerPack: 24, Price: 40}
+	if err := svc.CreatePack(context.Background(), pack); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pack.ID == "" {
+		t.Fatal("expected pack ID to be assigned")
+	}
+}
+
+func TestReceiveStock_InPacks(t *testing.T) {
+	txManager := newPackTestTxManager([]*domain.Product{
+		{ID: "p1", Name: "Cable", SKU: "CABLE", BasePrice: 1.5, Quantity: 12.5, UnitOfMeasure: domain.UnitMetre, QuantityPrecision: 2},
+	}, []*domain.ProductPack{
+		{ID: "drum", ProductID: "p1", Name: "Drum", UnitsPerPack: 100},
+	})
+	svc := NewInventoryService(txManager.packRepo, txManager.productRepo, txManager)
+
+	product, err := svc.ReceiveStock(context.Background(), ports.ReceiveStockRequest{ProductID: "p1", PackID: "drum", Quantity: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if product.Quantity != 312.5 {
+		t.Fatalf("expected 312.5 m after receiving 3 drums, got %v", product.Quantity)
+	}
+
+	logs := txManager.auditRepo.logs
+	if len(logs) != 1 || logs[0].Action != "STOCK_RECEIVED" {
+		t.Fatalf("expected a STOCK_RECEIVED audit log, got %+v", logs)
+	}
+
+	_, err = svc.ReceiveStock(context.Background(), ports.ReceiveStockRequest{ProductID: "p1", PackID: "drum", Quantity: 1.5})
+	if !errors.Is(err, ErrInvalidPack) {
+		t.Fatalf("expected ErrInvalidPack for part of a pack, got %v", err)
+	}
+}
+
+func TestProcessSale_PackBarcodeUsesPackPrice(t *testing.T) {
+	txManager := newPackTestTxManager([]*domain.Product{
+		{ID: "p1", Name: "Beer", SKU: "BEER", BasePrice: 2, CostPrice: 1, Quantity: 60},
+	}, []*domain.ProductPack{
+		{ID: "case", ProductID: "p1", Name: "Case", Barcode: "5000000000012", GTIN: "05000000000012", UnitsPerPack: 24, Price: 36},
+	})
+	svc := NewSaleService(txManager)
+
+	sale, err := svc.ProcessSale(context.Background(), ports.SaleRequest{Items: []ports.SaleItemRequest{
+		{Barcode: "5000000000012"},
+		{ProductID: "p1", Quantity: 3},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// One case at 36.00 plus three singles at 2.00
+	if sale.TotalAmount != 42 {
+		t.Fatalf("expected total 42, got %v", sale.TotalAmount)
+	}
+	if got := txManager.productRepo.products[0].Quantity; got != 33 {
+		t.Fatalf("expected 33 singles left, got %v", got)
+	}
+
+	items := txManager.saleRepo.saleItems
+	if len(items) != 2 || items[0].PackID != "case" || items[0].Quantity != 24 || items[0].UnitPrice != 1.5 {
+		t.Fatalf("expected a case line of 24 units at 1.50, got %+v", items[0])
+	}
+}
+
+func TestProcessSale_PackWithoutPriceUsesUnitPrice(t *testing.T) {
+	txManager := newPackTestTxManager([]*domain.Product{
+		{ID: "p1", Name: "Beer", SKU: "BEER", BasePrice: 2, Quantity: 60},
+	}, []*domain.ProductPack{
+		{ID: "six", ProductID: "p1", Name: "Six pack", UnitsPerPack: 6},
+	})
+	svc := NewSaleService(txManager)
+
+	sale, err := svc.ProcessSale(context.Background(), ports.SaleRequest{Items: []ports.SaleItemRequest{
+		{PackID: "six", Quantity: 2},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sale.TotalAmount != 24 {
+		t.Fatalf("expected total 24, got %v", sale.TotalAmount)
+	}
+	if got := txManager.productRepo.products[0].Quantity; got != 48 {
+		t.Fatalf("expected 48 left, got %v", got)
+	}
+}
@@ -0,0 +1,374 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// ErrInvalidLabelRequest is returned when a label request names an unknown
+// format or template, or a custom template is malformed.
+var ErrInvalidLabelRequest = errors.New("invalid label request")
+
+// ErrNoLabelProducts is returned when a label request matches no products.
+var ErrNoLabelProducts = errors.New("no products to label")
+
+// maxLabelProducts caps the products printed in one request.
+const maxLabelProducts = 1000
+
+// maxLabelCopies caps the copies printed per product.
+const maxLabelCopies = 100
+
+// DefaultLabelTemplates are the built-in label layouts.
+var DefaultLabelTemplates = []domain.LabelTemplate{
+	{
+		Name:     "shelf",
+		WidthMM:  60,
+		HeightMM: 35,
+		Fields: []domain.LabelField{
+			{Source: domain.LabelSourceName, X: 3, Y: 3, Size: 10},
+			{Source: domain.LabelSourcePrice, X: 3, Y: 10, Size: 24},
+			{Source: domain.LabelSourceUnitPrice, X: 3, Y: 22, Size: 7},
+			{Source: domain.LabelSourceSKU, X: 3, Y: 29, Size: 6},
+		},
+		NetContentKey: "net_content",
+	},
+	{
+		Name:     "barcode",
+		WidthMM:  50,
+		HeightMM: 25,
+		Fields: []domain.LabelField{
+			{Source: domain.LabelSourceName, X: 3, Y: 2, Size: 7},
+			{Source: domain.LabelSourceBarcode, X: 5, Y: 6, Size: 10},
+			{Source: domain.LabelSourcePrice, X: 36, Y: 2, Size: 7},
+		},
+	},
+}
+
+// netContentUnits converts pack content units to the unit prices are shown per.
+var netContentUnits = map[string]struct {
+	unit   string
+	factor float64
+}{
+	"kg": {domain.UnitKilogram, 1},
+	"g":  {domain.UnitKilogram, 0.001},
+	"l":  {domain.UnitLitre, 1},
+	"cl": {domain.UnitLitre, 0.01},
+	"ml": {domain.UnitLitre, 0.001},
+	"m":  {domain.UnitMetre, 1},
+	"cm": {domain.UnitMetre, 0.01},
+	"mm": {domain.UnitMetre, 0.001},
+}
+
+// netContentDimensions maps the dimension of a measured attribute to the unit
+// prices are shown per; measure factors are already relative to these units.
+var netContentDimensions = map[string]string{
+	"mass":   domain.UnitKilogram,
+	"volume": domain.UnitLitre,
+	"length": domain.UnitMetre,
+}
+
+// LabelService implements shelf label and barcode sticker printing.
+type LabelService struct {
+	productSvc   ports.ProductService
+	categoryRepo ports.CategoryRepository
+	barcodeRepo  ports.BarcodeRepository
+	renderers    map[string]ports.LabelRenderer
+	templates    map[string]domain.LabelTemplate
+}
+
+// NewLabelService creates a new label service instance. renderers maps a
+// format name such as "zpl" or "pdf" to its renderer.
+func NewLabelService(productSvc ports.ProductService, categoryRepo ports.CategoryRepository, barcodeRepo ports.BarcodeRepository, renderers map[string]ports.LabelRenderer) *LabelService {
+	templates := make(map[string]domain.LabelTemplate, len(DefaultLabelTemplates))
+	for _, t := range DefaultLabelTemplates {
+		templates[t.Name] = t
+	}
+
+	return &LabelService{
+		productSvc:   productSvc,
+		categoryRepo: categoryRepo,
+		barcodeRepo:  barcodeRepo,
+		renderers:    renderers,
+		templates:    templates,
+	}
+}
+
+// ListTemplates returns the built-in label templates sorted by name.
+func (s *LabelService) ListTemplates() []domain.LabelTemplate {
+	templates := make([]domain.LabelTemplate, 0, len(s.templates))
+	for _, t := range s.templates {
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates
+}
+
+// RenderLabels selects products, builds their labels and writes them to w in
+// the requested format. It returns the content type of the output.
+func (s *LabelService) RenderLabels(ctx context.Context, req ports.LabelRequest, w io.Writer) (string, error) {
+	renderer, ok := s.renderers[req.Format]
+	if !ok {
+		return "", fmt.Errorf("%w: unknown format %q", ErrInvalidLabelRequest, req.Format)
+	}
+
+	template, err := s.resolveTemplate(req)
+	if err != nil {
+		return "", err
+	}
+
+	copies := req.Copies
+	if copies <= 0 {
+		copies = 1
+	}
+	if copies > maxLabelCopies {
+		return "", fmt.Errorf("%w: at most %d copies per product", ErrInvalidLabelRequest, maxLabelCopies)
+	}
+
+	products, err := s.selectProducts(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	if len(products) == 0 {
+		return "", ErrNoLabelProducts
+	}
+
+	labels := make([]domain.Label, 0, len(products)*copies)
+	for _, product := range products {
+		label, err := s.buildLabel(ctx, product, template)
+		if err != nil {
+			return "", err
+		}
+		for i := 0; i < copies; i++ {
+			labels = append(labels, label)
+		}
+	}
+
+	// Render fully before writing so a failure leaves w untouched
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, template, labels); err != nil {
+		return "", fmt.Errorf("render labels: %w", err)
+	}
+	if _, err := buf.WriteTo(w); err != nil {
+		return "", err
+	}
+
+	return renderer.ContentType(), nil
+}
+
+// resolveTemplate returns the request's custom template or the named built-in.
+func (s *LabelService) resolveTemplate(req ports.LabelRequest) (domain.LabelTemplate, error) {
+	if req.Custom == nil {
+		name := req.Template
+		if name == "" {
+			name = "shelf"
+		}
+		template, ok := s.templates[name]
+		if !ok {
+			return domain.LabelTemplate{}, fmt.Errorf("%w: unknown template %q", ErrInvalidLabelRequest, name)
+		}
+		return template, nil
+	}
+
+	template := *req.Custom
+	if template.WidthMM <= 0 || template.HeightMM <= 0 {
+		return domain.LabelTemplate{}, fmt.Errorf("%w: template width and height must be positive", ErrInvalidLabelRequest)
+	}
+	if len(template.Fields) == 0 {
+		return domain.LabelTemplate{}, fmt.Errorf("%w: template has no fields", ErrInvalidLabelRequest)
+	}
+	for _, field := range template.Fields {
+		switch field.Source {
+		case domain.LabelSourceName, domain.LabelSourceSKU, domain.LabelSourcePrice,
+			domain.LabelSourceUnitPrice, domain.LabelSourceBarcode, domain.LabelSourceText:
+		default:
+			if !strings.HasPrefix(field.Source, domain.LabelSourceProperty) || field.Source == domain.LabelSourceProperty {
+				return domain.LabelTemplate{}, fmt.Errorf("%w: unknown field source %q", ErrInvalidLabelRequest, field.Source)
+			}
+		}
+		if field.X < 0 || field.Y < 0 || field.X > template.WidthMM || field.Y > template.HeightMM {
+			return domain.LabelTemplate{}, fmt.Errorf("%w: field %q lies outside the label", ErrInvalidLabelRequest, field.Source)
+		}
+	}
+	if template.Name == "" {
+		template.Name = "custom"
+	}
+	return template, nil
+}
+
+// selectProducts loads the requested products by ID, or by search filter.
+func (s *LabelService) selectProducts(ctx context.Context, req ports.LabelRequest) ([]*domain.Product, error) {
+	if len(req.ProductIDs) > 0 {
+		if len(req.ProductIDs) > maxLabelProducts {
+			return nil, fmt.Errorf("%w: at most %d products per request", ErrInvalidLabelRequest, maxLabelProducts)
+		}
+		products := make([]*domain.Product, 0, len(req.ProductIDs))
+		for _, id := range req.ProductIDs {
+			product, err := s.productSvc.GetProduct(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("product %s: %w", id, err)
+			}
+			products = append(products, product)
+		}
+		return products, nil
+	}
+
+	filter := req.Filter
+	if filter.Limit > maxLabelProducts {
+		return nil, fmt.Errorf("%w: at most %d products per request", ErrInvalidLabelRequest, maxLabelProducts)
+	}
+	paged := filter.Limit > 0
+	if !paged {
+		filter.Limit = maxLabelProducts
+	}
+	page, err := s.productSvc.SearchProducts(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	// Without a limit every match is wanted, so a filter matching more than
+	// one request can print is refused rather than silently cut short
+	if !paged && page.PageInfo.Total > maxLabelProducts {
+		return nil, fmt.Errorf("%w: filter matches %d products, at most %d per request; narrow the filter or page through it with a limit",
+			ErrInvalidLabelRequest, page.PageInfo.Total, maxLabelProducts)
+	}
+	return page.Products, nil
+}
+
+// buildLabel collects the data printed on a product's label.
+func (s *LabelService) buildLabel(ctx context.Context, product *domain.Product, template domain.LabelTemplate) (domain.Label, error) {
+	label := domain.Label{
+		ProductID:  product.ID,
+		Name:       product.Name,
+		SKU:        product.SKU,
+		Price:      product.BasePrice,
+		Barcode:    product.SKU,
+		Properties: product.Properties,
+	}
+	attrs, err := s.netContentAttributes(ctx, product, template.NetContentKey)
+	if err != nil {
+		return domain.Label{}, err
+	}
+	label.UnitPrice, label.UnitMeasure = labelUnitPrice(product, template.NetContentKey, attrs)
+
+	barcodes, err := s.barcodeRepo.ListByProduct(ctx, product.ID)
+	if err != nil {
+		return domain.Label{}, fmt.Errorf("barcodes for product %s: %w", product.ID, err)
+	}
+	if len(barcodes) > 0 {
+		label.Barcode = barcodes[0].Code
+	}
+
+	return label, nil
+}
+
+// netContentAttributes returns the attribute definitions of a product's
+// category when its pack contents are stored as a bare number, whose unit
+// only the category's definition records.
+func (s *LabelService) netContentAttributes(ctx context.Context, product *domain.Product, netContentKey string) ([]domain.AttributeDefinition, error) {
+	if netContentKey == "" || product.CategoryID == "" {
+		return nil, nil
+	}
+	if _, ok := product.PropertyOriginals[netContentKey]; ok {
+		return nil, nil
+	}
+	if _, ok := product.Properties[netContentKey].(string); ok {
+		return nil, nil
+	}
+	if _, ok := toNumber(product.Properties[netContentKey]); !ok {
+		return nil, nil
+	}
+
+	category, err := resolveCategory(ctx, s.categoryRepo, product.CategoryID)
+	if err != nil {
+		return nil, fmt.Errorf("category for product %s: %w", product.ID, err)
+	}
+	return effectiveAttributes(category), nil
+}
+
+// labelUnitPrice returns the price per kg, l or m shown on a label. Products
+// sold by measure use their base price; items sold each use the pack contents
+// held in the netContentKey property, either as text such as "500 g" or "1.5 l"
+// or as a measured number in the unit of its attribute among attrs.
+func labelUnitPrice(product *domain.Product, netContentKey string, attrs []domain.AttributeDefinition) (float64, string) {
+	switch product.UnitOfMeasure {
+	case domain.UnitKilogram, domain.UnitLitre, domain.UnitMetre:
+		return product.BasePrice, product.UnitOfMeasure
+	}
+
+	if netContentKey == "" {
+		return 0, ""
+	}
+	raw, ok := product.Properties[netContentKey]
+	if !ok {
+		return 0, ""
+	}
+
+	// Measured numbers keep the text they were entered as
+	text, ok := product.PropertyOriginals[netContentKey]
+	if !ok {
+		text = fmt.Sprint(raw)
+	}
+	amount, unit, ok := parseNetContent(text)
+	if !ok {
+		amount, unit, ok = measuredNetContent(raw, netContentKey, attrs)
+	}
+	if !ok {
+		return 0, ""
+	}
+	return roundQuantity(product.BasePrice/amount, 2), unit
+}
+
+// measuredNetContent converts pack contents stored as a bare number in the
+// unit of its measured attribute, such as 500 for a net_content in g, into
+// an amount of kg, l or m.
+func measuredNetContent(raw interface{}, netContentKey string, attrs []domain.AttributeDefinition) (float64, string, bool) {
+	value, ok := toNumber(raw)
+	if !ok || value <= 0 {
+		return 0, "", false
+	}
+	for _, attr := range attrs {
+		if attr.Key != netContentKey {
+			continue
+		}
+		from, ok := attributeUnit(attr)
+		if !ok {
+			return 0, "", false
+		}
+		unit, ok := netContentDimensions[from.dimension]
+		if !ok {
+			return 0, "", false
+		}
+		return value * from.factor, unit, true
+	}
+	return 0, "", false
+}
+
+// parseNetContent parses pack contents such as "500 g" or "1.5l" into an
+// amount of kg, l or m.
+func parseNetContent(value string) (float64, string, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	split := strings.IndexFunc(value, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if split <= 0 {
+		return 0, "", false
+	}
+
+	amount, err := strconv.ParseFloat(value[:split], 64)
+	if err != nil || amount <= 0 {
+		return 0, "", false
+	}
+	conv, ok := netContentUnits[strings.TrimSpace(value[split:])]
+	if !ok {
+		return 0, "", false
+	}
+	return amount * conv.factor, conv.unit, true
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// --- Fake LabelRenderer ---
+
+type fakeLabelRenderer struct {
+	template domain.LabelTemplate
+	labels   []domain.Label
+}
+
+func (f *fakeLabelRenderer) ContentType() string { return "text/test" }
+func (f *fakeLabelRenderer) Render(w io.Writer, template domain.LabelTemplate, labels []domain.Label) error {
+	f.template = template
+	f.labels = labels
+	_, err := io.WriteString(w, "rendered")
+	return err
+}
+
+func newLabelTestService(products []*domain.Product, barcodes []*domain.ProductBarcode) (*LabelService, *fakeLabelRenderer) {
+	productRepo := &mockProductRepository{products: products}
+	categoryRepo := &mockCategoryRepository{categories: make(map[string]*domain.Category)}
+	auditRepo := &mockAuditLogRepository{}
+	productSvc := NewProductService(productRepo, categoryRepo, NewAuditService(auditRepo), &mockTransactionManager{
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+		auditRepo:    auditRepo,
+	}, &mockSynonymRepository{})
+
+	renderer := &fakeLabelRenderer{}
+	svc := NewLabelService(productSvc, categoryRepo, &mockBarcodeRepository{barcodes: barcodes}, map[string]ports.LabelRenderer{"test": renderer})
+	return svc, renderer
+}
+
+// --- LabelService Tests ---
+
+func TestRenderLabels_ByProductID(t *testing.T) {
+	svc, renderer := newLabelTestService([]*domain.Product{
+		{ID: "p1", Name: "Cola 500ml", SKU: "COLA", BasePrice: 1.20, Properties: map[string]interface{}{"net_content": "500 ml"}},
+		{ID: "p2", Name: "Cheddar", SKU: "CHED", BasePrice: 12.50, UnitOfMeasure: domain.UnitKilogram},
+	}, []*domain.ProductBarcode{
+		{Code: "036000291452", GTIN: "00036000291452", ProductID: "p1", Format: domain.BarcodeUPCA},
+	})
+
+	var out bytes.Buffer
+	contentType, err := svc.RenderLabels(context.Background(), ports.LabelRequest{
+		ProductIDs: []string{"p1", "p2"},
+		Format:     "test",
+		Copies:     2,
+	}, &out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if contentType != "text/test" || out.String() != "rendered" {
+		t.Fatalf("unexpected output %q (%s)", out.String(), contentType)
+	}
+	if renderer.template.Name != "shelf" {
+		t.Fatalf("expected the shelf template by default, got %q", renderer.template.Name)
+	}
+	if len(renderer.labels) != 4 {
+		t.Fatalf("expected 2 copies of 2 labels, got %d", len(renderer.labels))
+	}
+
+	cola := renderer.labels[0]
+	if cola.Barcode != "036000291452" || cola.UnitMeasure != domain.UnitLitre || cola.UnitPrice != 2.40 {
+		t.Fatalf("expected GTIN barcode and 2.40 per l, got %+v", cola)
+	}
+	cheddar := renderer.labels[2]
+	if cheddar.Barcode != "CHED" || cheddar.UnitMeasure != domain.UnitKilogram || cheddar.UnitPrice != 12.50 {
+		t.Fatalf("expected SKU barcode and 12.50 per kg, got %+v", cheddar)
+	}
+}
+
+func TestRenderLabels_MeasuredNetContent(t *testing.T) {
+	svc, renderer := newLabelTestService([]*domain.Product{
+		// Entered as a number, so only the attribute knows it is in g
+		{ID: "p1", Name: "Crisps", SKU: "CRISP", BasePrice: 2.00, CategoryID: "snacks",
+			Properties: map[string]interface{}{"net_content": 500.0}},
+		// Entered as text and stored in g, keeping the text
+		{ID: "p2", Name: "Nuts", SKU: "NUTS", BasePrice: 3.00, CategoryID: "snacks",
+			Properties:        map[string]interface{}{"net_content": 250.0},
+			PropertyOriginals: map[string]string{"net_content": "0.25 kg"}},
+	}, nil)
+	svc.categoryRepo.(*mockCategoryRepository).categories["snacks"] = &domain.Category{
+		ID: "snacks", Name: "Snacks",
+		AttributeDefinitions: []domain.AttributeDefinition{{Key: "net_content", Type: "number", Unit: "g"}},
+	}
+
+	_, err := svc.RenderLabels(context.Background(), ports.LabelRequest{ProductIDs: []string{"p1", "p2"}, Format: "test"}, io.Discard)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	crisps, nuts := renderer.labels[0], renderer.labels[1]
+	if crisps.UnitMeasure != domain.UnitKilogram || crisps.UnitPrice != 4.00 {
+		t.Errorf("expected 4.00 per kg for 500 g, got %.2f per %q", crisps.UnitPrice, crisps.UnitMeasure)
+	}
+	if nuts.UnitMeasure != domain.UnitKilogram || nuts.UnitPrice != 12.00 {
+		t.Errorf("expected 12.00 per kg for 0.25 kg, got %.2f per %q", nuts.UnitPrice, nuts.UnitMeasure)
+	}
+}
+
+func TestRenderLabels_InvalidRequests(t *testing.T) {
+	svc, _ := newLabelTestService([]*domain.Product{{ID: "p1", Name: "Widget", SKU: "W"}}, nil)
+	ctx := context.Background()
+
+	cases := map[string]ports.LabelRequest{
+		"unknown format":   {ProductIDs: []string{"p1"}, Format: "bmp"},
+		"unknown template": {ProductIDs: []string{"p1"}, Format: "test", Template: "poster"},
+		"bad field source": {ProductIDs: []string{"p1"}, Format: "test", Custom: &domain.LabelTemplate{
+			WidthMM: 40, HeightMM: 20, Fields: []domain.LabelField{{Source: "colour"}},
+		}},
+		"field off label": {ProductIDs: []string{"p1"}, Format: "test", Custom: &domain.LabelTemplate{
+			WidthMM: 40, HeightMM: 20, Fields: []domain.LabelField{{Source: domain.LabelSourceName, X: 50}},
+		}},
+	}
+	for name, req := range cases {
+		if _, err := svc.RenderLabels(ctx, req, io.Discard); !errors.Is(err, ErrInvalidLabelRequest) {
+			t.Errorf("%s: expected ErrInvalidLabelRequest, got %v", name, err)
+		}
+	}
+}
+
+func TestRenderLabels_FilterOverLimit(t *testing.T) {
+	products := make([]*domain.Product, maxLabelProducts+1)
+	for i := range products {
+		products[i] = &domain.Product{ID: fmt.Sprintf("p%d", i), Name: "Widget", SKU: fmt.Sprintf("W%d", i)}
+	}
+	svc, _ := newLabelTestService(products, nil)
+	ctx := context.Background()
+
+	_, err := svc.RenderLabels(ctx, ports.LabelRequest{Format: "test"}, io.Discard)
+	if !errors.Is(err, ErrInvalidLabelRequest) || !strings.Contains(err.Error(), fmt.Sprint(maxLabelProducts+1)) {
+		t.Fatalf("expected ErrInvalidLabelRequest naming %d products, got %v", maxLabelProducts+1, err)
+	}
+
+	req := ports.LabelRequest{Format: "test"}
+	req.Filter.Limit = maxLabelProducts + 1
+	if _, err := svc.RenderLabels(ctx, req, io.Discard); !errors.Is(err, ErrInvalidLabelRequest) {
+		t.Fatalf("expected ErrInvalidLabelRequest for a limit over %d, got %v", maxLabelProducts, err)
+	}
+}
+
+func TestRenderLabels_NoProducts(t *testing.T) {
+	svc, _ := newLabelTestService(nil, nil)
+
+	_, err := svc.RenderLabels(context.Background(), ports.LabelRequest{Format: "test"}, io.Discard)
+	if !errors.Is(err, ErrNoLabelProducts) {
+		t.Fatalf("expected ErrNoLabelProducts, got %v", err)
+	}
+}
+
+func TestParseNetContent(t *testing.T) {
+	cases := map[string]struct {
+		amount float64
+		unit   string
+	}{
+		"500 g": {0.5, domain.UnitKilogram},
+		"1.5l":  {1.5, domain.UnitLitre},
+		"75 CL": {0.75, domain.UnitLitre},
+		"250cm": {2.5, domain.UnitMetre},
+	}
+	for value, want := range cases {
+		amount, unit, ok := parseNetContent(value)
+		if !ok || amount != want.amount || unit != want.unit {
+			t.Errorf("parseNetContent(%q) = %v %q %v; want %v %q", value, amount, unit, ok, want.amount, want.unit)
+		}
+	}
+
+	for _, value := range []string{"", "g", "12 stone", "0 g"} {
+		if _, _, ok := parseNetContent(value); ok {
+			t.Errorf("parseNetContent(%q): expected failure", value)
+		}
+	}
+}
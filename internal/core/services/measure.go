@@ -0,0 +1,148 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+// measurePrecision is the number of decimal places kept when a measured
+// property is converted to its attribute's unit.
+const measurePrecision = 9
+
+// measureUnit is a unit a measured property may be given in.
+type measureUnit struct {
+	dimension string
+	factor    float64 // Size of the unit in the dimension's base unit
+}
+
+// measureUnits maps the lower-case symbols and names of the supported units
+// to their dimension. A number attribute whose Unit is one of these accepts
+// values in any unit of the same dimension.
+var measureUnits = map[string]measureUnit{
+	"ma": {"current", 0.001}, "a": {"current", 1}, "amp": {"current", 1}, "amps": {"current", 1},
+	"mv": {"voltage", 0.001}, "v": {"voltage", 1}, "kv": {"voltage", 1000}, "volt": {"voltage", 1}, "volts": {"voltage", 1},
+	"w": {"power", 1}, "kw": {"power", 1000}, "watt": {"power", 1}, "watts": {"power", 1},
+	"ohm": {"resistance", 1}, "ohms": {"resistance", 1}, "kohm": {"resistance", 1000},
+	"mm": {"length", 0.001}, "cm": {"length", 0.01}, "m": {"length", 1}, "km": {"length", 1000},
+	"metre": {"length", 1}, "metres": {"length", 1}, "meter": {"length", 1}, "meters": {"length", 1},
+	"ml": {"volume", 0.001}, "cl": {"volume", 0.01}, "l": {"volume", 1},
+	"litre": {"volume", 1}, "litres": {"volume", 1}, "liter": {"volume", 1}, "liters": {"volume", 1},
+	"mg": {"mass", 0.000001}, "g": {"mass", 0.001}, "kg": {"mass", 1},
+}
+
+// attributeUnit returns the unit of a measured number attribute. ok is false
+// for other attributes, including numbers whose Unit is only a label.
+func attributeUnit(attr domain.AttributeDefinition) (measureUnit, bool) {
+	if attr.Type != "number" {
+		return measureUnit{}, false
+	}
+	unit, ok := measureUnits[strings.ToLower(strings.TrimSpace(attr.Unit))]
+	return unit, ok
+}
+
+// parseMeasure parses a value such as "220v", "0.22 kV" or "220" into the
+// attribute's unit; a value without a unit is taken to be in it already. It
+// returns what is wrong with the value, or an empty string if it is valid.
+func parseMeasure(attr domain.AttributeDefinition, value string) (float64, string) {
+	to, ok := attributeUnit(attr)
+	if !ok {
+		return 0, "must be a number"
+	}
+
+	trimmed := strings.TrimSpace(value)
+	split := strings.IndexFunc(trimmed, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.' && r != '-' && r != '+'
+	})
+	if split < 0 {
+		split = len(trimmed)
+	}
+	amount, err := strconv.ParseFloat(trimmed[:split], 64)
+	if err != nil {
+		return 0, fmt.Sprintf("value %q must be a number with an optional unit", value)
+	}
+
+	symbol := strings.TrimSpace(trimmed[split:])
+	if symbol == "" {
+		return amount, ""
+	}
+	from, ok := measureUnits[strings.ToLower(symbol)]
+	if !ok {
+		return 0, fmt.Sprintf("value %q has unknown unit %q", value, symbol)
+	}
+	if from.dimension != to.dimension {
+		return 0, fmt.Sprintf("value %q is a %s, not a %s in %s", value, from.dimension, to.dimension, attr.Unit)
+	}
+	return roundQuantity(amount*from.factor/to.factor, measurePrecision), ""
+}
+
+// normalizeMeasures converts a product's measured properties given as text,
+// such as "220v", to numbers in their attribute's unit and keeps the text in
+// PropertyOriginals. Originals that no longer match their property's value
+// are dropped. It reports whether any property was converted.
+func normalizeMeasures(category *domain.Category, product *domain.Product) bool {
+	if category == nil {
+		return false
+	}
+
+	measured := make(map[string]domain.AttributeDefinition)
+	for _, attr := range effectiveAttributes(category) {
+		if _, ok := attributeUnit(attr); ok {
+			measured[attr.Key] = attr
+		}
+	}
+
+	var converted bool
+	for key, attr := range measured {
+		text, ok := product.Properties[key].(string)
+		if !ok {
+			continue
+		}
+		value, msg := parseMeasure(attr, text)
+		if msg != "" {
+			continue // Reported by validation
+		}
+		if product.PropertyOriginals == nil {
+			product.PropertyOriginals = make(map[string]string)
+		}
+		product.Properties[key] = value
+		product.PropertyOriginals[key] = text
+		converted = true
+	}
+
+	for key, text := range product.PropertyOriginals {
+		attr, ok := measured[key]
+		if !ok {
+			delete(product.PropertyOriginals, key)
+			continue
+		}
+		value, msg := parseMeasure(attr, text)
+		current, isNumber := toNumber(product.Properties[key])
+		if msg != "" || !isNumber || math.Abs(current-value) > math.Pow10(-measurePrecision) {
+			delete(product.PropertyOriginals, key)
+		}
+	}
+	return converted
+}
+
+// rescaleMeasure converts a stored measured value from the unit of its
+// previous attribute definition to that of its new one. ok is false when
+// the value needs no conversion.
+func rescaleMeasure(val interface{}, previous, attr domain.AttributeDefinition) (float64, bool) {
+	from, ok := attributeUnit(previous)
+	if !ok {
+		return 0, false
+	}
+	to, ok := attributeUnit(attr)
+	if !ok || from.dimension != to.dimension || from.factor == to.factor {
+		return 0, false
+	}
+	value, ok := val.(float64)
+	if !ok {
+		return 0, false
+	}
+	return roundQuantity(value*from.factor/to.factor, measurePrecision), true
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+func TestParseMeasure(t *testing.T) {
+	voltage := domain.AttributeDefinition{Key: "voltage", Type: "number", Unit: "V"}
+	current := domain.AttributeDefinition{Key: "current", Type: "number", Unit: "A"}
+
+	tests := []struct {
+		attr  domain.AttributeDefinition
+		value string
+		want  float64
+		valid bool
+	}{
+		{voltage, "220v", 220, true},
+		{voltage, "0.22 kV", 220, true},
+		{voltage, "220", 220, true},
+		{current, "500mA", 0.5, true},
+		{current, "1.5 A", 1.5, true},
+		{voltage, "220 mm", 0, false},
+		{voltage, "220 parsecs", 0, false},
+		{voltage, "high", 0, false},
+	}
+	for _, tt := range tests {
+		got, msg := parseMeasure(tt.attr, tt.value)
+		if tt.valid && (msg != "" || got != tt.want) {
+			t.Errorf("%q: expected %v, got %v (%s)", tt.value, tt.want, got, msg)
+		}
+		if !tt.valid && msg == "" {
+			t.Errorf("%q: expected an error, got %v", tt.value, got)
+		}
+	}
+}
+
+func TestNormalizeMeasures(t *testing.T) {
+	category := &domain.Category{AttributeDefinitions: []domain.AttributeDefinition{
+		{Key: "length", Type: "number", Unit: "m"},
+		{Key: "volume", Type: "number", Unit: "ml"},
+		{Key: "rating", Type: "number", Unit: "Stars"},
+	}}
+	product := &domain.Product{
+		Properties:        map[string]interface{}{"length": "250 mm", "volume": 330.0, "rating": "4"},
+		PropertyOriginals: map[string]string{"volume": "0.5 l"},
+	}
+
+	if !normalizeMeasures(category, product) {
+		t.Fatal("expected a property to be converted")
+	}
+	if product.Properties["length"] != 0.25 {
+		t.Errorf("expected length 0.25, got %v", product.Properties["length"])
+	}
+	if product.Properties["rating"] != "4" {
+		t.Errorf("expected a labelled number to be left alone, got %v", product.Properties["rating"])
+	}
+	if product.PropertyOriginals["length"] != "250 mm" {
+		t.Errorf("expected the original length to be kept, got %q", product.PropertyOriginals["length"])
+	}
+	if _, ok := product.PropertyOriginals["volume"]; ok {
+		t.Error("expected the stale volume original to be dropped")
+	}
+}
+
+func TestCreateProduct_NormalizesMeasures(t *testing.T) {
+	productRepo := &mockProductRepository{}
+	categoryRepo := &mockCategoryRepository{categories: map[string]*domain.Category{
+		"cables": {ID: "cables", Name: "Cables", AttributeDefinitions: []domain.AttributeDefinition{
+			{Key: "voltage", Type: "number", Unit: "V", Max: floatPtr(1000)},
+		}},
+	}}
+	svc := NewProductService(productRepo, categoryRepo, NewAuditService(&mockAuditLogRepository{}), &mockSaleTxManager{productRepo: productRepo}, &mockSynonymRepository{})
+
+	product := &domain.Product{ID: "p1", SKU: "CAB-1", CategoryID: "cables", Properties: map[string]interface{}{"voltage": "0.6 kV"}}
+	if err := svc.CreateProduct(context.Background(), product); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if product.Properties["voltage"] != 600.0 || product.PropertyOriginals["voltage"] != "0.6 kV" {
+		t.Errorf("expected 600 V from 0.6 kV, got %v from %q", product.Properties["voltage"], product.PropertyOriginals["voltage"])
+	}
+
+	// Limits apply in the attribute's unit
+	product = &domain.Product{ID: "p2", SKU: "CAB-2", CategoryID: "cables", Properties: map[string]interface{}{"voltage": "1.1kV"}}
+	if err := svc.CreateProduct(context.Background(), product); !errors.Is(err, ErrInvalidProperty) {
+		t.Fatalf("expected 1.1 kV to exceed the 1000 V maximum, got %v", err)
+	}
+	product = &domain.Product{ID: "p3", SKU: "CAB-3", CategoryID: "cables", Properties: map[string]interface{}{"voltage": "3 mA"}}
+	if err := svc.CreateProduct(context.Background(), product); !errors.Is(err, ErrInvalidProperty) {
+		t.Fatalf("expected a current to be rejected for a voltage, got %v", err)
+	}
+}
+
+func TestUpdateCategory_RescalesChangedUnit(t *testing.T) {
+	categoryRepo := &mockCategoryRepository{categories: map[string]*domain.Category{
+		"cables": {ID: "cables", Name: "Cables", Version: 1, AttributeDefinitions: []domain.AttributeDefinition{
+			{Key: "length", Type: "number", Unit: "m"},
+		}},
+	}}
+	productRepo := &mockProductRepository{products: []*domain.Product{
+		{ID: "p1", SKU: "CAB-1", CategoryID: "cables", Properties: map[string]interface{}{"length": 2.5}, PropertyOriginals: map[string]string{"length": "250cm"}},
+		{ID: "p2", SKU: "CAB-2", CategoryID: "cables", Properties: map[string]interface{}{"length": "3 m"}},
+	}}
+	txManager := &mockSaleTxManager{productRepo: productRepo, categoryRepo: categoryRepo, auditRepo: &mockAuditLogRepository{}}
+	svc := NewCategoryService(categoryRepo, txManager)
+
+	evolution, err := svc.UpdateCategory(context.Background(), "cables", ports.CategoryUpdate{
+		AttributeDefinitions: &[]domain.AttributeDefinition{{Key: "length", Type: "number", Unit: "mm"}},
+		ConvertTypes:         true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if evolution.Migrated != 2 {
+		t.Errorf("expected 2 migrated products, got %d", evolution.Migrated)
+	}
+
+	first, second := productRepo.products[0], productRepo.products[1]
+	if first.Properties["length"] != 2500.0 || first.PropertyOriginals["length"] != "250cm" {
+		t.Errorf("expected 2500 mm keeping its original, got %v from %q", first.Properties["length"], first.PropertyOriginals["length"])
+	}
+	if second.Properties["length"] != 3000.0 || second.PropertyOriginals["length"] != "3 m" {
+		t.Errorf("expected 3000 mm from 3 m, got %v from %q", second.Properties["length"], second.PropertyOriginals["length"])
+	}
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+// ErrInvalidPage is returned when a listing is asked for an unknown sort or
+// order, or given a cursor that is malformed or from a differently sorted
+// listing.
+var ErrInvalidPage = errors.New("invalid page request")
+
+// pageCursor is the content of an opaque page cursor: the key a page starts
+// from and the sort it was taken under.
+type pageCursor struct {
+	Sort   string      `json:"s"`
+	Order  string      `json:"o"`
+	Value  interface{} `json:"v"`
+	ID     string      `json:"id"`
+	Before bool        `json:"b,omitempty"`
+}
+
+// resolvePage fills in a page request's default sort and order and decodes
+// its cursor into After or Before. sorts maps each sort the listing supports
+// to its default order.
+func resolvePage(req domain.PageRequest, defaultSort string, sorts map[string]string) (domain.PageRequest, error) {
+	if req.Sort == "" {
+		req.Sort = defaultSort
+	}
+	defaultOrder, ok := sorts[req.Sort]
+	if !ok {
+		return req, fmt.Errorf("%w: cannot sort by %q", ErrInvalidPage, req.Sort)
+	}
+	switch req.Order {
+	case "":
+		req.Order = defaultOrder
+	case domain.SortAsc, domain.SortDesc:
+	default:
+		return req, fmt.Errorf("%w: order must be %s or %s", ErrInvalidPage, domain.SortAsc, domain.SortDesc)
+	}
+
+	req.After, req.Before = nil, nil
+	if req.Cursor == "" {
+		return req, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(req.Cursor)
+	if err != nil {
+		return req, fmt.Errorf("%w: malformed cursor", ErrInvalidPage)
+	}
+	var cursor pageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return req, fmt.Errorf("%w: malformed cursor", ErrInvalidPage)
+	}
+	if cursor.Sort != req.Sort || cursor.Order != req.Order {
+		return req, fmt.Errorf("%w: cursor is for a listing sorted by %s %s", ErrInvalidPage, cursor.Sort, cursor.Order)
+	}
+
+	key := &domain.PageKey{Value: cursor.Value, ID: cursor.ID}
+	if cursor.Before {
+		req.Before = key
+	} else {
+		req.After = key
+	}
+	return req, nil
+}
+
+// setPageCursors encodes the cursors of the pages either side of a fetched
+// page from the keys of its first and last items.
+func setPageCursors(info *domain.PageInfo, req domain.PageRequest) {
+	if info.HasNext && info.Last != nil {
+		info.NextCursor = encodePageCursor(pageCursor{Sort: req.Sort, Order: req.Order, Value: info.Last.Value, ID: info.Last.ID})
+	}
+	if info.HasPrev && info.First != nil {
+		info.PrevCursor = encodePageCursor(pageCursor{Sort: req.Sort, Order: req.Order, Value: info.First.Value, ID: info.First.ID, Before: true})
+	}
+}
+
+// encodePageCursor encodes a cursor as URL-safe text.
+func encodePageCursor(cursor pageCursor) string {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "" // Sort values are strings and numbers, which always encode
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+func TestResolvePage(t *testing.T) {
+	req, err := resolvePage(domain.PageRequest{}, domain.SortCreatedAt, productSorts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Sort != domain.SortCreatedAt || req.Order != domain.SortDesc {
+		t.Errorf("expected created_at desc by default, got %s %s", req.Sort, req.Order)
+	}
+
+	cursor := encodePageCursor(pageCursor{Sort: domain.SortPrice, Order: domain.SortAsc, Value: 9.5, ID: "p1", Before: true})
+	req, err = resolvePage(domain.PageRequest{Sort: domain.SortPrice, Cursor: cursor}, domain.SortCreatedAt, productSorts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.After != nil || req.Before == nil || req.Before.Value != 9.5 || req.Before.ID != "p1" {
+		t.Errorf("expected the cursor to page before 9.5/p1, got after %+v before %+v", req.After, req.Before)
+	}
+
+	invalid := []domain.PageRequest{
+		{Sort: "colour"},
+		{Order: "sideways"},
+		{Cursor: "not a cursor"},
+		{Sort: domain.SortName, Cursor: cursor}, // Taken under the price sort
+	}
+	for _, req := range invalid {
+		if _, err := resolvePage(req, domain.SortCreatedAt, productSorts); !errors.Is(err, ErrInvalidPage) {
+			t.Errorf("%+v: expected ErrInvalidPage, got %v", req, err)
+		}
+	}
+}
+
+func TestSearchProducts_Cursors(t *testing.T) {
+	repo := &searchMockProductRepository{products: []*domain.Product{{ID: "1"}, {ID: "2"}, {ID: "3"}}}
+	svc := NewProductService(repo, &mockCategoryRepository{}, nil, nil, &mockSynonymRepository{})
+
+	opts := domain.FilterOptions{PageRequest: domain.PageRequest{Limit: 2}}
+	first, err := svc.SearchProducts(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.Total != 3 || len(first.Products) != 2 {
+		t.Fatalf("expected 2 of 3 products, got %d of %d", len(first.Products), first.Total)
+	}
+	if first.NextCursor == "" || first.PrevCursor != "" {
+		t.Fatalf("expected only a next cursor, got next %q prev %q", first.NextCursor, first.PrevCursor)
+	}
+
+	opts.Cursor = first.NextCursor
+	second, err := svc.SearchProducts(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(second.Products) != 1 || second.Products[0].ID != "3" {
+		t.Fatalf("expected product 3 on the next page, got %+v", second.Products)
+	}
+	if second.NextCursor != "" || second.PrevCursor == "" {
+		t.Errorf("expected only a previous cursor, got next %q prev %q", second.NextCursor, second.PrevCursor)
+	}
+
+	// The cursor belongs to the newest-first listing
+	opts.Sort = domain.SortName
+	if _, err := svc.SearchProducts(context.Background(), opts); !errors.Is(err, ErrInvalidPage) {
+		t.Errorf("expected a cursor from another sort to be rejected, got %v", err)
+	}
+}
+
+func TestSearchProducts_RelevanceNeedsQuery(t *testing.T) {
+	svc := NewProductService(&searchMockProductRepository{}, &mockCategoryRepository{}, nil, nil, &mockSynonymRepository{})
+
+	opts := domain.FilterOptions{PageRequest: domain.PageRequest{Sort: domain.SortRelevance}}
+	if _, err := svc.SearchProducts(context.Background(), opts); !errors.Is(err, ErrInvalidPage) {
+		t.Errorf("expected ErrInvalidPage, got %v", err)
+	}
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// ErrInvalidPriceList is returned when a price list is malformed or a sale
+// names a price list that does not exist.
+var ErrInvalidPriceList = errors.New("invalid price list")
+
+// ErrInvalidPriceRule is returned when a price list rule is malformed.
+var ErrInvalidPriceRule = errors.New("invalid price list rule")
+
+// PriceListService implements management of customer price lists.
+type PriceListService struct {
+	priceListRepo ports.PriceListRepository
+	productRepo   ports.ProductRepository
+	categoryRepo  ports.CategoryRepository
+}
+
+// NewPriceListService creates a new price list service instance.
+func NewPriceListService(priceListRepo ports.PriceListRepository, productRepo ports.ProductRepository, categoryRepo ports.CategoryRepository) *PriceListService {
+	return &PriceListService{
+		priceListRepo: priceListRepo,
+		productRepo:   productRepo,
+		categoryRepo:  categoryRepo,
+	}
+}
+
+// CreatePriceList validates and stores a new, empty price list.
+func (s *PriceListService) CreatePriceList(ctx context.Context, list *domain.PriceList) error {
+	if list.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidPriceList)
+	}
+
+	list.ID = uuid.New().String()
+	list.Rules = nil
+	list.CreatedAt = time.Now()
+	return s.priceListRepo.Create(ctx, list)
+}
+
+// GetPriceList retrieves a price list with its rules.
+func (s *PriceListService) GetPriceList(ctx context.Context, id string) (*domain.PriceList, error) {
+	list, err := s.priceListRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	list.Rules, err = s.priceListRepo.ListRules(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("list rules: %w", err)
+	}
+	return list, nil
+}
+
+// ListPriceLists retrieves price lists with pagination.
+func (s *PriceListService) ListPriceLists(ctx context.Context, limit, offset int) ([]*domain.PriceList, error) {
+	return s.priceListRepo.List(ctx, limit, offset)
+}
+
+// DeletePriceList deletes a price list and its rules.
+func (s *PriceListService) DeletePriceList(ctx context.Context, id string) error {
+	return s.priceListRepo.Delete(ctx, id)
+}
+
+// AddRule validates and adds a product or category override to a price list.
+func (s *PriceListService) AddRule(ctx context.Context, rule *domain.PriceListRule) error {
+	if (rule.CategoryID == "") == (rule.ProductID == "") {
+		return fmt.Errorf("%w: exactly one of category_id or product_id is required", ErrInvalidPriceRule)
+	}
+	if (rule.Price == nil) == (rule.DiscountPercent == nil) {
+		return fmt.Errorf("%w: exactly one of price or discount_percent is required", ErrInvalidPriceRule)
+	}
+	if rule.Price != nil && *rule.Price < 0 {
+		return fmt.Errorf("%w: price must not be negative", ErrInvalidPriceRule)
+	}
+	if rule.DiscountPercent != nil && (*rule.DiscountPercent < 0 || *rule.DiscountPercent > 100) {
+		return fmt.Errorf("%w: discount_percent must be between 0 and 100", ErrInvalidPriceRule)
+	}
+	if rule.MinQuantity < 0 {
+		return fmt.Errorf("%w: min_quantity must not be negative", ErrInvalidPriceRule)
+	}
+
+	if _, err := s.priceListRepo.GetByID(ctx, rule.PriceListID); err != nil {
+		return err
+	}
+	if rule.ProductID != "" {
+		if _, err := s.productRepo.GetByID(ctx, rule.ProductID); err != nil {
+			return fmt.Errorf("%w: product %s: %v", ErrInvalidPriceRule, rule.ProductID, err)
+		}
+	} else if _, err := s.categoryRepo.GetByID(ctx, rule.CategoryID); err != nil {
+		return fmt.Errorf("%w: category %s: %v", ErrInvalidPriceRule, rule.CategoryID, err)
+	}
+
+	var categoryIDs []string
+	if rule.CategoryID != "" {
+		categoryIDs = []string{rule.CategoryID}
+	}
+	existing, err := s.priceListRepo.ListRulesFor(ctx, rule.PriceListID, rule.ProductID, categoryIDs)
+	if err != nil {
+		return fmt.Errorf("list rules: %w", err)
+	}
+	for _, other := range existing {
+		if other.ProductID == rule.ProductID && other.CategoryID == rule.CategoryID && other.MinQuantity == rule.MinQuantity {
+			return fmt.Errorf("%w: a rule for min_quantity %v already exists", ErrInvalidPriceRule, rule.MinQuantity)
+		}
+	}
+
+	rule.ID = uuid.New().String()
+	rule.CreatedAt = time.Now()
+	return s.priceListRepo.CreateRule(ctx, rule)
+}
+
+// RemoveRule deletes a rule from a price list.
+func (s *PriceListService) RemoveRule(ctx context.Context, priceListID, ruleID string) error {
+	return s.priceListRepo.DeleteRule(ctx, priceListID, ruleID)
+}
+
+// QuotePrice returns the unit price a quantity of a product would be charged
+// at under a price list. An empty priceListID quotes the base price.
+func (s *PriceListService) QuotePrice(ctx context.Context, priceListID, productID string, quantity float64) (float64, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return 0, err
+	}
+	if priceListID == "" {
+		return product.BasePrice, nil
+	}
+
+	if _, err := s.priceListRepo.GetByID(ctx, priceListID); err != nil {
+		if errors.Is(err, ports.ErrNotFound) {
+			return 0, fmt.Errorf("%w: %s not found", ErrInvalidPriceList, priceListID)
+		}
+		return 0, fmt.Errorf("price list lookup: %w", err)
+	}
+	lineage, err := categoryLineage(ctx, s.categoryRepo, product.CategoryID)
+	if err != nil {
+		return 0, fmt.Errorf("category of product %s: %w", product.ID, err)
+	}
+	rules, err := s.priceListRepo.ListRulesFor(ctx, priceListID, product.ID, lineage)
+	if err != nil {
+		return 0, fmt.Errorf("price list lookup for product %s: %w", product.ID, err)
+	}
+	return resolveListPrice(product, lineage, rules, quantity), nil
+}
+
+// resolveListPrice returns the unit price of a product under a price list's
+// rules for the given quantity, given the product's category lineage nearest
+// first. Product rules take precedence over category rules, and rules on a
+// nearer category over those on its ancestors; among the most specific
+// rules, the highest quantity break not above quantity applies. Products
+// without an applicable rule are charged their base price.
+func resolveListPrice(product *domain.Product, lineage []string, rules []*domain.PriceListRule, quantity float64) float64 {
+	var best *domain.PriceListRule
+	bestRank := -1
+	for _, rule := range rules {
+		if rule.MinQuantity > quantity {
+			continue
+		}
+		rank := ruleSpecificity(product, lineage, rule)
+		if rank < 0 {
+			continue
+		}
+
+		switch {
+		case best == nil, rank < bestRank:
+			best, bestRank = rule, rank
+		case rank == bestRank && rule.MinQuantity > best.MinQuantity:
+			best = rule
+		}
+	}
+
+	switch {
+	case best == nil:
+		return product.BasePrice
+	case best.Price != nil:
+		return *best.Price
+	default:
+		return roundMoney(product.BasePrice * (1 - *best.DiscountPercent/100))
+	}
+}
+
+// ruleSpecificity ranks how closely a rule targets a product: 0 for a rule on
+// the product itself, then 1, 2, ... for its category and each ancestor in
+// turn. It returns -1 for a rule that does not apply to the product.
+func ruleSpecificity(product *domain.Product, lineage []string, rule *domain.PriceListRule) int {
+	if rule.ProductID != "" {
+		if rule.ProductID == product.ID {
+			return 0
+		}
+		return -1
+	}
+	for i, id := range lineage {
+		if rule.CategoryID == id {
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// roundMoney rounds an amount to whole cents.
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// --- Mock PriceListRepository ---
+
+type mockPriceListRepository struct {
+	lists []*domain.PriceList
+	rules []*domain.PriceListRule
+	err   error // Returned by GetByID when set
+}
+
+func (m *mockPriceListRepository) Create(_ context.Context, list *domain.PriceList) error {
+	m.lists = append(m.lists, list)
+	return nil
+}
+func (m *mockPriceListRepository) GetByID(_ context.Context, id string) (*domain.PriceList, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, l := range m.lists {
+		if l.ID == id {
+			return l, nil
+		}
+	}
+	return nil, fmt.Errorf("price list %w", ports.ErrNotFound)
+}
+func (m *mockPriceListRepository) List(_ context.Context, _, _ int) ([]*domain.PriceList, error) {
+	return m.lists, nil
+}
+func (m *mockPriceListRepository) Delete(_ context.Context, id string) error {
+	for i, l := range m.lists {
+		if l.ID == id {
+			m.lists = append(m.lists[:i], m.lists[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("price list %w", ports.ErrNotFound)
+}
+func (m *mockPriceListRepository) CreateRule(_ context.Context, rule *domain.PriceListRule) error {
+	m.rules = append(m.rules, rule)
+	return nil
+}
+func (m *mockPriceListRepository) ListRules(_ context.Context, priceListID string) ([]*domain.PriceListRule, error) {
+	var out []*domain.PriceListRule
+	for _, r := range m.rules {
+		if r.PriceListID == priceListID {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+func (m *mockPriceListRepository) ListRulesFor(_ context.Context, priceListID, productID string, categoryIDs []string) ([]*domain.PriceListRule, error) {
+	var out []*domain.PriceListRule
+	for _, r := range m.rules {
+		matched := r.ProductID != "" && r.ProductID == productID
+		for _, id := range categoryIDs {
+			matched = matched || (r.CategoryID != "" && r.CategoryID == id)
+		}
+		if r.PriceListID == priceListID && matched {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+func (m *mockPriceListRepository) DeleteRule(_ context.Context, priceListID, ruleID string) error {
+	for i, r := range m.rules {
+		if r.PriceListID == priceListID && r.ID == ruleID {
+			m.rules = append(m.rules[:i], m.rules[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("price list rule not found")
+}
+func (m *mockPriceListRepository) DeleteRulesByProduct(_ context.Context, productID string) error {
+	var kept []*domain.PriceListRule
+	for _, r := range m.rules {
+		if r.ProductID != productID {
+			kept = append(kept, r)
+		}
+	}
+	m.rules = kept
+	return nil
+}
+
+func floatPtr(f float64) *float64 { return &f }
+
+// newTradeTestService sets up a "Trade" price list with quantity breaks on a
+// cable and a category-wide discount on fittings.
+func newTradeTestService(t *testing.T) (*PriceListService, *mockSaleTxManager, string) {
+	t.Helper()
+	txManager := newPackTestTxManager([]*domain.Product{
+		{ID: "cable", Name: "Cable", SKU: "CABLE", BasePrice: 2.00, Quantity: 500, CategoryID: "electrical"},
+		{ID: "socket", Name: "Socket", SKU: "SOCKET", BasePrice: 4.99, Quantity: 100, CategoryID: "electrical"},
+		{ID: "hammer", Name: "Hammer", SKU: "HAMMER", BasePrice: 12.00, Quantity: 10, CategoryID: "tools"},
+	}, nil)
+	txManager.categoryRepo.categories["electrical"] = &domain.Category{ID: "electrical", Name: "Electrical"}
+	txManager.categoryRepo.categories["tools"] = &domain.Category{ID: "tools", Name: "Tools"}
+	txManager.priceListRepo = &mockPriceListRepository{}
+
+	svc := NewPriceListService(txManager.priceListRepo, txManager.productRepo, txManager.categoryRepo)
+	ctx := context.Background()
+
+	trade := &domain.PriceList{Name: "Trade"}
+	if err := svc.CreatePriceList(ctx, trade); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, rule := range []*domain.PriceListRule{
+		{ProductID: "cable", MinQuantity: 1, Price: floatPtr(1.80)},
+		{ProductID: "cable", MinQuantity: 10, Price: floatPtr(1.60)},
+		{ProductID: "cable", MinQuantity: 50, Price: floatPtr(1.40)},
+		{CategoryID: "electrical", DiscountPercent: floatPtr(10)},
+	} {
+		rule.PriceListID = trade.ID
+		if err := svc.AddRule(ctx, rule); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	return svc, txManager, trade.ID
+}
+
+// --- PriceListService Tests ---
+
+func TestAddRule_Validation(t *testing.T) {
+	svc, _, listID := newTradeTestService(t)
+	ctx := context.Background()
+
+	cases := []*domain.PriceListRule{
+		{PriceListID: listID, Price: floatPtr(1)},
+		{PriceListID: listID, ProductID: "cable", CategoryID: "electrical", Price: floatPtr(1)},
+		{PriceListID: listID, ProductID: "cable"},
+		{PriceListID: listID, ProductID: "cable", Price: floatPtr(1), DiscountPercent: floatPtr(5)},
+		{PriceListID: listID, ProductID: "cable", DiscountPercent: floatPtr(120)},
+		{PriceListID: listID, ProductID: "missing", Price: floatPtr(1)},
+		{PriceListID: listID, ProductID: "cable", MinQuantity: 10, Price: floatPtr(1.5)}, // duplicate break
+	}
+	for i, rule := range cases {
+		if err := svc.AddRule(ctx, rule); !errors.Is(err, ErrInvalidPriceRule) {
+			t.Errorf("case %d: expected ErrInvalidPriceRule, got %v", i, err)
+		}
+	}
+
+	if err := svc.AddRule(ctx, &domain.PriceListRule{PriceListID: "missing", ProductID: "cable", Price: floatPtr(1)}); err == nil {
+		t.Error("expected an error for an unknown price list")
+	}
+}
+
+func TestQuotePrice_QuantityBreaks(t *testing.T) {
+	svc, _, listID := newTradeTestService(t)
+	ctx := context.Background()
+
+	cases := []struct {
+		productID string
+		quantity  float64
+		want      float64
+	}{
+		{"cable", 1, 1.80},
+		{"cable", 9, 1.80},
+		{"cable", 10, 1.60},
+		{"cable", 49.5, 1.60},
+		{"cable", 50, 1.40},
+		{"socket", 3, 4.49},  // category discount, rounded to cents
+		{"hammer", 1, 12.00}, // no rule: base price
+	}
+	for _, tc := range cases {
+		got, err := svc.QuotePrice(ctx, listID, tc.productID, tc.quantity)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != tc.want {
+			t.Errorf("%s x %v: expected %v, got %v", tc.productID, tc.quantity, tc.want, got)
+		}
+	}
+
+	if got, err := svc.QuotePrice(ctx, "", "cable", 100); err != nil || got != 2.00 {
+		t.Fatalf("expected base price 2.00 without a price list, got %v, %v", got, err)
+	}
+}
+
+func TestQuotePrice_InheritsCategoryRules(t *testing.T) {
+	svc, txManager, listID := newTradeTestService(t)
+	ctx := context.Background()
+
+	// Sockets sit under Wiring, a subcategory of Electrical
+	txManager.categoryRepo.categories["wiring"] = &domain.Category{ID: "wiring", Name: "Wiring", ParentID: "electrical"}
+	txManager.productRepo.products[1].CategoryID = "wiring"
+
+	got, err := svc.QuotePrice(ctx, listID, "socket", 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != 4.49 {
+		t.Fatalf("expected the Electrical discount to apply to Wiring, got %v", got)
+	}
+
+	// A rule on the nearer category wins over the parent's, even at a
+	// lower quantity break
+	if err := svc.AddRule(ctx, &domain.PriceListRule{PriceListID: listID, CategoryID: "wiring", DiscountPercent: floatPtr(5)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc.AddRule(ctx, &domain.PriceListRule{PriceListID: listID, CategoryID: "electrical", MinQuantity: 2, DiscountPercent: floatPtr(20)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err = svc.QuotePrice(ctx, listID, "socket", 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != 4.74 {
+		t.Fatalf("expected the Wiring discount to win, got %v", got)
+	}
+}
+
+func TestProcessSale_WithPriceList(t *testing.T) {
+	_, txManager, listID := newTradeTestService(t)
+	saleSvc := NewSaleService(txManager)
+
+	// Two cable lines add up to 12 m, reaching the 10+ break
+	sale, err := saleSvc.ProcessSale(context.Background(), ports.SaleRequest{
+		PriceListID: listID,
+		Items: []ports.SaleItemRequest{
+			{ProductID: "cable", Quantity: 8},
+			{ProductID: "cable", Quantity: 4},
+			{ProductID: "hammer", Quantity: 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(sale.TotalAmount-31.20) > 1e-9 || sale.PriceListID != listID {
+		t.Fatalf("expected total 31.20 at the trade price list, got %+v", sale)
+	}
+
+	items := txManager.saleRepo.saleItems
+	if items[0].UnitPrice != 1.60 || items[1].UnitPrice != 1.60 || items[2].UnitPrice != 12.00 {
+		t.Fatalf("expected unit price snapshots 1.60, 1.60, 12.00, got %+v", items)
+	}
+}
+
+func TestProcessSale_UnknownPriceList(t *testing.T) {
+	_, txManager, _ := newTradeTestService(t)
+
+	_, err := NewSaleService(txManager).ProcessSale(context.Background(), ports.SaleRequest{
+		PriceListID: "missing",
+		Items:       []ports.SaleItemRequest{{ProductID: "cable", Quantity: 1}},
+	})
+	if !errors.Is(err, ErrInvalidPriceList) {
+		t.Fatalf("expected ErrInvalidPriceList, got %v", err)
+	}
+}
+
+func TestPriceListLookupFailure_NotInvalid(t *testing.T) {
+	svc, txManager, listID := newTradeTestService(t)
+	dbErr := errors.New("database is locked")
+	txManager.priceListRepo.err = dbErr
+	ctx := context.Background()
+
+	_, err := NewSaleService(txManager).ProcessSale(ctx, ports.SaleRequest{
+		PriceListID: listID,
+		Items:       []ports.SaleItemRequest{{ProductID: "cable", Quantity: 1}},
+	})
+	if !errors.Is(err, dbErr) || errors.Is(err, ErrInvalidPriceList) {
+		t.Fatalf("sale: expected the lookup failure, got %v", err)
+	}
+
+	_, err = svc.QuotePrice(ctx, listID, "cable", 1)
+	if !errors.Is(err, dbErr) || errors.Is(err, ErrInvalidPriceList) {
+		t.Fatalf("quote: expected the lookup failure, got %v", err)
+	}
+}
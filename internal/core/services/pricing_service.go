@@ -0,0 +1,383 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// ErrInvalidPriceChange is returned when a scheduled price change is malformed.
+var ErrInvalidPriceChange = errors.New("invalid price change")
+
+// ErrPriceChangeOverlap is returned when a scheduled price change overlaps
+// another open change for the same product.
+var ErrPriceChangeOverlap = errors.New("price change overlaps another scheduled change")
+
+// ErrPriceChangeClosed is returned when cancelling a change that is already
+// completed or cancelled.
+var ErrPriceChangeClosed = errors.New("price change already closed")
+
+// ErrNoPriceAt is returned when a product had no price at the requested time.
+var ErrNoPriceAt = errors.New("no price at the requested time")
+
+// schedulerUserID is recorded in the audit log for changes applied by the scheduler.
+const schedulerUserID = "scheduler"
+
+// PricingService implements price history lookups and scheduled price changes.
+type PricingService struct {
+	priceRepo   ports.PriceRepository
+	productRepo ports.ProductRepository
+	txManager   ports.TransactionManager
+	now         func() time.Time // clock used to decide which changes are due
+}
+
+// NewPricingService creates a new pricing service instance.
+func NewPricingService(priceRepo ports.PriceRepository, productRepo ports.ProductRepository, txManager ports.TransactionManager) *PricingService {
+	return &PricingService{
+		priceRepo:   priceRepo,
+		productRepo: productRepo,
+		txManager:   txManager,
+		now:         time.Now,
+	}
+}
+
+// GetPriceHistory retrieves a product's base price history, newest first.
+func (s *PricingService) GetPriceHistory(ctx context.Context, productID string, limit, offset int) ([]*domain.PriceHistoryEntry, error) {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	return s.priceRepo.ListHistory(ctx, productID, limit, offset)
+}
+
+// GetPriceAt retrieves the base price a product had at the given time.
+func (s *PricingService) GetPriceAt(ctx context.Context, productID string, at time.Time) (*domain.PriceHistoryEntry, error) {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	entry, err := s.priceRepo.GetPriceAt(ctx, productID, at)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("%w: product %s at %s", ErrNoPriceAt, productID, at.Format(time.RFC3339))
+	}
+	return entry, nil
+}
+
+// SchedulePriceChange validates and stores a price change to be applied by
+// the scheduler. A change may not overlap another open temporary change for
+// the same product, since reverting one would undo the other.
+func (s *PricingService) SchedulePriceChange(ctx context.Context, change *domain.ScheduledPriceChange) error {
+	if change.ProductID == "" {
+		return fmt.Errorf("%w: product_id is required", ErrInvalidPriceChange)
+	}
+	if change.Price < 0 {
+		return fmt.Errorf("%w: price must not be negative", ErrInvalidPriceChange)
+	}
+	if change.EffectiveFrom.IsZero() {
+		return fmt.Errorf("%w: effective_from is required", ErrInvalidPriceChange)
+	}
+	if change.EffectiveTo != nil {
+		if !change.EffectiveTo.After(change.EffectiveFrom) {
+			return fmt.Errorf("%w: effective_to must be after effective_from", ErrInvalidPriceChange)
+		}
+		if !change.EffectiveTo.After(s.now()) {
+			return fmt.Errorf("%w: effective_to is in the past", ErrInvalidPriceChange)
+		}
+	}
+
+	change.ID = uuid.New().String()
+	change.Status = domain.PriceChangeStatusPending
+	change.PreviousPrice = nil
+	change.AppliedAt = nil
+	change.RevertedAt = nil
+	change.CreatedAt = s.now()
+	if change.CreatedBy == "" {
+		change.CreatedBy = "system"
+	}
+
+	return s.txManager.WithTx(ctx, func(tx ports.Ports) error {
+		product, err := tx.ProductRepo.GetByID(ctx, change.ProductID)
+		if err != nil {
+			return fmt.Errorf("product %s: %w", change.ProductID, err)
+		}
+
+		open, err := tx.PriceRepo.ListOpenByProduct(ctx, product.ID)
+		if err != nil {
+			return fmt.Errorf("list scheduled changes: %w", err)
+		}
+		for _, other := range open {
+			if priceWindowsOverlap(change, other) {
+				return fmt.Errorf("%w: %s", ErrPriceChangeOverlap, other.ID)
+			}
+		}
+
+		if err := tx.PriceRepo.CreateScheduled(ctx, change); err != nil {
+			return fmt.Errorf("create scheduled change: %w", err)
+		}
+
+		payload := map[string]interface{}{
+			"price_change_id": change.ID,
+			"product_id":      product.ID,
+			"sku":             product.SKU,
+			"price":           change.Price,
+			"effective_from":  change.EffectiveFrom.Format(time.RFC3339),
+			"reason":          change.Reason,
+		}
+		if change.EffectiveTo != nil {
+			payload["effective_to"] = change.EffectiveTo.Format(time.RFC3339)
+		}
+		if err := newTxAuditService(ctx, tx.AuditRepo).LogAction(ctx, "PRICE_CHANGE_SCHEDULED", change.CreatedBy, payload); err != nil {
+			return fmt.Errorf("audit log: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetScheduledChange retrieves a scheduled price change by ID.
+func (s *PricingService) GetScheduledChange(ctx context.Context, id string) (*domain.ScheduledPriceChange, error) {
+	return s.priceRepo.GetScheduled(ctx, id)
+}
+
+// ListScheduledChanges retrieves scheduled price changes, optionally for one
+// product and in one status.
+func (s *PricingService) ListScheduledChanges(ctx context.Context, productID, status string, limit, offset int) ([]*domain.ScheduledPriceChange, error) {
+	return s.priceRepo.ListScheduled(ctx, productID, status, limit, offset)
+}
+
+// CancelScheduledChange cancels a pending change, or ends an active
+// temporary change early by restoring the previous price.
+func (s *PricingService) CancelScheduledChange(ctx context.Context, id, userID string) (*domain.ScheduledPriceChange, error) {
+	if userID == "" {
+		userID = "system"
+	}
+
+	var change *domain.ScheduledPriceChange
+	err := s.txManager.WithTx(ctx, func(tx ports.Ports) error {
+		var err error
+		change, err = tx.PriceRepo.GetScheduled(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		switch change.Status {
+		case domain.PriceChangeStatusPending:
+			change.Status = domain.PriceChangeStatusCancelled
+			if err := tx.PriceRepo.UpdateScheduled(ctx, change); err != nil {
+				return fmt.Errorf("update scheduled change: %w", err)
+			}
+			payload := map[string]interface{}{
+				"price_change_id": change.ID,
+				"product_id":      change.ProductID,
+			}
+			if err := newTxAuditService(ctx, tx.AuditRepo).LogAction(ctx, "PRICE_CHANGE_CANCELLED", userID, payload); err != nil {
+				return fmt.Errorf("audit log: %w", err)
+			}
+			return nil
+		case domain.PriceChangeStatusActive:
+			return revertPriceChange(ctx, tx, change, s.now(), domain.PriceChangeStatusCancelled, userID)
+		default:
+			return fmt.Errorf("%w: %s is %s", ErrPriceChangeClosed, change.ID, change.Status)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+// ApplyDueChanges reverts temporary changes whose window has ended, then
+// applies pending changes whose window has started. Ending windows first lets
+// back-to-back promotions hand over cleanly. Each change is applied in its own
+// transaction so one failure does not hold up the rest; the number of changes
+// applied or reverted is returned with any errors joined.
+func (s *PricingService) ApplyDueChanges(ctx context.Context) (int, error) {
+	now := s.now()
+	processed := 0
+	var errs []error
+
+	ending, err := s.priceRepo.ListDueToEnd(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("list changes due to end: %w", err)
+	}
+	for _, due := range ending {
+		err := s.txManager.WithTx(ctx, func(tx ports.Ports) error {
+			change, err := tx.PriceRepo.GetScheduled(ctx, due.ID)
+			if err != nil {
+				return err
+			}
+			if change.Status != domain.PriceChangeStatusActive {
+				return nil // Cancelled since it was listed
+			}
+			return revertPriceChange(ctx, tx, change, now, domain.PriceChangeStatusCompleted, schedulerUserID)
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("revert price change %s: %w", due.ID, err))
+			continue
+		}
+		processed++
+	}
+
+	starting, err := s.priceRepo.ListDueToStart(ctx, now)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("list changes due to start: %w", err))
+		return processed, errors.Join(errs...)
+	}
+	for _, due := range starting {
+		err := s.txManager.WithTx(ctx, func(tx ports.Ports) error {
+			change, err := tx.PriceRepo.GetScheduled(ctx, due.ID)
+			if err != nil {
+				return err
+			}
+			if change.Status != domain.PriceChangeStatusPending {
+				return nil
+			}
+			return applyPriceChange(ctx, tx, change, now)
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("apply price change %s: %w", due.ID, err))
+			continue
+		}
+		processed++
+	}
+
+	return processed, errors.Join(errs...)
+}
+
+// applyPriceChange sets the product's base price to the scheduled price,
+// remembering the price it replaces. A temporary change whose whole window
+// passed while the scheduler was not running is closed without being applied.
+func applyPriceChange(ctx context.Context, tx ports.Ports, change *domain.ScheduledPriceChange, now time.Time) error {
+	if change.EffectiveTo != nil && !change.EffectiveTo.After(now) {
+		change.Status = domain.PriceChangeStatusCompleted
+		if err := tx.PriceRepo.UpdateScheduled(ctx, change); err != nil {
+			return fmt.Errorf("update scheduled change: %w", err)
+		}
+		payload := map[string]interface{}{
+			"price_change_id": change.ID,
+			"product_id":      change.ProductID,
+			"reason":          "window ended before it was applied",
+		}
+		if err := newTxAuditService(ctx, tx.AuditRepo).LogAction(ctx, "PRICE_CHANGE_SKIPPED", schedulerUserID, payload); err != nil {
+			return fmt.Errorf("audit log: %w", err)
+		}
+		return nil
+	}
+
+	product, err := tx.ProductRepo.GetByID(ctx, change.ProductID)
+	if err != nil {
+		return fmt.Errorf("product %s: %w", change.ProductID, err)
+	}
+
+	previous := product.BasePrice
+	product.BasePrice = change.Price
+	if err := tx.ProductRepo.Update(ctx, product); err != nil {
+		return fmt.Errorf("update product %s: %w", product.ID, err)
+	}
+	// The history records the price from when it was due, not when the
+	// scheduler got to it
+	if previous != change.Price {
+		if err := tx.PriceRepo.SetLatestChangeTime(ctx, product.ID, change.EffectiveFrom); err != nil {
+			return fmt.Errorf("date price history: %w", err)
+		}
+	}
+
+	change.PreviousPrice = &previous
+	change.AppliedAt = &now
+	change.Status = domain.PriceChangeStatusActive
+	if change.EffectiveTo == nil {
+		change.Status = domain.PriceChangeStatusCompleted
+	}
+	if err := tx.PriceRepo.UpdateScheduled(ctx, change); err != nil {
+		return fmt.Errorf("update scheduled change: %w", err)
+	}
+
+	payload := map[string]interface{}{
+		"price_change_id": change.ID,
+		"product_id":      product.ID,
+		"sku":             product.SKU,
+		"old_price":       previous,
+		"new_price":       change.Price,
+	}
+	if err := newTxAuditService(ctx, tx.AuditRepo).LogAction(ctx, "PRICE_CHANGE_APPLIED", schedulerUserID, payload); err != nil {
+		return fmt.Errorf("audit log: %w", err)
+	}
+
+	return nil
+}
+
+// revertPriceChange restores the price an active temporary change replaced
+// and closes the change with the given status. If the price was edited by
+// hand during the window the edit is kept rather than overwritten.
+func revertPriceChange(ctx context.Context, tx ports.Ports, change *domain.ScheduledPriceChange, now time.Time, status, userID string) error {
+	product, err := tx.ProductRepo.GetByID(ctx, change.ProductID)
+	if err != nil {
+		return fmt.Errorf("product %s: %w", change.ProductID, err)
+	}
+
+	restored := change.PreviousPrice != nil && product.BasePrice == change.Price
+	if restored {
+		product.BasePrice = *change.PreviousPrice
+		if err := tx.ProductRepo.Update(ctx, product); err != nil {
+			return fmt.Errorf("update product %s: %w", product.ID, err)
+		}
+		// A window that ran its course ended at effective_to, however late
+		// the scheduler got to it
+		if *change.PreviousPrice != change.Price {
+			changedAt := now
+			if status == domain.PriceChangeStatusCompleted && change.EffectiveTo != nil {
+				changedAt = *change.EffectiveTo
+			}
+			if err := tx.PriceRepo.SetLatestChangeTime(ctx, product.ID, changedAt); err != nil {
+				return fmt.Errorf("date price history: %w", err)
+			}
+		}
+	}
+
+	change.Status = status
+	change.RevertedAt = &now
+	if err := tx.PriceRepo.UpdateScheduled(ctx, change); err != nil {
+		return fmt.Errorf("update scheduled change: %w", err)
+	}
+
+	payload := map[string]interface{}{
+		"price_change_id": change.ID,
+		"product_id":      product.ID,
+		"sku":             product.SKU,
+		"price":           product.BasePrice,
+		"restored":        restored,
+	}
+	if err := newTxAuditService(ctx, tx.AuditRepo).LogAction(ctx, "PRICE_CHANGE_REVERTED", userID, payload); err != nil {
+		return fmt.Errorf("audit log: %w", err)
+	}
+
+	return nil
+}
+
+// priceWindowsOverlap reports whether two scheduled changes conflict. A
+// permanent change takes effect at a single moment, so two permanent changes
+// never conflict, but one may not start inside a temporary window.
+func priceWindowsOverlap(a, b *domain.ScheduledPriceChange) bool {
+	inWindow := func(t time.Time, c *domain.ScheduledPriceChange) bool {
+		return !t.Before(c.EffectiveFrom) && t.Before(*c.EffectiveTo)
+	}
+
+	switch {
+	case a.EffectiveTo == nil && b.EffectiveTo == nil:
+		return false
+	case a.EffectiveTo == nil:
+		return inWindow(a.EffectiveFrom, b)
+	case b.EffectiveTo == nil:
+		return inWindow(b.EffectiveFrom, a)
+	default:
+		return a.EffectiveFrom.Before(*b.EffectiveTo) && b.EffectiveFrom.Before(*a.EffectiveTo)
+	}
+}
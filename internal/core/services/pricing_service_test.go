@@ -0,0 +1,404 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+// --- Mock PriceRepository ---
+
+type mockPriceRepository struct {
+	history   []*domain.PriceHistoryEntry
+	changes   []*domain.ScheduledPriceChange
+	changedAt map[string]time.Time // Latest change time set per product
+}
+
+func (m *mockPriceRepository) ListHistory(_ context.Context, productID string, limit, offset int) ([]*domain.PriceHistoryEntry, error) {
+	var out []*domain.PriceHistoryEntry
+	for i := len(m.history) - 1; i >= 0; i-- {
+		if m.history[i].ProductID == productID {
+			out = append(out, m.history[i])
+		}
+	}
+	if offset >= len(out) {
+		return nil, nil
+	}
+	out = out[offset:]
+	if limit < len(out) {
+		out = out[:limit]
+	}
+	return out, nil
+}
+func (m *mockPriceRepository) GetPriceAt(_ context.Context, productID string, at time.Time) (*domain.PriceHistoryEntry, error) {
+	var found *domain.PriceHistoryEntry
+	for _, h := range m.history {
+		if h.ProductID == productID && !h.ChangedAt.After(at) {
+			found = h
+		}
+	}
+	return found, nil
+}
+func (m *mockPriceRepository) SetLatestChangeTime(_ context.Context, productID string, at time.Time) error {
+	if m.changedAt == nil {
+		m.changedAt = make(map[string]time.Time)
+	}
+	m.changedAt[productID] = at
+	return nil
+}
+func (m *mockPriceRepository) CreateScheduled(_ context.Context, change *domain.ScheduledPriceChange) error {
+	m.changes = append(m.changes, change)
+	return nil
+}
+func (m *mockPriceRepository) GetScheduled(_ context.Context, id string) (*domain.ScheduledPriceChange, error) {
+	for _, c := range m.changes {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return nil, errors.New("scheduled price change not found")
+}
+func (m *mockPriceRepository) ListScheduled(_ context.Context, productID, status string, limit, offset int) ([]*domain.ScheduledPriceChange, error) {
+	var out []*domain.ScheduledPriceChange
+	for _, c := range m.changes {
+		if (productID == "" || c.ProductID == productID) && (status == "" || c.Status == status) {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+func (m *mockPriceRepository) ListOpenByProduct(_ context.Context, productID string) ([]*domain.ScheduledPriceChange, error) {
+	var out []*domain.ScheduledPriceChange
+	for _, c := range m.changes {
+		if c.ProductID == productID && (c.Status == domain.PriceChangeStatusPending || c.Status == domain.PriceChangeStatusActive) {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+func (m *mockPriceRepository) ListDueToStart(_ context.Context, now time.Time) ([]*domain.ScheduledPriceChange, error) {
+	var out []*domain.ScheduledPriceChange
+	for _, c := range m.changes {
+		if c.Status == domain.PriceChangeStatusPending && !c.EffectiveFrom.After(now) {
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].EffectiveFrom.Before(out[j].EffectiveFrom) })
+	return out, nil
+}
+func (m *mockPriceRepository) ListDueToEnd(_ context.Context, now time.Time) ([]*domain.ScheduledPriceChange, error) {
+	var out []*domain.ScheduledPriceChange
+	for _, c := range m.changes {
+		if c.Status == domain.PriceChangeStatusActive && c.EffectiveTo != nil && !c.EffectiveTo.After(now) {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+func (m *mockPriceRepository) UpdateScheduled(_ context.Context, change *domain.ScheduledPriceChange) error {
+	return nil
+}
+func (m *mockPriceRepository) DeleteByProduct(_ context.Context, productID string) error {
+	var history []*domain.PriceHistoryEntry
+	for _, h := range m.history {
+		if h.ProductID != productID {
+			history = append(history, h)
+		}
+	}
+	var changes []*domain.ScheduledPriceChange
+	for _, c := range m.changes {
+		if c.ProductID != productID {
+			changes = append(changes, c)
+		}
+	}
+	m.history, m.changes = history, changes
+	return nil
+}
+
+// pricingTestClock is a settable clock for scheduler tests.
+type pricingTestClock struct {
+	t time.Time
+}
+
+func (c *pricingTestClock) now() time.Time { return c.t }
+
+func newPricingTestService() (*PricingService, *mockSaleTxManager, *pricingTestClock) {
+	txManager := newPackTestTxManager([]*domain.Product{
+		{ID: "p1", Name: "Drill", SKU: "DRILL", BasePrice: 100, Quantity: 5},
+	}, nil)
+	txManager.priceRepo = &mockPriceRepository{}
+
+	clock := &pricingTestClock{t: time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)}
+	svc := NewPricingService(txManager.priceRepo, txManager.productRepo, txManager)
+	svc.now = clock.now
+	return svc, txManager, clock
+}
+
+func timePtr(t time.Time) *time.Time { return &t }
+
+// --- PricingService Tests ---
+
+func TestSchedulePriceChange_Validation(t *testing.T) {
+	svc, _, clock := newPricingTestService()
+	ctx := context.Background()
+
+	cases := []*domain.ScheduledPriceChange{
+		{ProductID: "p1", Price: -1, EffectiveFrom: clock.t},
+		{ProductID: "p1", Price: 80},
+		{ProductID: "p1", Price: 80, EffectiveFrom: clock.t, EffectiveTo: timePtr(clock.t)},
+		{ProductID: "p1", Price: 80, EffectiveFrom: clock.t.Add(-48 * time.Hour), EffectiveTo: timePtr(clock.t.Add(-time.Hour))},
+	}
+	for i, change := range cases {
+		if err := svc.SchedulePriceChange(ctx, change); !errors.Is(err, ErrInvalidPriceChange) {
+			t.Errorf("case %d: expected ErrInvalidPriceChange, got %v", i, err)
+		}
+	}
+
+	if err := svc.SchedulePriceChange(ctx, &domain.ScheduledPriceChange{ProductID: "missing", Price: 1, EffectiveFrom: clock.t}); err == nil {
+		t.Error("expected an error for an unknown product")
+	}
+}
+
+func TestSchedulePriceChange_Overlap(t *testing.T) {
+	svc, _, clock := newPricingTestService()
+	ctx := context.Background()
+	friday := clock.t.Add(4 * 24 * time.Hour)
+	monday := friday.Add(3 * 24 * time.Hour)
+
+	weekend := &domain.ScheduledPriceChange{ProductID: "p1", Price: 79, EffectiveFrom: friday, EffectiveTo: &monday}
+	if err := svc.SchedulePriceChange(ctx, weekend); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if weekend.ID == "" || weekend.Status != domain.PriceChangeStatusPending {
+		t.Fatalf("expected a pending change with an ID, got %+v", weekend)
+	}
+
+	overlapping := &domain.ScheduledPriceChange{ProductID: "p1", Price: 85, EffectiveFrom: friday.Add(24 * time.Hour), EffectiveTo: timePtr(monday.Add(24 * time.Hour))}
+	if err := svc.SchedulePriceChange(ctx, overlapping); !errors.Is(err, ErrPriceChangeOverlap) {
+		t.Fatalf("expected ErrPriceChangeOverlap, got %v", err)
+	}
+	permanentInside := &domain.ScheduledPriceChange{ProductID: "p1", Price: 110, EffectiveFrom: friday.Add(time.Hour)}
+	if err := svc.SchedulePriceChange(ctx, permanentInside); !errors.Is(err, ErrPriceChangeOverlap) {
+		t.Fatalf("expected ErrPriceChangeOverlap for a permanent change inside the window, got %v", err)
+	}
+
+	// Back-to-back windows and a permanent change after the window are fine
+	nextWeek := &domain.ScheduledPriceChange{ProductID: "p1", Price: 90, EffectiveFrom: monday, EffectiveTo: timePtr(monday.Add(24 * time.Hour))}
+	if err := svc.SchedulePriceChange(ctx, nextWeek); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	permanentAfter := &domain.ScheduledPriceChange{ProductID: "p1", Price: 110, EffectiveFrom: monday.Add(48 * time.Hour)}
+	if err := svc.SchedulePriceChange(ctx, permanentAfter); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestApplyDueChanges_TemporaryWindow(t *testing.T) {
+	svc, txManager, clock := newPricingTestService()
+	ctx := context.Background()
+	product := txManager.productRepo.products[0]
+
+	start := clock.t.Add(time.Hour)
+	end := start.Add(48 * time.Hour)
+	promo := &domain.ScheduledPriceChange{ProductID: "p1", Price: 79, EffectiveFrom: start, EffectiveTo: &end}
+	if err := svc.SchedulePriceChange(ctx, promo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n, err := svc.ApplyDueChanges(ctx); err != nil || n != 0 {
+		t.Fatalf("expected nothing due yet, got %d, %v", n, err)
+	}
+
+	clock.t = start
+	if n, err := svc.ApplyDueChanges(ctx); err != nil || n != 1 {
+		t.Fatalf("expected 1 change applied, got %d, %v", n, err)
+	}
+	if product.BasePrice != 79 || promo.Status != domain.PriceChangeStatusActive || *promo.PreviousPrice != 100 {
+		t.Fatalf("expected promo price 79 active over 100, got price %v, %+v", product.BasePrice, promo)
+	}
+
+	clock.t = end
+	if n, err := svc.ApplyDueChanges(ctx); err != nil || n != 1 {
+		t.Fatalf("expected 1 change reverted, got %d, %v", n, err)
+	}
+	if product.BasePrice != 100 || promo.Status != domain.PriceChangeStatusCompleted || promo.RevertedAt == nil {
+		t.Fatalf("expected price restored to 100, got price %v, %+v", product.BasePrice, promo)
+	}
+
+	var actions []string
+	for _, log := range txManager.auditRepo.logs {
+		actions = append(actions, log.Action)
+	}
+	want := []string{"PRICE_CHANGE_SCHEDULED", "PRICE_CHANGE_APPLIED", "PRICE_CHANGE_REVERTED"}
+	if len(actions) != len(want) {
+		t.Fatalf("expected audit actions %v, got %v", want, actions)
+	}
+	for i := range want {
+		if actions[i] != want[i] {
+			t.Fatalf("expected audit actions %v, got %v", want, actions)
+		}
+	}
+}
+
+func TestApplyDueChanges_DatesHistoryWhenDue(t *testing.T) {
+	svc, txManager, clock := newPricingTestService()
+	ctx := context.Background()
+
+	start := clock.t.Add(time.Hour)
+	end := start.Add(24 * time.Hour)
+	promo := &domain.ScheduledPriceChange{ProductID: "p1", Price: 79, EffectiveFrom: start, EffectiveTo: &end}
+	if err := svc.SchedulePriceChange(ctx, promo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The scheduler runs late for both the start and the end
+	clock.t = start.Add(10 * time.Minute)
+	if _, err := svc.ApplyDueChanges(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := txManager.priceRepo.changedAt["p1"]; !got.Equal(start) {
+		t.Errorf("expected the promo price dated %v, got %v", start, got)
+	}
+
+	clock.t = end.Add(3 * time.Hour)
+	if _, err := svc.ApplyDueChanges(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := txManager.priceRepo.changedAt["p1"]; !got.Equal(end) {
+		t.Errorf("expected the restored price dated %v, got %v", end, got)
+	}
+}
+
+func TestApplyDueChanges_KeepsManualEdit(t *testing.T) {
+	svc, txManager, clock := newPricingTestService()
+	ctx := context.Background()
+	product := txManager.productRepo.products[0]
+
+	end := clock.t.Add(24 * time.Hour)
+	promo := &domain.ScheduledPriceChange{ProductID: "p1", Price: 79, EffectiveFrom: clock.t, EffectiveTo: &end}
+	if err := svc.SchedulePriceChange(ctx, promo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.ApplyDueChanges(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Price edited by hand during the promotion
+	product.BasePrice = 75
+
+	clock.t = end
+	if _, err := svc.ApplyDueChanges(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if product.BasePrice != 75 || promo.Status != domain.PriceChangeStatusCompleted {
+		t.Fatalf("expected the manual price 75 kept, got %v, %+v", product.BasePrice, promo)
+	}
+}
+
+func TestApplyDueChanges_PermanentAndMissedWindow(t *testing.T) {
+	svc, txManager, clock := newPricingTestService()
+	ctx := context.Background()
+	product := txManager.productRepo.products[0]
+
+	end := clock.t.Add(2 * time.Hour)
+	missed := &domain.ScheduledPriceChange{ProductID: "p1", Price: 50, EffectiveFrom: clock.t.Add(time.Hour), EffectiveTo: &end}
+	permanent := &domain.ScheduledPriceChange{ProductID: "p1", Price: 120, EffectiveFrom: clock.t.Add(3 * time.Hour)}
+	for _, change := range []*domain.ScheduledPriceChange{missed, permanent} {
+		if err := svc.SchedulePriceChange(ctx, change); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// The scheduler was down for the whole promotion window
+	clock.t = clock.t.Add(4 * time.Hour)
+	if n, err := svc.ApplyDueChanges(ctx); err != nil || n != 2 {
+		t.Fatalf("expected 2 changes processed, got %d, %v", n, err)
+	}
+	if missed.Status != domain.PriceChangeStatusCompleted || missed.AppliedAt != nil {
+		t.Fatalf("expected the missed window closed without applying, got %+v", missed)
+	}
+	if product.BasePrice != 120 || permanent.Status != domain.PriceChangeStatusCompleted {
+		t.Fatalf("expected permanent price 120, got %v, %+v", product.BasePrice, permanent)
+	}
+}
+
+func TestApplyDueChanges_DeletedProduct(t *testing.T) {
+	svc, txManager, clock := newPricingTestService()
+	ctx := context.Background()
+	productSvc := NewProductService(txManager.productRepo, txManager.categoryRepo, NewAuditService(txManager.auditRepo), txManager, &mockSynonymRepository{})
+
+	change := &domain.ScheduledPriceChange{ProductID: "p1", Price: 80, EffectiveFrom: clock.t.Add(time.Hour)}
+	if err := svc.SchedulePriceChange(ctx, change); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := productSvc.DeleteProduct(ctx, "p1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(txManager.priceRepo.changes) != 0 {
+		t.Fatalf("expected the pending change deleted with the product, got %d", len(txManager.priceRepo.changes))
+	}
+
+	// The scheduler has nothing left to apply for the product
+	clock.t = clock.t.Add(2 * time.Hour)
+	if n, err := svc.ApplyDueChanges(ctx); err != nil || n != 0 {
+		t.Fatalf("expected no changes processed, got %d, %v", n, err)
+	}
+}
+
+func TestCancelScheduledChange(t *testing.T) {
+	svc, txManager, clock := newPricingTestService()
+	ctx := context.Background()
+	product := txManager.productRepo.products[0]
+
+	end := clock.t.Add(24 * time.Hour)
+	pending := &domain.ScheduledPriceChange{ProductID: "p1", Price: 60, EffectiveFrom: end.Add(time.Hour)}
+	active := &domain.ScheduledPriceChange{ProductID: "p1", Price: 79, EffectiveFrom: clock.t, EffectiveTo: &end}
+	for _, change := range []*domain.ScheduledPriceChange{pending, active} {
+		if err := svc.SchedulePriceChange(ctx, change); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, err := svc.ApplyDueChanges(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := svc.CancelScheduledChange(ctx, pending.ID, "mgr"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pending.Status != domain.PriceChangeStatusCancelled {
+		t.Fatalf("expected pending change cancelled, got %s", pending.Status)
+	}
+
+	// Cancelling an active promotion ends it early
+	if _, err := svc.CancelScheduledChange(ctx, active.ID, "mgr"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if product.BasePrice != 100 || active.Status != domain.PriceChangeStatusCancelled {
+		t.Fatalf("expected price restored to 100, got %v, %+v", product.BasePrice, active)
+	}
+
+	if _, err := svc.CancelScheduledChange(ctx, active.ID, "mgr"); !errors.Is(err, ErrPriceChangeClosed) {
+		t.Fatalf("expected ErrPriceChangeClosed, got %v", err)
+	}
+}
+
+func TestGetPriceAt(t *testing.T) {
+	svc, txManager, clock := newPricingTestService()
+	ctx := context.Background()
+	txManager.priceRepo.history = []*domain.PriceHistoryEntry{
+		{ID: 1, ProductID: "p1", Price: 90, ChangedAt: clock.t.Add(-72 * time.Hour)},
+		{ID: 2, ProductID: "p1", Price: 100, ChangedAt: clock.t.Add(-24 * time.Hour)},
+	}
+
+	entry, err := svc.GetPriceAt(ctx, "p1", clock.t.Add(-48*time.Hour))
+	if err != nil || entry.Price != 90 {
+		t.Fatalf("expected 90 two days ago, got %+v, %v", entry, err)
+	}
+	if _, err := svc.GetPriceAt(ctx, "p1", clock.t.Add(-96*time.Hour)); !errors.Is(err, ErrNoPriceAt) {
+		t.Fatalf("expected ErrNoPriceAt before the product existed, got %v", err)
+	}
+}
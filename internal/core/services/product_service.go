@@ -3,9 +3,13 @@ package services
 import (
 	"context"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
+	"math"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,40 +23,85 @@ type ProductService struct {
 	categoryRepo ports.CategoryRepository
 	auditSvc     ports.AuditService
 	txManager    ports.TransactionManager
+	synonymRepo  ports.SynonymRepository
 }
 
 // NewProductService creates a new product service instance.
-func NewProductService(productRepo ports.ProductRepository, categoryRepo ports.CategoryRepository, auditSvc ports.AuditService, txManager ports.TransactionManager) *ProductService {
+func NewProductService(productRepo ports.ProductRepository, categoryRepo ports.CategoryRepository, auditSvc ports.AuditService, txManager ports.TransactionManager, synonymRepo ports.SynonymRepository) *ProductService {
 	return &ProductService{
 		productRepo:  productRepo,
 		categoryRepo: categoryRepo,
 		auditSvc:     auditSvc,
 		txManager:    txManager,
+		synonymRepo:  synonymRepo,
 	}
 }
 
-// validateProductProperties fetches the category and validates product properties.
-func (s *ProductService) validateProductProperties(ctx context.Context, product *domain.Product) error {
+// productCategory fetches a product's category with its merged schema, or
+// nil if the product has no category.
+func (s *ProductService) productCategory(ctx context.Context, product *domain.Product) (*domain.Category, error) {
 	if product.CategoryID == "" {
+		return nil, nil
+	}
+	return resolveCategory(ctx, s.categoryRepo, product.CategoryID)
+}
+
+// validateProductProperties validates product properties against the
+// category's merged schema and checks that unique properties are not
+// already used by another product in the category. Every failing property
+// is reported together.
+func validateProductProperties(ctx context.Context, productRepo ports.ProductRepository, category *domain.Category, product *domain.Product) error {
+	if category == nil {
 		return nil
 	}
 
-	category, err := s.categoryRepo.GetByID(ctx, product.CategoryID)
-	if err != nil {
+	var verr *ValidationError
+	if err := ValidateProperties(category, product.Properties); err != nil && !errors.As(err, &verr) {
 		return err
 	}
+	if verr == nil {
+		verr = &ValidationError{}
+	}
+
+	for _, attr := range effectiveAttributes(category) {
+		val, exists := product.Properties[attr.Key]
+		if !attr.Unique || !exists || verr.has(attr.Key) {
+			continue
+		}
+		taken, err := productRepo.ExistsWithProperty(ctx, product.CategoryID, attr.Key, val, product.ID)
+		if err != nil {
+			return fmt.Errorf("unique property %q: %w", attr.Key, err)
+		}
+		if taken {
+			verr.add(attr.Key, "property %q value %v is already used by another product in the category", attr.Key, val)
+		}
+	}
 
-	return ValidateProperties(category, product.Properties)
+	return verr.errOrNil()
 }
 
-// CreateProduct creates a new product and logs the action.
+// CreateProduct creates a new product and logs the action. Properties the
+// product omits are given their attribute's default, and measured
+// properties entered with a unit are converted to their attribute's unit.
 func (s *ProductService) CreateProduct(ctx context.Context, product *domain.Product) error {
+	if err := normalizeUnitOfMeasure(product); err != nil {
+		return err
+	}
+
 	// Validate properties against category blueprint
-	if err := s.validateProductProperties(ctx, product); err != nil {
+	category, err := s.productCategory(ctx, product)
+	if err != nil {
 		return err
 	}
+	product.Properties = applyAttributeDefaults(category, product.Properties)
+	coercePropertyTypes(category, product.Properties)
+	normalizeMeasures(category, product)
+	if err := validateProductProperties(ctx, s.productRepo, category, product); err != nil {
+		return err
+	}
+	product.SearchTerms = searchTerms(category, product)
 
-	err := s.productRepo.Create(ctx, product)
+	err = s.productRepo.Create(ctx, product)
 	if err != nil {
 		return err
 	}
@@ -82,36 +131,343 @@ func (s *ProductService) GetProductBySKU(ctx context.Context, sku string) (*doma
 	return s.productRepo.GetBySKU(ctx, sku)
 }
 
-// ListProducts retrieves all products with pagination.
-func (s *ProductService) ListProducts(ctx context.Context, limit, offset int) ([]*domain.Product, error) {
-	return s.productRepo.List(ctx, limit, offset)
+// productSorts maps each product sort to its default order.
+var productSorts = map[string]string{
+	domain.SortRelevance: domain.SortAsc,
+	domain.SortName:      domain.SortAsc,
+	domain.SortPrice:     domain.SortAsc,
+	domain.SortQuantity:  domain.SortAsc,
+	domain.SortUpdatedAt: domain.SortDesc,
+	domain.SortCreatedAt: domain.SortDesc,
+}
+
+// ListProducts retrieves a page of all products, newest first unless
+// another sort is requested, with the total and the cursors of the pages
+// either side.
+func (s *ProductService) ListProducts(ctx context.Context, page domain.PageRequest) (*domain.ProductPage, error) {
+	return s.SearchProducts(ctx, domain.FilterOptions{PageRequest: page})
+}
+
+// SearchProducts retrieves products matching the given filter options. A
+// CategoryID matches products in that category and every category below it.
+// Property filter keys are validated against the category blueprint, with
+// inherited and descendant attributes, when a CategoryID is provided,
+// preventing SQL injection via JSON paths. Filters are typed by their
+// attribute: numbers are compared as numbers, in any compatible unit for
+// measured attributes, and each type supports its own operators.
+// Results are sorted by relevance when there is a Query and newest first
+// otherwise, unless another sort is requested; relevance needs a Query. The
+// page carries the number of matches and the cursors of the pages either
+// side. A Query in FTS5 syntax is used as given; see resolveSearchText for
+// the other search modes.
+func (s *ProductService) SearchProducts(ctx context.Context, opts domain.FilterOptions) (*domain.ProductPage, error) {
+	opts, err := s.resolveSearchText(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultSort := domain.SortCreatedAt
+	if opts.Query != "" {
+		defaultSort = domain.SortRelevance
+	}
+	page, err := resolvePage(opts.PageRequest, defaultSort, productSorts)
+	if err != nil {
+		return nil, err
+	}
+	if page.Sort == domain.SortRelevance && opts.Query == "" {
+		return nil, fmt.Errorf("%w: sorting by relevance needs a search query", ErrInvalidPage)
+	}
+	opts.PageRequest = page
+
+	attrs, err := s.searchAttributes(ctx, opts.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	opts, err = resolvePropertyFilters(opts, attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.productRepo.Search(ctx, opts, attributeKeys(attrs))
+	if err != nil {
+		return nil, err
+	}
+	setPageCursors(&result.PageInfo, page)
+	return result, nil
+}
+
+// priceFacetBounds are the bounds between the price buckets of search facets.
+var priceFacetBounds = []float64{10, 25, 50, 100, 250, 500}
+
+// FacetProducts counts the products matching the given filter options by
+// category, price bucket and stock and, when a CategoryID is provided, by the
+// values of every select and boolean attribute that can be filtered on.
+// Every option of a select attribute is listed, including those no matching
+// product has.
+func (s *ProductService) FacetProducts(ctx context.Context, opts domain.FilterOptions) (*domain.SearchFacets, error) {
+	opts, err := s.resolveSearchText(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	attrs, err := s.searchAttributes(ctx, opts.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	opts, err = resolvePropertyFilters(opts, attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	var faceted []domain.AttributeDefinition
+	request := domain.FacetRequest{PriceBounds: priceFacetBounds}
+	seen := make(map[string]bool)
+	for _, attr := range attrs {
+		if seen[attr.Key] || (attr.Type != "select" && attr.Type != "boolean") {
+			continue
+		}
+		seen[attr.Key] = true
+		faceted = append(faceted, attr)
+		request.AttributeKeys = append(request.AttributeKeys, attr.Key)
+	}
+
+	facets, err := s.productRepo.CountFacets(ctx, opts, attributeKeys(attrs), request)
+	if err != nil {
+		return nil, fmt.Errorf("count facets: %w", err)
+	}
+
+	counted := make(map[string][]domain.FacetValue, len(facets.Attributes))
+	for _, facet := range facets.Attributes {
+		counted[facet.Key] = facet.Values
+	}
+	facets.Attributes = make([]domain.AttributeFacet, 0, len(faceted))
+	for _, attr := range faceted {
+		facets.Attributes = append(facets.Attributes, domain.AttributeFacet{
+			Key:    attr.Key,
+			Type:   attr.Type,
+			Values: facetValues(attr, counted[attr.Key]),
+		})
+	}
+	return facets, nil
+}
+
+// maxSuggestions caps the products suggested for a search.
+const maxSuggestions = 50
+
+// SuggestProducts offers up to limit products, 10 by default, whose name or
+// SKU starts with the words typed so far, best match first. Synonyms apply,
+// and when too few products match the rest are filled with likely
+// misspellings.
+func (s *ProductService) SuggestProducts(ctx context.Context, query string, limit int) ([]domain.ProductSuggestion, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > maxSuggestions {
+		limit = maxSuggestions
+	}
+	words := searchWords(query)
+	if len(words) == 0 {
+		return []domain.ProductSuggestion{}, nil
+	}
+
+	synonyms, err := s.synonymIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	suggestions, err := s.productRepo.Suggest(ctx, matchExpression(words, synonyms, true), limit)
+	if err != nil {
+		return nil, fmt.Errorf("suggest products: %w", err)
+	}
+	if len(suggestions) >= limit {
+		return suggestions, nil
+	}
+
+	fuzzy, err := s.fuzzyMatches(ctx, words, limit)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(suggestions))
+	for _, suggestion := range suggestions {
+		seen[suggestion.ID] = true
+	}
+	for _, suggestion := range fuzzy {
+		if len(suggestions) == limit {
+			break
+		}
+		if !seen[suggestion.ID] {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	return suggestions, nil
 }
 
-// SearchProducts retrieves products matching the given filter options.
-// Property filter keys are validated against the category blueprint when a
-// CategoryID is provided, preventing SQL injection via JSON paths.
-func (s *ProductService) SearchProducts(ctx context.Context, opts domain.FilterOptions) ([]*domain.Product, error) {
-	var allowedKeys []string
-	if opts.CategoryID != "" {
-		category, err := s.categoryRepo.GetByID(ctx, opts.CategoryID)
+// resolveSearchText turns a plain-text search into an FTS5 query. Text,
+// prefix and fuzzy searches keep only the letters and digits of the query,
+// require every word, and match a phrase with synonyms by any of them.
+// Prefix searches match words by their start, and fuzzy searches also match
+// products whose name or SKU looks like a misspelling of the query. A query
+// without letters or digits does not filter by text.
+func (s *ProductService) resolveSearchText(ctx context.Context, opts domain.FilterOptions) (domain.FilterOptions, error) {
+	switch opts.Mode {
+	case "", domain.SearchModeFTS:
+		return opts, nil
+	case domain.SearchModeText, domain.SearchModePrefix, domain.SearchModeFuzzy:
+	default:
+		return opts, fmt.Errorf("%w: unknown mode %q (expected %s, %s, %s or %s)", ErrInvalidSearch, opts.Mode,
+			domain.SearchModeFTS, domain.SearchModeText, domain.SearchModePrefix, domain.SearchModeFuzzy)
+	}
+
+	words := searchWords(opts.Query)
+	synonyms, err := s.synonymIndex(ctx)
+	if err != nil {
+		return opts, err
+	}
+	opts.Query = matchExpression(words, synonyms, opts.Mode == domain.SearchModePrefix)
+
+	if opts.Mode == domain.SearchModeFuzzy && opts.Query != "" {
+		fuzzy, err := s.fuzzyMatches(ctx, words, fuzzyMatchLimit)
 		if err != nil {
-			return nil, fmt.Errorf("category lookup: %w", err)
+			return opts, err
+		}
+		for _, match := range fuzzy {
+			opts.FuzzyIDs = append(opts.FuzzyIDs, match.ID)
+		}
+	}
+	opts.Mode = domain.SearchModeFTS
+	return opts, nil
+}
+
+// synonymIndex loads the search synonyms.
+func (s *ProductService) synonymIndex(ctx context.Context) (synonymIndex, error) {
+	synonyms, err := s.synonymRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list synonyms: %w", err)
+	}
+	return newSynonymIndex(synonyms), nil
+}
+
+// fuzzyMatches returns up to limit products whose name or SKU is alike
+// enough to the searched words to be a misspelling of them, most alike
+// first.
+func (s *ProductService) fuzzyMatches(ctx context.Context, words []string, limit int) ([]domain.ProductSuggestion, error) {
+	match := trigramMatch(words)
+	if match == "" {
+		return nil, nil
+	}
+	candidates, err := s.productRepo.MatchTrigrams(ctx, match, fuzzyCandidateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("match trigrams: %w", err)
+	}
+
+	scores := make(map[string]float64, len(candidates))
+	var matches []domain.ProductSuggestion
+	for _, candidate := range candidates {
+		score := math.Max(textSimilarity(words, candidate.Name), textSimilarity(words, candidate.SKU))
+		if score < fuzzyThreshold {
+			continue
 		}
-		for _, attr := range category.AttributeDefinitions {
-			allowedKeys = append(allowedKeys, attr.Key)
+		candidate.Fuzzy = true
+		scores[candidate.ID] = score
+		matches = append(matches, candidate)
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return scores[matches[i].ID] > scores[matches[j].ID]
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// searchAttributes returns the attribute definitions a search of a category
+// can filter on: the category's own, those it inherits and those of the
+// categories below it. There are none without a category.
+func (s *ProductService) searchAttributes(ctx context.Context, categoryID string) ([]domain.AttributeDefinition, error) {
+	return categorySearchAttributes(ctx, s.categoryRepo, categoryID)
+}
+
+// categorySearchAttributes is searchAttributes for callers holding only a
+// category repository.
+func categorySearchAttributes(ctx context.Context, categoryRepo ports.CategoryRepository, categoryID string) ([]domain.AttributeDefinition, error) {
+	if categoryID == "" {
+		return nil, nil
+	}
+	category, err := resolveCategory(ctx, categoryRepo, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("category lookup: %w", err)
+	}
+	descendants, err := categoryRepo.ListDescendants(ctx, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("category lookup: %w", err)
+	}
+
+	attrs := append([]domain.AttributeDefinition{}, category.EffectiveAttributes...)
+	for _, d := range descendants {
+		attrs = append(attrs, d.AttributeDefinitions...)
+	}
+	return attrs, nil
+}
+
+// attributeKeys returns the keys of attribute definitions.
+func attributeKeys(attrs []domain.AttributeDefinition) []string {
+	keys := make([]string, 0, len(attrs))
+	for _, attr := range attrs {
+		keys = append(keys, attr.Key)
+	}
+	return keys
+}
+
+// facetValues orders an attribute's counted values: a select attribute's
+// options as defined, or true then false for a boolean, each listed even
+// without products, followed by any other values products hold.
+func facetValues(attr domain.AttributeDefinition, counted []domain.FacetValue) []domain.FacetValue {
+	defined := attr.Options
+	if attr.Type == "boolean" {
+		defined = []string{"true", "false"}
+	}
+
+	counts := make(map[string]int, len(counted))
+	for _, v := range counted {
+		counts[v.Value] = v.Count
+	}
+	values := make([]domain.FacetValue, 0, len(defined)+len(counted))
+	for _, option := range defined {
+		values = append(values, domain.FacetValue{Value: option, Count: counts[option]})
+	}
+	for _, v := range counted {
+		if !contains(defined, v.Value) {
+			values = append(values, v)
 		}
 	}
-	return s.productRepo.Search(ctx, opts, allowedKeys)
+	return values
 }
 
-// UpdateProduct updates a product and logs the action.
+// UpdateProduct updates a product and logs the action. Measured properties
+// entered with a unit are converted to their attribute's unit. A unit of
+// measure or precision that the product's stock does not fit is rejected
+// rather than rounding the stock.
 func (s *ProductService) UpdateProduct(ctx context.Context, product *domain.Product) error {
+	stock := product.Quantity
+	if err := normalizeUnitOfMeasure(product); err != nil {
+		return err
+	}
+	if !fitsPrecision(stock, product.QuantityPrecision) || !fitsPrecision(product.QuarantinedQuantity, product.QuantityPrecision) {
+		return fmt.Errorf("%w: stock of %v (%v quarantined) does not fit %d decimal places",
+			ErrInvalidUnitOfMeasure, stock, product.QuarantinedQuantity, product.QuantityPrecision)
+	}
+
 	// Validate properties against category blueprint
-	if err := s.validateProductProperties(ctx, product); err != nil {
+	category, err := s.productCategory(ctx, product)
+	if err != nil {
+		return err
+	}
+	coercePropertyTypes(category, product.Properties)
+	normalizeMeasures(category, product)
+	if err := validateProductProperties(ctx, s.productRepo, category, product); err != nil {
 		return err
 	}
+	product.SearchTerms = searchTerms(category, product)
 
-	err := s.productRepo.Update(ctx, product)
+	err = s.productRepo.Update(ctx, product)
 	if err != nil {
 		return err
 	}
@@ -130,9 +486,48 @@ func (s *ProductService) UpdateProduct(ctx context.Context, product *domain.Prod
 	return nil
 }
 
-// DeleteProduct deletes a product and logs the action.
+// DeleteProduct deletes a product together with its barcodes and packs, so
+// their codes can be assigned again, and with its price history, scheduled
+// price changes, price list rules, cost layers and associations, and logs
+// the action. The rows are deleted here rather than left to ON DELETE
+// CASCADE, which SQLite does not enforce without foreign keys turned on.
 func (s *ProductService) DeleteProduct(ctx context.Context, id string) error {
-	err := s.productRepo.Delete(ctx, id)
+	err := s.txManager.WithTx(ctx, func(tx ports.Ports) error {
+		barcodes, err := tx.BarcodeRepo.ListByProduct(ctx, id)
+		if err != nil {
+			return err
+		}
+		for _, barcode := range barcodes {
+			if err := tx.BarcodeRepo.Delete(ctx, id, barcode.GTIN); err != nil {
+				return fmt.Errorf("barcode %s: %w", barcode.Code, err)
+			}
+		}
+
+		packs, err := tx.PackRepo.ListByProduct(ctx, id)
+		if err != nil {
+			return err
+		}
+		for _, pack := range packs {
+			if err := tx.PackRepo.Delete(ctx, pack.ID); err != nil {
+				return fmt.Errorf("pack %s: %w", pack.ID, err)
+			}
+		}
+
+		if err := tx.PriceRepo.DeleteByProduct(ctx, id); err != nil {
+			return fmt.Errorf("price history: %w", err)
+		}
+		if err := tx.PriceListRepo.DeleteRulesByProduct(ctx, id); err != nil {
+			return fmt.Errorf("price list rules: %w", err)
+		}
+		if err := tx.CostLayerRepo.DeleteByProduct(ctx, id); err != nil {
+			return fmt.Errorf("cost layers: %w", err)
+		}
+		if err := tx.AssociationRepo.DeleteByProduct(ctx, id); err != nil {
+			return fmt.Errorf("associations: %w", err)
+		}
+
+		return tx.ProductRepo.Delete(ctx, id)
+	})
 	if err != nil {
 		return err
 	}
@@ -150,15 +545,82 @@ func (s *ProductService) DeleteProduct(ctx context.Context, id string) error {
 	return nil
 }
 
+// RebuildSearchIndex brings every product's searchable attribute values up to
+// date with its category's attribute definitions and rebuilds the full-text
+// indexes, returning the number of products whose values changed. Run it
+// after marking attributes searchable outside a category update, or to
+// repair the indexes.
+func (s *ProductService) RebuildSearchIndex(ctx context.Context) (int, error) {
+	var reindexed int
+	err := s.txManager.WithTx(ctx, func(tx ports.Ports) error {
+		products, err := categoryProducts(ctx, tx.ProductRepo, "")
+		if err != nil {
+			return err
+		}
+
+		categories := make(map[string]*domain.Category)
+		for _, product := range products {
+			category, resolved := categories[product.CategoryID]
+			if !resolved && product.CategoryID != "" {
+				if category, err = resolveCategory(ctx, tx.CategoryRepo, product.CategoryID); err != nil {
+					return fmt.Errorf("product %s: %w", product.ID, err)
+				}
+				categories[product.CategoryID] = category
+			}
+
+			terms := searchTerms(category, product)
+			if terms == product.SearchTerms {
+				continue
+			}
+			product.SearchTerms = terms
+			if err := tx.ProductRepo.Update(ctx, product); err != nil {
+				return fmt.Errorf("update product %s: %w", product.ID, err)
+			}
+			reindexed++
+		}
+
+		if err := tx.ProductRepo.RebuildSearchIndex(ctx); err != nil {
+			return err
+		}
+
+		payload := map[string]interface{}{
+			"action":    "rebuild_search_index",
+			"reindexed": reindexed,
+		}
+		if err := newTxAuditService(ctx, tx.AuditRepo).LogAction(ctx, "REBUILD_SEARCH_INDEX", "system", payload); err != nil {
+			return fmt.Errorf("audit log: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return reindexed, nil
+}
+
+// importColumns are CSV columns mapped to product fields rather than properties.
+var importColumns = map[string]bool{
+	"name":               true,
+	"sku":                true,
+	"base_price":         true,
+	"quantity":           true,
+	"cost_price":         true,
+	"unit_of_measure":    true,
+	"quantity_precision": true,
+	"barcode":            true,
+	"description":        true,
+}
+
 // ImportProducts imports products from a CSV reader within a single transaction.
 // CSV must have a header row. The columns "name", "sku", and "base_price" are required.
+// An optional "barcode" column assigns GTINs, several separated by "|".
 // Additional columns are mapped to product properties using the header as the key.
 func (s *ProductService) ImportProducts(ctx context.Context, categoryID string, csvReader io.Reader) (int, error) {
 	// Fetch category once (outside the transaction) for validation.
 	var category *domain.Category
 	if categoryID != "" {
 		var err error
-		category, err = s.categoryRepo.GetByID(ctx, categoryID)
+		category, err = resolveCategory(ctx, s.categoryRepo, categoryID)
 		if err != nil {
 			return 0, fmt.Errorf("category lookup: %w", err)
 		}
@@ -233,16 +695,25 @@ func (s *ProductService) ImportProducts(ctx context.Context, categoryID string,
 			// Build properties from extra columns
 			properties := make(map[string]interface{})
 			for header, idx := range colIndex {
-				if header == "name" || header == "sku" || header == "base_price" || header == "quantity" || header == "cost_price" {
+				if importColumns[header] {
 					continue
 				}
 				properties[header] = row[idx]
 			}
 
+			// Multiselect values are separated by "|"
+			if category != nil {
+				for _, attr := range effectiveAttributes(category) {
+					if val, ok := properties[attr.Key].(string); ok && attr.Type == "multiselect" {
+						properties[attr.Key] = splitImportList(val)
+					}
+				}
+			}
+
 			// Parse optional quantity column
-			var quantity int
+			var quantity float64
 			if qIdx, ok := colIndex["quantity"]; ok && row[qIdx] != "" {
-				q, err := strconv.Atoi(row[qIdx])
+				q, err := strconv.ParseFloat(row[qIdx], 64)
 				if err != nil {
 					return fmt.Errorf("CSV line %d: invalid quantity %q: %w", lineNum+2, row[qIdx], err)
 				}
@@ -259,11 +730,18 @@ func (s *ProductService) ImportProducts(ctx context.Context, categoryID string,
 				costPrice = cp
 			}
 
-			// Validate against category blueprint
-			if category != nil {
-				if err := ValidateProperties(category, properties); err != nil {
-					return fmt.Errorf("CSV line %d: %w", lineNum+2, err)
+			// Parse optional unit of measure columns
+			var unitOfMeasure string
+			if uIdx, ok := colIndex["unit_of_measure"]; ok {
+				unitOfMeasure = row[uIdx]
+			}
+			precision := domain.DefaultQuantityPrecision
+			if pIdx, ok := colIndex["quantity_precision"]; ok && row[pIdx] != "" {
+				p, err := strconv.Atoi(row[pIdx])
+				if err != nil {
+					return fmt.Errorf("CSV line %d: invalid quantity_precision %q: %w", lineNum+2, row[pIdx], err)
 				}
+				precision = p
 			}
 
 			now := time.Now()
@@ -278,12 +756,53 @@ func (s *ProductService) ImportProducts(ctx context.Context, categoryID string,
 				Properties: properties,
 				CreatedAt:  now,
 				UpdatedAt:  now,
+
+				UnitOfMeasure:     unitOfMeasure,
+				QuantityPrecision: precision,
 			}
+			if dIdx, ok := colIndex["description"]; ok {
+				product.Description = row[dIdx]
+			}
+			if err := normalizeUnitOfMeasure(product); err != nil {
+				return fmt.Errorf("CSV line %d: %w", lineNum+2, err)
+			}
+
+			// Validate against category blueprint, including products
+			// imported earlier in the file for unique properties
+			product.Properties = applyAttributeDefaults(category, product.Properties)
+			coercePropertyTypes(category, product.Properties)
+			normalizeMeasures(category, product)
+			if err := validateProductProperties(ctx, tx.ProductRepo, category, product); err != nil {
+				return fmt.Errorf("CSV line %d: %w", lineNum+2, err)
+			}
+			product.SearchTerms = searchTerms(category, product)
 
 			if err := tx.ProductRepo.Create(ctx, product); err != nil {
 				return fmt.Errorf("CSV line %d: insert product: %w", lineNum+2, err)
 			}
 
+			// Assign optional barcodes, several separated by "|"
+			if bIdx, ok := colIndex["barcode"]; ok {
+				for _, code := range splitImportList(row[bIdx]) {
+					format, err := ValidateGTIN(code)
+					if err != nil {
+						return fmt.Errorf("CSV line %d: %w", lineNum+2, err)
+					}
+					if err := checkBarcodeAvailable(ctx, tx, code); err != nil {
+						return fmt.Errorf("CSV line %d: %w", lineNum+2, err)
+					}
+					if err := tx.BarcodeRepo.Create(ctx, &domain.ProductBarcode{
+						Code:      code,
+						GTIN:      NormalizeGTIN(code),
+						ProductID: product.ID,
+						Format:    format,
+						CreatedAt: now,
+					}); err != nil {
+						return fmt.Errorf("CSV line %d: insert barcode: %w", lineNum+2, err)
+					}
+				}
+			}
+
 			// Create audit log inside the same transaction
 			txAuditSvc := NewAuditService(tx.AuditRepo)
 			txAuditSvc.SetPrevHash(prevHash)
@@ -308,3 +827,15 @@ func (s *ProductService) ImportProducts(ctx context.Context, categoryID string,
 
 	return imported, nil
 }
+
+// splitImportList splits a CSV cell holding several values separated by "|",
+// dropping blanks.
+func splitImportList(cell string) []string {
+	var values []string
+	for _, v := range strings.Split(cell, "|") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -14,6 +15,7 @@ import (
 
 type mockProductRepository struct {
 	products []*domain.Product
+	rebuilt  int // Search index rebuilds
 }
 
 func (m *mockProductRepository) Create(_ context.Context, product *domain.Product) error {
@@ -31,7 +33,7 @@ func (m *mockProductRepository) GetByID(_ context.Context, id string) (*domain.P
 			return p, nil
 		}
 	}
-	return nil, errors.New("product not found")
+	return nil, fmt.Errorf("product %w", ports.ErrNotFound)
 }
 func (m *mockProductRepository) GetBySKU(_ context.Context, sku string) (*domain.Product, error) {
 	for _, p := range m.products {
@@ -41,11 +43,29 @@ func (m *mockProductRepository) GetBySKU(_ context.Context, sku string) (*domain
 	}
 	return nil, errors.New("product not found")
 }
-func (m *mockProductRepository) List(_ context.Context, _, _ int) ([]*domain.Product, error) {
-	return m.products, nil
+func (m *mockProductRepository) Search(_ context.Context, _ domain.FilterOptions, _ []string) (*domain.ProductPage, error) {
+	return &domain.ProductPage{Products: m.products, PageInfo: domain.PageInfo{Total: len(m.products)}}, nil
+}
+func (m *mockProductRepository) Suggest(_ context.Context, _ string, _ int) ([]domain.ProductSuggestion, error) {
+	return nil, nil
+}
+func (m *mockProductRepository) MatchTrigrams(_ context.Context, _ string, _ int) ([]domain.ProductSuggestion, error) {
+	return nil, nil
 }
-func (m *mockProductRepository) Search(_ context.Context, _ domain.FilterOptions, _ []string) ([]*domain.Product, error) {
-	return m.products, nil
+func (m *mockProductRepository) RebuildSearchIndex(_ context.Context) error {
+	m.rebuilt++
+	return nil
+}
+func (m *mockProductRepository) CountFacets(_ context.Context, _ domain.FilterOptions, _ []string, _ domain.FacetRequest) (*domain.SearchFacets, error) {
+	return &domain.SearchFacets{Total: len(m.products)}, nil
+}
+func (m *mockProductRepository) ExistsWithProperty(_ context.Context, categoryID, key string, value interface{}, excludeID string) (bool, error) {
+	for _, p := range m.products {
+		if p.CategoryID == categoryID && p.ID != excludeID && p.Properties[key] == value {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 func (m *mockProductRepository) GetInventorySummary(_ context.Context) (*domain.InventorySummary, error) {
 	return &domain.InventorySummary{}, nil
@@ -59,10 +79,20 @@ func (m *mockProductRepository) Update(_ context.Context, product *domain.Produc
 	}
 	return errors.New("product not found")
 }
-func (m *mockProductRepository) Delete(_ context.Context, _ string) error          { return nil }
+func (m *mockProductRepository) Delete(_ context.Context, id string) error {
+	for i, p := range m.products {
+		if p.ID == id {
+			m.products = append(m.products[:i], m.products[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
 
 type mockCategoryRepository struct {
 	categories map[string]*domain.Category
+	versions   []*domain.CategoryVersion
+	references map[string]int
 }
 
 func (m *mockCategoryRepository) Create(_ context.Context, c *domain.Category) error {
@@ -72,17 +102,70 @@ func (m *mockCategoryRepository) Create(_ context.Context, c *domain.Category) e
 func (m *mockCategoryRepository) GetByID(_ context.Context, id string) (*domain.Category, error) {
 	c, ok := m.categories[id]
 	if !ok {
-		return nil, errors.New("category not found")
+		return nil, fmt.Errorf("category %w", ports.ErrNotFound)
 	}
 	return c, nil
 }
-func (m *mockCategoryRepository) List(_ context.Context, _, _ int) ([]*domain.Category, error) {
+func (m *mockCategoryRepository) List(_ context.Context, _ domain.PageRequest) (*domain.CategoryPage, error) {
 	var out []*domain.Category
 	for _, c := range m.categories {
 		out = append(out, c)
 	}
+	return &domain.CategoryPage{Categories: out, PageInfo: domain.PageInfo{Total: len(out)}}, nil
+}
+func (m *mockCategoryRepository) Update(_ context.Context, c *domain.Category) error {
+	if _, ok := m.categories[c.ID]; !ok {
+		return fmt.Errorf("category %w", ports.ErrNotFound)
+	}
+	m.categories[c.ID] = c
+	m.versions = append(m.versions, &domain.CategoryVersion{CategoryID: c.ID, Version: c.Version, Name: c.Name, AttributeDefinitions: c.AttributeDefinitions})
+	return nil
+}
+func (m *mockCategoryRepository) Delete(_ context.Context, id string) error {
+	if _, ok := m.categories[id]; !ok {
+		return fmt.Errorf("category %w", ports.ErrNotFound)
+	}
+	delete(m.categories, id)
+	return nil
+}
+func (m *mockCategoryRepository) ListVersions(_ context.Context, categoryID string) ([]*domain.CategoryVersion, error) {
+	var out []*domain.CategoryVersion
+	for i := len(m.versions) - 1; i >= 0; i-- {
+		if m.versions[i].CategoryID == categoryID {
+			out = append(out, m.versions[i])
+		}
+	}
+	return out, nil
+}
+func (m *mockCategoryRepository) ListAncestors(_ context.Context, id string) ([]*domain.Category, error) {
+	c, ok := m.categories[id]
+	if !ok {
+		return nil, fmt.Errorf("category %w", ports.ErrNotFound)
+	}
+	chain := []*domain.Category{c}
+	for parent, ok := m.categories[c.ParentID]; ok; parent, ok = m.categories[parent.ParentID] {
+		chain = append([]*domain.Category{parent}, chain...)
+	}
+	return chain, nil
+}
+func (m *mockCategoryRepository) ListDescendants(_ context.Context, id string) ([]*domain.Category, error) {
+	var out []*domain.Category
+	parents := map[string]bool{id: true}
+	for len(parents) > 0 {
+		next := make(map[string]bool)
+		for _, c := range m.categories {
+			if parents[c.ParentID] {
+				out = append(out, c)
+				next[c.ID] = true
+			}
+		}
+		parents = next
+	}
 	return out, nil
 }
+func (m *mockCategoryRepository) CountReferences(_ context.Context, id string) (int, error) {
+	return m.references[id], nil
+}
 
 type mockAuditLogRepository struct {
 	logs []*domain.AuditLog
@@ -99,26 +182,52 @@ func (m *mockAuditLogRepository) GetLastLog(_ context.Context) (*domain.AuditLog
 	}
 	return m.logs[len(m.logs)-1], nil
 }
-func (m *mockAuditLogRepository) List(_ context.Context, _, _ int) ([]*domain.AuditLog, error) {
-	return m.logs, nil
+func (m *mockAuditLogRepository) List(_ context.Context, _ domain.PageRequest) (*domain.AuditLogPage, error) {
+	return &domain.AuditLogPage{Logs: m.logs, PageInfo: domain.PageInfo{Total: len(m.logs)}}, nil
 }
 func (m *mockAuditLogRepository) VerifyChain(_ context.Context) (bool, error) {
 	return true, nil
 }
 
 type mockTransactionManager struct {
-	productRepo  *mockProductRepository
-	categoryRepo *mockCategoryRepository
-	auditRepo    *mockAuditLogRepository
-	saleRepo     ports.SaleRepository
+	productRepo     *mockProductRepository
+	categoryRepo    *mockCategoryRepository
+	auditRepo       *mockAuditLogRepository
+	saleRepo        ports.SaleRepository
+	recallRepo      ports.RecallRepository
+	packRepo        ports.PackRepository
+	barcodeRepo     ports.BarcodeRepository
+	priceRepo       ports.PriceRepository
+	priceListRepo   ports.PriceListRepository
+	costLayerRepo   ports.CostLayerRepository
+	associationRepo ports.AssociationRepository
 }
 
 func (m *mockTransactionManager) WithTx(_ context.Context, fn func(tx ports.Ports) error) error {
+	if m.priceRepo == nil {
+		m.priceRepo = &mockPriceRepository{}
+	}
+	if m.priceListRepo == nil {
+		m.priceListRepo = &mockPriceListRepository{}
+	}
+	if m.costLayerRepo == nil {
+		m.costLayerRepo = &mockCostLayerRepository{}
+	}
+	if m.associationRepo == nil {
+		m.associationRepo = &mockAssociationRepository{}
+	}
 	txPorts := ports.Ports{
-		ProductRepo:  m.productRepo,
-		CategoryRepo: m.categoryRepo,
-		AuditRepo:    m.auditRepo,
-		SaleRepo:     m.saleRepo,
+		ProductRepo:     m.productRepo,
+		CategoryRepo:    m.categoryRepo,
+		AuditRepo:       m.auditRepo,
+		SaleRepo:        m.saleRepo,
+		RecallRepo:      m.recallRepo,
+		PackRepo:        m.packRepo,
+		BarcodeRepo:     m.barcodeRepo,
+		PriceRepo:       m.priceRepo,
+		PriceListRepo:   m.priceListRepo,
+		CostLayerRepo:   m.costLayerRepo,
+		AssociationRepo: m.associationRepo,
 	}
 	return fn(txPorts)
 }
@@ -132,7 +241,7 @@ func TestImportProducts_BasicCSV(t *testing.T) {
 	auditSvc := NewAuditService(auditRepo)
 	txManager := &mockTransactionManager{productRepo: productRepo, categoryRepo: categoryRepo, auditRepo: auditRepo}
 
-	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager)
+	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager, &mockSynonymRepository{})
 
 	csv := "name,sku,base_price\nWidget A,SKU-001,9.99\nWidget B,SKU-002,19.99\n"
 	count, err := svc.ImportProducts(context.Background(), "", strings.NewReader(csv))
@@ -165,7 +274,7 @@ func TestImportProducts_WithCategoryValidation(t *testing.T) {
 	auditSvc := NewAuditService(auditRepo)
 	txManager := &mockTransactionManager{productRepo: productRepo, categoryRepo: categoryRepo, auditRepo: auditRepo}
 
-	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager)
+	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager, &mockSynonymRepository{})
 
 	// CSV includes the voltage column
 	csv := "name,sku,base_price,voltage\nWire,SKU-100,5.00,220V\n"
@@ -193,7 +302,7 @@ func TestImportProducts_CategoryValidationFails(t *testing.T) {
 	auditSvc := NewAuditService(auditRepo)
 	txManager := &mockTransactionManager{productRepo: productRepo, categoryRepo: categoryRepo, auditRepo: auditRepo}
 
-	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager)
+	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager, &mockSynonymRepository{})
 
 	// CSV missing required "voltage" column
 	csv := "name,sku,base_price\nWire,SKU-100,5.00\n"
@@ -213,7 +322,7 @@ func TestImportProducts_MissingRequiredColumn(t *testing.T) {
 	auditSvc := NewAuditService(auditRepo)
 	txManager := &mockTransactionManager{productRepo: productRepo, categoryRepo: categoryRepo, auditRepo: auditRepo}
 
-	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager)
+	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager, &mockSynonymRepository{})
 
 	// Missing "sku" column
 	csv := "name,base_price\nWidget,9.99\n"
@@ -233,7 +342,7 @@ func TestImportProducts_InvalidBasePrice(t *testing.T) {
 	auditSvc := NewAuditService(auditRepo)
 	txManager := &mockTransactionManager{productRepo: productRepo, categoryRepo: categoryRepo, auditRepo: auditRepo}
 
-	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager)
+	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager, &mockSynonymRepository{})
 
 	csv := "name,sku,base_price\nWidget,SKU-001,not_a_number\n"
 	_, err := svc.ImportProducts(context.Background(), "", strings.NewReader(csv))
@@ -252,7 +361,7 @@ func TestImportProducts_EmptyCSV(t *testing.T) {
 	auditSvc := NewAuditService(auditRepo)
 	txManager := &mockTransactionManager{productRepo: productRepo, categoryRepo: categoryRepo, auditRepo: auditRepo}
 
-	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager)
+	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager, &mockSynonymRepository{})
 
 	// Header only, no data rows
 	csv := "name,sku,base_price\n"
@@ -272,7 +381,7 @@ func TestImportProducts_AuditChainSequential(t *testing.T) {
 	auditSvc := NewAuditService(auditRepo)
 	txManager := &mockTransactionManager{productRepo: productRepo, categoryRepo: categoryRepo, auditRepo: auditRepo}
 
-	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager)
+	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager, &mockSynonymRepository{})
 
 	csv := "name,sku,base_price\nA,SKU-A,1.00\nB,SKU-B,2.00\nC,SKU-C,3.00\n"
 	count, err := svc.ImportProducts(context.Background(), "", strings.NewReader(csv))
@@ -299,7 +408,7 @@ func TestImportProducts_CategoryNotFound(t *testing.T) {
 	auditSvc := NewAuditService(auditRepo)
 	txManager := &mockTransactionManager{productRepo: productRepo, categoryRepo: categoryRepo, auditRepo: auditRepo}
 
-	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager)
+	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager, &mockSynonymRepository{})
 
 	csv := "name,sku,base_price\nWidget,SKU-001,9.99\n"
 	_, err := svc.ImportProducts(context.Background(), "nonexistent-cat", strings.NewReader(csv))
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+// filterOperators lists the operators each attribute type supports.
+var filterOperators = map[string][]string{
+	"number":      {domain.FilterEq, domain.FilterGt, domain.FilterGte, domain.FilterLt, domain.FilterLte, domain.FilterBetween},
+	"date":        {domain.FilterEq, domain.FilterGt, domain.FilterGte, domain.FilterLt, domain.FilterLte, domain.FilterBetween},
+	"string":      {domain.FilterEq, domain.FilterIn, domain.FilterPrefix, domain.FilterContains},
+	"select":      {domain.FilterEq, domain.FilterIn},
+	"multiselect": {domain.FilterEq, domain.FilterIn},
+	"boolean":     {domain.FilterEq},
+}
+
+// resolvePropertyFilters types a search's property filters by their
+// attribute definitions. Equality filters from Properties become eq filters,
+// or between filters when a number or date is given as "min..max". Filters
+// on keys without an attribute are dropped, as the repository would ignore
+// them.
+func resolvePropertyFilters(opts domain.FilterOptions, attrs []domain.AttributeDefinition) (domain.FilterOptions, error) {
+	byKey := make(map[string]domain.AttributeDefinition, len(attrs))
+	for _, attr := range attrs {
+		if _, exists := byKey[attr.Key]; !exists {
+			byKey[attr.Key] = attr
+		}
+	}
+
+	requested := make([]domain.PropertyFilter, 0, len(opts.Properties)+len(opts.Filters))
+	for key, value := range opts.Properties {
+		requested = append(requested, domain.PropertyFilter{Key: key, Op: domain.FilterEq, Value: value})
+	}
+	requested = append(requested, opts.Filters...)
+
+	resolved := make([]domain.PropertyFilter, 0, len(requested))
+	for _, filter := range requested {
+		attr, ok := byKey[filter.Key]
+		if !ok {
+			continue
+		}
+		if filter.Op == "" {
+			filter.Op = domain.FilterEq
+		}
+		if filter.Op == domain.FilterEq && (attr.Type == "number" || attr.Type == "date") && strings.Contains(filter.Value, "..") {
+			filter.Op = domain.FilterBetween
+		}
+		if !contains(filterOperators[attr.Type], filter.Op) {
+			return opts, fmt.Errorf("%w: filter on %q cannot use %q with a %s attribute (expected one of %s)",
+				ErrInvalidProperty, filter.Key, filter.Op, attr.Type, strings.Join(filterOperators[attr.Type], ", "))
+		}
+
+		operands, msg := filterOperands(attr, filter)
+		if msg != "" {
+			return opts, fmt.Errorf("%w: filter on %q %s", ErrInvalidProperty, filter.Key, msg)
+		}
+		filter.Type = attr.Type
+		filter.Operands = operands
+		resolved = append(resolved, filter)
+	}
+
+	opts.Properties = nil
+	opts.Filters = resolved
+	return opts, nil
+}
+
+// filterOperands parses a filter's value into typed operands for its
+// attribute, returning what is wrong with it or an empty string if it is
+// valid.
+func filterOperands(attr domain.AttributeDefinition, filter domain.PropertyFilter) ([]interface{}, string) {
+	switch filter.Op {
+	case domain.FilterBetween:
+		low, high, ok := strings.Cut(filter.Value, "..")
+		if !ok || (strings.TrimSpace(low) == "" && strings.TrimSpace(high) == "") {
+			return nil, fmt.Sprintf("value %q must be a range written min..max", filter.Value)
+		}
+		operands := make([]interface{}, 2)
+		for i, end := range []string{low, high} {
+			if strings.TrimSpace(end) == "" {
+				continue
+			}
+			operand, msg := filterOperand(attr, end)
+			if msg != "" {
+				return nil, msg
+			}
+			operands[i] = operand
+		}
+		return operands, ""
+	case domain.FilterIn:
+		var operands []interface{}
+		for _, item := range strings.Split(filter.Value, ",") {
+			operand, msg := filterOperand(attr, item)
+			if msg != "" {
+				return nil, msg
+			}
+			operands = append(operands, operand)
+		}
+		return operands, ""
+	case domain.FilterPrefix, domain.FilterContains:
+		if filter.Value == "" {
+			return nil, "needs a value"
+		}
+		return []interface{}{filter.Value}, ""
+	default:
+		operand, msg := filterOperand(attr, filter.Value)
+		if msg != "" {
+			return nil, msg
+		}
+		return []interface{}{operand}, ""
+	}
+}
+
+// filterOperand parses a single filter value as its attribute's type,
+// returning what is wrong with it or an empty string if it is valid.
+func filterOperand(attr domain.AttributeDefinition, text string) (interface{}, string) {
+	text = strings.TrimSpace(text)
+	switch attr.Type {
+	case "number":
+		if _, measured := attributeUnit(attr); measured {
+			value, msg := parseMeasure(attr, text)
+			if msg != "" {
+				return nil, msg
+			}
+			return value, ""
+		}
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Sprintf("value %q must be a number", text)
+		}
+		return value, ""
+	case "boolean":
+		value, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, fmt.Sprintf("value %q must be true or false", text)
+		}
+		return value, ""
+	case "date":
+		if _, err := time.Parse(attributeDateLayout, text); err != nil {
+			return nil, fmt.Sprintf("value %q is not a date in YYYY-MM-DD form", text)
+		}
+		return text, ""
+	case "select", "multiselect":
+		if len(attr.Options) > 0 && !contains(attr.Options, text) {
+			return nil, fmt.Sprintf("value %q is not in allowed options %v", text, attr.Options)
+		}
+		return text, ""
+	default:
+		return text, ""
+	}
+}
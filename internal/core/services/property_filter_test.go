@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+var filterTestAttrs = []domain.AttributeDefinition{
+	{Key: "voltage", Type: "number", Unit: "V"},
+	{Key: "cores", Type: "number"},
+	{Key: "brand", Type: "string"},
+	{Key: "base", Type: "select", Options: []string{"E27", "B22"}},
+	{Key: "finishes", Type: "multiselect", Options: []string{"brass", "chrome"}},
+	{Key: "dimmable", Type: "boolean"},
+	{Key: "expires", Type: "date"},
+}
+
+func TestResolvePropertyFilters(t *testing.T) {
+	opts := domain.FilterOptions{
+		Properties: map[string]string{"dimmable": "true", "unknown": "x"},
+		Filters: []domain.PropertyFilter{
+			{Key: "voltage", Op: domain.FilterBetween, Value: "0.2kV..250v"},
+			{Key: "cores", Op: domain.FilterGte, Value: "3"},
+			{Key: "base", Op: domain.FilterIn, Value: "E27, B22"},
+			{Key: "brand", Op: domain.FilterPrefix, Value: "Ac"},
+			{Key: "expires", Value: "..2026-12-31"},
+		},
+	}
+
+	got, err := resolvePropertyFilters(opts, filterTestAttrs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Properties != nil {
+		t.Errorf("expected equality filters to move into Filters, got %v", got.Properties)
+	}
+	if len(got.Filters) != 6 {
+		t.Fatalf("expected 6 filters without the unknown key, got %+v", got.Filters)
+	}
+
+	byKey := make(map[string]domain.PropertyFilter)
+	for _, f := range got.Filters {
+		byKey[f.Key] = f
+	}
+	if f := byKey["dimmable"]; f.Op != domain.FilterEq || f.Type != "boolean" || f.Operands[0] != true {
+		t.Errorf("expected dimmable eq true, got %+v", f)
+	}
+	if f := byKey["voltage"]; f.Operands[0] != 200.0 || f.Operands[1] != 250.0 {
+		t.Errorf("expected voltage between 200 and 250 V, got %+v", f)
+	}
+	if f := byKey["cores"]; f.Op != domain.FilterGte || f.Operands[0] != 3.0 {
+		t.Errorf("expected cores gte 3, got %+v", f)
+	}
+	if f := byKey["base"]; len(f.Operands) != 2 || f.Operands[0] != "E27" || f.Operands[1] != "B22" {
+		t.Errorf("expected base in E27, B22, got %+v", f)
+	}
+	if f := byKey["expires"]; f.Op != domain.FilterBetween || f.Operands[0] != nil || f.Operands[1] != "2026-12-31" {
+		t.Errorf("expected expires up to 2026-12-31, got %+v", f)
+	}
+	if len(opts.Filters) != 5 || opts.Filters[0].Type != "" {
+		t.Error("expected the caller's filters to be left unchanged")
+	}
+}
+
+func TestResolvePropertyFilters_Invalid(t *testing.T) {
+	invalid := []domain.PropertyFilter{
+		{Key: "voltage", Op: domain.FilterGt, Value: "5 kg"},
+		{Key: "cores", Op: domain.FilterPrefix, Value: "3"},
+		{Key: "brand", Op: domain.FilterGt, Value: "A"},
+		{Key: "base", Op: domain.FilterIn, Value: "E27,GU10"},
+		{Key: "dimmable", Value: "sometimes"},
+		{Key: "expires", Op: domain.FilterGte, Value: "31/12/2026"},
+		{Key: "cores", Op: domain.FilterBetween, Value: ".."},
+		{Key: "finishes", Op: "near", Value: "brass"},
+	}
+	for _, f := range invalid {
+		_, err := resolvePropertyFilters(domain.FilterOptions{Filters: []domain.PropertyFilter{f}}, filterTestAttrs)
+		if !errors.Is(err, ErrInvalidProperty) {
+			t.Errorf("%s %s %q: expected ErrInvalidProperty, got %v", f.Key, f.Op, f.Value, err)
+		}
+	}
+}
+
+func TestImportProducts_FiltersOnImportedValues(t *testing.T) {
+	categoryRepo := &mockCategoryRepository{categories: map[string]*domain.Category{
+		"cables": {ID: "cables", Name: "Cables", AttributeDefinitions: []domain.AttributeDefinition{
+			{Key: "cores", Type: "number"},
+			{Key: "armoured", Type: "boolean"},
+		}},
+	}}
+	productRepo := &mockProductRepository{}
+	auditRepo := &mockAuditLogRepository{}
+	txManager := &mockSaleTxManager{productRepo: productRepo, categoryRepo: categoryRepo, auditRepo: auditRepo}
+	importSvc := NewProductService(productRepo, categoryRepo, NewAuditService(auditRepo), txManager, &mockSynonymRepository{})
+
+	csv := "name,sku,base_price,cores,armoured\nCable,C-1,2.50,3,true\n"
+	if _, err := importSvc.ImportProducts(context.Background(), "cables", strings.NewReader(csv)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	props := productRepo.products[0].Properties
+	if props["cores"] != 3.0 || props["armoured"] != true {
+		t.Fatalf("expected the CSV text stored as a number and a boolean, got %#v", props)
+	}
+
+	searchRepo := &searchMockProductRepository{products: productRepo.products}
+	searchSvc := NewProductService(searchRepo, categoryRepo, nil, nil, &mockSynonymRepository{})
+	opts := domain.FilterOptions{CategoryID: "cables", Properties: map[string]string{"cores": "3", "armoured": "true"}}
+	page, err := searchSvc.SearchProducts(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Products) != 1 {
+		t.Errorf("expected the imported cable to match, got %d product(s)", len(page.Products))
+	}
+}
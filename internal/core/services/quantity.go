@@ -0,0 +1,78 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+// ErrInvalidQuantity is returned when a quantity does not fit the product's unit of measure.
+var ErrInvalidQuantity = errors.New("invalid quantity")
+
+// ErrInvalidUnitOfMeasure is returned when a product has an unknown unit of measure or precision.
+var ErrInvalidUnitOfMeasure = errors.New("invalid unit of measure")
+
+// maxQuantityPrecision is the largest number of decimal places a quantity may carry.
+const maxQuantityPrecision = 6
+
+// defaultQuantityPrecision is the precision used for each unit when none is configured.
+var defaultQuantityPrecision = map[string]int{
+	domain.UnitEach:     0,
+	domain.UnitKilogram: 3,
+	domain.UnitMetre:    2,
+	domain.UnitLitre:    3,
+}
+
+// normalizeUnitOfMeasure fills in a product's default unit of measure and,
+// when asked for with DefaultQuantityPrecision, its precision, validates them
+// and rounds its stock to that precision. A precision of 0 is kept as whole
+// quantities.
+func normalizeUnitOfMeasure(product *domain.Product) error {
+	if product.UnitOfMeasure == "" {
+		product.UnitOfMeasure = domain.UnitEach
+	}
+
+	precision, ok := defaultQuantityPrecision[product.UnitOfMeasure]
+	if !ok {
+		return fmt.Errorf("%w: %q (expected each, kg, m or l)", ErrInvalidUnitOfMeasure, product.UnitOfMeasure)
+	}
+	if product.QuantityPrecision == domain.DefaultQuantityPrecision {
+		product.QuantityPrecision = precision
+	}
+	if product.QuantityPrecision < 0 || product.QuantityPrecision > maxQuantityPrecision {
+		return fmt.Errorf("%w: precision must be between 0 and %d", ErrInvalidUnitOfMeasure, maxQuantityPrecision)
+	}
+	if product.UnitOfMeasure == domain.UnitEach && product.QuantityPrecision != 0 {
+		return fmt.Errorf("%w: products sold each must use whole quantities", ErrInvalidUnitOfMeasure)
+	}
+
+	product.Quantity = roundQuantity(product.Quantity, product.QuantityPrecision)
+	return nil
+}
+
+// checkQuantity validates that a requested quantity is positive and carries no
+// more decimal places than the product allows.
+func checkQuantity(product *domain.Product, quantity float64) error {
+	if quantity <= 0 || math.IsNaN(quantity) || math.IsInf(quantity, 0) {
+		return fmt.Errorf("%w for product %s", ErrInvalidQuantity, product.ID)
+	}
+	if !fitsPrecision(quantity, product.QuantityPrecision) {
+		return fmt.Errorf("%w: product %s allows at most %d decimal places, got %v",
+			ErrInvalidQuantity, product.ID, product.QuantityPrecision, quantity)
+	}
+	return nil
+}
+
+// fitsPrecision reports whether a quantity carries no more than the given
+// number of decimal places.
+func fitsPrecision(quantity float64, precision int) bool {
+	return math.Abs(roundQuantity(quantity, precision)-quantity) <= 1e-9
+}
+
+// roundQuantity rounds a quantity to the given number of decimal places.
+func roundQuantity(quantity float64, precision int) float64 {
+	scale := math.Pow10(precision)
+	return math.Round(quantity*scale) / scale
+}
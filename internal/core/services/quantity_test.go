@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+func newWeighedTestService(products []*domain.Product) (*SaleService, *mockSaleTxManager) {
+	txManager := &mockSaleTxManager{
+		productRepo:  &mockProductRepository{products: products},
+		categoryRepo: &mockCategoryRepository{categories: make(map[string]*domain.Category)},
+		auditRepo:    &mockAuditLogRepository{},
+		saleRepo:     &mockSaleRepository{},
+	}
+	return NewSaleService(txManager), txManager
+}
+
+// --- Embedded barcode Tests ---
+
+func TestParseEmbeddedBarcode(t *testing.T) {
+	weight, err := ParseEmbeddedBarcode("2112345012506", DefaultEmbeddedBarcodeRules)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if weight == nil || weight.Kind != domain.EmbeddedWeight || weight.ItemCode != "12345" || weight.Value != 1.25 {
+		t.Fatalf("expected 1.25 kg of item 12345, got %+v", weight)
+	}
+
+	price, err := ParseEmbeddedBarcode("2312345004994", DefaultEmbeddedBarcodeRules)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if price == nil || price.Kind != domain.EmbeddedPrice || price.Value != 4.99 {
+		t.Fatalf("expected a 4.99 price barcode, got %+v", price)
+	}
+
+	// A regular EAN-13 outside the in-store prefixes is not embedded
+	plain, err := ParseEmbeddedBarcode("4006381333931", DefaultEmbeddedBarcodeRules)
+	if err != nil || plain != nil {
+		t.Fatalf("expected no embedded data, got %+v, %v", plain, err)
+	}
+
+	_, err = ParseEmbeddedBarcode("2112345012507", DefaultEmbeddedBarcodeRules)
+	if !errors.Is(err, ErrInvalidBarcode) {
+		t.Fatalf("expected ErrInvalidBarcode for a bad check digit, got %v", err)
+	}
+}
+
+// --- Quantity Tests ---
+
+func TestNormalizeUnitOfMeasure(t *testing.T) {
+	product := &domain.Product{UnitOfMeasure: domain.UnitKilogram, QuantityPrecision: domain.DefaultQuantityPrecision, Quantity: 2.34567}
+	if err := normalizeUnitOfMeasure(product); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if product.QuantityPrecision != 3 || product.Quantity != 2.346 {
+		t.Fatalf("expected precision 3 and quantity 2.346, got %d and %v", product.QuantityPrecision, product.Quantity)
+	}
+
+	// An explicit precision of 0 is kept rather than replaced by the default
+	whole := &domain.Product{UnitOfMeasure: domain.UnitKilogram, QuantityPrecision: 0, Quantity: 2.6}
+	if err := normalizeUnitOfMeasure(whole); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if whole.QuantityPrecision != 0 || whole.Quantity != 3 {
+		t.Fatalf("expected precision 0 and quantity 3, got %d and %v", whole.QuantityPrecision, whole.Quantity)
+	}
+
+	if err := normalizeUnitOfMeasure(&domain.Product{UnitOfMeasure: "stone"}); !errors.Is(err, ErrInvalidUnitOfMeasure) {
+		t.Fatalf("expected ErrInvalidUnitOfMeasure for unknown unit, got %v", err)
+	}
+	if err := normalizeUnitOfMeasure(&domain.Product{QuantityPrecision: 2}); !errors.Is(err, ErrInvalidUnitOfMeasure) {
+		t.Fatalf("expected ErrInvalidUnitOfMeasure for fractional each, got %v", err)
+	}
+}
+
+func TestImportProducts_ZeroQuantityPrecision(t *testing.T) {
+	productRepo := &mockProductRepository{}
+	categoryRepo := &mockCategoryRepository{categories: make(map[string]*domain.Category)}
+	auditRepo := &mockAuditLogRepository{}
+	txManager := &mockTransactionManager{productRepo: productRepo, categoryRepo: categoryRepo, auditRepo: auditRepo}
+	svc := NewProductService(productRepo, categoryRepo, NewAuditService(auditRepo), txManager, &mockSynonymRepository{})
+
+	csv := "name,sku,base_price,unit_of_measure,quantity_precision\nSack,SACK,20,kg,0\nFlour,FLOUR,2,kg,\n"
+	if _, err := svc.ImportProducts(context.Background(), "", strings.NewReader(csv)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	precisions := make(map[string]int)
+	for _, p := range productRepo.products {
+		precisions[p.SKU] = p.QuantityPrecision
+	}
+	if precisions["SACK"] != 0 || precisions["FLOUR"] != 3 {
+		t.Fatalf("expected precision 0 for SACK and the kg default 3 for FLOUR, got %v", precisions)
+	}
+}
+
+func TestUpdateProduct_RejectsRoundingStock(t *testing.T) {
+	productRepo := &mockProductRepository{products: []*domain.Product{
+		{ID: "p1", SKU: "FLOUR", UnitOfMeasure: domain.UnitKilogram, QuantityPrecision: 3, Quantity: 2.5},
+	}}
+	categoryRepo := &mockCategoryRepository{categories: make(map[string]*domain.Category)}
+	auditRepo := &mockAuditLogRepository{}
+	txManager := &mockTransactionManager{productRepo: productRepo, categoryRepo: categoryRepo, auditRepo: auditRepo}
+	svc := NewProductService(productRepo, categoryRepo, NewAuditService(auditRepo), txManager, &mockSynonymRepository{})
+
+	// 2.5 kg cannot become whole units without rounding the stock
+	for _, change := range []*domain.Product{
+		{ID: "p1", SKU: "FLOUR", UnitOfMeasure: domain.UnitEach, QuantityPrecision: domain.DefaultQuantityPrecision, Quantity: 2.5},
+		{ID: "p1", SKU: "FLOUR", UnitOfMeasure: domain.UnitKilogram, QuantityPrecision: 0, Quantity: 2.5},
+	} {
+		if err := svc.UpdateProduct(context.Background(), change); !errors.Is(err, ErrInvalidUnitOfMeasure) {
+			t.Fatalf("expected ErrInvalidUnitOfMeasure for %s with precision %d, got %v", change.UnitOfMeasure, change.QuantityPrecision, err)
+		}
+	}
+
+	// Stock that fits the new precision is kept as it is
+	product := &domain.Product{ID: "p1", SKU: "FLOUR", UnitOfMeasure: domain.UnitKilogram, QuantityPrecision: 1, Quantity: 2.5}
+	if err := svc.UpdateProduct(context.Background(), product); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if product.Quantity != 2.5 {
+		t.Fatalf("expected quantity 2.5, got %v", product.Quantity)
+	}
+}
+
+func TestProcessSale_FractionalQuantity(t *testing.T) {
+	svc, txManager := newWeighedTestService([]*domain.Product{
+		{ID: "p1", Name: "Rope", SKU: "ROPE", BasePrice: 2.00, Quantity: 10, UnitOfMeasure: domain.UnitMetre, QuantityPrecision: 2},
+	})
+
+	sale, err := svc.ProcessSale(context.Background(), ports.SaleRequest{Items: []ports.SaleItemRequest{
+		{ProductID: "p1", Quantity: 2.75},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sale.TotalAmount != 5.50 {
+		t.Fatalf("expected total 5.50, got %v", sale.TotalAmount)
+	}
+	if got := txManager.productRepo.products[0].Quantity; got != 7.25 {
+		t.Fatalf("expected 7.25 m left, got %v", got)
+	}
+
+	_, err = svc.ProcessSale(context.Background(), ports.SaleRequest{Items: []ports.SaleItemRequest{
+		{ProductID: "p1", Quantity: 1.255},
+	}})
+	if !errors.Is(err, ErrInvalidQuantity) {
+		t.Fatalf("expected ErrInvalidQuantity for too many decimals, got %v", err)
+	}
+}
+
+func TestProcessSale_WholeUnitsOnly(t *testing.T) {
+	svc, _ := newWeighedTestService([]*domain.Product{
+		{ID: "p1", Name: "Widget", SKU: "SKU-001", BasePrice: 10, Quantity: 10},
+	})
+
+	_, err := svc.ProcessSale(context.Background(), ports.SaleRequest{Items: []ports.SaleItemRequest{
+		{ProductID: "p1", Quantity: 0.5},
+	}})
+	if !errors.Is(err, ErrInvalidQuantity) {
+		t.Fatalf("expected ErrInvalidQuantity, got %v", err)
+	}
+}
+
+func TestProcessSale_WeightEmbeddedBarcode(t *testing.T) {
+	svc, txManager := newWeighedTestService([]*domain.Product{
+		{ID: "p1", Name: "Cheddar", SKU: "12345", BasePrice: 12.00, Quantity: 5, UnitOfMeasure: domain.UnitKilogram, QuantityPrecision: 3},
+	})
+
+	sale, err := svc.ProcessSale(context.Background(), ports.SaleRequest{Items: []ports.SaleItemRequest{
+		{Barcode: "2112345012506"},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sale.TotalAmount != 15.00 {
+		t.Fatalf("expected 1.25 kg at 12.00 to total 15.00, got %v", sale.TotalAmount)
+	}
+	if got := txManager.productRepo.products[0].Quantity; got != 3.75 {
+		t.Fatalf("expected 3.75 kg left, got %v", got)
+	}
+	if items := txManager.saleRepo.saleItems; len(items) != 1 || items[0].Quantity != 1.25 {
+		t.Fatalf("expected one sale item of 1.25, got %+v", items)
+	}
+}
+
+func TestProcessSale_PriceEmbeddedBarcode(t *testing.T) {
+	svc, txManager := newWeighedTestService([]*domain.Product{
+		{ID: "p1", Name: "Ham", SKU: "12345", BasePrice: 20.00, Quantity: 2, UnitOfMeasure: domain.UnitKilogram, QuantityPrecision: 3},
+	})
+
+	sale, err := svc.ProcessSale(context.Background(), ports.SaleRequest{Items: []ports.SaleItemRequest{
+		{Barcode: "2312345004994"},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The printed price is charged as-is; stock drops by the equivalent weight
+	if sale.TotalAmount != 4.99 {
+		t.Fatalf("expected total 4.99, got %v", sale.TotalAmount)
+	}
+	if got := txManager.productRepo.products[0].Quantity; math.Abs(got-1.75) > 1e-9 {
+		t.Fatalf("expected 1.75 kg left, got %v", got)
+	}
+}
+
+func TestProcessSale_PriceEmbeddedBarcodeWholeUnits(t *testing.T) {
+	svc, txManager := newWeighedTestService([]*domain.Product{
+		{ID: "p1", Name: "Candle", SKU: "12345", BasePrice: 1.00, Quantity: 10},
+	})
+
+	// 2.50 is not a whole number of candles
+	_, err := svc.ProcessSale(context.Background(), ports.SaleRequest{Items: []ports.SaleItemRequest{
+		{Barcode: "2312345002501"},
+	}})
+	if !errors.Is(err, ErrInvalidBarcode) {
+		t.Fatalf("expected ErrInvalidBarcode, got %v", err)
+	}
+
+	sale, err := svc.ProcessSale(context.Background(), ports.SaleRequest{Items: []ports.SaleItemRequest{
+		{Barcode: "2312345003003"},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sale.TotalAmount != 3.00 {
+		t.Fatalf("expected total 3.00, got %v", sale.TotalAmount)
+	}
+	if got := txManager.productRepo.products[0].Quantity; got != 7 {
+		t.Fatalf("expected 7 candles left, got %v", got)
+	}
+}
+
+func TestProcessSale_WeightBarcodeRequiresWeighedProduct(t *testing.T) {
+	svc, _ := newWeighedTestService([]*domain.Product{
+		{ID: "p1", Name: "Widget", SKU: "12345", BasePrice: 10, Quantity: 5},
+	})
+
+	_, err := svc.ProcessSale(context.Background(), ports.SaleRequest{Items: []ports.SaleItemRequest{
+		{Barcode: "2112345012506"},
+	}})
+	if !errors.Is(err, ErrInvalidBarcode) {
+		t.Fatalf("expected ErrInvalidBarcode, got %v", err)
+	}
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// ErrProductRecalled is returned when a product under an active recall is sold.
+var ErrProductRecalled = errors.New("product recalled")
+
+// ErrRecallAlreadyActive is returned when declaring a recall for a product that is already recalled.
+var ErrRecallAlreadyActive = errors.New("recall already active")
+
+// ErrRecallClosed is returned when closing a recall that is no longer active.
+var ErrRecallClosed = errors.New("recall already closed")
+
+// RecallService implements the product recall workflow.
+type RecallService struct {
+	recallRepo ports.RecallRepository
+	txManager  ports.TransactionManager
+}
+
+// NewRecallService creates a new recall service instance.
+func NewRecallService(recallRepo ports.RecallRepository, txManager ports.TransactionManager) *RecallService {
+	return &RecallService{
+		recallRepo: recallRepo,
+		txManager:  txManager,
+	}
+}
+
+// DeclareRecall atomically records a recall, moves the product's on-hand stock
+// into quarantine and writes the action to the audit chain. The returned report
+// lists every sale affected by the recall.
+func (s *RecallService) DeclareRecall(ctx context.Context, recall *domain.Recall) (*domain.RecallReport, error) {
+	if recall.ProductID == "" {
+		return nil, errors.New("product_id is required")
+	}
+	if recall.SoldFrom != nil && recall.SoldTo != nil && recall.SoldTo.Before(*recall.SoldFrom) {
+		return nil, errors.New("sold_to must not be before sold_from")
+	}
+
+	recall.ID = uuid.New().String()
+	recall.Status = domain.RecallStatusActive
+	recall.CreatedAt = time.Now()
+	recall.ClosedAt = nil
+
+	var report *domain.RecallReport
+	err := s.txManager.WithTx(ctx, func(tx ports.Ports) error {
+		product, err := tx.ProductRepo.GetByID(ctx, recall.ProductID)
+		if err != nil {
+			return fmt.Errorf("product %s: %w", recall.ProductID, err)
+		}
+
+		active, err := tx.RecallRepo.GetActiveByProduct(ctx, product.ID)
+		if err != nil {
+			return fmt.Errorf("active recall lookup: %w", err)
+		}
+		if active != nil {
+			return fmt.Errorf("%w: product %s (recall %s)", ErrRecallAlreadyActive, product.ID, active.ID)
+		}
+
+		// Quarantine all sellable stock
+		recall.QuarantinedQuantity = product.Quantity
+		product.QuarantinedQuantity += product.Quantity
+		product.Quantity = 0
+		if err := tx.ProductRepo.Update(ctx, product); err != nil {
+			return fmt.Errorf("quarantine stock for product %s: %w", product.ID, err)
+		}
+
+		if err := tx.RecallRepo.Create(ctx, recall); err != nil {
+			return fmt.Errorf("create recall: %w", err)
+		}
+
+		report, err = buildRecallReport(ctx, tx.RecallRepo, recall)
+		if err != nil {
+			return err
+		}
+
+		payload := map[string]interface{}{
+			"recall_id":            recall.ID,
+			"product_id":           product.ID,
+			"sku":                  product.SKU,
+			"reason":               recall.Reason,
+			"quarantined_quantity": recall.QuarantinedQuantity,
+			"affected_sales":       len(report.Sales),
+			"affected_quantity":    report.TotalQuantity,
+		}
+		if recall.SoldFrom != nil {
+			payload["sold_from"] = recall.SoldFrom.Format(time.RFC3339)
+		}
+		if recall.SoldTo != nil {
+			payload["sold_to"] = recall.SoldTo.Format(time.RFC3339)
+		}
+		if err := newTxAuditService(ctx, tx.AuditRepo).LogAction(ctx, "PRODUCT_RECALLED", "system", payload); err != nil {
+			return fmt.Errorf("audit log: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// GetRecall retrieves a recall by ID.
+func (s *RecallService) GetRecall(ctx context.Context, id string) (*domain.Recall, error) {
+	return s.recallRepo.GetByID(ctx, id)
+}
+
+// ListRecalls retrieves recalls with pagination.
+func (s *RecallService) ListRecalls(ctx context.Context, limit, offset int) ([]*domain.Recall, error) {
+	return s.recallRepo.List(ctx, limit, offset)
+}
+
+// GetRecallReport returns every sale and quantity affected by a recall.
+func (s *RecallService) GetRecallReport(ctx context.Context, id string) (*domain.RecallReport, error) {
+	recall, err := s.recallRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return buildRecallReport(ctx, s.recallRepo, recall)
+}
+
+// CloseRecall ends an active recall so the product can be sold again. When
+// releaseStock is true the quarantined stock is returned to sellable quantity;
+// otherwise it stays quarantined for disposal or return to the supplier.
+func (s *RecallService) CloseRecall(ctx context.Context, id string, releaseStock bool) (*domain.Recall, error) {
+	var recall *domain.Recall
+
+	err := s.txManager.WithTx(ctx, func(tx ports.Ports) error {
+		var err error
+		recall, err = tx.RecallRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if recall.Status != domain.RecallStatusActive {
+			return fmt.Errorf("%w: %s", ErrRecallClosed, recall.ID)
+		}
+
+		var released float64
+		if releaseStock {
+			product, err := tx.ProductRepo.GetByID(ctx, recall.ProductID)
+			if err != nil {
+				return fmt.Errorf("product %s: %w", recall.ProductID, err)
+			}
+
+			released = recall.QuarantinedQuantity
+			if released > product.QuarantinedQuantity {
+				released = product.QuarantinedQuantity
+			}
+			product.QuarantinedQuantity -= released
+			product.Quantity += released
+			if err := tx.ProductRepo.Update(ctx, product); err != nil {
+				return fmt.Errorf("release stock for product %s: %w", product.ID, err)
+			}
+		}
+
+		now := time.Now()
+		recall.Status = domain.RecallStatusClosed
+		recall.ClosedAt = &now
+		if err := tx.RecallRepo.Update(ctx, recall); err != nil {
+			return fmt.Errorf("close recall: %w", err)
+		}
+
+		if err := newTxAuditService(ctx, tx.AuditRepo).LogAction(ctx, "RECALL_CLOSED", "system", map[string]interface{}{
+			"recall_id":         recall.ID,
+			"product_id":        recall.ProductID,
+			"released_quantity": released,
+		}); err != nil {
+			return fmt.Errorf("audit log: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return recall, nil
+}
+
+// buildRecallReport collects the sales covered by a recall's product and date range.
+func buildRecallReport(ctx context.Context, recallRepo ports.RecallRepository, recall *domain.Recall) (*domain.RecallReport, error) {
+	sales, err := recallRepo.ListAffectedSales(ctx, recall.ProductID, recall.SoldFrom, recall.SoldTo)
+	if err != nil {
+		return nil, fmt.Errorf("list affected sales: %w", err)
+	}
+
+	report := &domain.RecallReport{
+		Recall: recall,
+		Sales:  sales,
+	}
+	for _, sale := range sales {
+		report.TotalQuantity += sale.Quantity
+		report.TotalValue += sale.UnitPrice * sale.Quantity
+	}
+
+	return report, nil
+}
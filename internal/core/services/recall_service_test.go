@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// --- Mock RecallRepository ---
+
+type mockRecallRepository struct {
+	recalls []*domain.Recall
+	sales   map[string][]domain.RecallAffectedSale // product ID -> sale lines
+}
+
+func (m *mockRecallRepository) Create(_ context.Context, recall *domain.Recall) error {
+	m.recalls = append(m.recalls, recall)
+	return nil
+}
+func (m *mockRecallRepository) GetByID(_ context.Context, id string) (*domain.Recall, error) {
+	for _, r := range m.recalls {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return nil, errors.New("recall not found")
+}
+func (m *mockRecallRepository) GetActiveByProduct(_ context.Context, productID string) (*domain.Recall, error) {
+	for _, r := range m.recalls {
+		if r.ProductID == productID && r.Status == domain.RecallStatusActive {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+func (m *mockRecallRepository) List(_ context.Context, _, _ int) ([]*domain.Recall, error) {
+	return m.recalls, nil
+}
+func (m *mockRecallRepository) Update(_ context.Context, recall *domain.Recall) error {
+	for i, r := range m.recalls {
+		if r.ID == recall.ID {
+			m.recalls[i] = recall
+			return nil
+		}
+	}
+	return errors.New("recall not found")
+}
+func (m *mockRecallRepository) ListAffectedSales(_ context.Context, productID string, from, to *time.Time) ([]domain.RecallAffectedSale, error) {
+	var out []domain.RecallAffectedSale
+	for _, s := range m.sales[productID] {
+		if from != nil && s.SoldAt.Before(*from) {
+			continue
+		}
+		if to != nil && s.SoldAt.After(*to) {
+			continue
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func newRecallTestService(products []*domain.Product, recallRepo *mockRecallRepository) (*RecallService, *mockSaleTxManager) {
+	txManager := &mockSaleTxManager{
+		productRepo:  &mockProductRepository{products: products},
+		categoryRepo: &mockCategoryRepository{categories: make(map[string]*domain.Category)},
+		auditRepo:    &mockAuditLogRepository{},
+		saleRepo:     &mockSaleRepository{},
+		recallRepo:   recallRepo,
+	}
+	return NewRecallService(recallRepo, txManager), txManager
+}
+
+// --- RecallService Tests ---
+
+func TestDeclareRecall_QuarantinesStockAndReports(t *testing.T) {
+	jan := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	mar := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	recallRepo := &mockRecallRepository{
+		sales: map[string][]domain.RecallAffectedSale{
+			"p1": {
+				{SaleID: "s1", SoldAt: jan, Quantity: 2, UnitPrice: 10},
+				{SaleID: "s2", SoldAt: mar, Quantity: 3, UnitPrice: 12},
+			},
+		},
+	}
+	svc, txManager := newRecallTestService([]*domain.Product{
+		{ID: "p1", Name: "Widget", SKU: "SKU-001", BasePrice: 10, Quantity: 40},
+	}, recallRepo)
+
+	from := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	report, err := svc.DeclareRecall(context.Background(), &domain.Recall{
+		ProductID: "p1",
+		Reason:    "Faulty batch",
+		SoldFrom:  &from,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	product := txManager.productRepo.products[0]
+	if product.Quantity != 0 || product.QuarantinedQuantity != 40 {
+		t.Fatalf("expected 0 sellable and 40 quarantined, got %v and %v", product.Quantity, product.QuarantinedQuantity)
+	}
+	if report.Recall.QuarantinedQuantity != 40 {
+		t.Fatalf("expected recall to record 40 quarantined, got %v", report.Recall.QuarantinedQuantity)
+	}
+
+	// Only the March sale falls inside the recall window
+	if len(report.Sales) != 1 || report.Sales[0].SaleID != "s2" {
+		t.Fatalf("expected only sale s2 to be affected, got %+v", report.Sales)
+	}
+	if report.TotalQuantity != 3 || report.TotalValue != 36 {
+		t.Fatalf("expected 3 units worth 36, got %v worth %v", report.TotalQuantity, report.TotalValue)
+	}
+
+	logs := txManager.auditRepo.logs
+	if len(logs) != 1 || logs[0].Action != "PRODUCT_RECALLED" {
+		t.Fatalf("expected a PRODUCT_RECALLED audit log, got %+v", logs)
+	}
+}
+
+func TestDeclareRecall_AlreadyActive(t *testing.T) {
+	recallRepo := &mockRecallRepository{
+		recalls: []*domain.Recall{{ID: "r1", ProductID: "p1", Status: domain.RecallStatusActive}},
+	}
+	svc, _ := newRecallTestService([]*domain.Product{
+		{ID: "p1", Name: "Widget", SKU: "SKU-001", Quantity: 5},
+	}, recallRepo)
+
+	_, err := svc.DeclareRecall(context.Background(), &domain.Recall{ProductID: "p1", Reason: "Again"})
+	if !errors.Is(err, ErrRecallAlreadyActive) {
+		t.Fatalf("expected ErrRecallAlreadyActive, got %v", err)
+	}
+}
+
+func TestProcessSale_BlockedByRecall(t *testing.T) {
+	recallRepo := &mockRecallRepository{
+		recalls: []*domain.Recall{{ID: "r1", ProductID: "p1", Status: domain.RecallStatusActive}},
+	}
+	txManager := &mockSaleTxManager{
+		productRepo: &mockProductRepository{products: []*domain.Product{
+			{ID: "p1", Name: "Widget", SKU: "SKU-001", BasePrice: 10, Quantity: 5},
+		}},
+		categoryRepo: &mockCategoryRepository{categories: make(map[string]*domain.Category)},
+		auditRepo:    &mockAuditLogRepository{},
+		saleRepo:     &mockSaleRepository{},
+		recallRepo:   recallRepo,
+	}
+
+	svc := NewSaleService(txManager)
+
+	_, err := svc.ProcessSale(context.Background(), ports.SaleRequest{Items: []ports.SaleItemRequest{
+		{ProductID: "p1", Quantity: 1},
+	}})
+	if !errors.Is(err, ErrProductRecalled) {
+		t.Fatalf("expected ErrProductRecalled, got %v", err)
+	}
+}
+
+func TestCloseRecall_ReleasesStock(t *testing.T) {
+	recallRepo := &mockRecallRepository{
+		recalls: []*domain.Recall{{ID: "r1", ProductID: "p1", Status: domain.RecallStatusActive, QuarantinedQuantity: 8}},
+	}
+	svc, txManager := newRecallTestService([]*domain.Product{
+		{ID: "p1", Name: "Widget", SKU: "SKU-001", Quantity: 2, QuarantinedQuantity: 8},
+	}, recallRepo)
+
+	recall, err := svc.CloseRecall(context.Background(), "r1", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if recall.Status != domain.RecallStatusClosed || recall.ClosedAt == nil {
+		t.Fatalf("expected closed recall, got %+v", recall)
+	}
+
+	product := txManager.productRepo.products[0]
+	if product.Quantity != 10 || product.QuarantinedQuantity != 0 {
+		t.Fatalf("expected 10 sellable and 0 quarantined, got %v and %v", product.Quantity, product.QuarantinedQuantity)
+	}
+
+	_, err = svc.CloseRecall(context.Background(), "r1", false)
+	if !errors.Is(err, ErrRecallClosed) {
+		t.Fatalf("expected ErrRecallClosed on second close, got %v", err)
+	}
+}
+
+func TestReceiveStock_QuarantinedUnderRecall(t *testing.T) {
+	recallRepo := &mockRecallRepository{}
+	svc, txManager := newRecallTestService([]*domain.Product{
+		{ID: "p1", Name: "Widget", SKU: "SKU-001", Quantity: 8},
+	}, recallRepo)
+	ctx := context.Background()
+
+	if _, err := svc.DeclareRecall(ctx, &domain.Recall{ProductID: "p1", Reason: "contamination"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	inventorySvc := NewInventoryService(&mockPackRepository{}, txManager.productRepo, txManager)
+	product, err := inventorySvc.ReceiveStock(ctx, ports.ReceiveStockRequest{ProductID: "p1", Quantity: 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if product.Quantity != 0 || product.QuarantinedQuantity != 13 {
+		t.Fatalf("expected the delivery quarantined, got %v sellable and %v quarantined", product.Quantity, product.QuarantinedQuantity)
+	}
+
+	if _, err := svc.CloseRecall(ctx, recallRepo.recalls[0].ID, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	product = txManager.productRepo.products[0]
+	if product.Quantity != 13 || product.QuarantinedQuantity != 0 {
+		t.Fatalf("expected all 13 released, got %v sellable and %v quarantined", product.Quantity, product.QuarantinedQuantity)
+	}
+}
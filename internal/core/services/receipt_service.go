@@ -0,0 +1,119 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// ErrNoPrinter is returned when printing is requested without a configured printer.
+var ErrNoPrinter = errors.New("no receipt printer configured")
+
+// ReceiptService implements sale receipt rendering and printing.
+type ReceiptService struct {
+	saleRepo    ports.SaleRepository
+	productRepo ports.ProductRepository
+	packRepo    ports.PackRepository
+	renderer    ports.ReceiptRenderer
+	printer     ports.PrinterSink // nil when no printer is attached
+	header      []string
+	footer      []string
+}
+
+// NewReceiptService creates a new receipt service instance. printer may be
+// nil, in which case receipts can only be downloaded.
+func NewReceiptService(saleRepo ports.SaleRepository, productRepo ports.ProductRepository, packRepo ports.PackRepository, renderer ports.ReceiptRenderer, printer ports.PrinterSink) *ReceiptService {
+	return &ReceiptService{
+		saleRepo:    saleRepo,
+		productRepo: productRepo,
+		packRepo:    packRepo,
+		renderer:    renderer,
+		printer:     printer,
+	}
+}
+
+// SetReceiptText sets the header and footer lines printed on every receipt.
+func (s *ReceiptService) SetReceiptText(header, footer []string) {
+	s.header = header
+	s.footer = footer
+}
+
+// RenderReceipt writes the receipt of a stored sale to w.
+func (s *ReceiptService) RenderReceipt(ctx context.Context, saleID string, w io.Writer) error {
+	receipt, err := s.buildReceipt(ctx, saleID)
+	if err != nil {
+		return err
+	}
+	return s.renderer.Render(w, receipt)
+}
+
+// PrintReceipt renders the receipt of a stored sale and sends it to the printer.
+func (s *ReceiptService) PrintReceipt(ctx context.Context, saleID string) error {
+	if s.printer == nil {
+		return ErrNoPrinter
+	}
+
+	var buf bytes.Buffer
+	if err := s.RenderReceipt(ctx, saleID, &buf); err != nil {
+		return err
+	}
+	if err := s.printer.Print(ctx, buf.Bytes()); err != nil {
+		return fmt.Errorf("print receipt for sale %s: %w", saleID, err)
+	}
+	return nil
+}
+
+// buildReceipt loads a sale with its items and resolves product and pack names.
+func (s *ReceiptService) buildReceipt(ctx context.Context, saleID string) (*domain.Receipt, error) {
+	sale, err := s.saleRepo.GetSale(ctx, saleID)
+	if err != nil {
+		return nil, fmt.Errorf("get sale %s: %w", saleID, err)
+	}
+
+	items, err := s.saleRepo.ListSaleItems(ctx, saleID)
+	if err != nil {
+		return nil, fmt.Errorf("list items of sale %s: %w", saleID, err)
+	}
+
+	receipt := &domain.Receipt{
+		Sale:   sale,
+		Header: s.header,
+		Footer: s.footer,
+		Lines:  make([]domain.ReceiptLine, 0, len(items)),
+	}
+
+	for _, item := range items {
+		line := domain.ReceiptLine{
+			Name:      item.ProductID,
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice,
+			Total:     item.UnitPrice * item.Quantity,
+		}
+
+		// Products deleted since the sale still print by ID
+		if product, err := s.productRepo.GetByID(ctx, item.ProductID); err == nil {
+			line.Name = product.Name
+			if product.UnitOfMeasure != "" && product.UnitOfMeasure != domain.UnitEach {
+				line.Unit = product.UnitOfMeasure
+			}
+		}
+
+		if item.PackID != "" {
+			if pack, err := s.packRepo.GetByID(ctx, item.PackID); err == nil && pack.UnitsPerPack > 0 {
+				line.Name = fmt.Sprintf("%s (%s)", line.Name, pack.Name)
+				line.Quantity = item.Quantity / pack.UnitsPerPack
+				line.UnitPrice = line.Total / line.Quantity
+				line.Unit = ""
+			}
+		}
+
+		receipt.Lines = append(receipt.Lines, line)
+	}
+
+	return receipt, nil
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// fakeReceiptRenderer records the receipt it was asked to render.
+type fakeReceiptRenderer struct {
+	receipt *domain.Receipt
+}
+
+func (r *fakeReceiptRenderer) Render(w io.Writer, receipt *domain.Receipt) error {
+	r.receipt = receipt
+	_, err := w.Write([]byte("receipt:" + receipt.Sale.ID))
+	return err
+}
+
+// fakePrinterSink records printed jobs.
+type fakePrinterSink struct {
+	jobs [][]byte
+	err  error
+}
+
+func (s *fakePrinterSink) Print(_ context.Context, data []byte) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.jobs = append(s.jobs, data)
+	return nil
+}
+
+func newReceiptTestSale(t *testing.T) (*mockSaleTxManager, *domain.Sale) {
+	t.Helper()
+	txManager := newPackTestTxManager([]*domain.Product{
+		{ID: "p1", Name: "Beer", SKU: "BEER", BasePrice: 2, Quantity: 60},
+		{ID: "p2", Name: "Rope", SKU: "ROPE", BasePrice: 1.5, Quantity: 10, UnitOfMeasure: domain.UnitMetre, QuantityPrecision: 2},
+	}, []*domain.ProductPack{
+		{ID: "case", ProductID: "p1", Name: "Case of 24", UnitsPerPack: 24, Price: 36},
+	})
+
+	sale, err := NewSaleService(txManager).ProcessSale(context.Background(), ports.SaleRequest{
+		CashierID: "alice",
+		Items: []ports.SaleItemRequest{
+			{PackID: "case", Quantity: 2},
+			{ProductID: "p2", Quantity: 2.5},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return txManager, sale
+}
+
+// --- ReceiptService Tests ---
+
+func TestRenderReceipt(t *testing.T) {
+	txManager, sale := newReceiptTestSale(t)
+	renderer := &fakeReceiptRenderer{}
+	svc := NewReceiptService(txManager.saleRepo, txManager.productRepo, txManager.packRepo, renderer, nil)
+	svc.SetReceiptText([]string{"Corner Shop"}, []string{"Thank you"})
+
+	var out bytes.Buffer
+	if err := svc.RenderReceipt(context.Background(), sale.ID, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "receipt:"+sale.ID {
+		t.Fatalf("expected the rendered receipt, got %q", out.String())
+	}
+
+	receipt := renderer.receipt
+	if receipt.Sale.TotalAmount != 75.75 || len(receipt.Header) != 1 || len(receipt.Footer) != 1 {
+		t.Fatalf("unexpected receipt %+v", receipt)
+	}
+	if len(receipt.Lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(receipt.Lines))
+	}
+
+	// Pack lines count packs at the pack price
+	pack := receipt.Lines[0]
+	if pack.Name != "Beer (Case of 24)" || pack.Quantity != 2 || pack.UnitPrice != 36 || pack.Total != 72 {
+		t.Fatalf("unexpected pack line %+v", pack)
+	}
+	rope := receipt.Lines[1]
+	if rope.Name != "Rope" || rope.Quantity != 2.5 || rope.Unit != domain.UnitMetre || rope.Total != 3.75 {
+		t.Fatalf("unexpected rope line %+v", rope)
+	}
+}
+
+func TestRenderReceipt_UnknownSale(t *testing.T) {
+	txManager, _ := newReceiptTestSale(t)
+	svc := NewReceiptService(txManager.saleRepo, txManager.productRepo, txManager.packRepo, &fakeReceiptRenderer{}, nil)
+
+	err := svc.RenderReceipt(context.Background(), "missing", io.Discard)
+	if !errors.Is(err, ports.ErrNotFound) {
+		t.Fatalf("expected ports.ErrNotFound, got %v", err)
+	}
+}
+
+// failingSaleRepository is a sale repository whose reads fail.
+type failingSaleRepository struct {
+	mockSaleRepository
+}
+
+func (m *failingSaleRepository) GetSale(_ context.Context, _ string) (*domain.Sale, error) {
+	return nil, errors.New("database is locked")
+}
+
+func TestRenderReceipt_SaleReadFails(t *testing.T) {
+	txManager, sale := newReceiptTestSale(t)
+	svc := NewReceiptService(&failingSaleRepository{}, txManager.productRepo, txManager.packRepo, &fakeReceiptRenderer{}, nil)
+
+	err := svc.RenderReceipt(context.Background(), sale.ID, io.Discard)
+	if err == nil || errors.Is(err, ports.ErrNotFound) {
+		t.Fatalf("expected the read error rather than ports.ErrNotFound, got %v", err)
+	}
+}
+
+func TestPrintReceipt(t *testing.T) {
+	txManager, sale := newReceiptTestSale(t)
+
+	noPrinter := NewReceiptService(txManager.saleRepo, txManager.productRepo, txManager.packRepo, &fakeReceiptRenderer{}, nil)
+	if err := noPrinter.PrintReceipt(context.Background(), sale.ID); !errors.Is(err, ErrNoPrinter) {
+		t.Fatalf("expected ErrNoPrinter, got %v", err)
+	}
+
+	sink := &fakePrinterSink{}
+	svc := NewReceiptService(txManager.saleRepo, txManager.productRepo, txManager.packRepo, &fakeReceiptRenderer{}, sink)
+	if err := svc.PrintReceipt(context.Background(), sale.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sink.jobs) != 1 || string(sink.jobs[0]) != "receipt:"+sale.ID {
+		t.Fatalf("expected one print job, got %q", sink.jobs)
+	}
+
+	sink.err = errors.New("paper out")
+	if err := svc.PrintReceipt(context.Background(), sale.ID); err == nil {
+		t.Fatal("expected the printer error to be returned")
+	}
+}
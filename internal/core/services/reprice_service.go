@@ -0,0 +1,275 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/google/uuid"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// ErrInvalidReprice is returned when a bulk repricing request is malformed.
+var ErrInvalidReprice = errors.New("invalid reprice request")
+
+// maxRepriceProducts caps the products changed by one bulk repricing.
+const maxRepriceProducts = 5000
+
+// repricePageSize is the number of products fetched per search page.
+const repricePageSize = 500
+
+// RepriceService implements bulk repricing of products selected by search filter.
+type RepriceService struct {
+	productSvc   ports.ProductService
+	categoryRepo ports.CategoryRepository
+	txManager    ports.TransactionManager
+}
+
+// NewRepriceService creates a new reprice service instance.
+func NewRepriceService(productSvc ports.ProductService, categoryRepo ports.CategoryRepository, txManager ports.TransactionManager) *RepriceService {
+	return &RepriceService{
+		productSvc:   productSvc,
+		categoryRepo: categoryRepo,
+		txManager:    txManager,
+	}
+}
+
+// RepriceProducts applies a pricing rule to every product matching the
+// filter. A dry run returns the before and after prices without saving;
+// otherwise all prices are updated in one transaction with an audit entry per
+// product, so a failure leaves every price as it was.
+func (s *RepriceService) RepriceProducts(ctx context.Context, req ports.RepriceRequest) (*domain.RepriceResult, error) {
+	if err := validateRepriceRule(req.Rule); err != nil {
+		return nil, err
+	}
+	if !hasRepriceFilter(req.Filter) {
+		return nil, fmt.Errorf("%w: at least one filter is required", ErrInvalidReprice)
+	}
+	if err := s.checkPropertyFilters(ctx, req.Filter); err != nil {
+		return nil, err
+	}
+
+	ids, err := s.matchProducts(ctx, req.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.RepriceResult{DryRun: req.DryRun}
+
+	if req.DryRun {
+		for _, id := range ids {
+			product, err := s.productSvc.GetProduct(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("product %s: %w", id, err)
+			}
+			addRepriceChange(result, repriceProduct(product, req.Rule))
+		}
+		return result, nil
+	}
+
+	userID := req.UserID
+	if userID == "" {
+		userID = "system"
+	}
+	result.BatchID = uuid.New().String()
+
+	// Prices are recalculated from the rows read inside the transaction
+	err = s.txManager.WithTx(ctx, func(tx ports.Ports) error {
+		auditSvc := newTxAuditService(ctx, tx.AuditRepo)
+
+		for _, id := range ids {
+			product, err := tx.ProductRepo.GetByID(ctx, id)
+			if err != nil {
+				return fmt.Errorf("product %s: %w", id, err)
+			}
+
+			change := repriceProduct(product, req.Rule)
+			addRepriceChange(result, change)
+			if change.SkipReason != "" {
+				continue
+			}
+
+			product.BasePrice = change.NewPrice
+			if err := tx.ProductRepo.Update(ctx, product); err != nil {
+				return fmt.Errorf("update product %s: %w", product.ID, err)
+			}
+
+			payload := map[string]interface{}{
+				"batch_id":   result.BatchID,
+				"product_id": product.ID,
+				"sku":        product.SKU,
+				"old_price":  change.OldPrice,
+				"new_price":  change.NewPrice,
+				"rule":       req.Rule.Type,
+				"value":      req.Rule.Value,
+			}
+			if err := auditSvc.LogAction(ctx, "PRODUCT_REPRICED", userID, payload); err != nil {
+				return fmt.Errorf("audit log: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// checkPropertyFilters refuses property filters that search would drop for
+// want of an attribute, as they would leave the selection wider than asked.
+func (s *RepriceService) checkPropertyFilters(ctx context.Context, filter domain.FilterOptions) error {
+	requested := len(filter.Properties) + len(filter.Filters)
+	if requested == 0 {
+		return nil
+	}
+	if filter.CategoryID == "" {
+		return fmt.Errorf("%w: property filters require a category", ErrInvalidReprice)
+	}
+
+	attrs, err := categorySearchAttributes(ctx, s.categoryRepo, filter.CategoryID)
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool, len(attrs))
+	for _, attr := range attrs {
+		known[attr.Key] = true
+	}
+	for key := range filter.Properties {
+		if !known[key] {
+			return fmt.Errorf("%w: category has no attribute %q to filter on", ErrInvalidReprice, key)
+		}
+	}
+	for _, f := range filter.Filters {
+		if !known[f.Key] {
+			return fmt.Errorf("%w: category has no attribute %q to filter on", ErrInvalidReprice, f.Key)
+		}
+	}
+	return nil
+}
+
+// matchProducts returns the IDs of every product matching the filter,
+// ignoring the filter's own paging.
+func (s *RepriceService) matchProducts(ctx context.Context, filter domain.FilterOptions) ([]string, error) {
+	filter.PageRequest = domain.PageRequest{Limit: repricePageSize}
+
+	var ids []string
+	seen := make(map[string]bool)
+	for {
+		page, err := s.productSvc.SearchProducts(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, product := range page.Products {
+			if seen[product.ID] {
+				continue
+			}
+			seen[product.ID] = true
+			ids = append(ids, product.ID)
+		}
+		if len(ids) > maxRepriceProducts {
+			return nil, fmt.Errorf("%w: filter matches more than %d products", ErrInvalidReprice, maxRepriceProducts)
+		}
+		if page.NextCursor == "" {
+			return ids, nil
+		}
+		filter.Cursor = page.NextCursor
+	}
+}
+
+// repriceProduct calculates a product's new price under a rule. Products the
+// rule cannot price, or whose price would not change, are marked skipped.
+func repriceProduct(product *domain.Product, rule domain.RepriceRule) domain.RepriceChange {
+	change := domain.RepriceChange{
+		ProductID: product.ID,
+		SKU:       product.SKU,
+		Name:      product.Name,
+		OldPrice:  product.BasePrice,
+		NewPrice:  product.BasePrice,
+		CostPrice: product.CostPrice,
+	}
+
+	var price float64
+	switch rule.Type {
+	case domain.RepricePercentage:
+		price = product.BasePrice * (1 + rule.Value/100)
+	case domain.RepriceFixedAmount:
+		price = product.BasePrice + rule.Value
+	case domain.RepriceTargetMargin:
+		if product.CostPrice <= 0 {
+			change.SkipReason = "no cost price"
+			return change
+		}
+		price = product.CostPrice / (1 - rule.Value/100)
+	}
+
+	if rule.Rounding == domain.RepriceRound99 {
+		price = roundUpTo99(price)
+	} else {
+		price = roundMoney(price)
+	}
+
+	switch {
+	case price < 0:
+		change.SkipReason = "price would be negative"
+	case price == product.BasePrice:
+		change.SkipReason = "unchanged"
+	default:
+		change.NewPrice = price
+		change.BelowCost = product.CostPrice > 0 && price < product.CostPrice
+	}
+	return change
+}
+
+// roundUpTo99 rounds a price up to the next amount ending in .99, so 4.20
+// becomes 4.99 and 4.99 stays 4.99.
+func roundUpTo99(price float64) float64 {
+	if price <= 0 {
+		return roundMoney(price)
+	}
+	// Whole cents avoid float error at the .99 boundary
+	cents := int64(math.Round(price * 100))
+	return float64((cents+100)/100*100-1) / 100
+}
+
+// validateRepriceRule checks that a rule's type, value and rounding are usable.
+func validateRepriceRule(rule domain.RepriceRule) error {
+	switch rule.Type {
+	case domain.RepricePercentage:
+		if rule.Value <= -100 {
+			return fmt.Errorf("%w: percentage must be greater than -100", ErrInvalidReprice)
+		}
+	case domain.RepriceFixedAmount:
+	case domain.RepriceTargetMargin:
+		if rule.Value < 0 || rule.Value >= 100 {
+			return fmt.Errorf("%w: target margin must be at least 0 and below 100", ErrInvalidReprice)
+		}
+	default:
+		return fmt.Errorf("%w: unknown rule type %q", ErrInvalidReprice, rule.Type)
+	}
+
+	if rule.Rounding != domain.RepriceRoundCents && rule.Rounding != domain.RepriceRound99 {
+		return fmt.Errorf("%w: unknown rounding %q", ErrInvalidReprice, rule.Rounding)
+	}
+	return nil
+}
+
+// hasRepriceFilter reports whether a filter narrows the products at all, so
+// that an empty request cannot reprice the whole catalogue by accident.
+func hasRepriceFilter(filter domain.FilterOptions) bool {
+	return filter.Query != "" || filter.CategoryID != "" || filter.MinPrice != nil || filter.MaxPrice != nil ||
+		len(filter.Properties) > 0 || len(filter.Filters) > 0 || filter.ChangedSince != nil
+}
+
+// addRepriceChange records a product's change and updates the counts.
+func addRepriceChange(result *domain.RepriceResult, change domain.RepriceChange) {
+	result.Changes = append(result.Changes, change)
+	if change.SkipReason != "" {
+		result.Skipped++
+	} else {
+		result.Updated++
+	}
+}
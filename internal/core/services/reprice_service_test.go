@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+func newRepriceTestService(products []*domain.Product) (*RepriceService, *mockSaleTxManager) {
+	txManager := newPackTestTxManager(products, nil)
+	txManager.categoryRepo.categories["drinks"] = &domain.Category{ID: "drinks", Name: "Drinks"}
+	productSvc := NewProductService(txManager.productRepo, txManager.categoryRepo, NewAuditService(txManager.auditRepo), txManager, &mockSynonymRepository{})
+	return NewRepriceService(productSvc, txManager.categoryRepo, txManager), txManager
+}
+
+func repriceTestProducts() []*domain.Product {
+	return []*domain.Product{
+		{ID: "p1", SKU: "COLA", Name: "Cola", BasePrice: 1.20, CostPrice: 0.80, CategoryID: "drinks"},
+		{ID: "p2", SKU: "WATER", Name: "Water", BasePrice: 0.50, CategoryID: "drinks"},
+	}
+}
+
+// --- RepriceService Tests ---
+
+func TestRepriceProducts_DryRunLeavesPrices(t *testing.T) {
+	svc, txManager := newRepriceTestService(repriceTestProducts())
+
+	result, err := svc.RepriceProducts(context.Background(), ports.RepriceRequest{
+		Filter: domain.FilterOptions{CategoryID: "drinks"},
+		Rule:   domain.RepriceRule{Type: domain.RepricePercentage, Value: 10},
+		DryRun: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.DryRun || result.BatchID != "" {
+		t.Errorf("expected dry run without batch, got %+v", result)
+	}
+	if result.Updated != 2 || len(result.Changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", result)
+	}
+	if result.Changes[0].OldPrice != 1.20 || result.Changes[0].NewPrice != 1.32 {
+		t.Errorf("expected 1.20 -> 1.32, got %+v", result.Changes[0])
+	}
+	if txManager.productRepo.products[0].BasePrice != 1.20 {
+		t.Errorf("dry run changed price to %.2f", txManager.productRepo.products[0].BasePrice)
+	}
+	if len(txManager.auditRepo.logs) != 0 {
+		t.Errorf("dry run wrote %d audit logs", len(txManager.auditRepo.logs))
+	}
+}
+
+func TestRepriceProducts_PercentageRoundsTo99(t *testing.T) {
+	svc, _ := newRepriceTestService(repriceTestProducts())
+
+	result, err := svc.RepriceProducts(context.Background(), ports.RepriceRequest{
+		Filter: domain.FilterOptions{Query: "drinks"},
+		Rule:   domain.RepriceRule{Type: domain.RepricePercentage, Value: 5, Rounding: domain.RepriceRound99},
+		DryRun: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 1.26 -> 1.99, 0.525 -> 0.99
+	if result.Changes[0].NewPrice != 1.99 || result.Changes[1].NewPrice != 0.99 {
+		t.Errorf("expected 1.99 and 0.99, got %.2f and %.2f", result.Changes[0].NewPrice, result.Changes[1].NewPrice)
+	}
+}
+
+func TestRepriceProducts_TargetMarginSkipsWithoutCost(t *testing.T) {
+	svc, _ := newRepriceTestService(repriceTestProducts())
+
+	result, err := svc.RepriceProducts(context.Background(), ports.RepriceRequest{
+		Filter: domain.FilterOptions{CategoryID: "drinks"},
+		Rule:   domain.RepriceRule{Type: domain.RepriceTargetMargin, Value: 50},
+		DryRun: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Changes[0].NewPrice != 1.60 {
+		t.Errorf("expected 1.60 at 50%% margin, got %.2f", result.Changes[0].NewPrice)
+	}
+	if result.Changes[1].SkipReason != "no cost price" || result.Skipped != 1 {
+		t.Errorf("expected product without cost to be skipped, got %+v", result.Changes[1])
+	}
+}
+
+func TestRepriceProducts_FlagsBelowCost(t *testing.T) {
+	svc, _ := newRepriceTestService(repriceTestProducts())
+
+	result, err := svc.RepriceProducts(context.Background(), ports.RepriceRequest{
+		Filter: domain.FilterOptions{CategoryID: "drinks"},
+		Rule:   domain.RepriceRule{Type: domain.RepriceFixedAmount, Value: -0.45},
+		DryRun: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.Changes[0].BelowCost || result.Changes[0].NewPrice != 0.75 {
+		t.Errorf("expected 0.75 flagged below cost, got %+v", result.Changes[0])
+	}
+	if result.Changes[1].BelowCost {
+		t.Errorf("product without cost should not be flagged, got %+v", result.Changes[1])
+	}
+}
+
+func TestRepriceProducts_CommitUpdatesAndAudits(t *testing.T) {
+	svc, txManager := newRepriceTestService(repriceTestProducts())
+
+	result, err := svc.RepriceProducts(context.Background(), ports.RepriceRequest{
+		Filter: domain.FilterOptions{CategoryID: "drinks"},
+		Rule:   domain.RepriceRule{Type: domain.RepriceTargetMargin, Value: 50},
+		UserID: "manager",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.BatchID == "" {
+		t.Error("expected a batch ID")
+	}
+	if txManager.productRepo.products[0].BasePrice != 1.60 {
+		t.Errorf("expected price 1.60, got %.2f", txManager.productRepo.products[0].BasePrice)
+	}
+	if txManager.productRepo.products[1].BasePrice != 0.50 {
+		t.Errorf("skipped product changed to %.2f", txManager.productRepo.products[1].BasePrice)
+	}
+	if len(txManager.auditRepo.logs) != 1 {
+		t.Fatalf("expected 1 audit log, got %d", len(txManager.auditRepo.logs))
+	}
+	if log := txManager.auditRepo.logs[0]; log.Action != "PRODUCT_REPRICED" || log.UserID != "manager" {
+		t.Errorf("unexpected audit log %+v", log)
+	}
+}
+
+func TestRepriceProducts_Validation(t *testing.T) {
+	svc, _ := newRepriceTestService(repriceTestProducts())
+	filter := domain.FilterOptions{CategoryID: "drinks"}
+
+	tests := []struct {
+		name string
+		req  ports.RepriceRequest
+	}{
+		{"no filter", ports.RepriceRequest{Rule: domain.RepriceRule{Type: domain.RepricePercentage, Value: 5}}},
+		{"unknown type", ports.RepriceRequest{Filter: filter, Rule: domain.RepriceRule{Type: "double"}}},
+		{"percentage too low", ports.RepriceRequest{Filter: filter, Rule: domain.RepriceRule{Type: domain.RepricePercentage, Value: -100}}},
+		{"margin too high", ports.RepriceRequest{Filter: filter, Rule: domain.RepriceRule{Type: domain.RepriceTargetMargin, Value: 100}}},
+		{"unknown rounding", ports.RepriceRequest{Filter: filter, Rule: domain.RepriceRule{Type: domain.RepriceFixedAmount, Value: 1, Rounding: ".95"}}},
+		{"property without category", ports.RepriceRequest{
+			Filter: domain.FilterOptions{Properties: map[string]string{"brand": "nonexistent"}},
+			Rule:   domain.RepriceRule{Type: domain.RepricePercentage, Value: 5}, DryRun: true}},
+		{"unknown property", ports.RepriceRequest{
+			Filter: domain.FilterOptions{CategoryID: "drinks", Properties: map[string]string{"brnad": "Fizz"}},
+			Rule:   domain.RepriceRule{Type: domain.RepricePercentage, Value: 5}, DryRun: true}},
+		{"unknown typed filter", ports.RepriceRequest{
+			Filter: domain.FilterOptions{CategoryID: "drinks", Filters: []domain.PropertyFilter{{Key: "volume", Op: domain.FilterGt, Value: "1"}}},
+			Rule:   domain.RepriceRule{Type: domain.RepricePercentage, Value: 5}, DryRun: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.RepriceProducts(context.Background(), tt.req)
+			if !errors.Is(err, ErrInvalidReprice) {
+				t.Errorf("expected ErrInvalidReprice, got %v", err)
+			}
+		})
+	}
+}
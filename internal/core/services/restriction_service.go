@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// Reasons reported by RestrictionError.
+const (
+	RestrictionIDRequired        = "id_verification_required"
+	RestrictionUnderage          = "underage"
+	RestrictionOutsideSaleWindow = "outside_sale_window"
+	RestrictionQuantityExceeded  = "quantity_limit_exceeded"
+)
+
+// ErrRestrictedItem is matched by every RestrictionError via errors.Is.
+var ErrRestrictedItem = errors.New("restricted item")
+
+// ErrInvalidRestriction is returned when a restriction rule is malformed.
+var ErrInvalidRestriction = errors.New("invalid restriction")
+
+// RestrictionError reports a regulated item that cannot be sold as requested.
+type RestrictionError struct {
+	ProductID       string
+	Reason          string // One of the Restriction* reasons
+	MinimumAge      int
+	MaxQuantity     float64
+	SaleWindowStart string
+	SaleWindowEnd   string
+}
+
+// Error implements the error interface.
+func (e *RestrictionError) Error() string {
+	switch e.Reason {
+	case RestrictionIDRequired:
+		return fmt.Sprintf("%s: product %s requires ID verification (minimum age %d)", ErrRestrictedItem, e.ProductID, e.MinimumAge)
+	case RestrictionUnderage:
+		return fmt.Sprintf("%s: product %s cannot be sold to customers under %d", ErrRestrictedItem, e.ProductID, e.MinimumAge)
+	case RestrictionOutsideSaleWindow:
+		return fmt.Sprintf("%s: product %s can only be sold between %s and %s", ErrRestrictedItem, e.ProductID, e.SaleWindowStart, e.SaleWindowEnd)
+	case RestrictionQuantityExceeded:
+		return fmt.Sprintf("%s: product %s is limited to %v per transaction", ErrRestrictedItem, e.ProductID, e.MaxQuantity)
+	default:
+		return fmt.Sprintf("%s: product %s", ErrRestrictedItem, e.ProductID)
+	}
+}
+
+// Is reports whether target is ErrRestrictedItem.
+func (e *RestrictionError) Is(target error) bool {
+	return target == ErrRestrictedItem
+}
+
+// RestrictionService implements management of checkout restriction rules.
+type RestrictionService struct {
+	restrictionRepo ports.RestrictionRepository
+}
+
+// NewRestrictionService creates a new restriction service instance.
+func NewRestrictionService(restrictionRepo ports.RestrictionRepository) *RestrictionService {
+	return &RestrictionService{
+		restrictionRepo: restrictionRepo,
+	}
+}
+
+// CreateRestriction validates and stores a new restriction rule.
+func (s *RestrictionService) CreateRestriction(ctx context.Context, restriction *domain.SaleRestriction) error {
+	if (restriction.CategoryID == "") == (restriction.ProductID == "") {
+		return fmt.Errorf("%w: exactly one of category_id or product_id is required", ErrInvalidRestriction)
+	}
+	if restriction.MinimumAge < 0 || restriction.MaxQuantityPerTransaction < 0 {
+		return fmt.Errorf("%w: minimum_age and max_quantity_per_transaction must not be negative", ErrInvalidRestriction)
+	}
+	if (restriction.SaleWindowStart == "") != (restriction.SaleWindowEnd == "") {
+		return fmt.Errorf("%w: sale window needs both a start and an end", ErrInvalidRestriction)
+	}
+	if restriction.SaleWindowStart != "" {
+		start, err := parseTimeOfDay(restriction.SaleWindowStart)
+		if err != nil {
+			return fmt.Errorf("%w: sale_window_start: %v", ErrInvalidRestriction, err)
+		}
+		end, err := parseTimeOfDay(restriction.SaleWindowEnd)
+		if err != nil {
+			return fmt.Errorf("%w: sale_window_end: %v", ErrInvalidRestriction, err)
+		}
+		// An empty window would block the product at every hour
+		if start == end {
+			return fmt.Errorf("%w: sale window start and end must differ", ErrInvalidRestriction)
+		}
+	}
+
+	restriction.ID = uuid.New().String()
+	restriction.CreatedAt = time.Now()
+	return s.restrictionRepo.Create(ctx, restriction)
+}
+
+// GetRestriction retrieves a restriction rule by ID.
+func (s *RestrictionService) GetRestriction(ctx context.Context, id string) (*domain.SaleRestriction, error) {
+	return s.restrictionRepo.GetByID(ctx, id)
+}
+
+// ListRestrictions retrieves restriction rules with pagination.
+func (s *RestrictionService) ListRestrictions(ctx context.Context, limit, offset int) ([]*domain.SaleRestriction, error) {
+	return s.restrictionRepo.List(ctx, limit, offset)
+}
+
+// DeleteRestriction deletes a restriction rule.
+func (s *RestrictionService) DeleteRestriction(ctx context.Context, id string) error {
+	return s.restrictionRepo.Delete(ctx, id)
+}
+
+// checkRestrictions enforces every rule for a product against the sale being
+// processed. quantity is the product's total quantity across the whole sale.
+// Time windows and quantity limits are checked before ID so the till is not
+// asked for an ID check on a sale that would be refused anyway.
+func checkRestrictions(rules []*domain.SaleRestriction, productID string, quantity float64, req ports.SaleRequest, now time.Time) error {
+	for _, rule := range rules {
+		if rule.SaleWindowStart != "" && !withinSaleWindow(rule.SaleWindowStart, rule.SaleWindowEnd, now) {
+			return &RestrictionError{
+				ProductID:       productID,
+				Reason:          RestrictionOutsideSaleWindow,
+				SaleWindowStart: rule.SaleWindowStart,
+				SaleWindowEnd:   rule.SaleWindowEnd,
+			}
+		}
+		if rule.MaxQuantityPerTransaction > 0 && quantity > rule.MaxQuantityPerTransaction {
+			return &RestrictionError{
+				ProductID:   productID,
+				Reason:      RestrictionQuantityExceeded,
+				MaxQuantity: rule.MaxQuantityPerTransaction,
+			}
+		}
+	}
+
+	for _, rule := range rules {
+		if rule.MinimumAge <= 0 {
+			continue
+		}
+		if !req.IDVerified {
+			return &RestrictionError{
+				ProductID:  productID,
+				Reason:     RestrictionIDRequired,
+				MinimumAge: rule.MinimumAge,
+			}
+		}
+		if req.CustomerBirthDate != nil && ageOn(*req.CustomerBirthDate, now) < rule.MinimumAge {
+			return &RestrictionError{
+				ProductID:  productID,
+				Reason:     RestrictionUnderage,
+				MinimumAge: rule.MinimumAge,
+			}
+		}
+	}
+
+	return nil
+}
+
+// withinSaleWindow reports whether now falls inside [start, end). Windows whose
+// end is before their start wrap past midnight, e.g. 22:00-02:00.
+func withinSaleWindow(start, end string, now time.Time) bool {
+	from, err := parseTimeOfDay(start)
+	if err != nil {
+		return false
+	}
+	to, err := parseTimeOfDay(end)
+	if err != nil {
+		return false
+	}
+
+	minute := now.Hour()*60 + now.Minute()
+	if from <= to {
+		return minute >= from && minute < to
+	}
+	return minute >= from || minute < to
+}
+
+// parseTimeOfDay parses "HH:MM" into minutes since midnight.
+func parseTimeOfDay(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("expected HH:MM, got %q", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// ageOn returns the age in whole years on the given date.
+func ageOn(birthDate, date time.Time) int {
+	age := date.Year() - birthDate.Year()
+	if date.Month() < birthDate.Month() || (date.Month() == birthDate.Month() && date.Day() < birthDate.Day()) {
+		age--
+	}
+	return age
+}
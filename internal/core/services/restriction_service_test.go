@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// --- Mock RestrictionRepository ---
+
+type mockRestrictionRepository struct {
+	restrictions []*domain.SaleRestriction
+}
+
+func (m *mockRestrictionRepository) Create(_ context.Context, r *domain.SaleRestriction) error {
+	m.restrictions = append(m.restrictions, r)
+	return nil
+}
+func (m *mockRestrictionRepository) GetByID(_ context.Context, id string) (*domain.SaleRestriction, error) {
+	for _, r := range m.restrictions {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return nil, errors.New("restriction not found")
+}
+func (m *mockRestrictionRepository) List(_ context.Context, _, _ int) ([]*domain.SaleRestriction, error) {
+	return m.restrictions, nil
+}
+func (m *mockRestrictionRepository) ListForProduct(_ context.Context, productID string, categoryIDs []string) ([]*domain.SaleRestriction, error) {
+	var out []*domain.SaleRestriction
+	for _, r := range m.restrictions {
+		matched := r.ProductID == productID
+		for _, id := range categoryIDs {
+			matched = matched || (r.CategoryID != "" && r.CategoryID == id)
+		}
+		if matched {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+func (m *mockRestrictionRepository) Delete(_ context.Context, _ string) error { return nil }
+
+func newRestrictedSaleService(rules []*domain.SaleRestriction, clock time.Time) (*SaleService, *mockSaleTxManager) {
+	txManager := &mockSaleTxManager{
+		productRepo: &mockProductRepository{products: []*domain.Product{
+			{ID: "p1", Name: "Whisky", SKU: "LIQ-001", CategoryID: "liquor", BasePrice: 30, Quantity: 20},
+			{ID: "p2", Name: "Bread", SKU: "BRD-001", BasePrice: 2, Quantity: 20},
+		}},
+		categoryRepo: &mockCategoryRepository{categories: map[string]*domain.Category{
+			"liquor": {ID: "liquor", Name: "Liquor"},
+		}},
+		auditRepo:       &mockAuditLogRepository{},
+		saleRepo:        &mockSaleRepository{},
+		restrictionRepo: &mockRestrictionRepository{restrictions: rules},
+	}
+	svc := NewSaleService(txManager)
+	svc.now = func() time.Time { return clock }
+	return svc, txManager
+}
+
+// --- Restriction Tests ---
+
+func TestProcessSale_RestrictedItemRequiresIDVerification(t *testing.T) {
+	rules := []*domain.SaleRestriction{{ID: "r1", CategoryID: "liquor", MinimumAge: 18}}
+	svc, txManager := newRestrictedSaleService(rules, time.Date(2026, 5, 1, 15, 0, 0, 0, time.Local))
+
+	_, err := svc.ProcessSale(context.Background(), ports.SaleRequest{Items: []ports.SaleItemRequest{
+		{ProductID: "p1", Quantity: 1},
+	}})
+
+	var restrictionErr *RestrictionError
+	if !errors.As(err, &restrictionErr) {
+		t.Fatalf("expected RestrictionError, got %v", err)
+	}
+	if restrictionErr.Reason != RestrictionIDRequired || restrictionErr.MinimumAge != 18 {
+		t.Fatalf("expected ID check for age 18, got %+v", restrictionErr)
+	}
+	if !errors.Is(err, ErrRestrictedItem) {
+		t.Fatal("expected error to match ErrRestrictedItem")
+	}
+	if len(txManager.saleRepo.sales) != 0 {
+		t.Fatal("expected no sale to be recorded")
+	}
+
+	// With the cashier's attestation the sale goes through
+	sale, err := svc.ProcessSale(context.Background(), ports.SaleRequest{
+		CashierID:  "cashier-7",
+		IDVerified: true,
+		Items:      []ports.SaleItemRequest{{ProductID: "p1", Quantity: 1}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !sale.IDVerified || sale.CashierID != "cashier-7" {
+		t.Fatalf("expected sale to record the attestation, got %+v", sale)
+	}
+}
+
+func TestProcessSale_RestrictedItemUnderage(t *testing.T) {
+	rules := []*domain.SaleRestriction{{ID: "r1", ProductID: "p1", MinimumAge: 18}}
+	svc, _ := newRestrictedSaleService(rules, time.Date(2026, 5, 1, 15, 0, 0, 0, time.Local))
+
+	birthDate := time.Date(2008, 5, 2, 0, 0, 0, 0, time.UTC) // turns 18 tomorrow
+	_, err := svc.ProcessSale(context.Background(), ports.SaleRequest{
+		IDVerified:        true,
+		CustomerBirthDate: &birthDate,
+		Items:             []ports.SaleItemRequest{{ProductID: "p1", Quantity: 1}},
+	})
+
+	var restrictionErr *RestrictionError
+	if !errors.As(err, &restrictionErr) || restrictionErr.Reason != RestrictionUnderage {
+		t.Fatalf("expected underage RestrictionError, got %v", err)
+	}
+}
+
+func TestProcessSale_RestrictedItemOutsideSaleWindow(t *testing.T) {
+	rules := []*domain.SaleRestriction{{ID: "r1", CategoryID: "liquor", SaleWindowStart: "08:00", SaleWindowEnd: "22:00"}}
+	svc, _ := newRestrictedSaleService(rules, time.Date(2026, 5, 1, 23, 30, 0, 0, time.Local))
+
+	_, err := svc.ProcessSale(context.Background(), ports.SaleRequest{
+		IDVerified: true,
+		Items:      []ports.SaleItemRequest{{ProductID: "p1", Quantity: 1}},
+	})
+
+	var restrictionErr *RestrictionError
+	if !errors.As(err, &restrictionErr) || restrictionErr.Reason != RestrictionOutsideSaleWindow {
+		t.Fatalf("expected outside-window RestrictionError, got %v", err)
+	}
+
+	// Unrestricted products are unaffected
+	_, err = svc.ProcessSale(context.Background(), ports.SaleRequest{
+		Items: []ports.SaleItemRequest{{ProductID: "p2", Quantity: 1}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error for unrestricted product: %v", err)
+	}
+}
+
+func TestProcessSale_RestrictedItemQuantityAcrossLines(t *testing.T) {
+	rules := []*domain.SaleRestriction{{ID: "r1", ProductID: "p1", MaxQuantityPerTransaction: 2}}
+	svc, _ := newRestrictedSaleService(rules, time.Date(2026, 5, 1, 15, 0, 0, 0, time.Local))
+
+	_, err := svc.ProcessSale(context.Background(), ports.SaleRequest{Items: []ports.SaleItemRequest{
+		{ProductID: "p1", Quantity: 2},
+		{ProductID: "p1", Quantity: 1},
+	}})
+
+	var restrictionErr *RestrictionError
+	if !errors.As(err, &restrictionErr) || restrictionErr.Reason != RestrictionQuantityExceeded {
+		t.Fatalf("expected quantity RestrictionError, got %v", err)
+	}
+}
+
+func TestProcessSale_RestrictionOnParentCategory(t *testing.T) {
+	rules := []*domain.SaleRestriction{{ID: "r1", CategoryID: "alcohol", MinimumAge: 18}}
+	svc, txManager := newRestrictedSaleService(rules, time.Date(2026, 5, 1, 15, 0, 0, 0, time.Local))
+	txManager.categoryRepo.categories["alcohol"] = &domain.Category{ID: "alcohol", Name: "Alcohol"}
+	txManager.categoryRepo.categories["liquor"].ParentID = "alcohol"
+
+	_, err := svc.ProcessSale(context.Background(), ports.SaleRequest{Items: []ports.SaleItemRequest{
+		{ProductID: "p1", Quantity: 1},
+	}})
+
+	var restrictionErr *RestrictionError
+	if !errors.As(err, &restrictionErr) || restrictionErr.Reason != RestrictionIDRequired {
+		t.Fatalf("expected the Alcohol restriction to apply to Liquor, got %v", err)
+	}
+}
+
+func TestWithinSaleWindow_WrapsMidnight(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2026, 5, 1, h, m, 0, 0, time.Local) }
+
+	if !withinSaleWindow("22:00", "02:00", at(23, 0)) || !withinSaleWindow("22:00", "02:00", at(1, 59)) {
+		t.Fatal("expected times inside an overnight window to be allowed")
+	}
+	if withinSaleWindow("22:00", "02:00", at(2, 0)) || withinSaleWindow("22:00", "02:00", at(12, 0)) {
+		t.Fatal("expected times outside an overnight window to be refused")
+	}
+}
+
+func TestCreateRestriction_Validation(t *testing.T) {
+	svc := NewRestrictionService(&mockRestrictionRepository{})
+
+	cases := []*domain.SaleRestriction{
+		{MinimumAge: 18}, // no scope
+		{CategoryID: "c1", ProductID: "p1", MinimumAge: 18}, // both scopes
+		{CategoryID: "c1", SaleWindowStart: "08:00"},        // half a window
+		{CategoryID: "c1", SaleWindowStart: "8am", SaleWindowEnd: "22:00"},
+		{CategoryID: "c1", SaleWindowStart: "22:00", SaleWindowEnd: "22:00"}, // empty window
+	}
+	for i, c := range cases {
+		if err := svc.CreateRestriction(context.Background(), c); !errors.Is(err, ErrInvalidRestriction) {
+			t.Fatalf("case %d: expected ErrInvalidRestriction, got %v", i, err)
+		}
+	}
+
+	valid := &domain.SaleRestriction{CategoryID: "c1", MinimumAge: 18, SaleWindowStart: "08:00", SaleWindowEnd: "22:00"}
+	if err := svc.CreateRestriction(context.Background(), valid); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if valid.ID == "" {
+		t.Fatal("expected an ID to be assigned")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,65 +17,180 @@ var ErrInsufficientStock = errors.New("insufficient stock")
 
 // SaleService implements the sale processing logic.
 type SaleService struct {
-	txManager ports.TransactionManager
+	txManager     ports.TransactionManager
+	barcodeRules  []domain.EmbeddedBarcodeRule
+	costingMethod string
+	now           func() time.Time // clock used for sale timestamps and sale windows
 }
 
 // NewSaleService creates a new sale service instance.
 func NewSaleService(txManager ports.TransactionManager) *SaleService {
 	return &SaleService{
-		txManager: txManager,
+		txManager:     txManager,
+		barcodeRules:  DefaultEmbeddedBarcodeRules,
+		costingMethod: domain.CostingWeightedAverage,
+		now:           time.Now,
 	}
 }
 
-// ProcessSale executes an atomic checkout: validates stock, decrements quantities,
-// creates sale items with price snapshots, and records the sale with an audit log.
-func (s *SaleService) ProcessSale(ctx context.Context, items []ports.SaleItemRequest) (*domain.Sale, error) {
+// SetCostingMethod selects how the cost of sold stock is snapshotted. Under
+// FIFO a line is costed from the oldest cost layers it consumes; otherwise it
+// takes the product's cost price.
+func (s *SaleService) SetCostingMethod(method string) {
+	s.costingMethod = method
+}
+
+// SetEmbeddedBarcodeRules replaces the price-embedded barcode layouts
+// recognised at checkout.
+func (s *SaleService) SetEmbeddedBarcodeRules(rules []domain.EmbeddedBarcodeRule) {
+	s.barcodeRules = rules
+}
+
+// saleLine is a requested sale item resolved to a product and a quantity in
+// the product's unit of measure.
+type saleLine struct {
+	productID string
+	packID    string
+	quantity  float64
+	lineTotal *float64 // Fixed line total from a price-embedded label or pack price
+}
+
+// ProcessSale executes an atomic checkout: validates stock and restriction rules,
+// decrements quantities, creates sale items with price snapshots, and records
+// the sale with an audit log. When the request names a price list, unit prices
+// are resolved from it; lines with a fixed total, such as packs and
+// price-embedded labels, keep that total.
+func (s *SaleService) ProcessSale(ctx context.Context, req ports.SaleRequest) (*domain.Sale, error) {
+	items := req.Items
 	if len(items) == 0 {
 		return nil, errors.New("no items in sale")
 	}
 
 	sale := &domain.Sale{
-		ID:        uuid.New().String(),
-		CreatedAt: time.Now(),
+		ID:          uuid.New().String(),
+		CashierID:   req.CashierID,
+		IDVerified:  req.IDVerified,
+		PriceListID: req.PriceListID,
+		CreatedAt:   s.now(),
 	}
 
 	err := s.txManager.WithTx(ctx, func(tx ports.Ports) error {
-		var total float64
+		if req.PriceListID != "" {
+			if _, err := tx.PriceListRepo.GetByID(ctx, req.PriceListID); err != nil {
+				if errors.Is(err, ports.ErrNotFound) {
+					return fmt.Errorf("%w: %s not found", ErrInvalidPriceList, req.PriceListID)
+				}
+				return fmt.Errorf("price list lookup: %w", err)
+			}
+		}
 
+		// Resolve scanned barcodes to products and quantities first, so
+		// per-transaction limits see the total quantity of each product.
+		lines := make([]saleLine, 0, len(items))
+		quantities := make(map[string]float64, len(items))
 		for _, item := range items {
-			if item.Quantity <= 0 {
-				return fmt.Errorf("invalid quantity for product %s", item.ProductID)
+			line, err := s.resolveLine(ctx, tx, item)
+			if err != nil {
+				return err
+			}
+			lines = append(lines, line)
+			quantities[line.productID] += line.quantity
+		}
+
+		var total float64
+
+		for _, line := range lines {
+			product, err := tx.ProductRepo.GetByID(ctx, line.productID)
+			if err != nil {
+				return fmt.Errorf("product %s: %w", line.productID, err)
+			}
+
+			if err := checkQuantity(product, line.quantity); err != nil {
+				return err
+			}
+
+			// Block products under an active recall
+			recall, err := tx.RecallRepo.GetActiveByProduct(ctx, product.ID)
+			if err != nil {
+				return fmt.Errorf("recall check for product %s: %w", product.ID, err)
+			}
+			if recall != nil {
+				return fmt.Errorf("%w: product %s is under recall %s", ErrProductRecalled, product.ID, recall.ID)
 			}
 
-			product, err := tx.ProductRepo.GetByID(ctx, item.ProductID)
+			// Enforce age, time-of-day and quantity restrictions, including
+			// those set on any category above the product's own
+			lineage, err := categoryLineage(ctx, tx.CategoryRepo, product.CategoryID)
+			if err != nil {
+				return fmt.Errorf("category of product %s: %w", product.ID, err)
+			}
+			rules, err := tx.RestrictionRepo.ListForProduct(ctx, product.ID, lineage)
 			if err != nil {
-				return fmt.Errorf("product %s: %w", item.ProductID, err)
+				return fmt.Errorf("restriction lookup for product %s: %w", product.ID, err)
+			}
+			if err := checkRestrictions(rules, product.ID, quantities[product.ID], req, sale.CreatedAt); err != nil {
+				return err
 			}
 
-			if product.Quantity < item.Quantity {
-				return fmt.Errorf("%w: product %s has %d in stock, requested %d",
-					ErrInsufficientStock, item.ProductID, product.Quantity, item.Quantity)
+			if product.Quantity < line.quantity {
+				return fmt.Errorf("%w: product %s has %v %s in stock, requested %v",
+					ErrInsufficientStock, product.ID, product.Quantity, unitLabel(product), line.quantity)
+			}
+
+			// Consume cost layers oldest first
+			layers, err := openCostLayers(ctx, tx.CostLayerRepo, product, sale.CreatedAt)
+			if err != nil {
+				return err
+			}
+			consumedCost, layers, err := consumeCostLayers(ctx, tx.CostLayerRepo, layers, line.quantity)
+			if err != nil {
+				return err
+			}
+			unitCost := product.CostPrice
+			if s.costingMethod == domain.CostingFIFO {
+				unitCost = roundCost(consumedCost / line.quantity)
+				if cost, ok := layerUnitCost(layers); ok {
+					product.CostPrice = roundCost(cost)
+				}
 			}
 
 			// Decrement stock
-			product.Quantity -= item.Quantity
+			product.Quantity = roundQuantity(product.Quantity-line.quantity, product.QuantityPrecision)
 			if err := tx.ProductRepo.Update(ctx, product); err != nil {
-				return fmt.Errorf("update stock for product %s: %w", item.ProductID, err)
+				return fmt.Errorf("update stock for product %s: %w", product.ID, err)
+			}
+
+			// Quantity breaks see the product's total quantity in the sale
+			unitPrice := product.BasePrice
+			if req.PriceListID != "" && line.lineTotal == nil {
+				rules, err := tx.PriceListRepo.ListRulesFor(ctx, req.PriceListID, product.ID, lineage)
+				if err != nil {
+					return fmt.Errorf("price list lookup for product %s: %w", product.ID, err)
+				}
+				unitPrice = resolveListPrice(product, lineage, rules, quantities[product.ID])
 			}
 
 			// Create sale item with price snapshots
 			saleItem := &domain.SaleItem{
 				SaleID:    sale.ID,
-				ProductID: item.ProductID,
-				Quantity:  item.Quantity,
-				UnitPrice: product.BasePrice,
-				CostPrice: product.CostPrice,
+				ProductID: product.ID,
+				PackID:    line.packID,
+				Quantity:  line.quantity,
+				UnitPrice: unitPrice,
+				CostPrice: unitCost,
+			}
+			if line.lineTotal != nil {
+				saleItem.UnitPrice = *line.lineTotal / line.quantity
 			}
 			if err := tx.SaleRepo.CreateSaleItem(ctx, saleItem); err != nil {
-				return fmt.Errorf("create sale item for product %s: %w", item.ProductID, err)
+				return fmt.Errorf("create sale item for product %s: %w", product.ID, err)
 			}
 
-			total += product.BasePrice * float64(item.Quantity)
+			if line.lineTotal != nil {
+				total += *line.lineTotal
+			} else {
+				total += unitPrice * line.quantity
+			}
 		}
 
 		sale.TotalAmount = total
@@ -83,19 +199,22 @@ func (s *SaleService) ProcessSale(ctx context.Context, items []ports.SaleItemReq
 		}
 
 		// Audit log
-		lastLog, err := tx.AuditRepo.GetLastLog(ctx)
-		prevHash := ""
-		if err == nil && lastLog != nil {
-			prevHash = lastLog.CurrentHash
+		txAuditSvc := newTxAuditService(ctx, tx.AuditRepo)
+		userID := sale.CashierID
+		if userID == "" {
+			userID = "system"
 		}
-
-		txAuditSvc := NewAuditService(tx.AuditRepo)
-		txAuditSvc.SetPrevHash(prevHash)
-		if err := txAuditSvc.LogAction(ctx, "SALE_PROCESSED", "system", map[string]interface{}{
+		payload := map[string]interface{}{
 			"sale_id":      sale.ID,
 			"total_amount": sale.TotalAmount,
 			"item_count":   len(items),
-		}); err != nil {
+			"cashier_id":   sale.CashierID,
+			"id_verified":  sale.IDVerified,
+		}
+		if sale.PriceListID != "" {
+			payload["price_list_id"] = sale.PriceListID
+		}
+		if err := txAuditSvc.LogAction(ctx, "SALE_PROCESSED", userID, payload); err != nil {
 			return fmt.Errorf("audit log: %w", err)
 		}
 
@@ -108,3 +227,115 @@ func (s *SaleService) ProcessSale(ctx context.Context, items []ports.SaleItemReq
 
 	return sale, nil
 }
+
+// resolveLine maps a requested item to a product and quantity. Items may name
+// the product or pack directly or carry a scanned barcode, which is matched
+// against product and pack barcodes, then decoded as a price-embedded label
+// when it matches a configured layout and finally looked up as a SKU.
+func (s *SaleService) resolveLine(ctx context.Context, tx ports.Ports, item ports.SaleItemRequest) (saleLine, error) {
+	if item.ProductID != "" {
+		return saleLine{productID: item.ProductID, quantity: item.Quantity}, nil
+	}
+	if item.PackID != "" {
+		pack, err := tx.PackRepo.GetByID(ctx, item.PackID)
+		if err != nil {
+			return saleLine{}, fmt.Errorf("pack %s: %w", item.PackID, err)
+		}
+		return packLine(ctx, tx, pack, item.Quantity)
+	}
+	if item.Barcode == "" {
+		return saleLine{}, errors.New("each item needs a product_id, pack_id or barcode")
+	}
+
+	// Scans default to one unit, or one pack for pack barcodes
+	quantity := item.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+
+	// Registered barcodes come first, so a product's own code is never read
+	// as an in-store label that happens to share a layout's prefix
+	match, err := lookupBarcode(ctx, tx.BarcodeRepo, tx.PackRepo, tx.ProductRepo, item.Barcode)
+	if err != nil {
+		return saleLine{}, err
+	}
+	if match != nil && match.Pack != nil {
+		return packLine(ctx, tx, match.Pack, quantity)
+	}
+	if match != nil {
+		return saleLine{productID: match.Product.ID, quantity: quantity}, nil
+	}
+
+	embedded, err := ParseEmbeddedBarcode(item.Barcode, s.barcodeRules)
+	if err != nil {
+		return saleLine{}, err
+	}
+
+	if embedded == nil {
+		product, err := tx.ProductRepo.GetBySKU(ctx, item.Barcode)
+		if err != nil {
+			return saleLine{}, fmt.Errorf("barcode %s: %w", item.Barcode, err)
+		}
+		return saleLine{productID: product.ID, quantity: quantity}, nil
+	}
+
+	product, err := tx.ProductRepo.GetBySKU(ctx, embedded.ItemCode)
+	if err != nil {
+		return saleLine{}, fmt.Errorf("barcode %s item code %s: %w", item.Barcode, embedded.ItemCode, err)
+	}
+
+	switch embedded.Kind {
+	case domain.EmbeddedWeight:
+		if product.UnitOfMeasure != domain.UnitKilogram {
+			return saleLine{}, fmt.Errorf("%w: weight barcode %s for product %s sold in %s",
+				ErrInvalidBarcode, item.Barcode, product.ID, unitLabel(product))
+		}
+		return saleLine{productID: product.ID, quantity: roundQuantity(embedded.Value, product.QuantityPrecision)}, nil
+	case domain.EmbeddedPrice:
+		if product.BasePrice <= 0 {
+			return saleLine{}, fmt.Errorf("%w: price barcode %s for product %s without a unit price",
+				ErrInvalidBarcode, item.Barcode, product.ID)
+		}
+		// Weighed goods take the label's weight to the nearest step of their
+		// precision; whole units must match the label exactly, or the stock
+		// taken would not be what was charged
+		price := embedded.Value
+		quantity := roundQuantity(price/product.BasePrice, product.QuantityPrecision)
+		if product.QuantityPrecision == 0 && math.Abs(quantity*product.BasePrice-price) > 1e-9 {
+			return saleLine{}, fmt.Errorf("%w: price barcode %s of %.2f is not a whole number of product %s at %.2f",
+				ErrInvalidBarcode, item.Barcode, price, product.ID, product.BasePrice)
+		}
+		return saleLine{productID: product.ID, quantity: quantity, lineTotal: &price}, nil
+	default:
+		return saleLine{}, fmt.Errorf("%w: unknown embedded kind %q", ErrInvalidBarcode, embedded.Kind)
+	}
+}
+
+// packLine converts a number of whole packs into base units charged at the
+// pack price.
+func packLine(ctx context.Context, tx ports.Ports, pack *domain.ProductPack, packs float64) (saleLine, error) {
+	if err := checkPackCount(pack, packs); err != nil {
+		return saleLine{}, err
+	}
+
+	product, err := tx.ProductRepo.GetByID(ctx, pack.ProductID)
+	if err != nil {
+		return saleLine{}, fmt.Errorf("product %s: %w", pack.ProductID, err)
+	}
+
+	price := packPrice(pack, product) * packs
+	return saleLine{
+		productID: product.ID,
+		packID:    pack.ID,
+		quantity:  roundQuantity(packs*pack.UnitsPerPack, product.QuantityPrecision),
+		lineTotal: &price,
+	}, nil
+}
+
+// unitLabel returns the product's unit of measure, defaulting to each.
+func unitLabel(product *domain.Product) string {
+	if product.UnitOfMeasure == "" {
+		return domain.UnitEach
+	}
+	return product.UnitOfMeasure
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -27,21 +28,80 @@ func (m *mockSaleRepository) CreateSaleItem(_ context.Context, item *domain.Sale
 	return nil
 }
 
+func (m *mockSaleRepository) GetSale(_ context.Context, id string) (*domain.Sale, error) {
+	for _, s := range m.sales {
+		if s.ID == id {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("sale %w", ports.ErrNotFound)
+}
+
+func (m *mockSaleRepository) ListSaleItems(_ context.Context, saleID string) ([]*domain.SaleItem, error) {
+	var out []*domain.SaleItem
+	for _, item := range m.saleItems {
+		if item.SaleID == saleID {
+			out = append(out, item)
+		}
+	}
+	return out, nil
+}
+
 // --- Mock TransactionManager for SaleService tests ---
 
 type mockSaleTxManager struct {
-	productRepo  *mockProductRepository
-	categoryRepo *mockCategoryRepository
-	auditRepo    *mockAuditLogRepository
-	saleRepo     *mockSaleRepository
+	productRepo     *mockProductRepository
+	categoryRepo    *mockCategoryRepository
+	auditRepo       *mockAuditLogRepository
+	saleRepo        *mockSaleRepository
+	recallRepo      *mockRecallRepository
+	restrictionRepo *mockRestrictionRepository
+	packRepo        *mockPackRepository
+	barcodeRepo     *mockBarcodeRepository
+	priceRepo       *mockPriceRepository
+	priceListRepo   *mockPriceListRepository
+	costLayerRepo   *mockCostLayerRepository
+	associationRepo *mockAssociationRepository
 }
 
 func (m *mockSaleTxManager) WithTx(_ context.Context, fn func(tx ports.Ports) error) error {
+	if m.recallRepo == nil {
+		m.recallRepo = &mockRecallRepository{}
+	}
+	if m.restrictionRepo == nil {
+		m.restrictionRepo = &mockRestrictionRepository{}
+	}
+	if m.packRepo == nil {
+		m.packRepo = &mockPackRepository{}
+	}
+	if m.barcodeRepo == nil {
+		m.barcodeRepo = &mockBarcodeRepository{}
+	}
+	if m.priceRepo == nil {
+		m.priceRepo = &mockPriceRepository{}
+	}
+	if m.priceListRepo == nil {
+		m.priceListRepo = &mockPriceListRepository{}
+	}
+	if m.costLayerRepo == nil {
+		m.costLayerRepo = &mockCostLayerRepository{}
+	}
+	if m.associationRepo == nil {
+		m.associationRepo = &mockAssociationRepository{}
+	}
 	txPorts := ports.Ports{
-		ProductRepo:  m.productRepo,
-		CategoryRepo: m.categoryRepo,
-		AuditRepo:    m.auditRepo,
-		SaleRepo:     m.saleRepo,
+		ProductRepo:     m.productRepo,
+		CategoryRepo:    m.categoryRepo,
+		AuditRepo:       m.auditRepo,
+		SaleRepo:        m.saleRepo,
+		RecallRepo:      m.recallRepo,
+		RestrictionRepo: m.restrictionRepo,
+		PackRepo:        m.packRepo,
+		BarcodeRepo:     m.barcodeRepo,
+		PriceRepo:       m.priceRepo,
+		PriceListRepo:   m.priceListRepo,
+		CostLayerRepo:   m.costLayerRepo,
+		AssociationRepo: m.associationRepo,
 	}
 	return fn(txPorts)
 }
@@ -66,10 +126,10 @@ func TestProcessSale_Success(t *testing.T) {
 
 	svc := NewSaleService(txManager)
 
-	sale, err := svc.ProcessSale(context.Background(), []ports.SaleItemRequest{
+	sale, err := svc.ProcessSale(context.Background(), ports.SaleRequest{Items: []ports.SaleItemRequest{
 		{ProductID: "p1", Quantity: 2},
 		{ProductID: "p2", Quantity: 1},
-	})
+	}})
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -85,10 +145,10 @@ func TestProcessSale_Success(t *testing.T) {
 
 	// Verify stock decremented
 	if productRepo.products[0].Quantity != 98 {
-		t.Fatalf("expected product p1 quantity 98, got %d", productRepo.products[0].Quantity)
+		t.Fatalf("expected product p1 quantity 98, got %v", productRepo.products[0].Quantity)
 	}
 	if productRepo.products[1].Quantity != 49 {
-		t.Fatalf("expected product p2 quantity 49, got %d", productRepo.products[1].Quantity)
+		t.Fatalf("expected product p2 quantity 49, got %v", productRepo.products[1].Quantity)
 	}
 
 	// Verify sale items created with price snapshots
@@ -128,9 +188,9 @@ func TestProcessSale_InsufficientStock(t *testing.T) {
 
 	svc := NewSaleService(txManager)
 
-	_, err := svc.ProcessSale(context.Background(), []ports.SaleItemRequest{
+	_, err := svc.ProcessSale(context.Background(), ports.SaleRequest{Items: []ports.SaleItemRequest{
 		{ProductID: "p1", Quantity: 10},
-	})
+	}})
 
 	if err == nil {
 		t.Fatal("expected error for insufficient stock")
@@ -153,9 +213,9 @@ func TestProcessSale_ProductNotFound(t *testing.T) {
 
 	svc := NewSaleService(txManager)
 
-	_, err := svc.ProcessSale(context.Background(), []ports.SaleItemRequest{
+	_, err := svc.ProcessSale(context.Background(), ports.SaleRequest{Items: []ports.SaleItemRequest{
 		{ProductID: "nonexistent", Quantity: 1},
-	})
+	}})
 
 	if err == nil {
 		t.Fatal("expected error for nonexistent product")
@@ -172,7 +232,7 @@ func TestProcessSale_EmptyItems(t *testing.T) {
 
 	svc := NewSaleService(txManager)
 
-	_, err := svc.ProcessSale(context.Background(), []ports.SaleItemRequest{})
+	_, err := svc.ProcessSale(context.Background(), ports.SaleRequest{})
 	if err == nil {
 		t.Fatal("expected error for empty items")
 	}
@@ -185,7 +245,7 @@ func TestImportProducts_WithQuantityAndCostPrice(t *testing.T) {
 	auditSvc := NewAuditService(auditRepo)
 	txManager := &mockTransactionManager{productRepo: productRepo, categoryRepo: categoryRepo, auditRepo: auditRepo}
 
-	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager)
+	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager, &mockSynonymRepository{})
 
 	csv := "name,sku,base_price,quantity,cost_price\nWidget A,SKU-001,9.99,100,5.00\nWidget B,SKU-002,19.99,50,10.00\n"
 	count, err := svc.ImportProducts(context.Background(), "", strings.NewReader(csv))
@@ -198,13 +258,13 @@ func TestImportProducts_WithQuantityAndCostPrice(t *testing.T) {
 
 	// Verify quantity and cost_price were set
 	if productRepo.products[0].Quantity != 100 {
-		t.Fatalf("expected quantity 100, got %d", productRepo.products[0].Quantity)
+		t.Fatalf("expected quantity 100, got %v", productRepo.products[0].Quantity)
 	}
 	if productRepo.products[0].CostPrice != 5.00 {
 		t.Fatalf("expected cost_price 5.00, got %f", productRepo.products[0].CostPrice)
 	}
 	if productRepo.products[1].Quantity != 50 {
-		t.Fatalf("expected quantity 50, got %d", productRepo.products[1].Quantity)
+		t.Fatalf("expected quantity 50, got %v", productRepo.products[1].Quantity)
 	}
 	if productRepo.products[1].CostPrice != 10.00 {
 		t.Fatalf("expected cost_price 10.00, got %f", productRepo.products[1].CostPrice)
@@ -218,7 +278,7 @@ func TestImportProducts_WithoutQuantityAndCostPrice(t *testing.T) {
 	auditSvc := NewAuditService(auditRepo)
 	txManager := &mockTransactionManager{productRepo: productRepo, categoryRepo: categoryRepo, auditRepo: auditRepo}
 
-	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager)
+	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager, &mockSynonymRepository{})
 
 	// CSV without quantity/cost_price columns - should default to 0
 	csv := "name,sku,base_price\nWidget A,SKU-001,9.99\n"
@@ -231,7 +291,7 @@ func TestImportProducts_WithoutQuantityAndCostPrice(t *testing.T) {
 	}
 
 	if productRepo.products[0].Quantity != 0 {
-		t.Fatalf("expected quantity 0 (default), got %d", productRepo.products[0].Quantity)
+		t.Fatalf("expected quantity 0 (default), got %v", productRepo.products[0].Quantity)
 	}
 	if productRepo.products[0].CostPrice != 0.0 {
 		t.Fatalf("expected cost_price 0.0 (default), got %f", productRepo.products[0].CostPrice)
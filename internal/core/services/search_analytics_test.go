@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/torantous1337/retail-management/internal/core/domain"
@@ -10,7 +11,11 @@ import (
 // --- Enhanced mock with search/analytics support ---
 
 type searchMockProductRepository struct {
-	products []*domain.Product
+	products     []*domain.Product
+	facetRequest domain.FacetRequest
+	lastSearch   domain.FilterOptions
+	suggestMatch string
+	suggestions  []domain.ProductSuggestion
 }
 
 func (m *searchMockProductRepository) Create(_ context.Context, product *domain.Product) error {
@@ -33,13 +38,39 @@ func (m *searchMockProductRepository) GetBySKU(_ context.Context, sku string) (*
 	}
 	return nil, nil
 }
-func (m *searchMockProductRepository) List(_ context.Context, _, _ int) ([]*domain.Product, error) {
-	return m.products, nil
-}
 func (m *searchMockProductRepository) Update(_ context.Context, _ *domain.Product) error { return nil }
 func (m *searchMockProductRepository) Delete(_ context.Context, _ string) error          { return nil }
 
-func (m *searchMockProductRepository) Search(_ context.Context, opts domain.FilterOptions, allowedKeys []string) ([]*domain.Product, error) {
+func (m *searchMockProductRepository) Search(_ context.Context, opts domain.FilterOptions, allowedKeys []string) (*domain.ProductPage, error) {
+	m.lastSearch = opts
+
+	// Simulate paging in insertion order, keyed by ID
+	matched := m.match(opts, allowedKeys)
+	start := 0
+	for i, p := range matched {
+		if opts.After != nil && p.ID == opts.After.ID {
+			start = i + 1
+		}
+	}
+	end := len(matched)
+	if opts.Limit > 0 && start+opts.Limit < end {
+		end = start + opts.Limit
+	}
+
+	page := &domain.ProductPage{Products: matched[start:end], PageInfo: domain.PageInfo{
+		Total:   len(matched),
+		HasNext: end < len(matched),
+		HasPrev: start > 0,
+	}}
+	if len(page.Products) > 0 {
+		page.First = &domain.PageKey{ID: page.Products[0].ID}
+		page.Last = &domain.PageKey{ID: page.Products[len(page.Products)-1].ID}
+	}
+	return page, nil
+}
+
+// match simulates a search's filters.
+func (m *searchMockProductRepository) match(opts domain.FilterOptions, allowedKeys []string) []*domain.Product {
 	var result []*domain.Product
 	for _, p := range m.products {
 		// Simulate category filter
@@ -68,12 +99,64 @@ func (m *searchMockProductRepository) Search(_ context.Context, opts domain.Filt
 				break
 			}
 		}
+		for _, f := range opts.Filters {
+			if !allowed[f.Key] || f.Op != domain.FilterEq {
+				continue
+			}
+			if pv, ok := p.Properties[f.Key]; !ok || pv != f.Operands[0] {
+				match = false
+				break
+			}
+		}
 		if !match {
 			continue
 		}
 		result = append(result, p)
 	}
-	return result, nil
+	return result
+}
+
+func (m *searchMockProductRepository) Suggest(_ context.Context, match string, _ int) ([]domain.ProductSuggestion, error) {
+	m.suggestMatch = match
+	return m.suggestions, nil
+}
+
+func (m *searchMockProductRepository) RebuildSearchIndex(_ context.Context) error { return nil }
+
+// MatchTrigrams offers every product as a candidate for the service to score.
+func (m *searchMockProductRepository) MatchTrigrams(_ context.Context, _ string, _ int) ([]domain.ProductSuggestion, error) {
+	var candidates []domain.ProductSuggestion
+	for _, p := range m.products {
+		candidates = append(candidates, domain.ProductSuggestion{ID: p.ID, Name: p.Name, SKU: p.SKU})
+	}
+	return candidates, nil
+}
+
+func (m *searchMockProductRepository) CountFacets(ctx context.Context, opts domain.FilterOptions, allowedKeys []string, request domain.FacetRequest) (*domain.SearchFacets, error) {
+	m.facetRequest = request
+	matched := m.match(opts, allowedKeys)
+	facets := &domain.SearchFacets{Total: len(matched)}
+	for _, key := range request.AttributeKeys {
+		facet := domain.AttributeFacet{Key: key}
+		index := make(map[string]int)
+		for _, p := range matched {
+			v, ok := p.Properties[key]
+			if !ok {
+				continue
+			}
+			if i, seen := index[fmt.Sprint(v)]; seen {
+				facet.Values[i].Count++
+				continue
+			}
+			index[fmt.Sprint(v)] = len(facet.Values)
+			facet.Values = append(facet.Values, domain.FacetValue{Value: fmt.Sprint(v), Count: 1})
+		}
+		facets.Attributes = append(facets.Attributes, facet)
+	}
+	return facets, nil
+}
+func (m *searchMockProductRepository) ExistsWithProperty(_ context.Context, _, _ string, _ interface{}, _ string) (bool, error) {
+	return false, nil
 }
 
 func (m *searchMockProductRepository) GetInventorySummary(_ context.Context) (*domain.InventorySummary, error) {
@@ -85,10 +168,15 @@ func (m *searchMockProductRepository) GetInventorySummary(_ context.Context) (*d
 			catMap[catID] = &domain.CategoryBreakdown{CategoryID: catID}
 		}
 		catMap[catID].Count++
-		catMap[catID].TotalValue += p.BasePrice
+		catMap[catID].Quantity += p.Quantity
+		catMap[catID].TotalValue += p.Quantity * p.CostPrice
+		catMap[catID].RetailValue += p.Quantity * p.BasePrice
 		summary.TotalItems++
-		summary.TotalValue += p.BasePrice
+		summary.TotalQuantity += p.Quantity
+		summary.TotalValue += p.Quantity * p.CostPrice
+		summary.RetailValue += p.Quantity * p.BasePrice
 	}
+	summary.PotentialProfit = summary.RetailValue - summary.TotalValue
 	for _, bd := range catMap {
 		summary.CategoryBreakdown = append(summary.CategoryBreakdown, *bd)
 	}
@@ -109,14 +197,14 @@ func TestSearchProducts_NoFilters(t *testing.T) {
 	auditSvc := NewAuditService(auditRepo)
 	txManager := &mockTransactionManager{productRepo: &mockProductRepository{}, categoryRepo: categoryRepo, auditRepo: auditRepo}
 
-	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager)
+	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager, &mockSynonymRepository{})
 
 	results, err := svc.SearchProducts(context.Background(), domain.FilterOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(results) != 2 {
-		t.Fatalf("expected 2 products, got %d", len(results))
+	if len(results.Products) != 2 {
+		t.Fatalf("expected 2 products, got %d", len(results.Products))
 	}
 }
 
@@ -134,17 +222,17 @@ func TestSearchProducts_CategoryFilter(t *testing.T) {
 	auditSvc := NewAuditService(auditRepo)
 	txManager := &mockTransactionManager{productRepo: &mockProductRepository{}, categoryRepo: categoryRepo, auditRepo: auditRepo}
 
-	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager)
+	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager, &mockSynonymRepository{})
 
 	results, err := svc.SearchProducts(context.Background(), domain.FilterOptions{CategoryID: "cat-1"})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(results) != 1 {
-		t.Fatalf("expected 1 product, got %d", len(results))
+	if len(results.Products) != 1 {
+		t.Fatalf("expected 1 product, got %d", len(results.Products))
 	}
-	if results[0].ID != "1" {
-		t.Fatalf("expected product ID 1, got %s", results[0].ID)
+	if results.Products[0].ID != "1" {
+		t.Fatalf("expected product ID 1, got %s", results.Products[0].ID)
 	}
 }
 
@@ -161,7 +249,7 @@ func TestSearchProducts_PriceRange(t *testing.T) {
 	auditSvc := NewAuditService(auditRepo)
 	txManager := &mockTransactionManager{productRepo: &mockProductRepository{}, categoryRepo: categoryRepo, auditRepo: auditRepo}
 
-	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager)
+	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager, &mockSynonymRepository{})
 
 	min := 10.0
 	max := 20.0
@@ -172,11 +260,11 @@ func TestSearchProducts_PriceRange(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(results) != 1 {
-		t.Fatalf("expected 1 product, got %d", len(results))
+	if len(results.Products) != 1 {
+		t.Fatalf("expected 1 product, got %d", len(results.Products))
 	}
-	if results[0].ID != "2" {
-		t.Fatalf("expected product ID 2, got %s", results[0].ID)
+	if results.Products[0].ID != "2" {
+		t.Fatalf("expected product ID 2, got %s", results.Products[0].ID)
 	}
 }
 
@@ -200,7 +288,7 @@ func TestSearchProducts_PropertyFilter(t *testing.T) {
 	auditSvc := NewAuditService(auditRepo)
 	txManager := &mockTransactionManager{productRepo: &mockProductRepository{}, categoryRepo: categoryRepo, auditRepo: auditRepo}
 
-	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager)
+	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager, &mockSynonymRepository{})
 
 	results, err := svc.SearchProducts(context.Background(), domain.FilterOptions{
 		CategoryID: "cat-1",
@@ -209,11 +297,11 @@ func TestSearchProducts_PropertyFilter(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(results) != 1 {
-		t.Fatalf("expected 1 product, got %d", len(results))
+	if len(results.Products) != 1 {
+		t.Fatalf("expected 1 product, got %d", len(results.Products))
 	}
-	if results[0].ID != "1" {
-		t.Fatalf("expected product ID 1, got %s", results[0].ID)
+	if results.Products[0].ID != "1" {
+		t.Fatalf("expected product ID 1, got %s", results.Products[0].ID)
 	}
 }
 
@@ -228,7 +316,7 @@ func TestSearchProducts_PropertyFilterIgnoredWithoutCategory(t *testing.T) {
 	auditSvc := NewAuditService(auditRepo)
 	txManager := &mockTransactionManager{productRepo: &mockProductRepository{}, categoryRepo: categoryRepo, auditRepo: auditRepo}
 
-	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager)
+	svc := NewProductService(productRepo, categoryRepo, auditSvc, txManager, &mockSynonymRepository{})
 
 	// Without category_id, no allowedKeys => property filter keys are ignored
 	results, err := svc.SearchProducts(context.Background(), domain.FilterOptions{
@@ -238,21 +326,84 @@ func TestSearchProducts_PropertyFilterIgnoredWithoutCategory(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	// All products returned because no keys are allowed without category
-	if len(results) != 1 {
-		t.Fatalf("expected 1 product, got %d", len(results))
+	if len(results.Products) != 1 {
+		t.Fatalf("expected 1 product, got %d", len(results.Products))
+	}
+}
+
+func TestFacetProducts(t *testing.T) {
+	productRepo := &searchMockProductRepository{
+		products: []*domain.Product{
+			{ID: "1", SKU: "L-1", CategoryID: "lamps", Properties: map[string]interface{}{"base": "B22", "dimmable": true}},
+			{ID: "2", SKU: "L-2", CategoryID: "lamps", Properties: map[string]interface{}{"base": "B22", "dimmable": false}},
+			{ID: "3", SKU: "L-3", CategoryID: "lamps", Properties: map[string]interface{}{"base": "GU10"}},
+		},
+	}
+	categoryRepo := &mockCategoryRepository{categories: map[string]*domain.Category{
+		"lamps": {ID: "lamps", Name: "Lamps", AttributeDefinitions: []domain.AttributeDefinition{
+			{Key: "brand", Type: "string"},
+			{Key: "base", Type: "select", Options: []string{"E27", "B22"}},
+			{Key: "dimmable", Type: "boolean"},
+		}},
+	}}
+	svc := NewProductService(productRepo, categoryRepo, NewAuditService(&mockAuditLogRepository{}), &mockTransactionManager{}, &mockSynonymRepository{})
+
+	facets, err := svc.FacetProducts(context.Background(), domain.FilterOptions{CategoryID: "lamps"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(productRepo.facetRequest.PriceBounds) == 0 {
+		t.Error("expected price buckets to be requested")
+	}
+	if keys := productRepo.facetRequest.AttributeKeys; len(keys) != 2 || keys[0] != "base" || keys[1] != "dimmable" {
+		t.Fatalf("expected facets on the select and boolean attributes only, got %v", keys)
+	}
+	if len(facets.Attributes) != 2 {
+		t.Fatalf("expected 2 attribute facets, got %+v", facets.Attributes)
+	}
+
+	// Options in their defined order, with unused options and stray values
+	base := facets.Attributes[0]
+	want := []domain.FacetValue{{Value: "E27", Count: 0}, {Value: "B22", Count: 2}, {Value: "GU10", Count: 1}}
+	if base.Type != "select" || len(base.Values) != len(want) {
+		t.Fatalf("expected base values %v, got %+v", want, base)
+	}
+	for i, v := range want {
+		if base.Values[i] != v {
+			t.Errorf("expected base value %d to be %+v, got %+v", i, v, base.Values[i])
+		}
+	}
+
+	dimmable := facets.Attributes[1]
+	if len(dimmable.Values) != 2 || dimmable.Values[0] != (domain.FacetValue{Value: "true", Count: 1}) || dimmable.Values[1] != (domain.FacetValue{Value: "false", Count: 1}) {
+		t.Errorf("expected dimmable true 1, false 1, got %+v", dimmable.Values)
+	}
+}
+
+func TestFacetProducts_WithoutCategory(t *testing.T) {
+	productRepo := &searchMockProductRepository{products: []*domain.Product{{ID: "1", SKU: "A"}}}
+	svc := NewProductService(productRepo, &mockCategoryRepository{}, NewAuditService(&mockAuditLogRepository{}), &mockTransactionManager{}, &mockSynonymRepository{})
+
+	facets, err := svc.FacetProducts(context.Background(), domain.FilterOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if facets.Total != 1 || len(facets.Attributes) != 0 || len(productRepo.facetRequest.AttributeKeys) != 0 {
+		t.Errorf("expected only the general facets without a category, got %+v", facets)
 	}
 }
 
 func TestGetInventorySummary(t *testing.T) {
 	productRepo := &searchMockProductRepository{
 		products: []*domain.Product{
-			{ID: "1", Name: "A", SKU: "SKU-A", CategoryID: "cat-1", BasePrice: 10},
-			{ID: "2", Name: "B", SKU: "SKU-B", CategoryID: "cat-1", BasePrice: 20},
-			{ID: "3", Name: "C", SKU: "SKU-C", CategoryID: "cat-2", BasePrice: 30},
+			{ID: "1", Name: "A", SKU: "SKU-A", CategoryID: "cat-1", BasePrice: 10, CostPrice: 6, Quantity: 2},
+			{ID: "2", Name: "B", SKU: "SKU-B", CategoryID: "cat-1", BasePrice: 20, CostPrice: 12, Quantity: 1},
+			{ID: "3", Name: "C", SKU: "SKU-C", CategoryID: "cat-2", BasePrice: 30, CostPrice: 20},
 		},
 	}
 
-	svc := NewAnalyticsService(productRepo)
+	svc := NewAnalyticsService(productRepo, &mockCategoryRepository{}, &mockAnalyticsRepository{})
+	svc.SetCostingMethod(domain.CostingFIFO)
 
 	summary, err := svc.GetInventorySummary(context.Background())
 	if err != nil {
@@ -261,8 +412,15 @@ func TestGetInventorySummary(t *testing.T) {
 	if summary.TotalItems != 3 {
 		t.Fatalf("expected 3 total items, got %d", summary.TotalItems)
 	}
-	if summary.TotalValue != 60.0 {
-		t.Fatalf("expected total value 60.0, got %f", summary.TotalValue)
+	if summary.TotalValue != 24.0 {
+		t.Fatalf("expected total value at cost 24.0, got %f", summary.TotalValue)
+	}
+	if summary.RetailValue != 40.0 || summary.PotentialProfit != 16.0 || summary.MarginPercent != 40 {
+		t.Fatalf("expected retail 40.0, profit 16.0 at 40%%, got %f, %f, %f",
+			summary.RetailValue, summary.PotentialProfit, summary.MarginPercent)
+	}
+	if summary.CostingMethod != domain.CostingFIFO {
+		t.Fatalf("expected costing method fifo, got %q", summary.CostingMethod)
 	}
 	if len(summary.CategoryBreakdown) != 2 {
 		t.Fatalf("expected 2 category breakdowns, got %d", len(summary.CategoryBreakdown))
@@ -272,7 +430,7 @@ func TestGetInventorySummary(t *testing.T) {
 func TestGetInventorySummary_Empty(t *testing.T) {
 	productRepo := &searchMockProductRepository{}
 
-	svc := NewAnalyticsService(productRepo)
+	svc := NewAnalyticsService(productRepo, &mockCategoryRepository{}, &mockAnalyticsRepository{})
 
 	summary, err := svc.GetInventorySummary(context.Background())
 	if err != nil {
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+// ErrInvalidSearch is returned when a search asks for an unknown mode.
+var ErrInvalidSearch = errors.New("invalid search")
+
+// searchWords splits text into lower-case words of letters and digits. Any
+// other character separates words, so input such as `"MCB-32A` cannot break
+// an FTS5 query.
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// searchTerms returns the values of a product's searchable attributes as
+// text for the full-text index, one value after another. Measured values
+// give both the text entered and the number in the attribute's unit, and
+// multiselect values give each option.
+func searchTerms(category *domain.Category, product *domain.Product) string {
+	if category == nil {
+		return ""
+	}
+
+	var terms []string
+	for _, attr := range effectiveAttributes(category) {
+		if !attr.Searchable {
+			continue
+		}
+		if text, ok := product.PropertyOriginals[attr.Key]; ok {
+			terms = append(terms, text)
+		}
+		switch value := product.Properties[attr.Key].(type) {
+		case nil, bool:
+		case string:
+			terms = append(terms, value)
+		case []interface{}:
+			for _, option := range value {
+				terms = append(terms, fmt.Sprint(option))
+			}
+		default:
+			if n, ok := toNumber(value); ok {
+				terms = append(terms, strconv.FormatFloat(n, 'f', -1, 64))
+			} else {
+				terms = append(terms, fmt.Sprint(value))
+			}
+		}
+	}
+	return strings.Join(terms, " ")
+}
+
+// synonymIndex maps a phrase of search words to the phrases it also
+// matches.
+type synonymIndex map[string][]string
+
+// newSynonymIndex indexes synonyms both ways round.
+func newSynonymIndex(synonyms []*domain.SearchSynonym) synonymIndex {
+	index := make(synonymIndex)
+	for _, synonym := range synonyms {
+		term := strings.Join(searchWords(synonym.Term), " ")
+		alias := strings.Join(searchWords(synonym.Synonym), " ")
+		if term == "" || alias == "" || term == alias {
+			continue
+		}
+		index[term] = append(index[term], alias)
+		index[alias] = append(index[alias], term)
+	}
+	return index
+}
+
+// longestAt returns the number of words of the longest phrase with synonyms
+// starting at words[i], and its synonyms, or 0 if there is none.
+func (index synonymIndex) longestAt(words []string, i int) (int, []string) {
+	for n := len(words) - i; n > 0; n-- {
+		if alternatives, ok := index[strings.Join(words[i:i+n], " ")]; ok {
+			return n, alternatives
+		}
+	}
+	return 0, nil
+}
+
+// matchExpression builds an FTS5 expression requiring every word, or for a
+// phrase with synonyms the phrase or any of its synonyms. With prefix, each
+// word or phrase also matches as the start of a longer one. It returns an
+// empty string when there are no words.
+func matchExpression(words []string, synonyms synonymIndex, prefix bool) string {
+	quote := func(phrase string) string {
+		if prefix {
+			return `"` + phrase + `"*`
+		}
+		return `"` + phrase + `"`
+	}
+
+	var parts []string
+	for i := 0; i < len(words); {
+		n, alternatives := synonyms.longestAt(words, i)
+		if n == 0 {
+			parts = append(parts, quote(words[i]))
+			i++
+			continue
+		}
+		group := []string{quote(strings.Join(words[i:i+n], " "))}
+		for _, alternative := range alternatives {
+			group = append(group, quote(alternative))
+		}
+		parts = append(parts, "("+strings.Join(group, " OR ")+")")
+		i += n
+	}
+	return strings.Join(parts, " AND ")
+}
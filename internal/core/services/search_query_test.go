@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+func TestMatchExpression(t *testing.T) {
+	synonyms := newSynonymIndex([]*domain.SearchSynonym{{Term: "mcb", Synonym: "circuit breaker"}})
+
+	tests := []struct {
+		query  string
+		prefix bool
+		want   string
+	}{
+		{`lamp "E27`, false, `"lamp" AND "e27"`},
+		{`MCB 32A`, false, `("mcb" OR "circuit breaker") AND "32a"`},
+		{`circuit-breaker`, false, `("circuit breaker" OR "mcb")`},
+		{`circ bre`, true, `"circ"* AND "bre"*`},
+		{`(*) -`, false, ``},
+	}
+	for _, tt := range tests {
+		if got := matchExpression(searchWords(tt.query), synonyms, tt.prefix); got != tt.want {
+			t.Errorf("%q: expected %s, got %s", tt.query, tt.want, got)
+		}
+	}
+}
+
+func TestTextSimilarity(t *testing.T) {
+	if got := textSimilarity([]string{"circut", "breaker"}, "Circuit Breaker 3-pole"); got < fuzzyThreshold {
+		t.Errorf("expected a misspelling to be alike, got %v", got)
+	}
+	if got := textSimilarity([]string{"circut"}, "Garden Hose"); got >= fuzzyThreshold {
+		t.Errorf("expected an unrelated name not to be alike, got %v", got)
+	}
+}
+
+func TestSearchProducts_TextModes(t *testing.T) {
+	repo := &searchMockProductRepository{products: []*domain.Product{
+		{ID: "1", Name: "Circuit Breaker", SKU: "CB-1"},
+		{ID: "2", Name: "Garden Hose", SKU: "GH-1"},
+	}}
+	synonymRepo := &mockSynonymRepository{synonyms: []*domain.SearchSynonym{{Term: "mcb", Synonym: "circuit breaker"}}}
+	svc := NewProductService(repo, &mockCategoryRepository{}, nil, nil, synonymRepo)
+
+	if _, err := svc.SearchProducts(context.Background(), domain.FilterOptions{Query: `MCB "`, Mode: domain.SearchModeText}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `("mcb" OR "circuit breaker")`; repo.lastSearch.Query != want {
+		t.Errorf("expected %s, got %s", want, repo.lastSearch.Query)
+	}
+	if repo.lastSearch.Sort != domain.SortRelevance {
+		t.Errorf("expected a text search to sort by relevance, got %s", repo.lastSearch.Sort)
+	}
+
+	if _, err := svc.SearchProducts(context.Background(), domain.FilterOptions{Query: "circut", Mode: domain.SearchModeFuzzy}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.lastSearch.FuzzyIDs) != 1 || repo.lastSearch.FuzzyIDs[0] != "1" {
+		t.Errorf("expected the breaker as a misspelt match, got %v", repo.lastSearch.FuzzyIDs)
+	}
+
+	_, err := svc.SearchProducts(context.Background(), domain.FilterOptions{Query: "lamp", Mode: "sounds-like"})
+	if !errors.Is(err, ErrInvalidSearch) {
+		t.Errorf("expected ErrInvalidSearch, got %v", err)
+	}
+}
+
+func TestSuggestProducts(t *testing.T) {
+	repo := &searchMockProductRepository{
+		products: []*domain.Product{
+			{ID: "1", Name: "Circuit Breaker", SKU: "CB-1"},
+			{ID: "2", Name: "Circuit Tester", SKU: "CT-1"},
+		},
+		suggestions: []domain.ProductSuggestion{{ID: "2", Name: "Circuit Tester", SKU: "CT-1"}},
+	}
+	svc := NewProductService(repo, &mockCategoryRepository{}, nil, nil, &mockSynonymRepository{})
+
+	suggestions, err := svc.SuggestProducts(context.Background(), "circut", 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.suggestMatch != `"circut"*` {
+		t.Errorf("expected a prefix match, got %s", repo.suggestMatch)
+	}
+	if len(suggestions) != 2 || suggestions[0].ID != "2" || suggestions[0].Fuzzy || suggestions[1].ID != "1" || !suggestions[1].Fuzzy {
+		t.Fatalf("expected the prefix match then the misspelt one, got %+v", suggestions)
+	}
+
+	suggestions, err = svc.SuggestProducts(context.Background(), "--", 5)
+	if err != nil || len(suggestions) != 0 {
+		t.Errorf("expected no suggestions for punctuation, got %+v (%v)", suggestions, err)
+	}
+}
+
+func TestSearchTerms(t *testing.T) {
+	category := &domain.Category{AttributeDefinitions: []domain.AttributeDefinition{
+		{Key: "manufacturer", Type: "string", Searchable: true},
+		{Key: "voltage", Type: "number", Unit: "V", Searchable: true},
+		{Key: "finishes", Type: "multiselect", Searchable: true},
+		{Key: "colour", Type: "string"},
+	}}
+	product := &domain.Product{
+		Properties: map[string]interface{}{
+			"manufacturer": "ABB",
+			"voltage":      220.0,
+			"finishes":     []interface{}{"brass", "chrome"},
+			"colour":       "grey",
+		},
+		PropertyOriginals: map[string]string{"voltage": "0.22 kV"},
+	}
+
+	if got, want := searchTerms(category, product), "ABB 0.22 kV 220 brass chrome"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if got := searchTerms(nil, product); got != "" {
+		t.Errorf("expected no terms without a category, got %q", got)
+	}
+}
+
+func TestCreateProduct_IndexesSearchableAttributes(t *testing.T) {
+	svc, _ := newRulesTestService()
+	svc.categoryRepo.(*mockCategoryRepository).categories["lamps"].AttributeDefinitions[1].Searchable = true
+
+	product := &domain.Product{ID: "p1", SKU: "L-1", CategoryID: "lamps", Properties: map[string]interface{}{"model": "LX-1"}}
+	if err := svc.CreateProduct(context.Background(), product); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if product.SearchTerms != "LX-1" {
+		t.Errorf("expected the model to be searchable, got %q", product.SearchTerms)
+	}
+}
+
+func TestRebuildSearchIndex(t *testing.T) {
+	svc, productRepo := newRulesTestService(
+		&domain.Product{ID: "p1", SKU: "L-1", CategoryID: "lamps", Properties: map[string]interface{}{"model": "LX-1"}},
+		&domain.Product{ID: "p2", SKU: "L-2", CategoryID: "lamps", Properties: map[string]interface{}{"model": "LX-2"}, SearchTerms: "LX-2"},
+		&domain.Product{ID: "p3", SKU: "X-1"},
+	)
+	svc.categoryRepo.(*mockCategoryRepository).categories["lamps"].AttributeDefinitions[1].Searchable = true
+
+	reindexed, err := svc.RebuildSearchIndex(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reindexed != 1 || productRepo.products[0].SearchTerms != "LX-1" {
+		t.Errorf("expected only p1 to be reindexed, got %d with terms %q", reindexed, productRepo.products[0].SearchTerms)
+	}
+	if productRepo.rebuilt != 1 {
+		t.Errorf("expected the index to be rebuilt once, got %d", productRepo.rebuilt)
+	}
+}
+
+func TestUpdateCategory_ReindexesSearchableAttributes(t *testing.T) {
+	svc, productRepo, _, _ := newCategoryTestService()
+
+	update := ports.CategoryUpdate{
+		AttributeDefinitions: &[]domain.AttributeDefinition{{Key: "watage", Type: "string", Searchable: true}},
+		ExpectedVersion:      2,
+	}
+	evolution, err := svc.UpdateCategory(context.Background(), "bulbs", update)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if evolution.Reindexed != 2 {
+		t.Errorf("expected both bulbs to be reindexed, got %+v", evolution)
+	}
+	if productRepo.products[1].SearchTerms != "bright" {
+		t.Errorf("expected the wattage to be searchable, got %q", productRepo.products[1].SearchTerms)
+	}
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/torantous1337/retail-management/internal/core/domain"
+	"github.com/torantous1337/retail-management/internal/core/ports"
+)
+
+// ErrInvalidSynonym is returned when a search synonym is malformed or
+// already exists.
+var ErrInvalidSynonym = errors.New("invalid synonym")
+
+// SynonymService implements management of search synonyms.
+type SynonymService struct {
+	synonymRepo ports.SynonymRepository
+}
+
+// NewSynonymService creates a new synonym service instance.
+func NewSynonymService(synonymRepo ports.SynonymRepository) *SynonymService {
+	return &SynonymService{
+		synonymRepo: synonymRepo,
+	}
+}
+
+// AddSynonym stores a search synonym, with its term and synonym reduced to
+// the lower-case words a search matches on.
+func (s *SynonymService) AddSynonym(ctx context.Context, synonym *domain.SearchSynonym) error {
+	term := strings.Join(searchWords(synonym.Term), " ")
+	alias := strings.Join(searchWords(synonym.Synonym), " ")
+	if term == "" || alias == "" {
+		return fmt.Errorf("%w: term and synonym must contain letters or digits", ErrInvalidSynonym)
+	}
+	if term == alias {
+		return fmt.Errorf("%w: term and synonym are the same", ErrInvalidSynonym)
+	}
+
+	existing, err := s.synonymRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, other := range existing {
+		if (other.Term == term && other.Synonym == alias) || (other.Term == alias && other.Synonym == term) {
+			return fmt.Errorf("%w: %q and %q are already synonyms", ErrInvalidSynonym, term, alias)
+		}
+	}
+
+	synonym.ID = uuid.New().String()
+	synonym.Term = term
+	synonym.Synonym = alias
+	synonym.CreatedAt = time.Now()
+	return s.synonymRepo.Create(ctx, synonym)
+}
+
+// ListSynonyms retrieves every search synonym.
+func (s *SynonymService) ListSynonyms(ctx context.Context) ([]*domain.SearchSynonym, error) {
+	return s.synonymRepo.List(ctx)
+}
+
+// RemoveSynonym deletes a search synonym.
+func (s *SynonymService) RemoveSynonym(ctx context.Context, id string) error {
+	return s.synonymRepo.Delete(ctx, id)
+}
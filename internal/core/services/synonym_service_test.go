@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/torantous1337/retail-management/internal/core/domain"
+)
+
+// mockSynonymRepository is an in-memory search synonym repository.
+type mockSynonymRepository struct {
+	synonyms []*domain.SearchSynonym
+}
+
+func (m *mockSynonymRepository) Create(_ context.Context, synonym *domain.SearchSynonym) error {
+	m.synonyms = append(m.synonyms, synonym)
+	return nil
+}
+func (m *mockSynonymRepository) List(_ context.Context) ([]*domain.SearchSynonym, error) {
+	return m.synonyms, nil
+}
+func (m *mockSynonymRepository) Delete(_ context.Context, id string) error {
+	for i, synonym := range m.synonyms {
+		if synonym.ID == id {
+			m.synonyms = append(m.synonyms[:i], m.synonyms[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func TestAddSynonym(t *testing.T) {
+	repo := &mockSynonymRepository{}
+	svc := NewSynonymService(repo)
+
+	synonym := &domain.SearchSynonym{Term: " MCB ", Synonym: "Circuit-Breaker"}
+	if err := svc.AddSynonym(context.Background(), synonym); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if synonym.ID == "" || synonym.Term != "mcb" || synonym.Synonym != "circuit breaker" {
+		t.Errorf("expected a normalised synonym with an ID, got %+v", synonym)
+	}
+
+	invalid := []*domain.SearchSynonym{
+		{Term: "--", Synonym: "breaker"},
+		{Term: "MCB", Synonym: "mcb"},
+		{Term: "circuit breaker", Synonym: "mcb"}, // Already added the other way round
+	}
+	for _, synonym := range invalid {
+		if err := svc.AddSynonym(context.Background(), synonym); !errors.Is(err, ErrInvalidSynonym) {
+			t.Errorf("%q/%q: expected ErrInvalidSynonym, got %v", synonym.Term, synonym.Synonym, err)
+		}
+	}
+	if len(repo.synonyms) != 1 {
+		t.Errorf("expected 1 stored synonym, got %d", len(repo.synonyms))
+	}
+}
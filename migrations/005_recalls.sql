@@ -0,0 +1,24 @@
+-- Migration 005: Product Recalls
+-- Adds recall tracking and a quarantined (non-sellable) stock bucket on products.
+
+-- Stock moved out of quantity while a recall is in effect
+ALTER TABLE products ADD COLUMN quarantined_quantity INTEGER NOT NULL DEFAULT 0;
+
+-- Recalls table
+CREATE TABLE IF NOT EXISTS recalls (
+    id TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL REFERENCES products(id),
+    reason TEXT NOT NULL,
+    sold_from TIMESTAMP, -- Optional start of the affected sales window
+    sold_to TIMESTAMP, -- Optional end of the affected sales window
+    status TEXT NOT NULL DEFAULT 'active', -- 'active' or 'closed'
+    quarantined_quantity INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    closed_at TIMESTAMP
+);
+
+-- Index for the active-recall check performed on every sale line
+CREATE INDEX IF NOT EXISTS idx_recalls_product_status ON recalls(product_id, status);
+
+-- Index for recall reports over a product's sales history
+CREATE INDEX IF NOT EXISTS idx_sale_items_product_id ON sale_items(product_id);
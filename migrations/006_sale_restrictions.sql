@@ -0,0 +1,23 @@
+-- Migration 006: Age-Restricted and Regulated Items
+-- Adds checkout restriction rules and records the cashier's ID attestation on sales.
+
+-- Restriction rules scoped to a category or a single product
+CREATE TABLE IF NOT EXISTS sale_restrictions (
+    id TEXT PRIMARY KEY,
+    category_id TEXT REFERENCES categories(id),
+    product_id TEXT REFERENCES products(id),
+    minimum_age INTEGER NOT NULL DEFAULT 0,
+    sale_window_start TEXT, -- 'HH:MM' local time
+    sale_window_end TEXT, -- 'HH:MM' local time
+    max_quantity_per_transaction INTEGER NOT NULL DEFAULT 0, -- 0 = unlimited
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((category_id IS NULL) <> (product_id IS NULL))
+);
+
+-- Indexes for the per-line restriction lookup at checkout
+CREATE INDEX IF NOT EXISTS idx_sale_restrictions_category ON sale_restrictions(category_id);
+CREATE INDEX IF NOT EXISTS idx_sale_restrictions_product ON sale_restrictions(product_id);
+
+-- Cashier and ID attestation on sales
+ALTER TABLE sales ADD COLUMN cashier_id TEXT;
+ALTER TABLE sales ADD COLUMN id_verified INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,11 @@
+-- Migration 007: Units of Measure and Fractional Quantities
+-- Adds a unit of measure and quantity precision to products so goods can be
+-- stocked and sold by weight, length or volume.
+--
+-- products.quantity, products.quarantined_quantity, sale_items.quantity and
+-- recalls.quarantined_quantity keep their INTEGER declarations: SQLite's
+-- INTEGER affinity stores fractional values such as 1.25 as REAL unchanged,
+-- so no table rebuild is needed.
+
+ALTER TABLE products ADD COLUMN unit_of_measure TEXT NOT NULL DEFAULT 'each'; -- 'each', 'kg', 'm' or 'l'
+ALTER TABLE products ADD COLUMN quantity_precision INTEGER NOT NULL DEFAULT 0; -- decimal places allowed in quantities
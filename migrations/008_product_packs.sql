@@ -0,0 +1,19 @@
+-- Migration 008: Product Packs
+-- Adds per-product pack definitions (cases, drums) with their own barcode and
+-- price, and records which pack a sale line was sold as.
+
+CREATE TABLE IF NOT EXISTS product_packs (
+    id TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    barcode TEXT UNIQUE,
+    units_per_pack REAL NOT NULL CHECK (units_per_pack > 0), -- in the product's unit of measure
+    price REAL NOT NULL DEFAULT 0.0, -- 0 = units_per_pack × base_price
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Index for listing a product's packs
+CREATE INDEX IF NOT EXISTS idx_product_packs_product_id ON product_packs(product_id);
+
+-- Pack a sale line was sold as; quantity stays in base units
+ALTER TABLE sale_items ADD COLUMN pack_id TEXT REFERENCES product_packs(id);
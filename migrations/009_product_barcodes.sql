@@ -0,0 +1,26 @@
+-- Migration 009: Product Barcodes
+-- Allows several EAN-8, UPC-A, EAN-13 or GTIN-14 barcodes per product.
+-- Codes are keyed by their 14-digit GTIN form so the same code scanned as
+-- UPC-A or EAN-13 resolves to one product, and no two products can share it.
+-- Pack barcodes are keyed the same way, so a code cannot be assigned to both
+-- a product and a pack.
+
+CREATE TABLE IF NOT EXISTS product_barcodes (
+    gtin TEXT PRIMARY KEY, -- code zero-padded to 14 digits; the scanner lookup key
+    code TEXT NOT NULL, -- as printed on the product
+    product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    format TEXT NOT NULL, -- 'EAN-8', 'UPC-A', 'EAN-13' or 'GTIN-14'
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Index for listing a product's barcodes
+CREATE INDEX IF NOT EXISTS idx_product_barcodes_product_id ON product_barcodes(product_id);
+
+ALTER TABLE product_packs ADD COLUMN gtin TEXT; -- barcode zero-padded to 14 digits; the scanner lookup key
+
+-- Key the pack barcodes assigned before the column
+UPDATE product_packs SET gtin = substr('00000000000000' || barcode, -14)
+WHERE barcode IS NOT NULL AND gtin IS NULL;
+
+-- Index for pack scanner lookups
+CREATE INDEX IF NOT EXISTS idx_product_packs_gtin ON product_packs(gtin);
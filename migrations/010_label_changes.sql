@@ -0,0 +1,19 @@
+-- Migration 010: Label Change Tracking
+-- Records when a product field printed on shelf labels last changed, so
+-- labels can be reprinted for "changed since" without picking up the stock
+-- movements that also touch updated_at.
+
+ALTER TABLE products ADD COLUMN label_changed_at TIMESTAMP;
+
+-- Only real changes count: UPDATE statements rewrite every column
+CREATE TRIGGER IF NOT EXISTS products_label_changed
+AFTER UPDATE OF name, sku, base_price, properties, unit_of_measure ON products
+FOR EACH ROW
+WHEN OLD.name IS NOT NEW.name
+    OR OLD.sku IS NOT NEW.sku
+    OR OLD.base_price IS NOT NEW.base_price
+    OR OLD.properties IS NOT NEW.properties
+    OR OLD.unit_of_measure IS NOT NEW.unit_of_measure
+BEGIN
+    UPDATE products SET label_changed_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
@@ -0,0 +1,60 @@
+-- Migration 011: Price History and Scheduled Price Changes
+-- Keeps every base price a product has had, so past prices can be looked up,
+-- and stores price changes scheduled ahead of time, such as weekend promotions.
+
+-- Price history table, one row per base price from the moment it took effect
+CREATE TABLE IF NOT EXISTS price_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    price REAL NOT NULL,
+    changed_at TIMESTAMP NOT NULL -- Normalised with datetime() so rows compare as text
+);
+
+-- Index for history listings and price-as-of lookups
+CREATE INDEX IF NOT EXISTS idx_price_history_product ON price_history(product_id, changed_at);
+
+-- Recorded by triggers so that every write path is covered
+CREATE TRIGGER IF NOT EXISTS products_price_created
+AFTER INSERT ON products
+FOR EACH ROW
+BEGIN
+    INSERT INTO price_history (product_id, price, changed_at)
+    VALUES (NEW.id, NEW.base_price, datetime(COALESCE(NEW.created_at, CURRENT_TIMESTAMP)));
+END;
+
+CREATE TRIGGER IF NOT EXISTS products_price_changed
+AFTER UPDATE OF base_price ON products
+FOR EACH ROW
+WHEN OLD.base_price IS NOT NEW.base_price
+BEGIN
+    INSERT INTO price_history (product_id, price, changed_at)
+    VALUES (NEW.id, NEW.base_price, datetime(CURRENT_TIMESTAMP));
+END;
+
+-- Seed the current price of products created before history was kept
+INSERT INTO price_history (product_id, price, changed_at)
+SELECT p.id, p.base_price, datetime(p.created_at)
+FROM products p
+WHERE NOT EXISTS (SELECT 1 FROM price_history h WHERE h.product_id = p.id);
+
+-- Scheduled price changes table
+CREATE TABLE IF NOT EXISTS scheduled_price_changes (
+    id TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    price REAL NOT NULL,
+    effective_from TIMESTAMP NOT NULL,
+    effective_to TIMESTAMP, -- NULL for a permanent change
+    reason TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending', -- 'pending', 'active', 'completed' or 'cancelled'
+    previous_price REAL, -- Price replaced when applied, restored at effective_to
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    applied_at TIMESTAMP,
+    reverted_at TIMESTAMP
+);
+
+-- Index for the scheduler's due-change queries
+CREATE INDEX IF NOT EXISTS idx_scheduled_price_changes_status ON scheduled_price_changes(status, effective_from);
+
+-- Index for listing and overlap checks per product
+CREATE INDEX IF NOT EXISTS idx_scheduled_price_changes_product ON scheduled_price_changes(product_id, status);
@@ -0,0 +1,36 @@
+-- Migration 012: Customer Price Lists
+-- Adds named price lists with product and category overrides and quantity
+-- breaks, and records the price list a sale was charged at.
+
+-- Price lists table
+CREATE TABLE IF NOT EXISTS price_lists (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Price list rules scoped to a category or a single product
+CREATE TABLE IF NOT EXISTS price_list_rules (
+    id TEXT PRIMARY KEY,
+    price_list_id TEXT NOT NULL REFERENCES price_lists(id) ON DELETE CASCADE,
+    product_id TEXT REFERENCES products(id) ON DELETE CASCADE,
+    category_id TEXT REFERENCES categories(id),
+    min_quantity REAL NOT NULL DEFAULT 0, -- Quantity break: lowest quantity the rule applies to
+    price REAL, -- Fixed unit price
+    discount_percent REAL, -- Percentage off base_price
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((category_id IS NULL) <> (product_id IS NULL)),
+    CHECK ((price IS NULL) <> (discount_percent IS NULL))
+);
+
+-- One rule per quantity break for each product or category in a list
+CREATE UNIQUE INDEX IF NOT EXISTS idx_price_list_rules_unique
+ON price_list_rules(price_list_id, COALESCE(product_id, ''), COALESCE(category_id, ''), min_quantity);
+
+-- Index for the per-line price lookup at checkout
+CREATE INDEX IF NOT EXISTS idx_price_list_rules_product ON price_list_rules(price_list_id, product_id);
+CREATE INDEX IF NOT EXISTS idx_price_list_rules_category ON price_list_rules(price_list_id, category_id);
+
+-- Price list a sale was charged at
+ALTER TABLE sales ADD COLUMN price_list_id TEXT REFERENCES price_lists(id);
@@ -0,0 +1,23 @@
+-- Migration 013: Cost Layers
+-- Records each receipt of stock as a cost layer consumed oldest first, so
+-- stock can be carried at FIFO, weighted average or last cost.
+
+-- Cost layers table
+CREATE TABLE IF NOT EXISTS cost_layers (
+    id TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    quantity_received REAL NOT NULL,
+    quantity_remaining REAL NOT NULL,
+    unit_cost REAL NOT NULL,
+    received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Index for consuming a product's open layers in receipt order
+CREATE INDEX IF NOT EXISTS idx_cost_layers_product ON cost_layers(product_id, received_at);
+
+-- Open a layer at the current cost for stock on hand before layers existed
+INSERT INTO cost_layers (id, product_id, quantity_received, quantity_remaining, unit_cost, received_at)
+SELECT 'opening-' || p.id, p.id, p.quantity + p.quarantined_quantity, p.quantity + p.quarantined_quantity, p.cost_price, p.created_at
+FROM products p
+WHERE p.quantity + p.quarantined_quantity > 0
+  AND NOT EXISTS (SELECT 1 FROM cost_layers l WHERE l.product_id = p.id);
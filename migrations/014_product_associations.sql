@@ -0,0 +1,18 @@
+-- Migration 014: Product Associations
+-- Stores product pairs that sell together, mined from sale items, for
+-- "frequently bought with" lookups and association reports.
+
+-- Product associations table, one row per rule direction
+CREATE TABLE IF NOT EXISTS product_associations (
+    product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    associated_product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    pair_count INTEGER NOT NULL,
+    support REAL NOT NULL,
+    confidence REAL NOT NULL,
+    lift REAL NOT NULL,
+    computed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (product_id, associated_product_id)
+);
+
+-- Index for the top associations report
+CREATE INDEX IF NOT EXISTS idx_product_associations_lift ON product_associations(lift DESC);
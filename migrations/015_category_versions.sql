@@ -0,0 +1,22 @@
+-- Migration 015: Category Versions
+-- Numbers every change to a category's attribute definitions and keeps each
+-- revision, so that schema evolution can be traced and checked for conflicts.
+
+-- Current version of each category, incremented on every update
+ALTER TABLE categories ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+-- Category versions table, one row per revision
+CREATE TABLE IF NOT EXISTS category_versions (
+    category_id TEXT NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    attribute_definitions TEXT, -- JSON array of attribute definitions
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (category_id, version)
+);
+
+-- Seed the current revision of categories created before versions were kept
+INSERT INTO category_versions (category_id, version, name, attribute_definitions)
+SELECT c.id, c.version, c.name, c.attribute_definitions
+FROM categories c
+WHERE NOT EXISTS (SELECT 1 FROM category_versions v WHERE v.category_id = c.id);
@@ -0,0 +1,9 @@
+-- Migration 016: Category Hierarchy
+-- Arranges categories in a tree, e.g. Electrical > Cables > Armoured, with
+-- attribute definitions inherited down the tree.
+
+-- Parent of each category, NULL for a top-level category
+ALTER TABLE categories ADD COLUMN parent_id TEXT REFERENCES categories(id);
+
+-- Index for walking down the tree
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id);
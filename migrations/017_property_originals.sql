@@ -0,0 +1,7 @@
+-- Migration 017: Measured Property Originals
+-- Measured properties such as "voltage": "0.22 kV" are stored as numbers in
+-- their attribute's unit so they can be compared; the text as entered is
+-- kept alongside them.
+
+-- JSON object of the entered text, keyed like properties
+ALTER TABLE products ADD COLUMN property_originals TEXT;
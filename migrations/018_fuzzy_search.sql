@@ -0,0 +1,39 @@
+-- Migration 018: Typo-Tolerant Search
+-- Adds a trigram index on product name and sku for matching misspellings,
+-- and search synonyms applied to plain-text searches.
+
+-- Trigram index: every three-character run of the name and sku
+CREATE VIRTUAL TABLE IF NOT EXISTS products_trigram USING fts5(
+    name,
+    sku,
+    content='products',
+    content_rowid='rowid',
+    tokenize='trigram'
+);
+
+-- Triggers to keep the trigram index in sync with the products table.
+
+CREATE TRIGGER IF NOT EXISTS products_trigram_ai AFTER INSERT ON products BEGIN
+    INSERT INTO products_trigram(rowid, name, sku) VALUES (new.rowid, new.name, new.sku);
+END;
+
+CREATE TRIGGER IF NOT EXISTS products_trigram_ad AFTER DELETE ON products BEGIN
+    INSERT INTO products_trigram(products_trigram, rowid, name, sku) VALUES('delete', old.rowid, old.name, old.sku);
+END;
+
+CREATE TRIGGER IF NOT EXISTS products_trigram_au AFTER UPDATE ON products BEGIN
+    INSERT INTO products_trigram(products_trigram, rowid, name, sku) VALUES('delete', old.rowid, old.name, old.sku);
+    INSERT INTO products_trigram(rowid, name, sku) VALUES (new.rowid, new.name, new.sku);
+END;
+
+-- Index products that existed before the table
+INSERT INTO products_trigram(products_trigram) VALUES('rebuild');
+
+-- Search synonyms, matched both ways, e.g. 'mcb' and 'circuit breaker'
+CREATE TABLE IF NOT EXISTS search_synonyms (
+    id TEXT PRIMARY KEY,
+    term TEXT NOT NULL, -- Lower-case words separated by single spaces
+    synonym TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (term, synonym)
+);
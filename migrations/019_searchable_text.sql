@@ -0,0 +1,46 @@
+-- Migration 019: Searchable Descriptions and Attributes
+-- Adds a long description to products and indexes it for full-text search
+-- alongside name, sku and the values of attributes marked searchable, e.g.
+-- a manufacturer.
+
+ALTER TABLE products ADD COLUMN description TEXT NOT NULL DEFAULT '';
+
+-- Values of the product's searchable attributes, kept by the product service
+ALTER TABLE products ADD COLUMN search_terms TEXT NOT NULL DEFAULT '';
+
+-- Recreate the full-text index with the new columns
+DROP TRIGGER IF EXISTS products_ai;
+DROP TRIGGER IF EXISTS products_ad;
+DROP TRIGGER IF EXISTS products_au;
+DROP TABLE IF EXISTS products_fts;
+
+CREATE VIRTUAL TABLE IF NOT EXISTS products_fts USING fts5(
+    name,
+    sku,
+    description,
+    search_terms,
+    content='products',
+    content_rowid='rowid'
+);
+
+-- Triggers to keep the FTS index in sync with the products table.
+
+CREATE TRIGGER IF NOT EXISTS products_ai AFTER INSERT ON products BEGIN
+    INSERT INTO products_fts(rowid, name, sku, description, search_terms)
+    VALUES (new.rowid, new.name, new.sku, new.description, new.search_terms);
+END;
+
+CREATE TRIGGER IF NOT EXISTS products_ad AFTER DELETE ON products BEGIN
+    INSERT INTO products_fts(products_fts, rowid, name, sku, description, search_terms)
+    VALUES('delete', old.rowid, old.name, old.sku, old.description, old.search_terms);
+END;
+
+CREATE TRIGGER IF NOT EXISTS products_au AFTER UPDATE ON products BEGIN
+    INSERT INTO products_fts(products_fts, rowid, name, sku, description, search_terms)
+    VALUES('delete', old.rowid, old.name, old.sku, old.description, old.search_terms);
+    INSERT INTO products_fts(rowid, name, sku, description, search_terms)
+    VALUES (new.rowid, new.name, new.sku, new.description, new.search_terms);
+END;
+
+-- Index the products that existed before the new columns
+INSERT INTO products_fts(products_fts) VALUES('rebuild');